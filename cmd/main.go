@@ -2,16 +2,26 @@ package main
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/AndrewMislyuk/go-shop-backend/internal/config"
+	"github.com/AndrewMislyuk/go-shop-backend/internal/domain"
 	"github.com/AndrewMislyuk/go-shop-backend/internal/handler"
 	"github.com/AndrewMislyuk/go-shop-backend/internal/repository"
 	"github.com/AndrewMislyuk/go-shop-backend/internal/service"
+	"github.com/AndrewMislyuk/go-shop-backend/pkg/auth"
 	"github.com/AndrewMislyuk/go-shop-backend/pkg/database"
+	"github.com/AndrewMislyuk/go-shop-backend/pkg/hash"
+	"github.com/AndrewMislyuk/go-shop-backend/pkg/imaging"
+	"github.com/AndrewMislyuk/go-shop-backend/pkg/mailer"
+	"github.com/AndrewMislyuk/go-shop-backend/pkg/payment"
 	"github.com/AndrewMislyuk/go-shop-backend/pkg/server"
 	"github.com/AndrewMislyuk/go-shop-backend/pkg/storage"
 	"github.com/joho/godotenv"
@@ -53,7 +63,15 @@ func main() {
 		logrus.Fatal(err)
 	}
 
-	logrus.Printf("%+v\n", cfg)
+	// the config holds secrets (the legacy password salt, the SMTP password,
+	// the webhook secret), only what is safe to log is logged
+	logrus.WithFields(logrus.Fields{
+		"port":                 cfg.Server.Port,
+		"mail_driver":          cfg.Mail.Driver,
+		"payment_provider":     cfg.Payment.Provider,
+		"login_attempts_store": cfg.Auth.LoginAttempts.Store,
+		"base_currency":        cfg.Currency.Base,
+	}).Info("config loaded")
 
 	db, err := database.NewPostgresConnection(database.ConnectionInfo{
 		Host:     cfg.DB.Host,
@@ -77,31 +95,292 @@ func main() {
 
 	provider := storage.NewFileStorage(client, cfg.FileStorageConfig.Bucket, cfg.FileStorageConfig.Endpoint)
 
+	tokenManager, err := newTokenManager(cfg.Auth)
+	if err != nil {
+		logrus.Fatal(err)
+	}
+
+	hasher := hash.NewArgon2idHasher(hash.DefaultArgon2Params)
+
+	mailSender, err := newMailer(cfg.Mail)
+	if err != nil {
+		logrus.Fatal(err)
+	}
+
+	paymentProvider, err := newPaymentProvider(cfg.Payment)
+	if err != nil {
+		logrus.Fatal(err)
+	}
+
+	imageProcessor, err := newImageProcessor(cfg.Images)
+	if err != nil {
+		logrus.Fatal(err)
+	}
+
+	baseCurrency := domain.NormalizeCurrency(cfg.Currency.Base)
+	if !domain.IsCurrency(baseCurrency) {
+		logrus.Fatalf("unknown base currency %q", cfg.Currency.Base)
+	}
+
 	documentsRepo := repository.NewRepository(db)
-	documentsService := service.NewService(documentsRepo, provider)
-	handler := handler.NewHandler(documentsService)
+
+	loginAttempts, err := newLoginAttemptsStore(cfg.Auth.LoginAttempts, db)
+	if err != nil {
+		logrus.Fatal(err)
+	}
+
+	documentsService := service.NewService(service.Deps{
+		Repos:                documentsRepo,
+		Storage:              provider,
+		ImageProcessor:       imageProcessor,
+		Hasher:               hasher,
+		TokenManager:         tokenManager,
+		Mailer:               mailSender,
+		PaymentProvider:      paymentProvider,
+		LoginAttempts:        loginAttempts,
+		AccessTokenTTL:       cfg.Auth.AccessTokenTTL,
+		RefreshTokenTTL:      cfg.Auth.RefreshTokenTTL,
+		EmailVerificationTTL: cfg.Auth.EmailVerificationTTL,
+		PasswordResetTTL:     cfg.Auth.PasswordResetTTL,
+		RequireVerifiedEmail: cfg.Auth.RequireVerifiedEmail,
+		LegacyPasswordSalt:   cfg.Password.LegacySalt,
+		AppURL:               cfg.App.URL,
+		ReservationTTL:       cfg.Inventory.ReservationTTL,
+		GuestCartTTL:         cfg.Cart.GuestTTL,
+		PaymentTTL:           cfg.Orders.PaymentTTL,
+		BaseCurrency:         baseCurrency,
+		LoginGuard: service.LoginGuardConfig{
+			Window:             cfg.Auth.LoginAttempts.Window,
+			BaseDelay:          cfg.Auth.LoginAttempts.BaseDelay,
+			MaxDelay:           cfg.Auth.LoginAttempts.MaxDelay,
+			MaxAccountFailures: cfg.Auth.LoginAttempts.MaxAccountFailures,
+			MaxIPFailures:      cfg.Auth.LoginAttempts.MaxIPFailures,
+			LockoutDuration:    cfg.Auth.LoginAttempts.LockoutDuration,
+		},
+		UploadQueue: service.UploadQueueConfig{
+			Workers:     cfg.Files.Workers,
+			QueueSize:   cfg.Files.QueueSize,
+			MaxAttempts: cfg.Files.MaxAttempts,
+			Backoff:     cfg.Files.Backoff,
+			MaxBackoff:  cfg.Files.MaxBackoff,
+			StaleAfter:  cfg.Files.StaleAfter,
+		},
+		DirectUploads: service.DirectUploadConfig{
+			MaxSize:        cfg.Files.MaxDirectUploadSize,
+			UploadURLTTL:   cfg.Files.UploadURLTTL,
+			DownloadURLTTL: cfg.Files.DownloadURLTTL,
+		},
+	})
+
+	if cfg.Currency.RatesFile != "" {
+		if err := loadExchangeRates(cfg.Currency.RatesFile, documentsService.Currencies); err != nil {
+			logrus.Fatal(err)
+		}
+	}
+
+	router, err := handler.NewHandler(documentsService).InitRouter(cfg.Server.TrustedProxies)
+	if err != nil {
+		logrus.Fatal(err)
+	}
 
 	srv := new(server.Server)
 
 	go func() {
-		if err := srv.Run(fmt.Sprintf("%d", cfg.Server.Port), handler.InitRouter()); err != nil {
+		if err := srv.Run(fmt.Sprintf("%d", cfg.Server.Port), router); err != nil {
 			logrus.Fatal(err)
 		}
 	}()
 
 	logrus.Infoln("Server has been running...")
 
+	stopCleanup := make(chan struct{})
+	go runEvery("expiring stock reservations", intervalOr(cfg.Inventory.ExpireInterval, time.Minute), stopCleanup, documentsService.Inventory.ExpireReservations)
+	go runEvery("deleting abandoned guest carts", intervalOr(cfg.Cart.CleanupInterval, time.Hour), stopCleanup, documentsService.Carts.DeleteExpired)
+	go runEvery("cancelling unpaid orders", intervalOr(cfg.Orders.ExpireInterval, time.Minute), stopCleanup, documentsService.Orders.CancelExpired)
+	go runEvery("refreshing sale prices", intervalOr(cfg.Discounts.RefreshInterval, time.Minute), stopCleanup, documentsService.Discounts.RefreshSales)
+	go runEvery("deleting expired login attempts", intervalOr(cfg.Auth.LoginAttempts.CleanupInterval, time.Hour), stopCleanup, documentsService.User.DeleteExpiredLoginAttempts)
+	go runEvery("failing stale uploads", intervalOr(cfg.Files.StaleInterval, 5*time.Minute), stopCleanup, documentsService.Files.FailStaleUploads)
+
+	uploadsDone := make(chan struct{})
+	go func() {
+		documentsService.Files.RunUploads(stopCleanup)
+		close(uploadsDone)
+	}()
+
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGTERM, syscall.SIGINT)
 	<-quit
 
 	logrus.Infoln("Server was stopped")
 
+	close(stopCleanup)
+
 	if err := srv.Shutdown(context.Background()); err != nil {
 		logrus.Errorf("error occurred on server shutting down: %s", err.Error())
 	}
 
+	<-uploadsDone
+
 	if err := db.Close(); err != nil {
 		logrus.Errorf("error occurred on db connection close: %s", err.Error())
 	}
 }
+
+func newTokenManager(cfg config.Auth) (*auth.JWTManager, error) {
+	keys := make([]auth.Key, 0, len(cfg.Keys))
+
+	for _, keyCfg := range cfg.Keys {
+		var (
+			key auth.Key
+			err error
+		)
+
+		switch {
+		case keyCfg.Algorithm == auth.HS256:
+			key, err = auth.NewHMACKey(keyCfg.Id, []byte(os.Getenv(keyCfg.SecretEnv)))
+		case keyCfg.PrivateKeyFile != "":
+			key, err = readKeyFile(keyCfg.PrivateKeyFile, func(data []byte) (auth.Key, error) {
+				return auth.ParsePrivateKey(keyCfg.Id, keyCfg.Algorithm, data)
+			})
+		default:
+			key, err = readKeyFile(keyCfg.PublicKeyFile, func(data []byte) (auth.Key, error) {
+				return auth.ParsePublicKey(keyCfg.Id, keyCfg.Algorithm, data)
+			})
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		keys = append(keys, key)
+	}
+
+	return auth.NewJWTManager(cfg.SigningKeyId, keys...)
+}
+
+// runEvery calls task every interval until stop is closed. Task returns the
+// number of rows it has cleaned up, what is logged under name.
+func runEvery(name string, interval time.Duration, stop <-chan struct{}, task func() (int, error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			count, err := task()
+			if err != nil {
+				logrus.Errorf("error occurred on %s: %s", name, err.Error())
+			} else if count != 0 {
+				logrus.Infof("%s: %d", name, count)
+			}
+		case <-stop:
+			return
+		}
+	}
+}
+
+func intervalOr(interval, fallback time.Duration) time.Duration {
+	if interval == 0 {
+		return fallback
+	}
+
+	return interval
+}
+
+func newMailer(cfg config.Mail) (mailer.Mailer, error) {
+	switch cfg.Driver {
+	case "smtp":
+		return mailer.NewSMTPMailer(mailer.SMTPConfig{
+			Host:     cfg.SMTP.Host,
+			Port:     cfg.SMTP.Port,
+			Username: cfg.SMTP.Username,
+			Password: cfg.SMTP.Password,
+			From:     cfg.From,
+		}), nil
+	case "file":
+		return mailer.NewFileMailer(cfg.Dir, cfg.From)
+	case "log", "":
+		return mailer.NewLogMailer(), nil
+	default:
+		return nil, fmt.Errorf("unknown mail driver %q", cfg.Driver)
+	}
+}
+
+func newPaymentProvider(cfg config.Payment) (payment.Provider, error) {
+	// with an empty secret anyone could sign webhooks
+	if cfg.WebhookSecret == "" {
+		return nil, errors.New("PAYMENT_WEBHOOK_SECRET is empty")
+	}
+
+	switch cfg.Provider {
+	case "fake", "":
+		var deliver payment.Deliver
+		if cfg.FakeWebhookURL != "" {
+			deliver = payment.PostWebhook(cfg.FakeWebhookURL)
+		}
+
+		return payment.NewFakeProvider(cfg.WebhookSecret, deliver), nil
+	default:
+		return nil, fmt.Errorf("unknown payment provider %q", cfg.Provider)
+	}
+}
+
+// newImageProcessor checks that the renditions have distinct names, the
+// names are part of the keys of the stored files.
+func newImageProcessor(cfg config.Images) (*imaging.Processor, error) {
+	renditions := make([]imaging.Rendition, 0, len(cfg.Renditions))
+	names := map[string]bool{imaging.Original: true}
+
+	for _, rendition := range cfg.Renditions {
+		if rendition.Name == "" || names[rendition.Name] {
+			return nil, fmt.Errorf("image rendition name %q is empty or taken", rendition.Name)
+		}
+
+		names[rendition.Name] = true
+		renditions = append(renditions, imaging.Rendition{
+			Name:   rendition.Name,
+			Width:  rendition.Width,
+			Height: rendition.Height,
+		})
+	}
+
+	return imaging.NewProcessor(renditions, cfg.Quality), nil
+}
+
+// loadExchangeRates stores the rates of the file, a JSON object of rates by
+// currency code, e.g. {"USD": "0.0108"}.
+func loadExchangeRates(filename string, currencies service.Currencies) error {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+
+	var rates map[string]string
+	if err := json.Unmarshal(data, &rates); err != nil {
+		return fmt.Errorf("parse %s: %w", filename, err)
+	}
+
+	return currencies.SetRates(domain.SetExchangeRatesInput{
+		Rates: rates,
+	})
+}
+
+func newLoginAttemptsStore(cfg config.LoginAttempts, db *sql.DB) (repository.LoginAttempts, error) {
+	switch cfg.Store {
+	case "postgres":
+		return repository.NewLoginAttemptsPostgres(db), nil
+	case "memory", "":
+		return repository.NewLoginAttemptsMemory(), nil
+	default:
+		return nil, fmt.Errorf("unknown login attempts store %q", cfg.Store)
+	}
+}
+
+func readKeyFile(filename string, parse func([]byte) (auth.Key, error)) (auth.Key, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return auth.Key{}, err
+	}
+
+	return parse(data)
+}
@@ -1,6 +1,8 @@
 package config
 
 import (
+	"time"
+
 	"github.com/kelseyhightower/envconfig"
 	"github.com/spf13/viper"
 )
@@ -9,9 +11,26 @@ type Config struct {
 	DB                DB
 	Postgres          Postgres
 	FileStorageConfig FileStorageConfig
+	Auth              Auth `mapstructure:"auth"`
+	Password          Password
+	Mail              Mail      `mapstructure:"mail"`
+	Inventory         Inventory `mapstructure:"inventory"`
+	Cart              Cart      `mapstructure:"cart"`
+	Orders            Orders    `mapstructure:"orders"`
+	Discounts         Discounts `mapstructure:"discounts"`
+	Payment           Payment   `mapstructure:"payment"`
+	Currency          Currency  `mapstructure:"currency"`
+	Images            Images    `mapstructure:"images"`
+	Files             Files     `mapstructure:"files"`
+
+	App struct {
+		URL string `mapstructure:"url"`
+	} `mapstructure:"app"`
 
 	Server struct {
 		Port int `mapstructure:"port"`
+		// TrustedProxies may set the client IP with X-Forwarded-For.
+		TrustedProxies []string `mapstructure:"trusted_proxies"`
 	} `mapstructure:"server"`
 }
 
@@ -22,6 +41,143 @@ type FileStorageConfig struct {
 	SecretKey string `envconfig:"STORAGE_SECRET_KEY"`
 }
 
+type Auth struct {
+	AccessTokenTTL  time.Duration `mapstructure:"access_token_ttl"`
+	RefreshTokenTTL time.Duration `mapstructure:"refresh_token_ttl"`
+	SigningKeyId    string        `mapstructure:"signing_key_id"`
+	Keys            []SigningKey  `mapstructure:"keys"`
+
+	EmailVerificationTTL time.Duration `mapstructure:"email_verification_ttl"`
+	PasswordResetTTL     time.Duration `mapstructure:"password_reset_ttl"`
+	RequireVerifiedEmail bool          `mapstructure:"require_verified_email"`
+
+	LoginAttempts LoginAttempts `mapstructure:"login_attempts"`
+}
+
+// LoginAttempts configures brute-force protection of sign-in. Store is
+// "memory" or "postgres", the latter is shared between instances.
+type LoginAttempts struct {
+	Store              string        `mapstructure:"store"`
+	Window             time.Duration `mapstructure:"window"`
+	BaseDelay          time.Duration `mapstructure:"base_delay"`
+	MaxDelay           time.Duration `mapstructure:"max_delay"`
+	MaxAccountFailures int           `mapstructure:"max_account_failures"`
+	MaxIPFailures      int           `mapstructure:"max_ip_failures"`
+	LockoutDuration    time.Duration `mapstructure:"lockout_duration"`
+	CleanupInterval    time.Duration `mapstructure:"cleanup_interval"`
+}
+
+// Inventory configures stock reservations. Expired reservations are released
+// every ExpireInterval.
+type Inventory struct {
+	ReservationTTL time.Duration `mapstructure:"reservation_ttl"`
+	ExpireInterval time.Duration `mapstructure:"expire_interval"`
+}
+
+// Cart configures guest carts. A guest cart not changed for GuestTTL is
+// deleted by the cleanup running every CleanupInterval.
+type Cart struct {
+	GuestTTL        time.Duration `mapstructure:"guest_ttl"`
+	CleanupInterval time.Duration `mapstructure:"cleanup_interval"`
+}
+
+// Orders configures checkout. A pending order not paid within PaymentTTL is
+// cancelled by the job running every ExpireInterval.
+type Orders struct {
+	PaymentTTL     time.Duration `mapstructure:"payment_ttl"`
+	ExpireInterval time.Duration `mapstructure:"expire_interval"`
+}
+
+// Discounts configures sales. The prices of the products follow the sales
+// starting and ending on schedule every RefreshInterval.
+type Discounts struct {
+	RefreshInterval time.Duration `mapstructure:"refresh_interval"`
+}
+
+// Payment selects the payment provider, only "fake" is built in. The fake
+// provider posts its webhooks to FakeWebhookURL, none are sent when it is
+// empty.
+type Payment struct {
+	Provider       string `mapstructure:"provider"`
+	FakeWebhookURL string `mapstructure:"fake_webhook_url"`
+	WebhookSecret  string `envconfig:"PAYMENT_WEBHOOK_SECRET"`
+}
+
+// Currency sets the base currency products are priced and orders are paid
+// in. The exchange rates in RatesFile, a JSON object of rates by currency
+// code, are stored on start, overwriting the ones set before.
+type Currency struct {
+	Base      string `mapstructure:"base"`
+	RatesFile string `mapstructure:"rates_file"`
+}
+
+// Images configures the renditions uploaded images are scaled down to, each
+// of them is stored in WebP and in the format of the upload. Quality, from 1
+// to 100, applies to the lossy formats.
+type Images struct {
+	Quality    int              `mapstructure:"quality"`
+	Renditions []ImageRendition `mapstructure:"renditions"`
+}
+
+// ImageRendition bounds the size of a rendition, a zero side is unbounded.
+type ImageRendition struct {
+	Name   string `mapstructure:"name"`
+	Width  int    `mapstructure:"width"`
+	Height int    `mapstructure:"height"`
+}
+
+// Files configures the workers pushing the uploaded images to the storage.
+// The storage is tried MaxAttempts times, waiting Backoff doubled after every
+// failure up to MaxBackoff. Uploads not moved for StaleAfter are failed every
+// StaleInterval, their worker is gone. Clients upload files up to
+// MaxDirectUploadSize straight to the storage through URLs valid for
+// UploadURLTTL, the download URLs are valid for DownloadURLTTL.
+type Files struct {
+	Workers       int           `mapstructure:"workers"`
+	QueueSize     int           `mapstructure:"queue_size"`
+	MaxAttempts   int           `mapstructure:"max_attempts"`
+	Backoff       time.Duration `mapstructure:"backoff"`
+	MaxBackoff    time.Duration `mapstructure:"max_backoff"`
+	StaleAfter    time.Duration `mapstructure:"stale_after"`
+	StaleInterval time.Duration `mapstructure:"stale_interval"`
+
+	MaxDirectUploadSize int64         `mapstructure:"max_direct_upload_size"`
+	UploadURLTTL        time.Duration `mapstructure:"upload_url_ttl"`
+	DownloadURLTTL      time.Duration `mapstructure:"download_url_ttl"`
+}
+
+// Mail selects how emails are delivered: "smtp", "file" writes them to Dir,
+// "log" only logs them.
+type Mail struct {
+	Driver string `mapstructure:"driver"`
+	From   string `mapstructure:"from"`
+	Dir    string `mapstructure:"dir"`
+	SMTP   SMTP   `mapstructure:"smtp"`
+}
+
+type SMTP struct {
+	Host     string `mapstructure:"host"`
+	Port     int    `mapstructure:"port"`
+	Username string `mapstructure:"username"`
+	Password string `envconfig:"SMTP_PASSWORD"`
+}
+
+// SigningKey describes one JWT key. Asymmetric keys are read from PEM files,
+// a key with only a public_key_file can verify tokens but not sign them.
+// HMAC secrets are never stored in the config file, secret_env names the
+// environment variable that holds the secret.
+type SigningKey struct {
+	Id             string `mapstructure:"kid"`
+	Algorithm      string `mapstructure:"alg"`
+	PrivateKeyFile string `mapstructure:"private_key_file"`
+	PublicKeyFile  string `mapstructure:"public_key_file"`
+	SecretEnv      string `mapstructure:"secret_env"`
+}
+
+type Password struct {
+	LegacySalt string `envconfig:"PASSWORD_LEGACY_SALT"`
+}
+
 type DB struct {
 	Host    string `envconfig:"DB_HOST"`
 	Port    int    `envconfig:"DB_PORT"`
@@ -60,5 +216,17 @@ func New(folder, filename string) (*Config, error) {
 		return nil, err
 	}
 
+	if err := envconfig.Process("password", &cfg.Password); err != nil {
+		return nil, err
+	}
+
+	if err := envconfig.Process("smtp", &cfg.Mail.SMTP); err != nil {
+		return nil, err
+	}
+
+	if err := envconfig.Process("payment", &cfg.Payment); err != nil {
+		return nil, err
+	}
+
 	return cfg, nil
 }
@@ -0,0 +1,68 @@
+package domain
+
+import "time"
+
+// MaxCartItemQuantity caps the quantity of one cart line, merged lines
+// included.
+const MaxCartItemQuantity = 99
+
+var (
+	ErrCartNotFound     = NewError(ErrNotFound, "cart_not_found", "cart not found")
+	ErrCartItemNotFound = NewError(ErrNotFound, "cart_item_not_found", "cart item not found")
+)
+
+// Cart belongs either to a user or to a guest who holds the cart token. Only
+// the hash of the token is stored, Token is filled once, when a guest cart is
+// created. Prices of the items are the current ones, PriceChanged tells that
+// some of them differ from what the customer has seen before.
+type Cart struct {
+	Id           string     `json:"id"`
+	UserId       *string    `json:"-"`
+	TokenHash    string     `json:"-"`
+	Token        string     `json:"token,omitempty"`
+	Items        []CartItem `json:"items"`
+	ItemsCount   int        `json:"items_count"`
+	Total        Money      `json:"total"`
+	PriceChanged bool       `json:"price_changed"`
+	ExpiresAt    *time.Time `json:"expires_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// CartItem is a line of the cart. SeenPrice is the unit price the customer
+// has last seen, UnitPrice is the current one.
+type CartItem struct {
+	Id           string            `json:"id"`
+	CartId       string            `json:"-"`
+	ProductId    string            `json:"product_id"`
+	VariantId    *string           `json:"variant_id"`
+	Title        string            `json:"title"`
+	Image        string            `json:"image"`
+	Sku          string            `json:"sku,omitempty"`
+	Options      map[string]string `json:"options,omitempty"`
+	Quantity     int               `json:"quantity"`
+	UnitPrice    Money             `json:"unit_price"`
+	Sale         uint              `json:"sale"`
+	SeenPrice    Money             `json:"seen_price"`
+	PriceChanged bool              `json:"price_changed"`
+	LineTotal    Money             `json:"line_total"`
+	Available    int               `json:"available"`
+	CreatedAt    time.Time         `json:"created_at"`
+}
+
+// CartOwner identifies the cart of the request, the user when signed in or
+// else the guest cart token.
+type CartOwner struct {
+	UserId string
+	Token  string
+}
+
+type AddCartItemInput struct {
+	ProductId string  `json:"product_id" binding:"required,uuid"`
+	VariantId *string `json:"variant_id" binding:"omitempty,uuid"`
+	Quantity  int     `json:"quantity" binding:"required,min=1,max=99"`
+}
+
+type UpdateCartItemInput struct {
+	Quantity int `json:"quantity" binding:"required,min=1,max=99"`
+}
@@ -0,0 +1,94 @@
+package domain
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Levels of the taxonomy. Products belong to subtypes only.
+const (
+	CategoryLevelCategory = 1
+	CategoryLevelType     = 2
+	CategoryLevelSubtype  = 3
+)
+
+var (
+	ErrCategoryNotFound  = NewError(ErrNotFound, "category_not_found", "category not found")
+	ErrCategorySlugTaken = NewError(ErrConflict, "category_slug_taken", "category with this slug already exists on this level")
+	ErrCategoryNotEmpty  = NewError(ErrConflict, "category_not_empty", "category has subcategories or products")
+	ErrCategoryTooDeep   = NewError(ErrValidation, "category_too_deep", "subtypes can not have subcategories")
+	ErrCategoryNotLeaf   = NewError(ErrValidation, "category_not_subtype", "products can only be assigned to a subtype")
+	ErrInvalidSlug       = NewError(ErrValidation, "invalid_slug", "slug may contain only lowercase latin letters, digits and dashes")
+	ErrEmptyCategoryName = NewError(ErrValidation, "empty_category_name", "category needs a name in at least one language")
+)
+
+var slugRegexp = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// Category is a node of the category → type → subtype taxonomy. Names are
+// keyed by language code, e.g. {"ru": "Женщинам", "en": "Women"}.
+type Category struct {
+	Id        string            `json:"id"`
+	ParentId  *string           `json:"parent_id"`
+	Level     int               `json:"level"`
+	Slug      string            `json:"slug"`
+	Names     map[string]string `json:"names"`
+	Position  int               `json:"position"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt *time.Time        `json:"updated_at,omitempty"`
+}
+
+// CategoryNode is a category with its subtree. ProductsCount includes the
+// products of all descendants.
+type CategoryNode struct {
+	Category
+	ProductsCount int            `json:"products_count"`
+	Children      []CategoryNode `json:"children"`
+}
+
+type CreateCategoryInput struct {
+	ParentId *string           `json:"parent_id" binding:"omitempty,uuid"`
+	Slug     string            `json:"slug" binding:"required"`
+	Names    map[string]string `json:"names" binding:"required"`
+	Position int               `json:"position"`
+}
+
+func (i CreateCategoryInput) Validate() error {
+	if !slugRegexp.MatchString(i.Slug) {
+		return ErrInvalidSlug
+	}
+
+	return validateCategoryNames(i.Names)
+}
+
+type UpdateCategoryInput struct {
+	Slug     *string           `json:"slug"`
+	Names    map[string]string `json:"names"`
+	Position *int              `json:"position"`
+}
+
+func (i UpdateCategoryInput) Validate() error {
+	if i.Slug == nil && i.Names == nil && i.Position == nil {
+		return ErrEmptyUpdate
+	}
+
+	if i.Slug != nil && !slugRegexp.MatchString(*i.Slug) {
+		return ErrInvalidSlug
+	}
+
+	if i.Names != nil {
+		return validateCategoryNames(i.Names)
+	}
+
+	return nil
+}
+
+func validateCategoryNames(names map[string]string) error {
+	for _, name := range names {
+		if strings.TrimSpace(name) != "" {
+			return nil
+		}
+	}
+
+	return ErrEmptyCategoryName
+}
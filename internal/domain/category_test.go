@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateCategoryInput_Validate(t *testing.T) {
+	testTable := []struct {
+		name    string
+		input   CreateCategoryInput
+		wantErr error
+	}{
+		{
+			name:  "OK",
+			input: CreateCategoryInput{Slug: "starye-kollekcii", Names: map[string]string{"ru": "Старые коллекции"}},
+		},
+		{
+			name:    "Cyrillic Slug",
+			input:   CreateCategoryInput{Slug: "обувь", Names: map[string]string{"ru": "Обувь"}},
+			wantErr: ErrInvalidSlug,
+		},
+		{
+			name:    "Trailing Dash",
+			input:   CreateCategoryInput{Slug: "obuv-", Names: map[string]string{"ru": "Обувь"}},
+			wantErr: ErrInvalidSlug,
+		},
+		{
+			name:    "Blank Names",
+			input:   CreateCategoryInput{Slug: "obuv", Names: map[string]string{"ru": " "}},
+			wantErr: ErrEmptyCategoryName,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := testCase.input.Validate()
+			if testCase.wantErr != nil {
+				assert.ErrorIs(t, err, testCase.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
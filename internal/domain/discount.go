@@ -0,0 +1,287 @@
+package domain
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Kinds of discounts. Percent discounts take Value percent off, fixed ones
+// take Value off but never more than the price.
+const (
+	DiscountPercent = "percent"
+	DiscountFixed   = "fixed"
+)
+
+var (
+	ErrDiscountNotFound      = NewError(ErrNotFound, "discount_not_found", "discount not found")
+	ErrUnknownDiscountKind   = NewError(ErrValidation, "unknown_discount_kind", "discount kind must be one of percent, fixed")
+	ErrInvalidDiscountValue  = NewError(ErrValidation, "invalid_discount_value", "discount value must be positive, percents can not exceed 100")
+	ErrInvalidDiscountTarget = NewError(ErrValidation, "invalid_discount_target", "discount applies either to a product or to a category")
+	ErrInvalidDiscountPeriod = NewError(ErrValidation, "invalid_discount_period", "ends_at must be after starts_at")
+
+	ErrPromoCodeNotFound  = NewError(ErrNotFound, "promo_code_not_found", "promo code not found")
+	ErrPromoCodeTaken     = NewError(ErrConflict, "promo_code_taken", "promo code already exists")
+	ErrInvalidPromoCode   = NewError(ErrValidation, "invalid_promo_code", "promo code may contain only latin letters, digits, dashes and underscores")
+	ErrPromoCodeNotActive = NewError(ErrValidation, "promo_code_not_active", "promo code is not active")
+	ErrPromoCodeMinAmount = NewError(ErrValidation, "promo_code_min_amount", "order total is below the minimum amount of the promo code")
+	ErrPromoCodeUsedUp    = NewError(ErrConflict, "promo_code_used_up", "promo code has been used up")
+	ErrPromoCodeUserLimit = NewError(ErrConflict, "promo_code_user_limit", "promo code has already been used the maximum number of times")
+)
+
+var promoCodeRegexp = regexp.MustCompile(`^[A-Z0-9_-]+$`)
+
+// Discount is a sale of a product or of every product of a category, type or
+// subtype. The best active sale of a product lowers its price automatically.
+type Discount struct {
+	Id         string     `json:"id"`
+	Name       string     `json:"name"`
+	Kind       string     `json:"kind"`
+	Value      uint       `json:"value"`
+	ProductId  *string    `json:"product_id"`
+	CategoryId *string    `json:"category_id"`
+	StartsAt   time.Time  `json:"starts_at"`
+	EndsAt     *time.Time `json:"ends_at"`
+	Active     bool       `json:"active"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  *time.Time `json:"updated_at,omitempty"`
+}
+
+func (d Discount) Validate() error {
+	if (d.ProductId == nil) == (d.CategoryId == nil) {
+		return ErrInvalidDiscountTarget
+	}
+
+	return validateDiscount(d.Kind, d.Value, d.StartsAt, d.EndsAt)
+}
+
+type CreateDiscountInput struct {
+	Name       string     `json:"name" binding:"required,max=255"`
+	Kind       string     `json:"kind" binding:"required"`
+	Value      uint       `json:"value" binding:"required"`
+	ProductId  *string    `json:"product_id" binding:"omitempty,uuid"`
+	CategoryId *string    `json:"category_id" binding:"omitempty,uuid"`
+	StartsAt   *time.Time `json:"starts_at"`
+	EndsAt     *time.Time `json:"ends_at"`
+}
+
+// UpdateDiscountInput changes the given fields, the product or category of a
+// discount can't be changed.
+type UpdateDiscountInput struct {
+	Name     *string    `json:"name" binding:"omitempty,max=255"`
+	Kind     *string    `json:"kind"`
+	Value    *uint      `json:"value"`
+	StartsAt *time.Time `json:"starts_at"`
+	EndsAt   *time.Time `json:"ends_at"`
+	Active   *bool      `json:"active"`
+}
+
+func (i UpdateDiscountInput) Apply(d *Discount) error {
+	if i.Name == nil && i.Kind == nil && i.Value == nil && i.StartsAt == nil && i.EndsAt == nil && i.Active == nil {
+		return ErrEmptyUpdate
+	}
+
+	if i.Name != nil {
+		d.Name = *i.Name
+	}
+
+	if i.Kind != nil {
+		d.Kind = *i.Kind
+	}
+
+	if i.Value != nil {
+		d.Value = *i.Value
+	}
+
+	if i.StartsAt != nil {
+		d.StartsAt = *i.StartsAt
+	}
+
+	if i.EndsAt != nil {
+		d.EndsAt = i.EndsAt
+	}
+
+	if i.Active != nil {
+		d.Active = *i.Active
+	}
+
+	return d.Validate()
+}
+
+type DiscountsFilter struct {
+	ProductId  string `form:"product_id" binding:"omitempty,uuid"`
+	CategoryId string `form:"category_id" binding:"omitempty,uuid"`
+	Limit      int    `form:"limit" binding:"omitempty,min=1,max=100"`
+	Offset     int    `form:"offset" binding:"omitempty,min=0"`
+}
+
+type DiscountsList struct {
+	Discounts []Discount `json:"discounts"`
+	Total     int        `json:"total"`
+}
+
+// PromoCode takes its discount off the subtotal of an order. UsageLimit
+// limits the redemptions by all customers, PerUserLimit the redemptions by
+// one customer; nil means unlimited.
+type PromoCode struct {
+	Id             string     `json:"id"`
+	Code           string     `json:"code"`
+	Kind           string     `json:"kind"`
+	Value          uint       `json:"value"`
+	MinOrderAmount uint       `json:"min_order_amount"`
+	UsageLimit     *int       `json:"usage_limit"`
+	PerUserLimit   *int       `json:"per_user_limit"`
+	UsedCount      int        `json:"used_count"`
+	StartsAt       time.Time  `json:"starts_at"`
+	EndsAt         *time.Time `json:"ends_at"`
+	Active         bool       `json:"active"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      *time.Time `json:"updated_at,omitempty"`
+}
+
+func (p PromoCode) Validate() error {
+	if !promoCodeRegexp.MatchString(p.Code) {
+		return ErrInvalidPromoCode
+	}
+
+	return validateDiscount(p.Kind, p.Value, p.StartsAt, p.EndsAt)
+}
+
+// CheckApplicable tells whether the code can be applied to an order with
+// subtotal at timestamp. The usage limits are checked on redemption.
+func (p PromoCode) CheckApplicable(subtotal uint, timestamp time.Time) error {
+	if !p.Active || timestamp.Before(p.StartsAt) || (p.EndsAt != nil && !timestamp.Before(*p.EndsAt)) {
+		return ErrPromoCodeNotActive
+	}
+
+	if subtotal < p.MinOrderAmount {
+		return ErrPromoCodeMinAmount
+	}
+
+	return nil
+}
+
+// Amount is the discount of the code on subtotal.
+func (p PromoCode) Amount(subtotal uint) uint {
+	if p.Kind == DiscountPercent {
+		return subtotal * p.Value / 100
+	}
+
+	if p.Value > subtotal {
+		return subtotal
+	}
+
+	return p.Value
+}
+
+type CreatePromoCodeInput struct {
+	Code           string     `json:"code" binding:"required,max=64"`
+	Kind           string     `json:"kind" binding:"required"`
+	Value          uint       `json:"value" binding:"required"`
+	MinOrderAmount uint       `json:"min_order_amount"`
+	UsageLimit     *int       `json:"usage_limit" binding:"omitempty,min=1"`
+	PerUserLimit   *int       `json:"per_user_limit" binding:"omitempty,min=1"`
+	StartsAt       *time.Time `json:"starts_at"`
+	EndsAt         *time.Time `json:"ends_at"`
+}
+
+// UpdatePromoCodeInput changes the given fields, the code itself can't be
+// changed.
+type UpdatePromoCodeInput struct {
+	Kind           *string    `json:"kind"`
+	Value          *uint      `json:"value"`
+	MinOrderAmount *uint      `json:"min_order_amount"`
+	UsageLimit     *int       `json:"usage_limit" binding:"omitempty,min=1"`
+	PerUserLimit   *int       `json:"per_user_limit" binding:"omitempty,min=1"`
+	StartsAt       *time.Time `json:"starts_at"`
+	EndsAt         *time.Time `json:"ends_at"`
+	Active         *bool      `json:"active"`
+}
+
+func (i UpdatePromoCodeInput) Apply(p *PromoCode) error {
+	if i.Kind == nil && i.Value == nil && i.MinOrderAmount == nil && i.UsageLimit == nil && i.PerUserLimit == nil &&
+		i.StartsAt == nil && i.EndsAt == nil && i.Active == nil {
+		return ErrEmptyUpdate
+	}
+
+	if i.Kind != nil {
+		p.Kind = *i.Kind
+	}
+
+	if i.Value != nil {
+		p.Value = *i.Value
+	}
+
+	if i.MinOrderAmount != nil {
+		p.MinOrderAmount = *i.MinOrderAmount
+	}
+
+	if i.UsageLimit != nil {
+		p.UsageLimit = i.UsageLimit
+	}
+
+	if i.PerUserLimit != nil {
+		p.PerUserLimit = i.PerUserLimit
+	}
+
+	if i.StartsAt != nil {
+		p.StartsAt = *i.StartsAt
+	}
+
+	if i.EndsAt != nil {
+		p.EndsAt = i.EndsAt
+	}
+
+	if i.Active != nil {
+		p.Active = *i.Active
+	}
+
+	return p.Validate()
+}
+
+type PromoCodesFilter struct {
+	Limit  int `form:"limit" binding:"omitempty,min=1,max=100"`
+	Offset int `form:"offset" binding:"omitempty,min=0"`
+}
+
+type PromoCodesList struct {
+	PromoCodes []PromoCode `json:"promo_codes"`
+	Total      int         `json:"total"`
+}
+
+// PromoCodeRedemption is a use of a promo code by an order.
+type PromoCodeRedemption struct {
+	Id          string
+	PromoCodeId string
+	UserId      string
+	OrderId     string
+	Amount      uint
+	CreatedAt   time.Time
+}
+
+// NormalizePromoCode trims the code and upper-cases it, codes are
+// case-insensitive.
+func NormalizePromoCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+func validateDiscount(kind string, value uint, startsAt time.Time, endsAt *time.Time) error {
+	switch kind {
+	case DiscountPercent:
+		if value == 0 || value > 100 {
+			return ErrInvalidDiscountValue
+		}
+	case DiscountFixed:
+		if value == 0 {
+			return ErrInvalidDiscountValue
+		}
+	default:
+		return ErrUnknownDiscountKind
+	}
+
+	if endsAt != nil && !endsAt.After(startsAt) {
+		return ErrInvalidDiscountPeriod
+	}
+
+	return nil
+}
@@ -0,0 +1,83 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiscount_Validate(t *testing.T) {
+	productId := "453b4f0f-1f56-4c57-b43d-7b79792450a7"
+	categoryId := "0b5a3c1e-6a43-4b5e-8a1d-0f7f2d9a1c01"
+	startsAt := time.Date(2022, 03, 01, 0, 0, 0, 0, time.UTC)
+	endsAt := startsAt.Add(-time.Hour)
+
+	testTable := []struct {
+		name     string
+		discount Discount
+		wantErr  error
+	}{
+		{name: "Product Percent", discount: Discount{Kind: DiscountPercent, Value: 100, ProductId: &productId, StartsAt: startsAt}},
+		{name: "Category Fixed", discount: Discount{Kind: DiscountFixed, Value: 150000, CategoryId: &categoryId, StartsAt: startsAt}},
+		{name: "No Target", discount: Discount{Kind: DiscountPercent, Value: 10, StartsAt: startsAt}, wantErr: ErrInvalidDiscountTarget},
+		{name: "Both Targets", discount: Discount{Kind: DiscountPercent, Value: 10, ProductId: &productId, CategoryId: &categoryId, StartsAt: startsAt}, wantErr: ErrInvalidDiscountTarget},
+		{name: "Unknown Kind", discount: Discount{Kind: "bogo", Value: 10, ProductId: &productId, StartsAt: startsAt}, wantErr: ErrUnknownDiscountKind},
+		{name: "Percent Over 100", discount: Discount{Kind: DiscountPercent, Value: 101, ProductId: &productId, StartsAt: startsAt}, wantErr: ErrInvalidDiscountValue},
+		{name: "Zero Value", discount: Discount{Kind: DiscountFixed, ProductId: &productId, StartsAt: startsAt}, wantErr: ErrInvalidDiscountValue},
+		{name: "Ends Before Start", discount: Discount{Kind: DiscountFixed, Value: 100, ProductId: &productId, StartsAt: startsAt, EndsAt: &endsAt}, wantErr: ErrInvalidDiscountPeriod},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			assert.Equal(t, testCase.wantErr, testCase.discount.Validate())
+		})
+	}
+}
+
+func TestPromoCode_CheckApplicable(t *testing.T) {
+	startsAt := time.Date(2022, 03, 01, 0, 0, 0, 0, time.UTC)
+	endsAt := startsAt.AddDate(0, 1, 0)
+	code := PromoCode{Code: "SPRING10", Kind: DiscountPercent, Value: 10, MinOrderAmount: 300000, StartsAt: startsAt, EndsAt: &endsAt, Active: true}
+	inactive := code
+	inactive.Active = false
+
+	testTable := []struct {
+		name      string
+		code      PromoCode
+		subtotal  uint
+		timestamp time.Time
+		wantErr   error
+	}{
+		{name: "OK", code: code, subtotal: 300000, timestamp: startsAt},
+		{name: "Not Started", code: code, subtotal: 300000, timestamp: startsAt.Add(-time.Second), wantErr: ErrPromoCodeNotActive},
+		{name: "Ended", code: code, subtotal: 300000, timestamp: endsAt, wantErr: ErrPromoCodeNotActive},
+		{name: "Disabled", code: inactive, subtotal: 300000, timestamp: startsAt, wantErr: ErrPromoCodeNotActive},
+		{name: "Below Minimum", code: code, subtotal: 299999, timestamp: startsAt, wantErr: ErrPromoCodeMinAmount},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			assert.Equal(t, testCase.wantErr, testCase.code.CheckApplicable(testCase.subtotal, testCase.timestamp))
+		})
+	}
+}
+
+func TestPromoCode_Amount(t *testing.T) {
+	testTable := []struct {
+		name     string
+		code     PromoCode
+		subtotal uint
+		want     uint
+	}{
+		{name: "Percent", code: PromoCode{Kind: DiscountPercent, Value: 15}, subtotal: 9990, want: 1498},
+		{name: "Fixed", code: PromoCode{Kind: DiscountFixed, Value: 50000}, subtotal: 749000, want: 50000},
+		{name: "Fixed Over Subtotal", code: PromoCode{Kind: DiscountFixed, Value: 50000}, subtotal: 30000, want: 30000},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			assert.Equal(t, testCase.want, testCase.code.Amount(testCase.subtotal))
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package domain
+
+import "errors"
+
+// Error kinds. Every error returned by the domain matches exactly one of them
+// with errors.Is, handlers map the kind to the HTTP status.
+var (
+	ErrNotFound        = errors.New("not found")
+	ErrConflict        = errors.New("conflict")
+	ErrValidation      = errors.New("validation failed")
+	ErrUnauthorized    = errors.New("unauthorized")
+	ErrForbidden       = errors.New("forbidden")
+	ErrTooManyRequests = errors.New("too many requests")
+	ErrPaymentRequired = errors.New("payment required")
+	ErrTooLarge        = errors.New("too large")
+)
+
+// Error is a domain error with a stable machine-readable code, clients should
+// rely on the code rather than on the message.
+type Error struct {
+	kind    error
+	Code    string
+	Message string
+}
+
+func NewError(kind error, code, message string) *Error {
+	return &Error{
+		kind:    kind,
+		Code:    code,
+		Message: message,
+	}
+}
+
+// NewValidationError wraps errors of input binding and validation.
+func NewValidationError(err error) *Error {
+	return NewError(ErrValidation, "invalid_input", err.Error())
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Is(target error) bool {
+	return target == e.kind
+}
+
+func (e *Error) Kind() error {
+	return e.kind
+}
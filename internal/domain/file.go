@@ -2,6 +2,7 @@ package domain
 
 import (
 	"time"
+	"unicode/utf8"
 )
 
 type (
@@ -22,14 +23,166 @@ const (
 	Image FileType = "image"
 )
 
+var fileStatuses = map[FileStatus]string{
+	ClientUploadInProgress:  "client_upload_in_progress",
+	UploadedByClient:        "uploaded_by_client",
+	ClientUploadError:       "client_upload_error",
+	StorageUploadInProgress: "storage_upload_in_progress",
+	UploadedToStorage:       "uploaded_to_storage",
+	StorageUploadError:      "storage_upload_error",
+}
+
+// fileTransitions is the lifecycle of an upload: the client sends the file to
+// the API, then a worker pushes it to the storage.
+var fileTransitions = map[FileStatus][]FileStatus{
+	ClientUploadInProgress:  {UploadedByClient, ClientUploadError},
+	UploadedByClient:        {StorageUploadInProgress, StorageUploadError},
+	StorageUploadInProgress: {UploadedToStorage, StorageUploadError},
+}
+
+func (s FileStatus) String() string {
+	return fileStatuses[s]
+}
+
+func (s FileStatus) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// CanTransitionFile tells whether a file may move from one status to the
+// other.
+func CanTransitionFile(from, to FileStatus) bool {
+	for _, next := range fileTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+
+	return false
+}
+
+const maxAltTextLength = 255
+
+var (
+	ErrImageNotFound       = NewError(ErrNotFound, "image_not_found", "image not found")
+	ErrInvalidImagesOrder  = NewError(ErrValidation, "invalid_images_order", "the order must list every image of the product exactly once")
+	ErrAltTextTooLong      = NewError(ErrValidation, "alt_text_too_long", "alt text can not be longer than 255 characters")
+	ErrInvalidImage        = NewError(ErrValidation, "invalid_image", "image is damaged or not an image")
+	ErrFileStatusChanged   = NewError(ErrConflict, "file_status_changed", "file status was changed by someone else")
+	ErrUploadQueueFull     = NewError(ErrTooManyRequests, "upload_queue_full", "too many uploads in progress, try again later")
+	ErrUnsupportedFileType = NewError(ErrValidation, "unsupported_file_type", "file type is not supported")
+	ErrFileTooLarge        = NewError(ErrTooLarge, "file_too_large", "file is too large")
+	ErrFileNotUploaded     = NewError(ErrConflict, "file_not_uploaded", "file is not uploaded to the storage yet")
+	ErrFileMismatch        = NewError(ErrValidation, "file_mismatch", "uploaded file does not match the declared size or content type")
+	ErrFileNotStored       = NewError(ErrConflict, "file_not_stored", "file is not stored yet")
+	ErrProductIdRequired   = NewError(ErrValidation, "product_id_required", "select product id")
+)
+
+// File is an image of the gallery of a product. Images are shown in the
+// order of Position, the Primary one stands for the product in the lists.
+// ObjectName is the key of the file in the storage, URL and ObjectName point
+// to the original rendition. Only the images UploadedToStorage are part of
+// the gallery, Error tells why an upload has failed after Attempts.
 type File struct {
 	ID              string     `json:"id"`
 	ProductId       string     `json:"product_id"`
 	Type            FileType   `json:"type"`
 	ContentType     string     `json:"content_type"`
-	Name            string     `json:"name"`
+	Name            string     `json:"name,omitempty"`
+	ObjectName      string     `json:"-"`
 	Size            int64      `json:"size"`
+	Checksum        string     `json:"checksum,omitempty"`
 	Status          FileStatus `json:"status"`
+	Position        int        `json:"position"`
+	Primary         bool       `json:"primary"`
+	AltText         string     `json:"alt_text"`
+	Attempts        int        `json:"attempts"`
+	Error           string     `json:"error,omitempty"`
 	UploadStartedAt time.Time  `json:"upload_started_at"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       *time.Time `json:"updated_at"`
 	URL             string     `json:"url"`
+
+	Renditions []FileRendition `json:"renditions"`
+}
+
+// FileRendition is a copy of an image scaled to one of the configured sizes
+// and encoded in one of the formats. The original rendition is the uploaded
+// image with its metadata stripped.
+type FileRendition struct {
+	Name        string `json:"name"`
+	Format      string `json:"format"`
+	ContentType string `json:"content_type"`
+	Width       int    `json:"width"`
+	Height      int    `json:"height"`
+	Size        int64  `json:"size"`
+	URL         string `json:"url"`
+	ObjectName  string `json:"-"`
+}
+
+// ObjectNames returns the keys of all the renditions of the file in the
+// storage.
+func (f File) ObjectNames() []string {
+	names := []string{f.ObjectName}
+	for _, rendition := range f.Renditions {
+		if rendition.ObjectName != f.ObjectName {
+			names = append(names, rendition.ObjectName)
+		}
+	}
+
+	return names
+}
+
+func (f File) Validate() error {
+	if utf8.RuneCountInString(f.AltText) > maxAltTextLength {
+		return ErrAltTextTooLong
+	}
+
+	return nil
+}
+
+type UpdateImageInput struct {
+	AltText *string `json:"alt_text" binding:"required,max=255"`
+}
+
+// ReorderImagesInput lists the ids of all the images of a product in the new
+// order.
+type ReorderImagesInput struct {
+	Ids []string `json:"ids" binding:"required,dive,uuid"`
+}
+
+func (i ReorderImagesInput) Validate() error {
+	seen := make(map[string]bool, len(i.Ids))
+	for _, id := range i.Ids {
+		if seen[id] {
+			return ErrInvalidImagesOrder
+		}
+
+		seen[id] = true
+	}
+
+	return nil
+}
+
+// CreateUploadURLInput declares the image a client uploads to the storage
+// itself, the upload is checked against it on completion.
+type CreateUploadURLInput struct {
+	ProductId   string `json:"product_id" binding:"required,uuid"`
+	ContentType string `json:"content_type" binding:"required"`
+	Size        int64  `json:"size" binding:"required,min=1"`
+	AltText     string `json:"alt_text" binding:"max=255"`
+}
+
+// UploadURL is where the client sends the file with Method and Headers until
+// ExpiresAt, then completes the upload of File.
+type UploadURL struct {
+	File      File              `json:"file"`
+	URL       string            `json:"url"`
+	Method    string            `json:"method"`
+	Headers   map[string]string `json:"headers"`
+	ExpiresAt time.Time         `json:"expires_at"`
+}
+
+type DownloadURL struct {
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
 }
@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCanTransitionFile(t *testing.T) {
+	testTable := []struct {
+		name string
+		from FileStatus
+		to   FileStatus
+		want bool
+	}{
+		{name: "Received", from: ClientUploadInProgress, to: UploadedByClient, want: true},
+		{name: "Client Failed", from: ClientUploadInProgress, to: ClientUploadError, want: true},
+		{name: "Picked By Worker", from: UploadedByClient, to: StorageUploadInProgress, want: true},
+		{name: "Queue Full", from: UploadedByClient, to: StorageUploadError, want: true},
+		{name: "Stored", from: StorageUploadInProgress, to: UploadedToStorage, want: true},
+		{name: "Storage Failed", from: StorageUploadInProgress, to: StorageUploadError, want: true},
+		{name: "Skip Storage", from: UploadedByClient, to: UploadedToStorage, want: false},
+		{name: "Retry Failed", from: StorageUploadError, to: StorageUploadInProgress, want: false},
+		{name: "Same Status", from: UploadedToStorage, to: UploadedToStorage, want: false},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			assert.Equal(t, testCase.want, CanTransitionFile(testCase.from, testCase.to))
+		})
+	}
+}
+
+func TestFileStatus_MarshalText(t *testing.T) {
+	data, err := json.Marshal(File{Status: StorageUploadError})
+
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `"status":"storage_upload_error"`)
+}
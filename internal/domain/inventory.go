@@ -0,0 +1,152 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// Kinds of stock movements. Receipts and returns add to the stock, sales take
+// from it, adjustments may do both and need a reason.
+const (
+	MovementReceipt    = "receipt"
+	MovementSale       = "sale"
+	MovementReturn     = "return"
+	MovementAdjustment = "adjustment"
+)
+
+// Reservation statuses. Only active reservations hold stock.
+const (
+	ReservationActive    = "active"
+	ReservationConfirmed = "confirmed"
+	ReservationReleased  = "released"
+	ReservationExpired   = "expired"
+)
+
+var (
+	ErrInsufficientStock    = NewError(ErrConflict, "insufficient_stock", "not enough stock available")
+	ErrStockVersionConflict = NewError(ErrConflict, "stock_version_conflict", "stock level was changed by someone else")
+	ErrReservationNotFound  = NewError(ErrNotFound, "reservation_not_found", "reservation not found")
+	ErrReservationNotActive = NewError(ErrConflict, "reservation_not_active", "reservation is already confirmed, released or expired")
+	ErrVariantRequired      = NewError(ErrValidation, "variant_required", "product has variants, one of them must be chosen")
+	ErrUnknownMovementKind  = NewError(ErrValidation, "unknown_movement_kind", "movement kind must be one of receipt, sale, return, adjustment")
+	ErrInvalidQuantity      = NewError(ErrValidation, "invalid_quantity", "quantity must be positive, adjustments may be negative but not zero")
+	ErrEmptyReason          = NewError(ErrValidation, "empty_reason", "adjustments need a reason")
+)
+
+// StockLevel is the stock of a product without variants or of one variant.
+// Available is OnHand minus the quantity held by active reservations.
+type StockLevel struct {
+	Id           string    `json:"id"`
+	ProductId    string    `json:"product_id"`
+	ProductTitle string    `json:"product_title"`
+	VariantId    *string   `json:"variant_id"`
+	Sku          string    `json:"sku,omitempty"`
+	OnHand       int       `json:"on_hand"`
+	Reserved     int       `json:"reserved"`
+	Available    int       `json:"available"`
+	Version      int       `json:"version"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// StockMovement is an entry of the append-only ledger, Quantity is the signed
+// change of the on-hand stock.
+type StockMovement struct {
+	Id            string    `json:"id"`
+	StockLevelId  string    `json:"stock_level_id"`
+	ProductId     string    `json:"product_id"`
+	VariantId     *string   `json:"variant_id"`
+	Kind          string    `json:"kind"`
+	Quantity      int       `json:"quantity"`
+	Reason        string    `json:"reason"`
+	ActorId       *string   `json:"actor_id"`
+	ReservationId *string   `json:"reservation_id"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type Reservation struct {
+	Id           string     `json:"id"`
+	StockLevelId string     `json:"stock_level_id"`
+	ProductId    string     `json:"product_id"`
+	VariantId    *string    `json:"variant_id"`
+	Quantity     int        `json:"quantity"`
+	Status       string     `json:"status"`
+	ExpiresAt    time.Time  `json:"expires_at"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    *time.Time `json:"updated_at,omitempty"`
+}
+
+// StockMovementInput records a movement by hand. Quantity is positive for
+// every kind but adjustments, whose sign gives the direction. Version, when
+// given, must match the current version of the stock level.
+type StockMovementInput struct {
+	ProductId string  `json:"product_id" binding:"required,uuid"`
+	VariantId *string `json:"variant_id" binding:"omitempty,uuid"`
+	Kind      string  `json:"kind" binding:"required"`
+	Quantity  int     `json:"quantity" binding:"required"`
+	Reason    string  `json:"reason" binding:"max=255"`
+	Version   *int    `json:"version"`
+}
+
+func (i StockMovementInput) Validate() error {
+	switch i.Kind {
+	case MovementReceipt, MovementSale, MovementReturn:
+		if i.Quantity <= 0 {
+			return ErrInvalidQuantity
+		}
+	case MovementAdjustment:
+		if i.Quantity == 0 {
+			return ErrInvalidQuantity
+		}
+
+		if strings.TrimSpace(i.Reason) == "" {
+			return ErrEmptyReason
+		}
+	default:
+		return ErrUnknownMovementKind
+	}
+
+	return nil
+}
+
+// Delta is the signed change of the on-hand stock.
+func (i StockMovementInput) Delta() int {
+	if i.Kind == MovementSale {
+		return -i.Quantity
+	}
+
+	return i.Quantity
+}
+
+// CreateReservationInput holds stock for TTL seconds, the configured default
+// is used when TTL is zero.
+type CreateReservationInput struct {
+	ProductId string  `json:"product_id" binding:"required,uuid"`
+	VariantId *string `json:"variant_id" binding:"omitempty,uuid"`
+	Quantity  int     `json:"quantity" binding:"required,min=1"`
+	TTL       int     `json:"ttl" binding:"omitempty,min=1,max=86400"`
+}
+
+type StockLevelsFilter struct {
+	ProductId string `form:"product_id" binding:"omitempty,uuid"`
+	LowStock  *int   `form:"low_stock" binding:"omitempty,min=0"`
+	Limit     int    `form:"limit" binding:"omitempty,min=1,max=100"`
+	Offset    int    `form:"offset" binding:"omitempty,min=0"`
+}
+
+type StockLevelsList struct {
+	Levels []StockLevel `json:"levels"`
+	Total  int          `json:"total"`
+}
+
+type StockMovementsFilter struct {
+	ProductId string `form:"product_id" binding:"omitempty,uuid"`
+	VariantId string `form:"variant_id" binding:"omitempty,uuid"`
+	Kind      string `form:"kind"`
+	Limit     int    `form:"limit" binding:"omitempty,min=1,max=100"`
+	Offset    int    `form:"offset" binding:"omitempty,min=0"`
+}
+
+type StockMovementsList struct {
+	Movements []StockMovement `json:"movements"`
+	Total     int             `json:"total"`
+}
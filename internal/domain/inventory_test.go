@@ -0,0 +1,61 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStockMovementInput_Validate(t *testing.T) {
+	testTable := []struct {
+		name      string
+		input     StockMovementInput
+		wantDelta int
+		wantErr   error
+	}{
+		{
+			name:      "Receipt",
+			input:     StockMovementInput{Kind: MovementReceipt, Quantity: 10},
+			wantDelta: 10,
+		},
+		{
+			name:      "Sale",
+			input:     StockMovementInput{Kind: MovementSale, Quantity: 2},
+			wantDelta: -2,
+		},
+		{
+			name:      "Negative Adjustment",
+			input:     StockMovementInput{Kind: MovementAdjustment, Quantity: -3, Reason: "damaged"},
+			wantDelta: -3,
+		},
+		{
+			name:    "Negative Receipt",
+			input:   StockMovementInput{Kind: MovementReceipt, Quantity: -1},
+			wantErr: ErrInvalidQuantity,
+		},
+		{
+			name:    "Adjustment Without Reason",
+			input:   StockMovementInput{Kind: MovementAdjustment, Quantity: 1, Reason: " "},
+			wantErr: ErrEmptyReason,
+		},
+		{
+			name:    "Unknown Kind",
+			input:   StockMovementInput{Kind: "theft", Quantity: 1},
+			wantErr: ErrUnknownMovementKind,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := testCase.input.Validate()
+			if testCase.wantErr != nil {
+				assert.ErrorIs(t, err, testCase.wantErr)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.wantDelta, testCase.input.Delta())
+		})
+	}
+}
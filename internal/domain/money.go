@@ -0,0 +1,199 @@
+package domain
+
+import (
+	"math/big"
+	"strings"
+	"time"
+)
+
+var (
+	ErrUnknownCurrency      = NewError(ErrValidation, "unknown_currency", "currency must be a supported ISO 4217 code")
+	ErrCurrencyMismatch     = NewError(ErrValidation, "currency_mismatch", "product prices are set in the base currency, other currencies go to price lists")
+	ErrInvalidExchangeRate  = NewError(ErrValidation, "invalid_exchange_rate", "exchange rate must be a positive decimal number")
+	ErrExchangeRateNotFound = NewError(ErrNotFound, "exchange_rate_not_found", "no exchange rate for the currency")
+	ErrProductPriceNotFound = NewError(ErrNotFound, "product_price_not_found", "product has no price in this currency")
+	ErrBaseCurrency         = NewError(ErrValidation, "base_currency", "the base currency has neither exchange rate nor price list")
+)
+
+// currencyExponents are the numbers of digits after the decimal point of the
+// minor units of the supported ISO 4217 currencies.
+var currencyExponents = map[string]int{
+	"RUB": 2,
+	"BYN": 2,
+	"KZT": 2,
+	"UAH": 2,
+	"USD": 2,
+	"EUR": 2,
+	"GBP": 2,
+	"CNY": 2,
+	"TRY": 2,
+	"AED": 2,
+	"JPY": 0,
+	"KRW": 0,
+	"KWD": 3,
+}
+
+// Money is an amount in the minor units of Currency, e.g. kopecks for RUB or
+// cents for USD.
+type Money struct {
+	Amount   uint   `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+func NewMoney(amount uint, currency string) Money {
+	return Money{
+		Amount:   amount,
+		Currency: currency,
+	}
+}
+
+// Add sums two amounts of the same currency.
+func (m Money) Add(other Money) Money {
+	return NewMoney(m.Amount+other.Amount, m.Currency)
+}
+
+// Sub takes other off the amount but never goes below zero, the currencies
+// must be the same.
+func (m Money) Sub(other Money) Money {
+	if other.Amount > m.Amount {
+		return NewMoney(0, m.Currency)
+	}
+
+	return NewMoney(m.Amount-other.Amount, m.Currency)
+}
+
+// Mul is the amount of quantity units.
+func (m Money) Mul(quantity int) Money {
+	return NewMoney(m.Amount*uint(quantity), m.Currency)
+}
+
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+// IsCurrency tells whether code is a supported ISO 4217 code.
+func IsCurrency(code string) bool {
+	_, ok := currencyExponents[code]
+	return ok
+}
+
+// NormalizeCurrency trims the code and upper-cases it.
+func NormalizeCurrency(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// ExchangeRate is the number of units of Currency one unit of the base
+// currency buys. Rate is a decimal string, it is never rounded.
+type ExchangeRate struct {
+	Currency  string    `json:"currency"`
+	Rate      string    `json:"rate"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (r ExchangeRate) Validate() error {
+	if !IsCurrency(r.Currency) {
+		return ErrUnknownCurrency
+	}
+
+	if _, err := parseRate(r.Rate); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ExchangeRates lists the rates of the currencies products can be read in.
+type ExchangeRates struct {
+	Base  string         `json:"base"`
+	Rates []ExchangeRate `json:"rates"`
+}
+
+// SetExchangeRatesInput maps currency codes to their rates, e.g.
+// {"USD": "0.0108"}.
+type SetExchangeRatesInput struct {
+	Rates map[string]string `json:"rates" binding:"required"`
+}
+
+// ProductPrice is the regular price of a product in a currency other than
+// the base one, it is used instead of converting the base price.
+type ProductPrice struct {
+	ProductId string    `json:"product_id"`
+	Price     Money     `json:"price"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type SetProductPriceInput struct {
+	Amount uint `json:"amount" binding:"required"`
+}
+
+// MoneyConverter turns amounts into Currency at a fixed ratio of minor units,
+// rounding half up to the minor unit.
+type MoneyConverter struct {
+	currency string
+	ratio    *big.Rat
+}
+
+// NewRateConverter converts amounts of the base currency at rate.
+func NewRateConverter(base string, rate ExchangeRate) (MoneyConverter, error) {
+	ratio, err := parseRate(rate.Rate)
+	if err != nil {
+		return MoneyConverter{}, err
+	}
+
+	fromExp, ok := currencyExponents[base]
+	if !ok {
+		return MoneyConverter{}, ErrUnknownCurrency
+	}
+
+	toExp, ok := currencyExponents[rate.Currency]
+	if !ok {
+		return MoneyConverter{}, ErrUnknownCurrency
+	}
+
+	// rate is per major unit, the amounts are in minor units
+	for ; toExp > fromExp; toExp-- {
+		ratio.Mul(ratio, big.NewRat(10, 1))
+	}
+	for ; fromExp > toExp; fromExp-- {
+		ratio.Mul(ratio, big.NewRat(1, 10))
+	}
+
+	return MoneyConverter{
+		currency: rate.Currency,
+		ratio:    ratio,
+	}, nil
+}
+
+// NewPriceListConverter converts the prices of a product whose regular price
+// has a fixed counterpart listed in another currency, so sales and variant
+// prices keep their proportions.
+func NewPriceListConverter(regular, listed Money) MoneyConverter {
+	if regular.Amount == 0 {
+		return MoneyConverter{currency: listed.Currency, ratio: new(big.Rat)}
+	}
+
+	return MoneyConverter{
+		currency: listed.Currency,
+		ratio:    new(big.Rat).SetFrac(new(big.Int).SetUint64(uint64(listed.Amount)), new(big.Int).SetUint64(uint64(regular.Amount))),
+	}
+}
+
+func (c MoneyConverter) Convert(m Money) Money {
+	scaled := new(big.Rat).Mul(new(big.Rat).SetInt(new(big.Int).SetUint64(uint64(m.Amount))), c.ratio)
+
+	// half up: floor((2 * num + den) / (2 * den))
+	num := new(big.Int).Mul(scaled.Num(), big.NewInt(2))
+	num.Add(num, scaled.Denom())
+	den := new(big.Int).Mul(scaled.Denom(), big.NewInt(2))
+
+	return NewMoney(uint(new(big.Int).Quo(num, den).Uint64()), c.currency)
+}
+
+func parseRate(rate string) (*big.Rat, error) {
+	ratio, ok := new(big.Rat).SetString(strings.TrimSpace(rate))
+	if !ok || ratio.Sign() <= 0 {
+		return nil, ErrInvalidExchangeRate
+	}
+
+	return ratio, nil
+}
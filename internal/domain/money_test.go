@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateConverter_Convert(t *testing.T) {
+	testTable := []struct {
+		name    string
+		base    string
+		rate    ExchangeRate
+		amount  uint
+		want    Money
+		wantErr error
+	}{
+		{name: "Same Exponent", base: "RUB", rate: ExchangeRate{Currency: "USD", Rate: "0.0108"}, amount: 749000, want: NewMoney(8089, "USD")},
+		{name: "Half Up", base: "RUB", rate: ExchangeRate{Currency: "USD", Rate: "0.01"}, amount: 150, want: NewMoney(2, "USD")},
+		{name: "Below Half", base: "RUB", rate: ExchangeRate{Currency: "USD", Rate: "0.01"}, amount: 149, want: NewMoney(1, "USD")},
+		{name: "No Minor Units", base: "RUB", rate: ExchangeRate{Currency: "JPY", Rate: "1.62"}, amount: 749050, want: NewMoney(12135, "JPY")},
+		{name: "Three Digit Minor Units", base: "RUB", rate: ExchangeRate{Currency: "KWD", Rate: "0.0033"}, amount: 749000, want: NewMoney(24717, "KWD")},
+		{name: "From No Minor Units", base: "JPY", rate: ExchangeRate{Currency: "RUB", Rate: "0.62"}, amount: 1000, want: NewMoney(62000, "RUB")},
+		{name: "Unknown Currency", base: "RUB", rate: ExchangeRate{Currency: "XYZ", Rate: "1"}, wantErr: ErrUnknownCurrency},
+		{name: "Zero Rate", base: "RUB", rate: ExchangeRate{Currency: "USD", Rate: "0"}, wantErr: ErrInvalidExchangeRate},
+		{name: "Not A Number", base: "RUB", rate: ExchangeRate{Currency: "USD", Rate: "1,08"}, wantErr: ErrInvalidExchangeRate},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			converter, err := NewRateConverter(testCase.base, testCase.rate)
+			if testCase.wantErr != nil {
+				assert.ErrorIs(t, err, testCase.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.want, converter.Convert(NewMoney(testCase.amount, testCase.base)))
+		})
+	}
+}
+
+func TestPriceListConverter_Convert(t *testing.T) {
+	converter := NewPriceListConverter(NewMoney(1000000, "RUB"), NewMoney(11000, "USD"))
+
+	assert.Equal(t, NewMoney(11000, "USD"), converter.Convert(NewMoney(1000000, "RUB")))
+	assert.Equal(t, NewMoney(8250, "USD"), converter.Convert(NewMoney(750000, "RUB")))
+	assert.Equal(t, NewMoney(1, "USD"), converter.Convert(NewMoney(50, "RUB")))
+	assert.Equal(t, NewMoney(0, "USD"), converter.Convert(NewMoney(0, "RUB")))
+}
+
+func TestMoney_Sub(t *testing.T) {
+	assert.Equal(t, NewMoney(250, "RUB"), NewMoney(1000, "RUB").Sub(NewMoney(750, "RUB")))
+	assert.Equal(t, NewMoney(0, "RUB"), NewMoney(500, "RUB").Sub(NewMoney(750, "RUB")))
+}
+
+func TestPriceInput_Money(t *testing.T) {
+	price, err := PriceInput{Amount: 749000}.Money("RUB")
+	assert.NoError(t, err)
+	assert.Equal(t, NewMoney(749000, "RUB"), price)
+
+	price, err = PriceInput{Amount: 749000, Currency: "rub"}.Money("RUB")
+	assert.NoError(t, err)
+	assert.Equal(t, NewMoney(749000, "RUB"), price)
+
+	_, err = PriceInput{Amount: 749000, Currency: "USD"}.Money("RUB")
+	assert.ErrorIs(t, err, ErrCurrencyMismatch)
+}
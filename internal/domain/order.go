@@ -0,0 +1,123 @@
+package domain
+
+import "time"
+
+// Order statuses. A pending order waits for payment until ExpiresAt, paid
+// orders are shipped and delivered, cancelled and refunded are final.
+const (
+	OrderPending   = "pending"
+	OrderPaid      = "paid"
+	OrderShipped   = "shipped"
+	OrderDelivered = "delivered"
+	OrderCancelled = "cancelled"
+	OrderRefunded  = "refunded"
+)
+
+var orderTransitions = map[string][]string{
+	OrderPending:   {OrderPaid, OrderCancelled},
+	OrderPaid:      {OrderShipped, OrderRefunded},
+	OrderShipped:   {OrderDelivered},
+	OrderDelivered: {OrderRefunded},
+}
+
+var (
+	ErrOrderNotFound          = NewError(ErrNotFound, "order_not_found", "order not found")
+	ErrCartEmpty              = NewError(ErrValidation, "cart_empty", "cart is empty")
+	ErrCartPricesChanged      = NewError(ErrConflict, "cart_prices_changed", "prices in the cart have changed, review the cart before checkout")
+	ErrUnknownOrderStatus     = NewError(ErrValidation, "unknown_order_status", "order status must be one of pending, paid, shipped, delivered, cancelled, refunded")
+	ErrInvalidOrderTransition = NewError(ErrConflict, "invalid_order_transition", "order can not be moved to this status")
+	ErrOrderStatusChanged     = NewError(ErrConflict, "order_status_changed", "order status was changed by someone else")
+	ErrOrderStatusByPayment   = NewError(ErrValidation, "order_status_by_payment", "paid and refunded are set by the payment of the order")
+)
+
+// CanTransitionOrder tells whether an order may move from one status to the
+// other.
+func CanTransitionOrder(from, to string) bool {
+	for _, next := range orderTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+
+	return false
+}
+
+func IsOrderStatus(status string) bool {
+	switch status {
+	case OrderPending, OrderPaid, OrderShipped, OrderDelivered, OrderCancelled, OrderRefunded:
+		return true
+	default:
+		return false
+	}
+}
+
+// Order is a checked out cart. Items keep the product data as it was at the
+// time of purchase. Total is Subtotal minus the Discount of the PromoCode.
+type Order struct {
+	Id         string              `json:"id"`
+	Number     int64               `json:"number"`
+	UserId     string              `json:"user_id"`
+	Status     string              `json:"status"`
+	Items      []OrderItem         `json:"items,omitempty"`
+	ItemsCount int                 `json:"items_count"`
+	Subtotal   Money               `json:"subtotal"`
+	Discount   Money               `json:"discount"`
+	PromoCode  string              `json:"promo_code,omitempty"`
+	Total      Money               `json:"total"`
+	History    []OrderStatusChange `json:"history,omitempty"`
+	ExpiresAt  *time.Time          `json:"expires_at,omitempty"`
+	CreatedAt  time.Time           `json:"created_at"`
+	UpdatedAt  *time.Time          `json:"updated_at,omitempty"`
+}
+
+// OrderItem is a snapshot of a cart line. ProductId and VariantId are cleared
+// when the product is deleted, the rest stays. ReservationId holds the stock
+// of the line while the order is pending.
+type OrderItem struct {
+	Id            string            `json:"id"`
+	OrderId       string            `json:"-"`
+	ProductId     *string           `json:"product_id"`
+	VariantId     *string           `json:"variant_id"`
+	Title         string            `json:"title"`
+	Sku           string            `json:"sku,omitempty"`
+	Options       map[string]string `json:"options,omitempty"`
+	Price         Money             `json:"price"`
+	Sale          uint              `json:"sale"`
+	SaleOldPrice  Money             `json:"sale_old_price"`
+	Quantity      int               `json:"quantity"`
+	LineTotal     Money             `json:"line_total"`
+	ReservationId *string           `json:"-"`
+}
+
+// OrderStatusChange is an entry of the order history. FromStatus is nil for
+// the creation of the order, ActorId is nil for changes made by the system.
+type OrderStatusChange struct {
+	Id         string    `json:"id"`
+	OrderId    string    `json:"-"`
+	FromStatus *string   `json:"from_status"`
+	ToStatus   string    `json:"to_status"`
+	ActorId    *string   `json:"actor_id"`
+	Comment    string    `json:"comment"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type CheckoutInput struct {
+	PromoCode string `json:"promo_code" binding:"max=64"`
+}
+
+type ChangeOrderStatusInput struct {
+	Status  string `json:"status" binding:"required"`
+	Comment string `json:"comment" binding:"max=255"`
+}
+
+type OrdersFilter struct {
+	UserId string `form:"user_id" binding:"omitempty,uuid"`
+	Status string `form:"status"`
+	Limit  int    `form:"limit" binding:"omitempty,min=1,max=100"`
+	Offset int    `form:"offset" binding:"omitempty,min=0"`
+}
+
+type OrdersList struct {
+	Orders []Order `json:"orders"`
+	Total  int     `json:"total"`
+}
@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCanTransitionOrder(t *testing.T) {
+	testTable := []struct {
+		name string
+		from string
+		to   string
+		want bool
+	}{
+		{name: "Pay", from: OrderPending, to: OrderPaid, want: true},
+		{name: "Cancel Pending", from: OrderPending, to: OrderCancelled, want: true},
+		{name: "Ship", from: OrderPaid, to: OrderShipped, want: true},
+		{name: "Deliver", from: OrderShipped, to: OrderDelivered, want: true},
+		{name: "Refund Paid", from: OrderPaid, to: OrderRefunded, want: true},
+		{name: "Refund Delivered", from: OrderDelivered, to: OrderRefunded, want: true},
+		{name: "Ship Unpaid", from: OrderPending, to: OrderShipped, want: false},
+		{name: "Cancel Paid", from: OrderPaid, to: OrderCancelled, want: false},
+		{name: "Reopen Cancelled", from: OrderCancelled, to: OrderPending, want: false},
+		{name: "Same Status", from: OrderPaid, to: OrderPaid, want: false},
+		{name: "Unknown Status", from: "lost", to: OrderPaid, want: false},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			assert.Equal(t, testCase.want, CanTransitionOrder(testCase.from, testCase.to))
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package domain
+
+import "time"
+
+// Payment statuses. A payment is authorized by the provider and captured
+// right away, a failed payment was declined. Only one payment of an order may
+// be captured.
+const (
+	PaymentAuthorized = "authorized"
+	PaymentCaptured   = "captured"
+	PaymentFailed     = "failed"
+	PaymentRefunded   = "refunded"
+)
+
+var (
+	ErrPaymentNotFound    = NewError(ErrNotFound, "payment_not_found", "payment not found")
+	ErrOrderNotPayable    = NewError(ErrConflict, "order_not_payable", "only pending orders can be paid before they expire")
+	ErrOrderNotRefundable = NewError(ErrConflict, "order_not_refundable", "order has no captured payment to refund")
+	ErrInvalidWebhook     = NewError(ErrValidation, "invalid_webhook", "webhook signature is invalid")
+)
+
+// NewPaymentDeclinedError tells the customer why the provider declined the
+// payment, failureCode comes from the provider.
+func NewPaymentDeclinedError(failureCode string) *Error {
+	return NewError(ErrPaymentRequired, "payment_declined", "payment was declined: "+failureCode)
+}
+
+type Payment struct {
+	Id          string     `json:"id"`
+	OrderId     string     `json:"order_id"`
+	Provider    string     `json:"provider"`
+	IntentId    string     `json:"intent_id"`
+	Amount      uint       `json:"amount"`
+	Currency    string     `json:"currency"`
+	Status      string     `json:"status"`
+	FailureCode string     `json:"failure_code,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   *time.Time `json:"updated_at,omitempty"`
+}
+
+// PayOrderInput carries the payment method collected by the client, for the
+// fake provider it is one of its test card numbers.
+type PayOrderInput struct {
+	PaymentMethod string `json:"payment_method" binding:"required"`
+}
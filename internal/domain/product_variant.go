@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+var (
+	ErrVariantNotFound     = NewError(ErrNotFound, "variant_not_found", "variant not found")
+	ErrSkuTaken            = NewError(ErrConflict, "sku_taken", "variant with this SKU already exists")
+	ErrVariantOptionsTaken = NewError(ErrConflict, "variant_options_taken", "product already has a variant with these options")
+	ErrEmptySku            = NewError(ErrValidation, "empty_sku", "SKU is empty")
+)
+
+// ProductVariant is a purchasable version of a product, e.g. a size and a
+// colour. Price overrides the product price when set, it is in the currency of
+// the product. SalePrice is the price after the sales of the product. Stock is
+// the available quantity, it is changed through the inventory.
+type ProductVariant struct {
+	Id        string            `json:"id"`
+	ProductId string            `json:"product_id"`
+	Sku       string            `json:"sku"`
+	Options   map[string]string `json:"options"`
+	Price     *Money            `json:"price"`
+	SalePrice Money             `json:"sale_price"`
+	Stock     int               `json:"stock"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt *time.Time        `json:"updated_at,omitempty"`
+}
+
+type CreateVariantInput struct {
+	Sku     string            `json:"sku" binding:"required,max=64"`
+	Options map[string]string `json:"options"`
+	Price   *PriceInput       `json:"price"`
+}
+
+func (i CreateVariantInput) Validate() error {
+	if NormalizeSku(i.Sku) == "" {
+		return ErrEmptySku
+	}
+
+	return nil
+}
+
+// UpdateVariantInput changes the given fields. UseProductPrice drops the price
+// override.
+type UpdateVariantInput struct {
+	Sku             *string           `json:"sku" binding:"omitempty,max=64"`
+	Options         map[string]string `json:"options"`
+	Price           *PriceInput       `json:"price"`
+	UseProductPrice bool              `json:"use_product_price"`
+}
+
+func (i UpdateVariantInput) Validate() error {
+	if i.Sku == nil && i.Options == nil && i.Price == nil && !i.UseProductPrice {
+		return ErrEmptyUpdate
+	}
+
+	if i.Sku != nil && NormalizeSku(*i.Sku) == "" {
+		return ErrEmptySku
+	}
+
+	return nil
+}
+
+// NormalizeSku trims the SKU and upper-cases it, SKUs are compared as is.
+func NormalizeSku(sku string) string {
+	return strings.ToUpper(strings.TrimSpace(sku))
+}
@@ -2,38 +2,64 @@ package domain
 
 import "time"
 
+var ErrProductNotFound = NewError(ErrNotFound, "product_not_found", "product not found")
+
+// ProductsList is a product. Category, Type and Subtype are the slugs of the
+// taxonomy path of CategoryId. MinPrice and MaxPrice aggregate the variants,
+// a product without variants is sold as is for Price. InStock is true when
+// any of its stock levels has stock available. Prices are read with the best
+// active discount applied, Sale is then the percent taken off SaleOldPrice.
+// All prices are in the currency of the product unless converted on read.
+// Image is the URL of the primary image of the gallery Images.
 type ProductsList struct {
 	Id           string    `json:"id"`
 	Title        string    `json:"title" binding:"required"`
 	Image        string    `json:"image"`
-	Price        uint      `json:"price" binding:"required"`
+	Price        Money     `json:"price"`
 	Sale         uint      `json:"sale"`
-	SaleOldPrice uint      `json:"sale_old_price"`
-	Category     string    `json:"category" binding:"required"`
-	Type         string    `json:"type" binding:"required"`
-	Subtype      string    `json:"subtype" binding:"required"`
+	SaleOldPrice Money     `json:"sale_old_price"`
+	CategoryId   string    `json:"category_id"`
+	Category     string    `json:"category"`
+	Type         string    `json:"type"`
+	Subtype      string    `json:"subtype"`
 	Description  string    `json:"description"`
 	CreatedAt    time.Time `json:"created_at"`
+	MinPrice     Money     `json:"min_price"`
+	MaxPrice     Money     `json:"max_price"`
+	InStock      bool      `json:"in_stock"`
+
+	Variants []ProductVariant `json:"variants,omitempty"`
+	Images   []File           `json:"images,omitempty"`
+}
+
+// PriceInput is a price in the minor units of Currency, the base currency
+// when Currency is empty.
+type PriceInput struct {
+	Amount   uint   `json:"amount" binding:"required"`
+	Currency string `json:"currency"`
+}
+
+// Money returns the price in currency, the currency of the product. Prices
+// in other currencies are set through the price lists.
+func (i PriceInput) Money(currency string) (Money, error) {
+	if i.Currency != "" && NormalizeCurrency(i.Currency) != currency {
+		return Money{}, ErrCurrencyMismatch
+	}
+
+	return NewMoney(i.Amount, currency), nil
 }
 
+// CreateProductInput takes the regular price, sales are set up as discounts.
 type CreateProductInput struct {
-	Title        string `json:"title" binding:"required"`
-	Price        uint   `json:"price" binding:"required"`
-	Sale         uint   `json:"sale"`
-	SaleOldPrice uint   `json:"sale_old_price"`
-	Category     string `json:"category" binding:"required"`
-	Type         string `json:"type" binding:"required"`
-	Subtype      string `json:"subtype" binding:"required"`
-	Description  string `json:"description"`
+	Title       string     `json:"title" binding:"required"`
+	Price       PriceInput `json:"price" binding:"required"`
+	CategoryId  string     `json:"category_id" binding:"required,uuid"`
+	Description string     `json:"description"`
 }
 
 type UpdateProductInput struct {
-	Title        *string `json:"title" binding:"required"`
-	Price        *uint   `json:"price" binding:"required"`
-	Sale         *uint   `json:"sale"`
-	SaleOldPrice *uint   `json:"sale_old_price"`
-	Category     *string `json:"category" binding:"required"`
-	Type         *string `json:"type" binding:"required"`
-	Subtype      *string `json:"subtype" binding:"required"`
-	Description  *string `json:"description"`
+	Title       *string     `json:"title" binding:"required"`
+	Price       *PriceInput `json:"price" binding:"required"`
+	CategoryId  *string     `json:"category_id" binding:"required,uuid"`
+	Description *string     `json:"description"`
 }
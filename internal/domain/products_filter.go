@@ -0,0 +1,171 @@
+package domain
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+const (
+	ProductsSortPrice     = "price"
+	ProductsSortCreatedAt = "created_at"
+	ProductsSortTitle     = "title"
+
+	// DefaultProductsSort shows the newest products first.
+	DefaultProductsSort = "-" + ProductsSortCreatedAt
+)
+
+var (
+	ErrInvalidCursor      = NewError(ErrValidation, "invalid_cursor", "invalid cursor")
+	ErrCursorWithOffset   = NewError(ErrValidation, "cursor_with_offset", "cursor and offset can not be used together")
+	ErrInvalidPriceRange  = NewError(ErrValidation, "invalid_price_range", "min_price is greater than max_price")
+	ErrUnknownProductSort = NewError(ErrValidation, "unknown_sort", "unknown sort field")
+)
+
+// ProductsFilter describes a page of the catalogue. Category, Type and
+// Subtype are taxonomy slugs. Sort is a field name, optionally prefixed with
+// "-" for descending order. Either Offset or Cursor may be used for
+// pagination, not both. MinPrice, MaxPrice and the price sort are in the base
+// currency, Currency only converts the prices of the page.
+type ProductsFilter struct {
+	Category string `form:"category"`
+	Type     string `form:"type"`
+	Subtype  string `form:"subtype"`
+	MinPrice *uint  `form:"min_price"`
+	MaxPrice *uint  `form:"max_price"`
+	OnSale   *bool  `form:"on_sale"`
+	Sort     string `form:"sort"`
+	Limit    int    `form:"limit" binding:"omitempty,min=1,max=100"`
+	Offset   int    `form:"offset" binding:"omitempty,min=0"`
+	Cursor   string `form:"cursor"`
+	Currency string `form:"currency"`
+}
+
+func (f ProductsFilter) Validate() error {
+	if f.MinPrice != nil && f.MaxPrice != nil && *f.MinPrice > *f.MaxPrice {
+		return ErrInvalidPriceRange
+	}
+
+	if f.Cursor != "" && f.Offset != 0 {
+		return ErrCursorWithOffset
+	}
+
+	if _, _, err := f.SortField(); err != nil {
+		return err
+	}
+
+	if f.Currency != "" && !IsCurrency(f.Currency) {
+		return ErrUnknownCurrency
+	}
+
+	return nil
+}
+
+// SortField returns the field to sort by and whether the order is descending.
+func (f ProductsFilter) SortField() (string, bool, error) {
+	sort := f.Sort
+	if sort == "" {
+		sort = DefaultProductsSort
+	}
+
+	desc := strings.HasPrefix(sort, "-")
+	field := strings.TrimPrefix(sort, "-")
+
+	switch field {
+	case ProductsSortPrice, ProductsSortCreatedAt, ProductsSortTitle:
+		return field, desc, nil
+	default:
+		return "", false, ErrUnknownProductSort
+	}
+}
+
+// ProductsCursor points right after the last product of a page. It keeps the
+// sort it was issued for, so it can't be replayed with another order.
+type ProductsCursor struct {
+	Sort      string    `json:"s"`
+	Id        string    `json:"id"`
+	Price     uint      `json:"p,omitempty"`
+	CreatedAt time.Time `json:"c,omitempty"`
+	Title     string    `json:"t,omitempty"`
+}
+
+func NewProductsCursor(sort string, product ProductsList) ProductsCursor {
+	return ProductsCursor{
+		Sort:      sort,
+		Id:        product.Id,
+		Price:     product.Price.Amount,
+		CreatedAt: product.CreatedAt,
+		Title:     product.Title,
+	}
+}
+
+func (c ProductsCursor) Encode() string {
+	data, _ := json.Marshal(c)
+
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeProductsCursor parses a cursor issued for the given sort.
+func DecodeProductsCursor(value, sort string) (ProductsCursor, error) {
+	var cursor ProductsCursor
+
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return cursor, ErrInvalidCursor
+	}
+
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return cursor, ErrInvalidCursor
+	}
+
+	if cursor.Sort != sort || cursor.Id == "" {
+		return cursor, ErrInvalidCursor
+	}
+
+	return cursor, nil
+}
+
+type ProductsPage struct {
+	Products   []ProductsList
+	Total      int
+	NextCursor string
+}
+
+var (
+	ErrEmptySearchQuery = NewError(ErrValidation, "empty_search_query", "search query is empty")
+	ErrSearchWithCursor = NewError(ErrValidation, "search_with_cursor", "search results can only be paginated with offset")
+)
+
+// ProductsSearchInput is a full-text query with the listing filters. Results
+// are ordered by relevance unless Sort is given.
+type ProductsSearchInput struct {
+	Query string `form:"q" binding:"required,max=200"`
+	ProductsFilter
+}
+
+func (i ProductsSearchInput) Validate() error {
+	if strings.TrimSpace(i.Query) == "" {
+		return ErrEmptySearchQuery
+	}
+
+	if i.Cursor != "" {
+		return ErrSearchWithCursor
+	}
+
+	return i.ProductsFilter.Validate()
+}
+
+// ProductSearchResult is a product with its relevance and the matched words
+// wrapped in <b></b> in Highlight (title) and Snippet (description).
+type ProductSearchResult struct {
+	ProductsList
+	Rank      float64 `json:"rank"`
+	Highlight string  `json:"highlight"`
+	Snippet   string  `json:"snippet"`
+}
+
+type ProductsSearchPage struct {
+	Results []ProductSearchResult
+	Total   int
+}
@@ -0,0 +1,83 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProductsCursor(t *testing.T) {
+	product := ProductsList{
+		Id:        "453b4f0f-1f56-4c57-b43d-7b79792450a7",
+		Title:     "Твидовый кардиган из хлопка",
+		Price:     NewMoney(749000, "RUB"),
+		CreatedAt: time.Date(2022, 01, 12, 13, 8, 21, 32963, time.UTC),
+	}
+
+	encoded := NewProductsCursor("-price", product).Encode()
+
+	cursor, err := DecodeProductsCursor(encoded, "-price")
+	assert.NoError(t, err)
+	assert.Equal(t, NewProductsCursor("-price", product), cursor)
+
+	_, err = DecodeProductsCursor(encoded, "title")
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+
+	_, err = DecodeProductsCursor("not a cursor", "-price")
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+}
+
+func TestProductsFilter_Validate(t *testing.T) {
+	min, max := uint(500), uint(100)
+
+	testTable := []struct {
+		name    string
+		filter  ProductsFilter
+		wantErr error
+	}{
+		{
+			name:   "Default",
+			filter: ProductsFilter{},
+		},
+		{
+			name:   "Descending Title",
+			filter: ProductsFilter{Sort: "-title"},
+		},
+		{
+			name:    "Unknown Sort",
+			filter:  ProductsFilter{Sort: "description"},
+			wantErr: ErrUnknownProductSort,
+		},
+		{
+			name:    "Price Range",
+			filter:  ProductsFilter{MinPrice: &min, MaxPrice: &max},
+			wantErr: ErrInvalidPriceRange,
+		},
+		{
+			name:    "Cursor With Offset",
+			filter:  ProductsFilter{Cursor: "abc", Offset: 20},
+			wantErr: ErrCursorWithOffset,
+		},
+		{
+			name:   "Currency",
+			filter: ProductsFilter{Currency: "USD"},
+		},
+		{
+			name:    "Unknown Currency",
+			filter:  ProductsFilter{Currency: "XYZ"},
+			wantErr: ErrUnknownCurrency,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := testCase.filter.Validate()
+			if testCase.wantErr != nil {
+				assert.ErrorIs(t, err, testCase.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package domain
+
+const (
+	RoleAdmin    = "ADMIN"
+	RoleCustomer = "CUSTOMER"
+)
+
+const (
+	PermissionProductsWrite   = "products:write"
+	PermissionCategoriesWrite = "categories:write"
+	PermissionInventoryWrite  = "inventory:write"
+	PermissionOrdersManage    = "orders:manage"
+	PermissionDiscountsWrite  = "discounts:write"
+	PermissionCurrenciesWrite = "currencies:write"
+	PermissionFilesUpload     = "files:upload"
+	PermissionUsersManage     = "users:manage"
+)
+
+var ErrRoleNotFound = NewError(ErrNotFound, "role_not_found", "role not found")
+
+type Role struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions"`
+}
+
+type RoleInput struct {
+	Role string `json:"role" binding:"required"`
+}
@@ -0,0 +1,40 @@
+package domain
+
+import "time"
+
+var (
+	ErrInvalidCredentials   = NewError(ErrUnauthorized, "invalid_credentials", "invalid email or password")
+	ErrInvalidAccessToken   = NewError(ErrUnauthorized, "invalid_access_token", "invalid access token")
+	ErrInvalidRefreshToken  = NewError(ErrUnauthorized, "invalid_refresh_token", "invalid refresh token")
+	ErrRefreshTokenReused   = NewError(ErrUnauthorized, "refresh_token_reused", "refresh token has already been used")
+	ErrRefreshTokenNotFound = NewError(ErrNotFound, "refresh_token_not_found", "refresh token not found")
+)
+
+type RefreshToken struct {
+	Id        string
+	UserId    string
+	SessionId string
+	TokenHash string
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	RevokedAt *time.Time
+	CreatedAt time.Time
+}
+
+// Identity is the user an access token belongs to, resolved from the store,
+// together with the session the token was issued for.
+type Identity struct {
+	User      User
+	SessionId string
+}
+
+// Tokens are issued to the user UserId.
+type Tokens struct {
+	UserId       string
+	AccessToken  string
+	RefreshToken string
+}
+
+type RefreshTokenInput struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
@@ -1,16 +1,36 @@
 package domain
 
-import "time"
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	ErrUserNotFound  = NewError(ErrNotFound, "user_not_found", "user not found")
+	ErrUserBlocked   = NewError(ErrForbidden, "user_blocked", "user is blocked")
+	ErrWrongPassword = NewError(ErrValidation, "wrong_password", "current password is incorrect")
+	ErrBlockYourself = NewError(ErrValidation, "block_yourself", "you can't block yourself")
+	ErrEmptyUpdate   = NewError(ErrValidation, "empty_update", "update structure has no values")
+	ErrEmailTaken    = NewError(ErrConflict, "email_taken", "email is already taken")
+	ErrInvalidPhone  = NewError(ErrValidation, "invalid_phone", "invalid phone number")
+)
+
+var phoneRegexp = regexp.MustCompile(`^\+?[0-9]{5,15}$`)
 
 type User struct {
-	Id        string    `json:"id"`
-	Name      string    `json:"name" binding:"required,min=1"`
-	Surname   string    `json:"surname" binding:"required,min=1"`
-	Email     string    `json:"email" binding:"required,email"`
-	Phone     string    `json:"phone" binding:"required"`
-	Role      string    `json:"role" binding:"required"`
-	Password  string    `json:"password" binding:"required,min=5"`
-	CreatedAt time.Time `json:"created_at"`
+	Id              string     `json:"id"`
+	Name            string     `json:"name" binding:"required,min=1"`
+	Surname         string     `json:"surname" binding:"required,min=1"`
+	Email           string     `json:"email" binding:"required,email"`
+	Phone           string     `json:"phone" binding:"required"`
+	Roles           []string   `json:"roles"`
+	Permissions     []string   `json:"permissions"`
+	Password        string     `json:"-"`
+	CreatedAt       time.Time  `json:"created_at"`
+	EmailVerifiedAt *time.Time `json:"email_verified_at,omitempty"`
+	BlockedAt       *time.Time `json:"blocked_at,omitempty"`
+	DeletedAt       *time.Time `json:"-"`
 }
 
 type UserSignUp struct {
@@ -19,10 +39,109 @@ type UserSignUp struct {
 	Email    string `json:"email" binding:"required,email"`
 	Phone    string `json:"phone" binding:"required"`
 	Password string `json:"password" binding:"required,min=5"`
-	Role     string `json:"role" binding:"required"`
 }
 
 type UserSignIn struct {
 	Email    string `json:"email" binding:"required"`
 	Password string `json:"password" binding:"required"`
 }
+
+type UpdateUserInput struct {
+	Name    *string `json:"name" binding:"omitempty,min=1"`
+	Surname *string `json:"surname" binding:"omitempty,min=1"`
+	Email   *string `json:"email" binding:"omitempty,email"`
+	Phone   *string `json:"phone" binding:"omitempty,min=1"`
+}
+
+func (i UpdateUserInput) Validate() error {
+	if i.Name == nil && i.Surname == nil && i.Email == nil && i.Phone == nil {
+		return ErrEmptyUpdate
+	}
+
+	return nil
+}
+
+type ChangePasswordInput struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required,min=5"`
+}
+
+type UsersFilter struct {
+	Search  string `form:"search"`
+	Blocked *bool  `form:"blocked"`
+	Limit   int    `form:"limit" binding:"omitempty,min=1,max=100"`
+	Offset  int    `form:"offset" binding:"omitempty,min=0"`
+}
+
+type UsersList struct {
+	Users []User `json:"users"`
+	Total int    `json:"total"`
+}
+
+func (u User) HasPermission(permission string) bool {
+	for _, p := range u.Permissions {
+		if p == permission {
+			return true
+		}
+	}
+
+	return false
+}
+
+// NormalizeEmail returns the form emails are stored and looked up in.
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// NormalizePhone strips spaces, dashes, dots and brackets and replaces the
+// international 00 prefix with +.
+func NormalizePhone(phone string) (string, error) {
+	phone = strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '-', '.', '(', ')':
+			return -1
+		}
+
+		return r
+	}, strings.TrimSpace(phone))
+
+	if strings.HasPrefix(phone, "00") {
+		phone = "+" + strings.TrimPrefix(phone, "00")
+	}
+
+	if !phoneRegexp.MatchString(phone) {
+		return "", ErrInvalidPhone
+	}
+
+	return phone, nil
+}
+
+func (u *UserSignUp) Normalize() error {
+	phone, err := NormalizePhone(u.Phone)
+	if err != nil {
+		return err
+	}
+
+	u.Email = NormalizeEmail(u.Email)
+	u.Phone = phone
+
+	return nil
+}
+
+func (i *UpdateUserInput) Normalize() error {
+	if i.Email != nil {
+		email := NormalizeEmail(*i.Email)
+		i.Email = &email
+	}
+
+	if i.Phone != nil {
+		phone, err := NormalizePhone(*i.Phone)
+		if err != nil {
+			return err
+		}
+
+		i.Phone = &phone
+	}
+
+	return nil
+}
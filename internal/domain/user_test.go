@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeEmail(t *testing.T) {
+	assert.Equal(t, "test@gmail.com", NormalizeEmail("  Test@GMail.com "))
+}
+
+func TestNormalizePhone(t *testing.T) {
+	testTable := []struct {
+		name    string
+		phone   string
+		want    string
+		wantErr error
+	}{
+		{
+			name:  "Already Normalized",
+			phone: "+4412345678",
+			want:  "+4412345678",
+		},
+		{
+			name:  "Separators",
+			phone: " +44 (123) 456-78.90 ",
+			want:  "+441234567890",
+		},
+		{
+			name:  "International Prefix",
+			phone: "00380 67 123 45 67",
+			want:  "+380671234567",
+		},
+		{
+			name:    "Letters",
+			phone:   "+44 call me",
+			wantErr: ErrInvalidPhone,
+		},
+		{
+			name:    "Too Short",
+			phone:   "123",
+			wantErr: ErrInvalidPhone,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			got, err := NormalizePhone(testCase.phone)
+			if testCase.wantErr != nil {
+				assert.ErrorIs(t, err, testCase.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, testCase.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package domain
+
+import "time"
+
+const (
+	TokenPurposeEmailVerification = "email_verification"
+	TokenPurposePasswordReset     = "password_reset"
+)
+
+var (
+	ErrInvalidUserToken = NewError(ErrValidation, "invalid_token", "token is invalid or has expired")
+	ErrEmailNotVerified = NewError(ErrForbidden, "email_not_verified", "email is not verified")
+)
+
+// UserToken is a single-use token sent to the user by email.
+type UserToken struct {
+	Id        string
+	UserId    string
+	Purpose   string
+	TokenHash string
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+type VerifyEmailInput struct {
+	Token string `json:"token" binding:"required"`
+}
+
+type ForgotPasswordInput struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type ResetPasswordInput struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required,min=5"`
+}
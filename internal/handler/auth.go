@@ -1,12 +1,16 @@
 package handler
 
 import (
+	"errors"
+	"math"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/AndrewMislyuk/go-shop-backend/internal/domain"
 
 	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
 )
 
 type getCreationId struct {
@@ -14,7 +18,8 @@ type getCreationId struct {
 }
 
 type getUserToken struct {
-	Token string `json:"access_token"`
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
 }
 
 // @Summary SignUp
@@ -25,22 +30,22 @@ type getUserToken struct {
 // @Produce  json
 // @Param input body domain.UserSignUp true "User signup"
 // @Success 200 {object} getCreationId
-// @Failure 400,404 {object} errorResponse
+// @Failure 400,409 {object} errorResponse
 // @Failure 500 {object} errorResponse
 // @Failure default {object} errorResponse
 // @Router /auth/sign-up [post]
 func (h *Handler) signUp(c *gin.Context) {
 	var input domain.UserSignUp
 
-	if err := c.BindJSON(&input); err != nil {
-		newErrorResponse(c, http.StatusBadRequest, err.Error())
+	if err := c.ShouldBindJSON(&input); err != nil {
+		newErrorResponse(c, domain.NewValidationError(err))
 
 		return
 	}
 
 	id, err := h.userService.CreateUser(input)
 	if err != nil {
-		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		newErrorResponse(c, err)
 
 		return
 	}
@@ -52,34 +57,237 @@ func (h *Handler) signUp(c *gin.Context) {
 
 // @Summary SignIn
 // @Tags Auth
-// @Description user sign-in
+// @Description user sign-in, the guest cart of X-Cart-Token is merged into the cart of the user
 // @ID sign-in
 // @Accept  json
 // @Produce  json
 // @Param input body domain.UserSignIn true "User login"
+// @Param X-Cart-Token header string false "Guest cart token"
 // @Success 200 {object} getUserToken
-// @Failure 400,404 {object} errorResponse
+// @Failure 400,401,403 {object} errorResponse
+// @Failure 429 {object} errorResponse
 // @Failure 500 {object} errorResponse
 // @Failure default {object} errorResponse
 // @Router /auth/sign-in [post]
 func (h *Handler) signIn(c *gin.Context) {
 	var input domain.UserSignIn
 
-	if err := c.BindJSON(&input); err != nil {
-		newErrorResponse(c, http.StatusBadRequest, err.Error())
+	if err := c.ShouldBindJSON(&input); err != nil {
+		newErrorResponse(c, domain.NewValidationError(err))
+
+		return
+	}
+
+	tokens, err := h.userService.GenerateToken(input.Email, input.Password, c.ClientIP())
+	if err != nil {
+		var tooManyAttempts *domain.TooManyAttemptsError
+		if errors.As(err, &tooManyAttempts) {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(tooManyAttempts.RetryAfter.Seconds()))))
+		}
+
+		newErrorResponse(c, err)
+
+		return
+	}
+
+	// the sign-in succeeds even if the guest cart can't be merged
+	if cartToken := c.GetHeader(cartTokenHeader); cartToken != "" {
+		if err := h.cartService.Merge(tokens.UserId, cartToken); err != nil {
+			logrus.WithField("request_id", c.GetString(requestIdCtx)).Errorf("error occurred on merging guest cart: %s", err.Error())
+		}
+	}
+
+	c.JSON(http.StatusOK, getUserToken{
+		AccessToken:  tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+	})
+}
+
+// @Summary Refresh
+// @Tags Auth
+// @Description exchange a refresh token for a new token pair
+// @ID refresh
+// @Accept  json
+// @Produce  json
+// @Param input body domain.RefreshTokenInput true "Refresh token"
+// @Success 200 {object} getUserToken
+// @Failure 400,401,403 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /auth/refresh [post]
+func (h *Handler) refresh(c *gin.Context) {
+	var input domain.RefreshTokenInput
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		newErrorResponse(c, domain.NewValidationError(err))
 
 		return
 	}
 
-	token, err := h.userService.GenerateToken(input.Email, input.Password)
+	tokens, err := h.userService.RefreshTokens(input.RefreshToken)
 	if err != nil {
-		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		newErrorResponse(c, err)
 
 		return
 	}
 
 	c.JSON(http.StatusOK, getUserToken{
-		Token: token,
+		AccessToken:  tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+	})
+}
+
+// @Summary Logout
+// @Tags Auth
+// @Description revoke the session the refresh token belongs to
+// @ID logout
+// @Accept  json
+// @Produce  json
+// @Param input body domain.RefreshTokenInput true "Refresh token"
+// @Success 200 {object} statusResponse
+// @Failure 400,401 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /auth/logout [post]
+func (h *Handler) logout(c *gin.Context) {
+	var input domain.RefreshTokenInput
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		newErrorResponse(c, domain.NewValidationError(err))
+
+		return
+	}
+
+	if err := h.userService.Logout(input.RefreshToken); err != nil {
+		newErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{
+		Status: "ok",
+	})
+}
+
+// @Summary Logout All
+// @Security ApiKeyAuth
+// @Tags Auth
+// @Description revoke every session of the current user
+// @ID logout-all
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} statusResponse
+// @Failure 400,401 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /auth/logout-all [post]
+func (h *Handler) logoutAll(c *gin.Context) {
+	userId := c.GetString(userCtx)
+
+	if err := h.userService.LogoutAll(userId); err != nil {
+		newErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{
+		Status: "ok",
+	})
+}
+
+// @Summary Verify Email
+// @Tags Auth
+// @Description confirm the email with the token sent after sign-up
+// @ID verify-email
+// @Accept  json
+// @Produce  json
+// @Param input body domain.VerifyEmailInput true "Token from the email"
+// @Success 200 {object} statusResponse
+// @Failure 400 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /auth/verify-email [post]
+func (h *Handler) verifyEmail(c *gin.Context) {
+	var input domain.VerifyEmailInput
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		newErrorResponse(c, domain.NewValidationError(err))
+
+		return
+	}
+
+	if err := h.userService.VerifyEmail(input.Token); err != nil {
+		newErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{
+		Status: "ok",
+	})
+}
+
+// @Summary Forgot Password
+// @Tags Auth
+// @Description send a password reset link, the response is the same whether the account exists or not
+// @ID forgot-password
+// @Accept  json
+// @Produce  json
+// @Param input body domain.ForgotPasswordInput true "Account email"
+// @Success 200 {object} statusResponse
+// @Failure 400 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /auth/forgot-password [post]
+func (h *Handler) forgotPassword(c *gin.Context) {
+	var input domain.ForgotPasswordInput
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		newErrorResponse(c, domain.NewValidationError(err))
+
+		return
+	}
+
+	if err := h.userService.ForgotPassword(input.Email); err != nil {
+		newErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{
+		Status: "ok",
+	})
+}
+
+// @Summary Reset Password
+// @Tags Auth
+// @Description set a new password with the token from the reset email, all sessions are revoked
+// @ID reset-password
+// @Accept  json
+// @Produce  json
+// @Param input body domain.ResetPasswordInput true "Token and new password"
+// @Success 200 {object} statusResponse
+// @Failure 400 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /auth/reset-password [post]
+func (h *Handler) resetPassword(c *gin.Context) {
+	var input domain.ResetPasswordInput
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		newErrorResponse(c, domain.NewValidationError(err))
+
+		return
+	}
+
+	if err := h.userService.ResetPassword(input.Token, input.NewPassword); err != nil {
+		newErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{
+		Status: "ok",
 	})
 }
 
@@ -102,10 +310,22 @@ func (h *Handler) getMe(c *gin.Context) {
 
 	user, err := h.userService.GetMe(headerParts[1])
 	if err != nil {
-		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		newErrorResponse(c, err)
 
 		return
 	}
 
 	c.JSON(http.StatusOK, user)
 }
+
+// @Summary JWKS
+// @Tags Auth
+// @Description public keys to verify access tokens issued by this service
+// @ID jwks
+// @Produce  json
+// @Success 200 {object} auth.JSONWebKeySet
+// @Router /.well-known/jwks.json [get]
+func (h *Handler) getJWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, h.userService.JWKS())
+}
@@ -3,12 +3,15 @@ package handler
 import (
 	"bytes"
 	"errors"
+	"fmt"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/AndrewMislyuk/go-shop-backend/internal/domain"
 	"github.com/AndrewMislyuk/go-shop-backend/internal/service"
 	mock_service "github.com/AndrewMislyuk/go-shop-backend/internal/service/mock"
+	"github.com/AndrewMislyuk/go-shop-backend/pkg/auth"
 	"github.com/gin-gonic/gin"
 	"github.com/golang/mock/gomock"
 	"github.com/magiconair/properties/assert"
@@ -27,14 +30,13 @@ func TestHandler_signUp(t *testing.T) {
 	}{
 		{
 			name:      "OK",
-			inputBody: `{"name":"Test_Name","surname":"Test_Surname","email":"test@gmail.com","phone":"+4456780123","password":"1234QWER@","role":"ADMIN"}`,
+			inputBody: `{"name":"Test_Name","surname":"Test_Surname","email":"test@gmail.com","phone":"+4456780123","password":"1234QWER@"}`,
 			inputUser: domain.UserSignUp{
 				Name:     "Test_Name",
 				Surname:  "Test_Surname",
 				Email:    "test@gmail.com",
 				Phone:    "+4456780123",
 				Password: "1234QWER@",
-				Role:     "ADMIN",
 			},
 			mockBehavior: func(s *mock_service.MockUser, user domain.UserSignUp) {
 				s.EXPECT().CreateUser(user).Return("34c8d3e6-b8d7-43dc-847e-5764c4114856", nil)
@@ -48,25 +50,58 @@ func TestHandler_signUp(t *testing.T) {
 			inputBody:           `{}`,
 			mockBehavior:        func(s *mock_service.MockUser, user domain.UserSignUp) {},
 			expectedStatusCode:  400,
-			expectedRequestBody: `{"message":"Key: 'UserSignUp.Name' Error:Field validation for 'Name' failed on the 'required' tag\nKey: 'UserSignUp.Surname' Error:Field validation for 'Surname' failed on the 'required' tag\nKey: 'UserSignUp.Email' Error:Field validation for 'Email' failed on the 'required' tag\nKey: 'UserSignUp.Phone' Error:Field validation for 'Phone' failed on the 'required' tag\nKey: 'UserSignUp.Password' Error:Field validation for 'Password' failed on the 'required' tag\nKey: 'UserSignUp.Role' Error:Field validation for 'Role' failed on the 'required' tag"}`,
+			expectedRequestBody: `{"code":"invalid_input","message":"Key: 'UserSignUp.Name' Error:Field validation for 'Name' failed on the 'required' tag\nKey: 'UserSignUp.Surname' Error:Field validation for 'Surname' failed on the 'required' tag\nKey: 'UserSignUp.Email' Error:Field validation for 'Email' failed on the 'required' tag\nKey: 'UserSignUp.Phone' Error:Field validation for 'Phone' failed on the 'required' tag\nKey: 'UserSignUp.Password' Error:Field validation for 'Password' failed on the 'required' tag"}`,
 		},
 
 		{
 			name:      "Service Failure",
-			inputBody: `{"name":"Test_Name","surname":"Test_Surname","email":"test@gmail.com","phone":"+4456780123","password":"1234QWER@","role":"ADMIN"}`,
+			inputBody: `{"name":"Test_Name","surname":"Test_Surname","email":"test@gmail.com","phone":"+4456780123","password":"1234QWER@"}`,
 			inputUser: domain.UserSignUp{
 				Name:     "Test_Name",
 				Surname:  "Test_Surname",
 				Email:    "test@gmail.com",
 				Phone:    "+4456780123",
 				Password: "1234QWER@",
-				Role:     "ADMIN",
 			},
 			mockBehavior: func(s *mock_service.MockUser, user domain.UserSignUp) {
 				s.EXPECT().CreateUser(user).Return("", errors.New("service failure"))
 			},
 			expectedStatusCode:  500,
-			expectedRequestBody: `{"message":"service failure"}`,
+			expectedRequestBody: `{"code":"internal_error","message":"internal server error"}`,
+		},
+
+		{
+			name:      "Email Taken",
+			inputBody: `{"name":"Test_Name","surname":"Test_Surname","email":"Test@Gmail.com","phone":"+4456780123","password":"1234QWER@"}`,
+			inputUser: domain.UserSignUp{
+				Name:     "Test_Name",
+				Surname:  "Test_Surname",
+				Email:    "Test@Gmail.com",
+				Phone:    "+4456780123",
+				Password: "1234QWER@",
+			},
+			mockBehavior: func(s *mock_service.MockUser, user domain.UserSignUp) {
+				s.EXPECT().CreateUser(user).Return("", domain.ErrEmailTaken)
+			},
+			expectedStatusCode:  409,
+			expectedRequestBody: `{"code":"email_taken","message":"email is already taken"}`,
+		},
+
+		{
+			name:      "Invalid Phone",
+			inputBody: `{"name":"Test_Name","surname":"Test_Surname","email":"test@gmail.com","phone":"call me","password":"1234QWER@"}`,
+			inputUser: domain.UserSignUp{
+				Name:     "Test_Name",
+				Surname:  "Test_Surname",
+				Email:    "test@gmail.com",
+				Phone:    "call me",
+				Password: "1234QWER@",
+			},
+			mockBehavior: func(s *mock_service.MockUser, user domain.UserSignUp) {
+				s.EXPECT().CreateUser(user).Return("", domain.ErrInvalidPhone)
+			},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"code":"invalid_phone","message":"invalid phone number"}`,
 		},
 	}
 
@@ -84,6 +119,7 @@ func TestHandler_signUp(t *testing.T) {
 
 			// Test Server
 			r := gin.New()
+			r.Use(handler.handleErrors)
 			r.POST("/sign-up", handler.signUp)
 
 			// Test Request
@@ -110,6 +146,7 @@ func TestHandler_signIn(t *testing.T) {
 		mockBehavior        mockBehavior
 		expectedStatusCode  int
 		expectedRequestBody string
+		expectedRetryAfter  string
 	}{
 		{
 			name:      "OK",
@@ -119,10 +156,10 @@ func TestHandler_signIn(t *testing.T) {
 				Password: "1234QWER@",
 			},
 			mockBehavior: func(s *mock_service.MockUser, user domain.UserSignIn) {
-				s.EXPECT().GenerateToken(user.Email, user.Password).Return("token", nil)
+				s.EXPECT().GenerateToken(user.Email, user.Password, "192.0.2.1").Return(domain.Tokens{AccessToken: "token", RefreshToken: "refresh"}, nil)
 			},
 			expectedStatusCode:  200,
-			expectedRequestBody: `{"access_token":"token"}`,
+			expectedRequestBody: `{"access_token":"token","refresh_token":"refresh"}`,
 		},
 
 		{
@@ -130,7 +167,7 @@ func TestHandler_signIn(t *testing.T) {
 			inputBody:           `{}`,
 			mockBehavior:        func(s *mock_service.MockUser, user domain.UserSignIn) {},
 			expectedStatusCode:  400,
-			expectedRequestBody: `{"message":"Key: 'UserSignIn.Email' Error:Field validation for 'Email' failed on the 'required' tag\nKey: 'UserSignIn.Password' Error:Field validation for 'Password' failed on the 'required' tag"}`,
+			expectedRequestBody: `{"code":"invalid_input","message":"Key: 'UserSignIn.Email' Error:Field validation for 'Email' failed on the 'required' tag\nKey: 'UserSignIn.Password' Error:Field validation for 'Password' failed on the 'required' tag"}`,
 		},
 
 		{
@@ -141,10 +178,53 @@ func TestHandler_signIn(t *testing.T) {
 				Password: "1234QWER@",
 			},
 			mockBehavior: func(s *mock_service.MockUser, user domain.UserSignIn) {
-				s.EXPECT().GenerateToken(user.Email, user.Password).Return("", errors.New("service failure"))
+				s.EXPECT().GenerateToken(user.Email, user.Password, "192.0.2.1").Return(domain.Tokens{}, errors.New("service failure"))
 			},
 			expectedStatusCode:  500,
-			expectedRequestBody: `{"message":"service failure"}`,
+			expectedRequestBody: `{"code":"internal_error","message":"internal server error"}`,
+		},
+
+		{
+			name:      "Invalid Credentials",
+			inputBody: `{"email":"test@gmail.com","password":"wrong"}`,
+			inputUser: domain.UserSignIn{
+				Email:    "test@gmail.com",
+				Password: "wrong",
+			},
+			mockBehavior: func(s *mock_service.MockUser, user domain.UserSignIn) {
+				s.EXPECT().GenerateToken(user.Email, user.Password, "192.0.2.1").Return(domain.Tokens{}, domain.ErrInvalidCredentials)
+			},
+			expectedStatusCode:  401,
+			expectedRequestBody: `{"code":"invalid_credentials","message":"invalid email or password"}`,
+		},
+
+		{
+			name:      "Email Not Verified",
+			inputBody: `{"email":"test@gmail.com","password":"qwerty"}`,
+			inputUser: domain.UserSignIn{
+				Email:    "test@gmail.com",
+				Password: "qwerty",
+			},
+			mockBehavior: func(s *mock_service.MockUser, user domain.UserSignIn) {
+				s.EXPECT().GenerateToken(user.Email, user.Password, "192.0.2.1").Return(domain.Tokens{}, domain.ErrEmailNotVerified)
+			},
+			expectedStatusCode:  403,
+			expectedRequestBody: `{"code":"email_not_verified","message":"email is not verified"}`,
+		},
+
+		{
+			name:      "Too Many Attempts",
+			inputBody: `{"email":"test@gmail.com","password":"qwerty"}`,
+			inputUser: domain.UserSignIn{
+				Email:    "test@gmail.com",
+				Password: "qwerty",
+			},
+			mockBehavior: func(s *mock_service.MockUser, user domain.UserSignIn) {
+				s.EXPECT().GenerateToken(user.Email, user.Password, "192.0.2.1").Return(domain.Tokens{}, &domain.TooManyAttemptsError{RetryAfter: 1500 * time.Millisecond})
+			},
+			expectedStatusCode:  429,
+			expectedRequestBody: `{"code":"too_many_attempts","message":"too many failed sign-in attempts, retry in 2s"}`,
+			expectedRetryAfter:  "2",
 		},
 	}
 
@@ -162,6 +242,7 @@ func TestHandler_signIn(t *testing.T) {
 
 			// Test Server
 			r := gin.New()
+			r.Use(handler.handleErrors)
 			r.POST("/sign-in", handler.signIn)
 
 			// Test Request
@@ -171,6 +252,493 @@ func TestHandler_signIn(t *testing.T) {
 			// Perform Request
 			r.ServeHTTP(w, req)
 
+			// Assert
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedRequestBody, w.Body.String())
+			assert.Equal(t, testCase.expectedRetryAfter, w.Header().Get("Retry-After"))
+		})
+	}
+}
+
+func TestHandler_signInClientIP(t *testing.T) {
+	testTable := []struct {
+		name           string
+		trustedProxies []string
+		expectedIP     string
+	}{
+		{
+			name:       "Spoofed X-Forwarded-For",
+			expectedIP: "192.0.2.1",
+		},
+
+		{
+			name:           "Trusted Proxy",
+			trustedProxies: []string{"192.0.2.0/24"},
+			expectedIP:     "203.0.113.7",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			auth := mock_service.NewMockUser(c)
+			auth.EXPECT().GenerateToken("test@gmail.com", "1234QWER@", testCase.expectedIP).
+				Return(domain.Tokens{AccessToken: "token", RefreshToken: "refresh"}, nil)
+
+			services := &service.Service{User: auth}
+			handler := NewHandler(services)
+
+			// Test Server
+			r, err := handler.InitRouter(testCase.trustedProxies)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/auth/sign-in", bytes.NewBufferString(`{"email":"test@gmail.com","password":"1234QWER@"}`))
+			req.Header.Set("X-Forwarded-For", "203.0.113.7")
+
+			// Perform Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, 200, w.Code)
+		})
+	}
+}
+
+func TestHandler_refresh(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockUser, refreshToken string)
+
+	testTable := []struct {
+		name                string
+		inputBody           string
+		refreshToken        string
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name:         "OK",
+			inputBody:    `{"refresh_token":"refresh"}`,
+			refreshToken: "refresh",
+			mockBehavior: func(s *mock_service.MockUser, refreshToken string) {
+				s.EXPECT().RefreshTokens(refreshToken).Return(domain.Tokens{AccessToken: "new_token", RefreshToken: "new_refresh"}, nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"access_token":"new_token","refresh_token":"new_refresh"}`,
+		},
+
+		{
+			name:                "Empty Fields",
+			inputBody:           `{}`,
+			mockBehavior:        func(s *mock_service.MockUser, refreshToken string) {},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"code":"invalid_input","message":"Key: 'RefreshTokenInput.RefreshToken' Error:Field validation for 'RefreshToken' failed on the 'required' tag"}`,
+		},
+
+		{
+			name:         "Reused Token",
+			inputBody:    `{"refresh_token":"refresh"}`,
+			refreshToken: "refresh",
+			mockBehavior: func(s *mock_service.MockUser, refreshToken string) {
+				s.EXPECT().RefreshTokens(refreshToken).Return(domain.Tokens{}, domain.ErrRefreshTokenReused)
+			},
+			expectedStatusCode:  401,
+			expectedRequestBody: `{"code":"refresh_token_reused","message":"refresh token has already been used"}`,
+		},
+
+		{
+			name:         "Service Failure",
+			inputBody:    `{"refresh_token":"refresh"}`,
+			refreshToken: "refresh",
+			mockBehavior: func(s *mock_service.MockUser, refreshToken string) {
+				s.EXPECT().RefreshTokens(refreshToken).Return(domain.Tokens{}, errors.New("service failure"))
+			},
+			expectedStatusCode:  500,
+			expectedRequestBody: `{"code":"internal_error","message":"internal server error"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			auth := mock_service.NewMockUser(c)
+			testCase.mockBehavior(auth, testCase.refreshToken)
+
+			services := &service.Service{User: auth}
+			handler := NewHandler(services)
+
+			// Test Server
+			r := gin.New()
+			r.Use(handler.handleErrors)
+			r.POST("/refresh", handler.refresh)
+
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/refresh", bytes.NewBufferString(testCase.inputBody))
+
+			// Perform Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedRequestBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_logout(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockUser, refreshToken string)
+
+	testTable := []struct {
+		name                string
+		inputBody           string
+		refreshToken        string
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name:         "OK",
+			inputBody:    `{"refresh_token":"refresh"}`,
+			refreshToken: "refresh",
+			mockBehavior: func(s *mock_service.MockUser, refreshToken string) {
+				s.EXPECT().Logout(refreshToken).Return(nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"status":"ok"}`,
+		},
+
+		{
+			name:         "Unknown Token",
+			inputBody:    `{"refresh_token":"refresh"}`,
+			refreshToken: "refresh",
+			mockBehavior: func(s *mock_service.MockUser, refreshToken string) {
+				s.EXPECT().Logout(refreshToken).Return(domain.ErrInvalidRefreshToken)
+			},
+			expectedStatusCode:  401,
+			expectedRequestBody: `{"code":"invalid_refresh_token","message":"invalid refresh token"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			auth := mock_service.NewMockUser(c)
+			testCase.mockBehavior(auth, testCase.refreshToken)
+
+			services := &service.Service{User: auth}
+			handler := NewHandler(services)
+
+			// Test Server
+			r := gin.New()
+			r.Use(handler.handleErrors)
+			r.POST("/logout", handler.logout)
+
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/logout", bytes.NewBufferString(testCase.inputBody))
+
+			// Perform Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedRequestBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_logoutAll(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockUser, userId string)
+
+	testTable := []struct {
+		name                string
+		userId              string
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name:   "OK",
+			userId: "34c8d3e6-b8d7-43dc-847e-5764c4114856",
+			mockBehavior: func(s *mock_service.MockUser, userId string) {
+				s.EXPECT().LogoutAll(userId).Return(nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"status":"ok"}`,
+		},
+
+		{
+			name:   "Service Failure",
+			userId: "34c8d3e6-b8d7-43dc-847e-5764c4114856",
+			mockBehavior: func(s *mock_service.MockUser, userId string) {
+				s.EXPECT().LogoutAll(userId).Return(errors.New("service failure"))
+			},
+			expectedStatusCode:  500,
+			expectedRequestBody: `{"code":"internal_error","message":"internal server error"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			auth := mock_service.NewMockUser(c)
+			testCase.mockBehavior(auth, testCase.userId)
+
+			services := &service.Service{User: auth}
+			handler := NewHandler(services)
+
+			// Test Server
+			r := gin.New()
+			r.Use(handler.handleErrors)
+			r.POST("/logout-all", func(c *gin.Context) {
+				c.Set(userCtx, testCase.userId)
+			}, handler.logoutAll)
+
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/logout-all", nil)
+
+			// Perform Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedRequestBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_verifyEmail(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockUser, token string)
+
+	testTable := []struct {
+		name                string
+		inputBody           string
+		token               string
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name:      "OK",
+			inputBody: `{"token":"token"}`,
+			token:     "token",
+			mockBehavior: func(s *mock_service.MockUser, token string) {
+				s.EXPECT().VerifyEmail(token).Return(nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"status":"ok"}`,
+		},
+
+		{
+			name:                "Empty Token",
+			inputBody:           `{}`,
+			mockBehavior:        func(s *mock_service.MockUser, token string) {},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"code":"invalid_input","message":"Key: 'VerifyEmailInput.Token' Error:Field validation for 'Token' failed on the 'required' tag"}`,
+		},
+
+		{
+			name:      "Used Token",
+			inputBody: `{"token":"token"}`,
+			token:     "token",
+			mockBehavior: func(s *mock_service.MockUser, token string) {
+				s.EXPECT().VerifyEmail(token).Return(domain.ErrInvalidUserToken)
+			},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"code":"invalid_token","message":"token is invalid or has expired"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			auth := mock_service.NewMockUser(c)
+			testCase.mockBehavior(auth, testCase.token)
+
+			services := &service.Service{User: auth}
+			handler := NewHandler(services)
+
+			// Test Server
+			r := gin.New()
+			r.Use(handler.handleErrors)
+			r.POST("/verify-email", handler.verifyEmail)
+
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/verify-email", bytes.NewBufferString(testCase.inputBody))
+
+			// Perform Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedRequestBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_forgotPassword(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockUser, email string)
+
+	testTable := []struct {
+		name                string
+		inputBody           string
+		email               string
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name:      "OK",
+			inputBody: `{"email":"test@gmail.com"}`,
+			email:     "test@gmail.com",
+			mockBehavior: func(s *mock_service.MockUser, email string) {
+				s.EXPECT().ForgotPassword(email).Return(nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"status":"ok"}`,
+		},
+
+		{
+			name:                "Invalid Email",
+			inputBody:           `{"email":"test"}`,
+			mockBehavior:        func(s *mock_service.MockUser, email string) {},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"code":"invalid_input","message":"Key: 'ForgotPasswordInput.Email' Error:Field validation for 'Email' failed on the 'email' tag"}`,
+		},
+
+		{
+			name:      "Service Failure",
+			inputBody: `{"email":"test@gmail.com"}`,
+			email:     "test@gmail.com",
+			mockBehavior: func(s *mock_service.MockUser, email string) {
+				s.EXPECT().ForgotPassword(email).Return(errors.New("service failure"))
+			},
+			expectedStatusCode:  500,
+			expectedRequestBody: `{"code":"internal_error","message":"internal server error"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			auth := mock_service.NewMockUser(c)
+			testCase.mockBehavior(auth, testCase.email)
+
+			services := &service.Service{User: auth}
+			handler := NewHandler(services)
+
+			// Test Server
+			r := gin.New()
+			r.Use(handler.handleErrors)
+			r.POST("/forgot-password", handler.forgotPassword)
+
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/forgot-password", bytes.NewBufferString(testCase.inputBody))
+
+			// Perform Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedRequestBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_resetPassword(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockUser, token, password string)
+
+	testTable := []struct {
+		name                string
+		inputBody           string
+		token               string
+		password            string
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name:      "OK",
+			inputBody: `{"token":"token","new_password":"1234QWER@"}`,
+			token:     "token",
+			password:  "1234QWER@",
+			mockBehavior: func(s *mock_service.MockUser, token, password string) {
+				s.EXPECT().ResetPassword(token, password).Return(nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"status":"ok"}`,
+		},
+
+		{
+			name:                "Short Password",
+			inputBody:           `{"token":"token","new_password":"1234"}`,
+			mockBehavior:        func(s *mock_service.MockUser, token, password string) {},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"code":"invalid_input","message":"Key: 'ResetPasswordInput.NewPassword' Error:Field validation for 'NewPassword' failed on the 'min' tag"}`,
+		},
+
+		{
+			name:      "Expired Token",
+			inputBody: `{"token":"token","new_password":"1234QWER@"}`,
+			token:     "token",
+			password:  "1234QWER@",
+			mockBehavior: func(s *mock_service.MockUser, token, password string) {
+				s.EXPECT().ResetPassword(token, password).Return(domain.ErrInvalidUserToken)
+			},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"code":"invalid_token","message":"token is invalid or has expired"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			auth := mock_service.NewMockUser(c)
+			testCase.mockBehavior(auth, testCase.token, testCase.password)
+
+			services := &service.Service{User: auth}
+			handler := NewHandler(services)
+
+			// Test Server
+			r := gin.New()
+			r.Use(handler.handleErrors)
+			r.POST("/reset-password", handler.resetPassword)
+
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/reset-password", bytes.NewBufferString(testCase.inputBody))
+
+			// Perform Request
+			r.ServeHTTP(w, req)
+
 			// Assert
 			assert.Equal(t, testCase.expectedStatusCode, w.Code)
 			assert.Equal(t, testCase.expectedRequestBody, w.Body.String())
@@ -195,18 +763,19 @@ func TestHandler_getMe(t *testing.T) {
 			token:       "token",
 			mockBehavior: func(s *mock_service.MockUser, token string) {
 				s.EXPECT().GetMe(token).Return(domain.User{
-					Id:       "34c8d3e6-b8d7-43dc-847e-5764c4114856",
-					Name:     "Test_Name",
-					Surname:  "Test_Surname",
-					Email:    "test@gmail.com",
-					Phone:    "+4456781234",
-					Role:     "ADMIN",
-					Password: "1234QWER@",
+					Id:          "34c8d3e6-b8d7-43dc-847e-5764c4114856",
+					Name:        "Test_Name",
+					Surname:     "Test_Surname",
+					Email:       "test@gmail.com",
+					Phone:       "+4456781234",
+					Roles:       []string{"ADMIN"},
+					Permissions: []string{"products:write"},
+					Password:    "1234QWER@",
 					// CreatedAt: time.Now(),
 				}, nil)
 			},
 			expectedStatusCode:  200,
-			expectedRequestBody: `{"id":"34c8d3e6-b8d7-43dc-847e-5764c4114856","name":"Test_Name","surname":"Test_Surname","email":"test@gmail.com","phone":"+4456781234","role":"ADMIN","password":"1234QWER@","created_at":"0001-01-01T00:00:00Z"}`,
+			expectedRequestBody: `{"id":"34c8d3e6-b8d7-43dc-847e-5764c4114856","name":"Test_Name","surname":"Test_Surname","email":"test@gmail.com","phone":"+4456781234","roles":["ADMIN"],"permissions":["products:write"],"created_at":"0001-01-01T00:00:00Z"}`,
 		},
 
 		{
@@ -217,7 +786,18 @@ func TestHandler_getMe(t *testing.T) {
 				s.EXPECT().GetMe(token).Return(domain.User{}, errors.New("service failure"))
 			},
 			expectedStatusCode:  500,
-			expectedRequestBody: `{"message":"service failure"}`,
+			expectedRequestBody: `{"code":"internal_error","message":"internal server error"}`,
+		},
+
+		{
+			name:        "Deleted User",
+			headerValue: "Bearer token",
+			token:       "token",
+			mockBehavior: func(s *mock_service.MockUser, token string) {
+				s.EXPECT().GetMe(token).Return(domain.User{}, fmt.Errorf("%w: user not found", domain.ErrInvalidAccessToken))
+			},
+			expectedStatusCode:  401,
+			expectedRequestBody: `{"code":"invalid_access_token","message":"invalid access token: user not found"}`,
 		},
 	}
 
@@ -235,6 +815,7 @@ func TestHandler_getMe(t *testing.T) {
 
 			// Test Server
 			r := gin.New()
+			r.Use(handler.handleErrors)
 			r.GET("/get-me", handler.getMe)
 
 			// Test Request
@@ -251,3 +832,31 @@ func TestHandler_getMe(t *testing.T) {
 		})
 	}
 }
+
+func TestHandler_getJWKS(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	user := mock_service.NewMockUser(c)
+	user.EXPECT().JWKS().Return(auth.JSONWebKeySet{
+		Keys: []auth.JSONWebKey{
+			{KeyType: "OKP", KeyId: "2022-07-eddsa", Use: "sig", Algorithm: "EdDSA", Curve: "Ed25519", X: "11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"},
+		},
+	})
+
+	services := &service.Service{User: user}
+	handler := NewHandler(services)
+
+	r := gin.New()
+	r.Use(handler.handleErrors)
+	r.GET("/.well-known/jwks.json", handler.getJWKS)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/.well-known/jwks.json", nil)
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, "public, max-age=300", w.Header().Get("Cache-Control"))
+	assert.Equal(t, `{"keys":[{"kty":"OKP","kid":"2022-07-eddsa","use":"sig","alg":"EdDSA","crv":"Ed25519","x":"11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"}]}`, w.Body.String())
+}
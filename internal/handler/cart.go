@@ -0,0 +1,131 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/AndrewMislyuk/go-shop-backend/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Get Cart
+// @Tags Cart
+// @Description get the cart of the user, or of the guest holding the cart token, with current prices
+// @ID get-cart
+// @Accept  json
+// @Produce  json
+// @Param X-Cart-Token header string false "Guest cart token"
+// @Success 200 {object} domain.Cart
+// @Failure 401 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/cart/ [get]
+func (h *Handler) getCart(c *gin.Context) {
+	cart, err := h.cartService.Get(cartOwner(c))
+	if err != nil {
+		newErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, cart)
+}
+
+// @Summary Add Cart Item
+// @Tags Cart
+// @Description add a product to the cart, a guest cart is created on first use and its token returned in X-Cart-Token
+// @ID add-cart-item
+// @Accept  json
+// @Produce  json
+// @Param X-Cart-Token header string false "Guest cart token"
+// @Param input body domain.AddCartItemInput true "Item info"
+// @Success 200 {object} domain.Cart
+// @Failure 400,401,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/cart/items [post]
+func (h *Handler) addCartItem(c *gin.Context) {
+	var input domain.AddCartItemInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		newErrorResponse(c, domain.NewValidationError(err))
+
+		return
+	}
+
+	cart, err := h.cartService.AddItem(cartOwner(c), input)
+	if err != nil {
+		newErrorResponse(c, err)
+
+		return
+	}
+
+	if cart.Token != "" {
+		c.Header(cartTokenHeader, cart.Token)
+	}
+
+	c.JSON(http.StatusOK, cart)
+}
+
+// @Summary Update Cart Item
+// @Tags Cart
+// @Description change the quantity of the cart item
+// @ID update-cart-item
+// @Accept  json
+// @Produce  json
+// @Param X-Cart-Token header string false "Guest cart token"
+// @Param id path string true "Cart item ID"
+// @Param input body domain.UpdateCartItemInput true "Item info"
+// @Success 200 {object} domain.Cart
+// @Failure 400,401,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/cart/items/{id} [put]
+func (h *Handler) updateCartItem(c *gin.Context) {
+	var input domain.UpdateCartItemInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		newErrorResponse(c, domain.NewValidationError(err))
+
+		return
+	}
+
+	cart, err := h.cartService.UpdateItem(cartOwner(c), c.Param("id"), input)
+	if err != nil {
+		newErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, cart)
+}
+
+// @Summary Remove Cart Item
+// @Tags Cart
+// @Description remove the item from the cart
+// @ID remove-cart-item
+// @Accept  json
+// @Produce  json
+// @Param X-Cart-Token header string false "Guest cart token"
+// @Param id path string true "Cart item ID"
+// @Success 200 {object} domain.Cart
+// @Failure 401,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/cart/items/{id} [delete]
+func (h *Handler) removeCartItem(c *gin.Context) {
+	cart, err := h.cartService.RemoveItem(cartOwner(c), c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, cart)
+}
+
+// cartOwner prefers the signed-in user over the guest cart token.
+func cartOwner(c *gin.Context) domain.CartOwner {
+	if userId := c.GetString(userCtx); userId != "" {
+		return domain.CartOwner{UserId: userId}
+	}
+
+	return domain.CartOwner{Token: c.GetHeader(cartTokenHeader)}
+}
@@ -0,0 +1,338 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/AndrewMislyuk/go-shop-backend/internal/domain"
+	"github.com/AndrewMislyuk/go-shop-backend/internal/repository"
+	"github.com/AndrewMislyuk/go-shop-backend/internal/service"
+	mock_service "github.com/AndrewMislyuk/go-shop-backend/internal/service/mock"
+	"github.com/AndrewMislyuk/go-shop-backend/pkg/auth"
+	"github.com/AndrewMislyuk/go-shop-backend/pkg/hash"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/magiconair/properties/assert"
+)
+
+func TestHandler_addCartItem(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockCarts, owner domain.CartOwner, input domain.AddCartItemInput)
+
+	testTable := []struct {
+		name                string
+		userId              string
+		cartToken           string
+		inputBody           string
+		input               domain.AddCartItemInput
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+		expectedCartToken   string
+	}{
+		{
+			name:      "New Guest Cart",
+			inputBody: `{"product_id":"453b4f0f-1f56-4c57-b43d-7b79792450a7","quantity":2}`,
+			input: domain.AddCartItemInput{
+				ProductId: "453b4f0f-1f56-4c57-b43d-7b79792450a7",
+				Quantity:  2,
+			},
+			mockBehavior: func(s *mock_service.MockCarts, owner domain.CartOwner, input domain.AddCartItemInput) {
+				s.EXPECT().AddItem(owner, input).Return(domain.Cart{
+					Id:    "8f1e2d3c-4b5a-4697-8877-665544332211",
+					Token: "guest-token",
+					Items: []domain.CartItem{
+						{Id: "1a2b3c4d-5e6f-4789-8abc-def012345678", ProductId: "453b4f0f-1f56-4c57-b43d-7b79792450a7", Title: "Твидовый кардиган из хлопка", Quantity: 2, UnitPrice: domain.NewMoney(749000, "RUB"), SeenPrice: domain.NewMoney(749000, "RUB"), LineTotal: domain.NewMoney(1498000, "RUB"), Available: 5},
+					},
+					ItemsCount: 2,
+					Total:      domain.NewMoney(1498000, "RUB"),
+				}, nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"id":"8f1e2d3c-4b5a-4697-8877-665544332211","token":"guest-token","items":[{"id":"1a2b3c4d-5e6f-4789-8abc-def012345678","product_id":"453b4f0f-1f56-4c57-b43d-7b79792450a7","variant_id":null,"title":"Твидовый кардиган из хлопка","image":"","quantity":2,"unit_price":{"amount":749000,"currency":"RUB"},"sale":0,"seen_price":{"amount":749000,"currency":"RUB"},"price_changed":false,"line_total":{"amount":1498000,"currency":"RUB"},"available":5,"created_at":"0001-01-01T00:00:00Z"}],"items_count":2,"total":{"amount":1498000,"currency":"RUB"},"price_changed":false,"created_at":"0001-01-01T00:00:00Z","updated_at":"0001-01-01T00:00:00Z"}`,
+			expectedCartToken:   "guest-token",
+		},
+
+		{
+			name:      "Signed In User Wins Over Token",
+			userId:    "e7a9c2d4-5f3b-4a61-8c0e-2b9d7f1a3e55",
+			cartToken: "guest-token",
+			inputBody: `{"product_id":"453b4f0f-1f56-4c57-b43d-7b79792450a7","quantity":1}`,
+			input: domain.AddCartItemInput{
+				ProductId: "453b4f0f-1f56-4c57-b43d-7b79792450a7",
+				Quantity:  1,
+			},
+			mockBehavior: func(s *mock_service.MockCarts, owner domain.CartOwner, input domain.AddCartItemInput) {
+				s.EXPECT().AddItem(domain.CartOwner{UserId: "e7a9c2d4-5f3b-4a61-8c0e-2b9d7f1a3e55"}, input).Return(domain.Cart{}, domain.ErrVariantRequired)
+			},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"code":"variant_required","message":"product has variants, one of them must be chosen"}`,
+		},
+
+		{
+			name:                "Too Many",
+			inputBody:           `{"product_id":"453b4f0f-1f56-4c57-b43d-7b79792450a7","quantity":100}`,
+			mockBehavior:        func(s *mock_service.MockCarts, owner domain.CartOwner, input domain.AddCartItemInput) {},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"code":"invalid_input","message":"Key: 'AddCartItemInput.Quantity' Error:Field validation for 'Quantity' failed on the 'max' tag"}`,
+		},
+
+		{
+			name:                "Invalid Product Id",
+			inputBody:           `{"product_id":"453b4f0f","quantity":1}`,
+			mockBehavior:        func(s *mock_service.MockCarts, owner domain.CartOwner, input domain.AddCartItemInput) {},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"code":"invalid_input","message":"Key: 'AddCartItemInput.ProductId' Error:Field validation for 'ProductId' failed on the 'uuid' tag"}`,
+		},
+
+		{
+			name:                "Empty Variant Id",
+			inputBody:           `{"product_id":"453b4f0f-1f56-4c57-b43d-7b79792450a7","variant_id":"","quantity":1}`,
+			mockBehavior:        func(s *mock_service.MockCarts, owner domain.CartOwner, input domain.AddCartItemInput) {},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"code":"invalid_input","message":"Key: 'AddCartItemInput.VariantId' Error:Field validation for 'VariantId' failed on the 'uuid' tag"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			carts := mock_service.NewMockCarts(c)
+			testCase.mockBehavior(carts, domain.CartOwner{UserId: testCase.userId, Token: testCase.cartToken}, testCase.input)
+
+			services := &service.Service{Carts: carts}
+			handler := NewHandler(services)
+
+			// Test Server
+			r := gin.New()
+			r.Use(handler.handleErrors)
+			r.POST("/cart/items", func(c *gin.Context) {
+				if testCase.userId != "" {
+					c.Set(userCtx, testCase.userId)
+				}
+			}, handler.addCartItem)
+
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/cart/items", bytes.NewBufferString(testCase.inputBody))
+			if testCase.cartToken != "" {
+				req.Header.Set(cartTokenHeader, testCase.cartToken)
+			}
+
+			// Perform Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedRequestBody, w.Body.String())
+			assert.Equal(t, testCase.expectedCartToken, w.Header().Get(cartTokenHeader))
+		})
+	}
+}
+
+func TestHandler_updateCartItem(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockCarts, owner domain.CartOwner, itemId string, input domain.UpdateCartItemInput)
+
+	testTable := []struct {
+		name                string
+		inputBody           string
+		input               domain.UpdateCartItemInput
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name:      "OK",
+			inputBody: `{"quantity":3}`,
+			input:     domain.UpdateCartItemInput{Quantity: 3},
+			mockBehavior: func(s *mock_service.MockCarts, owner domain.CartOwner, itemId string, input domain.UpdateCartItemInput) {
+				s.EXPECT().UpdateItem(owner, itemId, input).Return(domain.Cart{Id: "8f1e2d3c-4b5a-4697-8877-665544332211", Items: []domain.CartItem{}, Total: domain.NewMoney(0, "RUB")}, nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"id":"8f1e2d3c-4b5a-4697-8877-665544332211","items":[],"items_count":0,"total":{"amount":0,"currency":"RUB"},"price_changed":false,"created_at":"0001-01-01T00:00:00Z","updated_at":"0001-01-01T00:00:00Z"}`,
+		},
+
+		{
+			name:      "Item Not Found",
+			inputBody: `{"quantity":3}`,
+			input:     domain.UpdateCartItemInput{Quantity: 3},
+			mockBehavior: func(s *mock_service.MockCarts, owner domain.CartOwner, itemId string, input domain.UpdateCartItemInput) {
+				s.EXPECT().UpdateItem(owner, itemId, input).Return(domain.Cart{}, domain.ErrCartItemNotFound)
+			},
+			expectedStatusCode:  404,
+			expectedRequestBody: `{"code":"cart_item_not_found","message":"cart item not found"}`,
+		},
+
+		{
+			name:      "Zero Quantity",
+			inputBody: `{"quantity":0}`,
+			mockBehavior: func(s *mock_service.MockCarts, owner domain.CartOwner, itemId string, input domain.UpdateCartItemInput) {
+			},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"code":"invalid_input","message":"Key: 'UpdateCartItemInput.Quantity' Error:Field validation for 'Quantity' failed on the 'required' tag"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			carts := mock_service.NewMockCarts(c)
+			testCase.mockBehavior(carts, domain.CartOwner{Token: "guest-token"}, "1a2b3c4d-5e6f-4789-8abc-def012345678", testCase.input)
+
+			services := &service.Service{Carts: carts}
+			handler := NewHandler(services)
+
+			// Test Server
+			r := gin.New()
+			r.Use(handler.handleErrors)
+			r.PUT("/cart/items/:id", handler.updateCartItem)
+
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("PUT", "/cart/items/1a2b3c4d-5e6f-4789-8abc-def012345678", bytes.NewBufferString(testCase.inputBody))
+			req.Header.Set(cartTokenHeader, "guest-token")
+
+			// Perform Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedRequestBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_signInMergesGuestCart(t *testing.T) {
+	testTable := []struct {
+		name         string
+		mergeErr     error
+		expectedCode int
+	}{
+		{
+			name:         "OK",
+			expectedCode: 200,
+		},
+
+		{
+			name:         "Merge Failure Doesn't Fail Sign In",
+			mergeErr:     errors.New("merge failure"),
+			expectedCode: 200,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			user := mock_service.NewMockUser(c)
+			user.EXPECT().GenerateToken("test@gmail.com", "1234QWER@", "192.0.2.1").
+				Return(domain.Tokens{UserId: "e7a9c2d4-5f3b-4a61-8c0e-2b9d7f1a3e55", AccessToken: "token", RefreshToken: "refresh"}, nil)
+
+			carts := mock_service.NewMockCarts(c)
+			carts.EXPECT().Merge("e7a9c2d4-5f3b-4a61-8c0e-2b9d7f1a3e55", "guest-token").Return(testCase.mergeErr)
+
+			services := &service.Service{User: user, Carts: carts}
+			handler := NewHandler(services)
+
+			// Test Server
+			r := gin.New()
+			r.Use(handler.handleErrors)
+			r.POST("/sign-in", handler.signIn)
+
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/sign-in", bytes.NewBufferString(`{"email":"test@gmail.com","password":"1234QWER@"}`))
+			req.Header.Set(cartTokenHeader, "guest-token")
+
+			// Perform Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, testCase.expectedCode, w.Code)
+			assert.Equal(t, `{"access_token":"token","refresh_token":"refresh"}`, w.Body.String())
+		})
+	}
+}
+
+// TestHandler_signInMergesGuestCartOfSignedInUser signs in through the auth
+// service, the id of the user comes from the session it creates.
+func TestHandler_signInMergesGuestCartOfSignedInUser(t *testing.T) {
+	userId := "e7a9c2d4-5f3b-4a61-8c0e-2b9d7f1a3e55"
+
+	// Init Deps
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	hasher := hash.NewArgon2idHasher(hash.Argon2Params{Memory: 1024, Iterations: 1, Threads: 1, SaltLength: 16, KeyLength: 32})
+	passwordHash, err := hasher.Hash("1234QWER@")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key, err := auth.NewHMACKey("hs256-1", []byte("0123456789abcdef0123456789abcdef"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tokenManager, err := auth.NewJWTManager("hs256-1", key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifiedAt := time.Now()
+	mock.ExpectQuery("SELECT u.id").
+		WithArgs("test@gmail.com").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "surname", "email", "phone", "password_hash", "created_at", "email_verified_at",
+			"blocked_at", "deleted_at", "roles", "permissions"}).
+			AddRow(userId, "Test", "User", "test@gmail.com", "", passwordHash, verifiedAt, verifiedAt, nil, nil, "{}", "{}"))
+	mock.ExpectExec("INSERT INTO refresh_tokens").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	carts := mock_service.NewMockCarts(c)
+	carts.EXPECT().Merge(userId, "guest-token").Return(nil)
+
+	services := service.NewService(service.Deps{
+		Repos:           repository.NewRepository(db),
+		Hasher:          hasher,
+		TokenManager:    tokenManager,
+		LoginAttempts:   repository.NewLoginAttemptsMemory(),
+		AccessTokenTTL:  15 * time.Minute,
+		RefreshTokenTTL: time.Hour,
+	})
+	services.Carts = carts
+	handler := NewHandler(services)
+
+	// Test Server
+	r := gin.New()
+	r.Use(handler.handleErrors)
+	r.POST("/sign-in", handler.signIn)
+
+	// Test Request
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("POST", "/sign-in", bytes.NewBufferString(`{"email":"test@gmail.com","password":"1234QWER@"}`))
+	req.Header.Set(cartTokenHeader, "guest-token")
+
+	// Perform Request
+	r.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, nil, mock.ExpectationsWereMet())
+}
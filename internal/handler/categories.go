@@ -0,0 +1,155 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/AndrewMislyuk/go-shop-backend/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+type getCategoriesTreeResponse struct {
+	Data []domain.CategoryNode `json:"data"`
+}
+
+type getCategoryResponse struct {
+	Data domain.Category `json:"data"`
+}
+
+// @Summary Get Categories Tree
+// @Tags Categories
+// @Description get the category → type → subtype navigation tree with product counts
+// @ID get-categories-tree
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} getCategoriesTreeResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/categories/tree [get]
+func (h *Handler) getCategoriesTree(c *gin.Context) {
+	tree, err := h.categoriesService.Tree()
+	if err != nil {
+		newErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, getCategoriesTreeResponse{
+		Data: tree,
+	})
+}
+
+// @Summary Get Category By ID
+// @Tags Categories
+// @Description get category by id
+// @ID get-category-by-id
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Category ID"
+// @Success 200 {object} getCategoryResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/categories/{id} [get]
+func (h *Handler) getCategoryById(c *gin.Context) {
+	category, err := h.categoriesService.GetById(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, getCategoryResponse{
+		Data: category,
+	})
+}
+
+// @Summary Create Category
+// @Security ApiKeyAuth
+// @Tags Categories
+// @Description create a category, or a type / subtype when parent_id is given
+// @ID create-category
+// @Accept  json
+// @Produce  json
+// @Param input body domain.CreateCategoryInput true "Category info"
+// @Success 200 {object} getCreationId
+// @Failure 400,401,403,404,409 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/categories/ [post]
+func (h *Handler) createCategory(c *gin.Context) {
+	var input domain.CreateCategoryInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		newErrorResponse(c, domain.NewValidationError(err))
+
+		return
+	}
+
+	id, err := h.categoriesService.Create(input)
+	if err != nil {
+		newErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, getCreationId{
+		Id: id,
+	})
+}
+
+// @Summary Update Category
+// @Security ApiKeyAuth
+// @Tags Categories
+// @Description update slug, names or position of the category
+// @ID update-category
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Category ID"
+// @Param input body domain.UpdateCategoryInput true "Category info"
+// @Success 200 {object} statusResponse
+// @Failure 400,401,403,404,409 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/categories/{id} [put]
+func (h *Handler) updateCategory(c *gin.Context) {
+	var input domain.UpdateCategoryInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		newErrorResponse(c, domain.NewValidationError(err))
+
+		return
+	}
+
+	if err := h.categoriesService.Update(c.Param("id"), input); err != nil {
+		newErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{
+		Status: "ok",
+	})
+}
+
+// @Summary Delete Category
+// @Security ApiKeyAuth
+// @Tags Categories
+// @Description delete a category without subcategories and products
+// @ID delete-category
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Category ID"
+// @Success 200 {object} statusResponse
+// @Failure 401,403,404,409 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/categories/{id} [delete]
+func (h *Handler) deleteCategory(c *gin.Context) {
+	if err := h.categoriesService.Delete(c.Param("id")); err != nil {
+		newErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{
+		Status: "ok",
+	})
+}
@@ -0,0 +1,256 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/AndrewMislyuk/go-shop-backend/internal/domain"
+	"github.com/AndrewMislyuk/go-shop-backend/internal/service"
+	mock_service "github.com/AndrewMislyuk/go-shop-backend/internal/service/mock"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/magiconair/properties/assert"
+)
+
+func TestHandler_getCategoriesTree(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockCategories)
+
+	createdAt := time.Date(2022, 01, 12, 13, 8, 21, 0, time.UTC)
+	rootId := "0b5a3c1e-6a43-4b5e-8a1d-0f7f2d9a1c01"
+
+	testTable := []struct {
+		name                string
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name: "OK",
+			mockBehavior: func(s *mock_service.MockCategories) {
+				s.EXPECT().Tree().Return([]domain.CategoryNode{
+					{
+						Category:      domain.Category{Id: rootId, Level: 1, Slug: "zhenshchinam", Names: map[string]string{"en": "Women", "ru": "Женщинам"}, CreatedAt: createdAt},
+						ProductsCount: 2,
+						Children: []domain.CategoryNode{
+							{
+								Category:      domain.Category{Id: "5c2d9e7a-1f0b-4a3c-9e8d-7b6a5c4d3e02", ParentId: &rootId, Level: 2, Slug: "odezhda", Names: map[string]string{"ru": "Одежда"}, Position: 1, CreatedAt: createdAt},
+								ProductsCount: 2,
+								Children:      []domain.CategoryNode{},
+							},
+						},
+					},
+				}, nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"data":[{"id":"0b5a3c1e-6a43-4b5e-8a1d-0f7f2d9a1c01","parent_id":null,"level":1,"slug":"zhenshchinam","names":{"en":"Women","ru":"Женщинам"},"position":0,"created_at":"2022-01-12T13:08:21Z","products_count":2,"children":[{"id":"5c2d9e7a-1f0b-4a3c-9e8d-7b6a5c4d3e02","parent_id":"0b5a3c1e-6a43-4b5e-8a1d-0f7f2d9a1c01","level":2,"slug":"odezhda","names":{"ru":"Одежда"},"position":1,"created_at":"2022-01-12T13:08:21Z","products_count":2,"children":[]}]}]}`,
+		},
+
+		{
+			name: "Service Failure",
+			mockBehavior: func(s *mock_service.MockCategories) {
+				s.EXPECT().Tree().Return(nil, errors.New("service failure"))
+			},
+			expectedStatusCode:  500,
+			expectedRequestBody: `{"code":"internal_error","message":"internal server error"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			categories := mock_service.NewMockCategories(c)
+			testCase.mockBehavior(categories)
+
+			services := &service.Service{Categories: categories}
+			handler := NewHandler(services)
+
+			// Test Server
+			r := gin.New()
+			r.Use(handler.handleErrors)
+			r.GET("/categories/tree", handler.getCategoriesTree)
+
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/categories/tree", nil)
+
+			// Perform Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedRequestBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_createCategory(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockCategories, input domain.CreateCategoryInput)
+
+	parentId := "0b5a3c1e-6a43-4b5e-8a1d-0f7f2d9a1c01"
+
+	testTable := []struct {
+		name                string
+		inputBody           string
+		input               domain.CreateCategoryInput
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name:      "OK",
+			inputBody: `{"parent_id":"0b5a3c1e-6a43-4b5e-8a1d-0f7f2d9a1c01","slug":"obuv","names":{"ru":"Обувь","en":"Shoes"},"position":2}`,
+			input: domain.CreateCategoryInput{
+				ParentId: &parentId,
+				Slug:     "obuv",
+				Names:    map[string]string{"ru": "Обувь", "en": "Shoes"},
+				Position: 2,
+			},
+			mockBehavior: func(s *mock_service.MockCategories, input domain.CreateCategoryInput) {
+				s.EXPECT().Create(input).Return("5c2d9e7a-1f0b-4a3c-9e8d-7b6a5c4d3e02", nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"id":"5c2d9e7a-1f0b-4a3c-9e8d-7b6a5c4d3e02"}`,
+		},
+
+		{
+			name:                "Empty Fields",
+			inputBody:           `{}`,
+			mockBehavior:        func(s *mock_service.MockCategories, input domain.CreateCategoryInput) {},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"code":"invalid_input","message":"Key: 'CreateCategoryInput.Slug' Error:Field validation for 'Slug' failed on the 'required' tag\nKey: 'CreateCategoryInput.Names' Error:Field validation for 'Names' failed on the 'required' tag"}`,
+		},
+
+		{
+			name:      "Slug Taken",
+			inputBody: `{"slug":"zhenshchinam","names":{"ru":"Женщинам"}}`,
+			input: domain.CreateCategoryInput{
+				Slug:  "zhenshchinam",
+				Names: map[string]string{"ru": "Женщинам"},
+			},
+			mockBehavior: func(s *mock_service.MockCategories, input domain.CreateCategoryInput) {
+				s.EXPECT().Create(input).Return("", domain.ErrCategorySlugTaken)
+			},
+			expectedStatusCode:  409,
+			expectedRequestBody: `{"code":"category_slug_taken","message":"category with this slug already exists on this level"}`,
+		},
+
+		{
+			name:      "Too Deep",
+			inputBody: `{"parent_id":"0b5a3c1e-6a43-4b5e-8a1d-0f7f2d9a1c01","slug":"obuv","names":{"ru":"Обувь"}}`,
+			input: domain.CreateCategoryInput{
+				ParentId: &parentId,
+				Slug:     "obuv",
+				Names:    map[string]string{"ru": "Обувь"},
+			},
+			mockBehavior: func(s *mock_service.MockCategories, input domain.CreateCategoryInput) {
+				s.EXPECT().Create(input).Return("", domain.ErrCategoryTooDeep)
+			},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"code":"category_too_deep","message":"subtypes can not have subcategories"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			categories := mock_service.NewMockCategories(c)
+			testCase.mockBehavior(categories, testCase.input)
+
+			services := &service.Service{Categories: categories}
+			handler := NewHandler(services)
+
+			// Test Server
+			r := gin.New()
+			r.Use(handler.handleErrors)
+			r.POST("/categories", handler.createCategory)
+
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/categories", bytes.NewBufferString(testCase.inputBody))
+
+			// Perform Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedRequestBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_deleteCategory(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockCategories, categoryId string)
+
+	testTable := []struct {
+		name                string
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name: "OK",
+			mockBehavior: func(s *mock_service.MockCategories, categoryId string) {
+				s.EXPECT().Delete(categoryId).Return(nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"status":"ok"}`,
+		},
+
+		{
+			name: "Not Empty",
+			mockBehavior: func(s *mock_service.MockCategories, categoryId string) {
+				s.EXPECT().Delete(categoryId).Return(domain.ErrCategoryNotEmpty)
+			},
+			expectedStatusCode:  409,
+			expectedRequestBody: `{"code":"category_not_empty","message":"category has subcategories or products"}`,
+		},
+
+		{
+			name: "Not Found",
+			mockBehavior: func(s *mock_service.MockCategories, categoryId string) {
+				s.EXPECT().Delete(categoryId).Return(domain.ErrCategoryNotFound)
+			},
+			expectedStatusCode:  404,
+			expectedRequestBody: `{"code":"category_not_found","message":"category not found"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			categories := mock_service.NewMockCategories(c)
+			testCase.mockBehavior(categories, "0b5a3c1e-6a43-4b5e-8a1d-0f7f2d9a1c01")
+
+			services := &service.Service{Categories: categories}
+			handler := NewHandler(services)
+
+			// Test Server
+			r := gin.New()
+			r.Use(handler.handleErrors)
+			r.DELETE("/categories/:id", handler.deleteCategory)
+
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("DELETE", "/categories/0b5a3c1e-6a43-4b5e-8a1d-0f7f2d9a1c01", nil)
+
+			// Perform Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedRequestBody, w.Body.String())
+		})
+	}
+}
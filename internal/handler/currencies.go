@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/AndrewMislyuk/go-shop-backend/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Get Exchange Rates
+// @Tags Currencies
+// @Description the base currency of the prices and the rates of the currencies products can be read in
+// @ID get-exchange-rates
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} domain.ExchangeRates
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/currencies [get]
+func (h *Handler) getExchangeRates(c *gin.Context) {
+	rates, err := h.currenciesService.GetRates()
+	if err != nil {
+		newErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, rates)
+}
+
+// @Summary Set Exchange Rates
+// @Security ApiKeyAuth
+// @Tags Currencies
+// @Description set the rates of the given currencies, units of the currency per one unit of the base currency
+// @ID set-exchange-rates
+// @Accept  json
+// @Produce  json
+// @Param input body domain.SetExchangeRatesInput true "Rates by currency"
+// @Success 200 {object} statusResponse
+// @Failure 400,401,403 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/currencies/rates [put]
+func (h *Handler) setExchangeRates(c *gin.Context) {
+	var input domain.SetExchangeRatesInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		newErrorResponse(c, domain.NewValidationError(err))
+
+		return
+	}
+
+	if err := h.currenciesService.SetRates(input); err != nil {
+		newErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{
+		Status: "ok",
+	})
+}
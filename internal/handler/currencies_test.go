@@ -0,0 +1,196 @@
+package handler
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/AndrewMislyuk/go-shop-backend/internal/domain"
+	"github.com/AndrewMislyuk/go-shop-backend/internal/service"
+	mock_service "github.com/AndrewMislyuk/go-shop-backend/internal/service/mock"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/magiconair/properties/assert"
+)
+
+func TestHandler_setExchangeRates(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockCurrencies, input domain.SetExchangeRatesInput)
+
+	testTable := []struct {
+		name                string
+		inputBody           string
+		input               domain.SetExchangeRatesInput
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name:      "OK",
+			inputBody: `{"rates":{"USD":"0.0108","EUR":"0.0099"}}`,
+			input: domain.SetExchangeRatesInput{
+				Rates: map[string]string{"USD": "0.0108", "EUR": "0.0099"},
+			},
+			mockBehavior: func(s *mock_service.MockCurrencies, input domain.SetExchangeRatesInput) {
+				s.EXPECT().SetRates(input).Return(nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"status":"ok"}`,
+		},
+
+		{
+			name:                "Empty Fields",
+			inputBody:           `{}`,
+			mockBehavior:        func(s *mock_service.MockCurrencies, input domain.SetExchangeRatesInput) {},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"code":"invalid_input","message":"Key: 'SetExchangeRatesInput.Rates' Error:Field validation for 'Rates' failed on the 'required' tag"}`,
+		},
+
+		{
+			name:      "Invalid Rate",
+			inputBody: `{"rates":{"USD":"0"}}`,
+			input: domain.SetExchangeRatesInput{
+				Rates: map[string]string{"USD": "0"},
+			},
+			mockBehavior: func(s *mock_service.MockCurrencies, input domain.SetExchangeRatesInput) {
+				s.EXPECT().SetRates(input).Return(domain.ErrInvalidExchangeRate)
+			},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"code":"invalid_exchange_rate","message":"exchange rate must be a positive decimal number"}`,
+		},
+
+		{
+			name:      "Base Currency",
+			inputBody: `{"rates":{"RUB":"1"}}`,
+			input: domain.SetExchangeRatesInput{
+				Rates: map[string]string{"RUB": "1"},
+			},
+			mockBehavior: func(s *mock_service.MockCurrencies, input domain.SetExchangeRatesInput) {
+				s.EXPECT().SetRates(input).Return(domain.ErrBaseCurrency)
+			},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"code":"base_currency","message":"the base currency has neither exchange rate nor price list"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			currencies := mock_service.NewMockCurrencies(c)
+			testCase.mockBehavior(currencies, testCase.input)
+
+			services := &service.Service{Currencies: currencies}
+			handler := NewHandler(services)
+
+			// Test Server
+			r := gin.New()
+			r.Use(handler.handleErrors)
+			r.PUT("/currencies/rates", handler.setExchangeRates)
+
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("PUT", "/currencies/rates", bytes.NewBufferString(testCase.inputBody))
+
+			// Perform Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedRequestBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_setProductPrice(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockProductPrices, productId, currency string, input domain.SetProductPriceInput)
+
+	productId := "f5e7d3a2-9c1b-4e8d-a6f4-3b2c1d0e9f8a"
+
+	testTable := []struct {
+		name                string
+		currency            string
+		inputBody           string
+		input               domain.SetProductPriceInput
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name:      "OK",
+			currency:  "usd",
+			inputBody: `{"amount":8900}`,
+			input:     domain.SetProductPriceInput{Amount: 8900},
+			mockBehavior: func(s *mock_service.MockProductPrices, productId, currency string, input domain.SetProductPriceInput) {
+				s.EXPECT().Set(productId, currency, input).Return(nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"status":"ok"}`,
+		},
+
+		{
+			name:      "Empty Fields",
+			currency:  "USD",
+			inputBody: `{}`,
+			mockBehavior: func(s *mock_service.MockProductPrices, productId, currency string, input domain.SetProductPriceInput) {
+			},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"code":"invalid_input","message":"Key: 'SetProductPriceInput.Amount' Error:Field validation for 'Amount' failed on the 'required' tag"}`,
+		},
+
+		{
+			name:      "Unknown Currency",
+			currency:  "XYZ",
+			inputBody: `{"amount":8900}`,
+			input:     domain.SetProductPriceInput{Amount: 8900},
+			mockBehavior: func(s *mock_service.MockProductPrices, productId, currency string, input domain.SetProductPriceInput) {
+				s.EXPECT().Set(productId, currency, input).Return(domain.ErrUnknownCurrency)
+			},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"code":"unknown_currency","message":"currency must be a supported ISO 4217 code"}`,
+		},
+
+		{
+			name:      "Product Not Found",
+			currency:  "USD",
+			inputBody: `{"amount":8900}`,
+			input:     domain.SetProductPriceInput{Amount: 8900},
+			mockBehavior: func(s *mock_service.MockProductPrices, productId, currency string, input domain.SetProductPriceInput) {
+				s.EXPECT().Set(productId, currency, input).Return(domain.ErrProductNotFound)
+			},
+			expectedStatusCode:  404,
+			expectedRequestBody: `{"code":"product_not_found","message":"product not found"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			prices := mock_service.NewMockProductPrices(c)
+			testCase.mockBehavior(prices, productId, testCase.currency, testCase.input)
+
+			services := &service.Service{ProductPrices: prices}
+			handler := NewHandler(services)
+
+			// Test Server
+			r := gin.New()
+			r.Use(handler.handleErrors)
+			r.PUT("/products/:id/prices/:currency", handler.setProductPrice)
+
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("PUT", "/products/"+productId+"/prices/"+testCase.currency, bytes.NewBufferString(testCase.inputBody))
+
+			// Perform Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedRequestBody, w.Body.String())
+		})
+	}
+}
@@ -0,0 +1,157 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/AndrewMislyuk/go-shop-backend/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Get All Discounts
+// @Security ApiKeyAuth
+// @Tags Discounts
+// @Description sales of products and categories, newest first
+// @ID get-all-discounts
+// @Accept  json
+// @Produce  json
+// @Param product_id query string false "Product ID"
+// @Param category_id query string false "Category ID"
+// @Param limit query int false "Page size"
+// @Param offset query int false "Page offset"
+// @Success 200 {object} domain.DiscountsList
+// @Failure 400,401,403 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/discounts [get]
+func (h *Handler) getAllDiscounts(c *gin.Context) {
+	var filter domain.DiscountsFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		newErrorResponse(c, domain.NewValidationError(err))
+
+		return
+	}
+
+	discounts, err := h.discountsService.GetAll(filter)
+	if err != nil {
+		newErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, discounts)
+}
+
+// @Summary Get Discount By Id
+// @Security ApiKeyAuth
+// @Tags Discounts
+// @Description get discount by id
+// @ID get-discount-by-id
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Discount ID"
+// @Success 200 {object} domain.Discount
+// @Failure 401,403,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/discounts/{id} [get]
+func (h *Handler) getDiscountById(c *gin.Context) {
+	discount, err := h.discountsService.GetById(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, discount)
+}
+
+// @Summary Create Discount
+// @Security ApiKeyAuth
+// @Tags Discounts
+// @Description create a sale of a product or of every product of a category, it lowers the prices while it lasts
+// @ID create-discount
+// @Accept  json
+// @Produce  json
+// @Param input body domain.CreateDiscountInput true "Discount info"
+// @Success 200 {object} getCreationId
+// @Failure 400,401,403,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/discounts [post]
+func (h *Handler) createDiscount(c *gin.Context) {
+	var input domain.CreateDiscountInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		newErrorResponse(c, domain.NewValidationError(err))
+
+		return
+	}
+
+	id, err := h.discountsService.Create(input)
+	if err != nil {
+		newErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, getCreationId{
+		Id: id,
+	})
+}
+
+// @Summary Update Discount
+// @Security ApiKeyAuth
+// @Tags Discounts
+// @Description change the value, the period or the state of the discount
+// @ID update-discount
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Discount ID"
+// @Param input body domain.UpdateDiscountInput true "Discount info"
+// @Success 200 {object} statusResponse
+// @Failure 400,401,403,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/discounts/{id} [put]
+func (h *Handler) updateDiscount(c *gin.Context) {
+	var input domain.UpdateDiscountInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		newErrorResponse(c, domain.NewValidationError(err))
+
+		return
+	}
+
+	if err := h.discountsService.Update(c.Param("id"), input); err != nil {
+		newErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{
+		Status: "ok",
+	})
+}
+
+// @Summary Delete Discount
+// @Security ApiKeyAuth
+// @Tags Discounts
+// @Description delete discount
+// @ID delete-discount
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Discount ID"
+// @Success 200 {object} statusResponse
+// @Failure 401,403,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/discounts/{id} [delete]
+func (h *Handler) deleteDiscount(c *gin.Context) {
+	if err := h.discountsService.Delete(c.Param("id")); err != nil {
+		newErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{
+		Status: "ok",
+	})
+}
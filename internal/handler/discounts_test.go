@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/AndrewMislyuk/go-shop-backend/internal/domain"
+	"github.com/AndrewMislyuk/go-shop-backend/internal/service"
+	mock_service "github.com/AndrewMislyuk/go-shop-backend/internal/service/mock"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/magiconair/properties/assert"
+)
+
+func TestHandler_createDiscount(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockDiscounts, input domain.CreateDiscountInput)
+
+	categoryId := "0b5a3c1e-6a43-4b5e-8a1d-0f7f2d9a1c01"
+
+	testTable := []struct {
+		name                string
+		inputBody           string
+		input               domain.CreateDiscountInput
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name:      "OK",
+			inputBody: `{"name":"Весенняя распродажа","kind":"percent","value":20,"category_id":"0b5a3c1e-6a43-4b5e-8a1d-0f7f2d9a1c01"}`,
+			input: domain.CreateDiscountInput{
+				Name:       "Весенняя распродажа",
+				Kind:       domain.DiscountPercent,
+				Value:      20,
+				CategoryId: &categoryId,
+			},
+			mockBehavior: func(s *mock_service.MockDiscounts, input domain.CreateDiscountInput) {
+				s.EXPECT().Create(input).Return("3f1c2b4a-8d7e-4a69-b5c3-2e1d0f9a8b7c", nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"id":"3f1c2b4a-8d7e-4a69-b5c3-2e1d0f9a8b7c"}`,
+		},
+
+		{
+			name:                "Empty Fields",
+			inputBody:           `{}`,
+			mockBehavior:        func(s *mock_service.MockDiscounts, input domain.CreateDiscountInput) {},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"code":"invalid_input","message":"Key: 'CreateDiscountInput.Name' Error:Field validation for 'Name' failed on the 'required' tag\nKey: 'CreateDiscountInput.Kind' Error:Field validation for 'Kind' failed on the 'required' tag\nKey: 'CreateDiscountInput.Value' Error:Field validation for 'Value' failed on the 'required' tag"}`,
+		},
+
+		{
+			name:      "Invalid Value",
+			inputBody: `{"name":"Весенняя распродажа","kind":"percent","value":120,"category_id":"0b5a3c1e-6a43-4b5e-8a1d-0f7f2d9a1c01"}`,
+			input: domain.CreateDiscountInput{
+				Name:       "Весенняя распродажа",
+				Kind:       domain.DiscountPercent,
+				Value:      120,
+				CategoryId: &categoryId,
+			},
+			mockBehavior: func(s *mock_service.MockDiscounts, input domain.CreateDiscountInput) {
+				s.EXPECT().Create(input).Return("", domain.ErrInvalidDiscountValue)
+			},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"code":"invalid_discount_value","message":"discount value must be positive, percents can not exceed 100"}`,
+		},
+
+		{
+			name:      "Category Not Found",
+			inputBody: `{"name":"Весенняя распродажа","kind":"fixed","value":50000,"category_id":"0b5a3c1e-6a43-4b5e-8a1d-0f7f2d9a1c01"}`,
+			input: domain.CreateDiscountInput{
+				Name:       "Весенняя распродажа",
+				Kind:       domain.DiscountFixed,
+				Value:      50000,
+				CategoryId: &categoryId,
+			},
+			mockBehavior: func(s *mock_service.MockDiscounts, input domain.CreateDiscountInput) {
+				s.EXPECT().Create(input).Return("", domain.ErrCategoryNotFound)
+			},
+			expectedStatusCode:  404,
+			expectedRequestBody: `{"code":"category_not_found","message":"category not found"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			discounts := mock_service.NewMockDiscounts(c)
+			testCase.mockBehavior(discounts, testCase.input)
+
+			services := &service.Service{Discounts: discounts}
+			handler := NewHandler(services)
+
+			// Test Server
+			r := gin.New()
+			r.Use(handler.handleErrors)
+			r.POST("/discounts", handler.createDiscount)
+
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/discounts", bytes.NewBufferString(testCase.inputBody))
+
+			// Perform Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedRequestBody, w.Body.String())
+		})
+	}
+}
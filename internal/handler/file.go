@@ -1,120 +1,245 @@
 package handler
 
 import (
-	"bytes"
+	"errors"
 	"io"
+	"mime/multipart"
 	"net/http"
-	"os"
+	"net/url"
 
 	"github.com/AndrewMislyuk/go-shop-backend/internal/domain"
 	"github.com/gin-gonic/gin"
 )
 
 const (
-	maxUploadSize = 5 << 20 // 5 megabytes
+	maxUploadSize    = 5 << 20 // 5 megabytes
+	maxFormValueSize = 4 << 10
 )
 
-var (
-	imageTypes = map[string]interface{}{
-		"image/jpeg": nil,
-		"image/png":  nil,
-		"image/jpg":  nil,
-		"image/webp": nil,
-	}
-)
-
-type UploadedImageURL struct {
-	URL string `json:"image_url"`
-}
+var errFormValueTooLong = domain.NewError(domain.ErrValidation, "form_value_too_long", "form value is too long")
 
 // @Summary Upload image
 // @Security ApiKeyAuth
 // @Tags Upload image
-// @Description upload image
+// @Description queue the image for the storage, the status of the returned file is polled with GET /api/file/{id}
 // @ID file-upload-image
-// @Accept json
+// @Accept multipart/form-data
 // @Produce json
 // @Param productId formData string true "productId"
 // @Param file formData file true "file"
-// @Success 200 {object} UploadedImageURL
-// @Failure 400,404 {object} errorResponse
+// @Success 200 {object} domain.File
+// @Failure 400,404,413,429 {object} errorResponse
 // @Failure 500 {object} errorResponse
 // @Failure default {object} errorResponse
 // @Router /api/file/upload [post]
 func (h *Handler) uploadImage(c *gin.Context) {
-	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxUploadSize)
-
-	err := c.Request.ParseForm()
+	file, values, err := h.receiveImage(c)
 	if err != nil {
-		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		newErrorResponse(c, err)
 
 		return
 	}
 
-	productId := c.PostForm("productId")
-	if productId == "" {
-		newErrorResponse(c, http.StatusBadRequest, "select product id")
+	file.ProductId = values.Get("productId")
+	if file.ProductId != "" && !isUUID(file.ProductId) {
+		h.fileService.DiscardStaged(file)
+		newErrorResponse(c, errInvalidId)
 
 		return
 	}
 
-	file, header, err := c.Request.FormFile("file")
+	image, err := h.fileService.Upload(file)
 	if err != nil {
-		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		newErrorResponse(c, err)
 
 		return
 	}
 
-	defer file.Close()
-
-	buffer := make([]byte, header.Size)
+	c.JSON(http.StatusOK, image)
+}
 
-	if _, err := file.Read(buffer); err != nil {
-		newErrorResponse(c, http.StatusBadRequest, err.Error())
+// @Summary Get File
+// @Security ApiKeyAuth
+// @Tags Upload image
+// @Description get the status of the upload of the file, its error once it has failed
+// @ID get-file
+// @Produce json
+// @Param id path string true "File ID"
+// @Success 200 {object} domain.File
+// @Failure 401,403,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/file/{id} [get]
+func (h *Handler) getFile(c *gin.Context) {
+	file, err := h.fileService.GetFile(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, err)
 
 		return
 	}
 
-	contentType := http.DetectContentType(buffer)
+	c.JSON(http.StatusOK, file)
+}
 
-	// Validate File Type
-	if _, ex := imageTypes[contentType]; !ex {
-		newErrorResponse(c, http.StatusBadRequest, "file type is not supported")
+// @Summary Create Upload URL
+// @Security ApiKeyAuth
+// @Tags Upload image
+// @Description presign the upload of an image straight to the storage. The client sends the file to url with method and headers, then completes the upload with POST /api/file/{id}/complete
+// @ID file-create-upload-url
+// @Accept json
+// @Produce json
+// @Param input body domain.CreateUploadURLInput true "Declared file"
+// @Success 200 {object} domain.UploadURL
+// @Failure 400,401,403,404,413 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/file/upload-url [post]
+func (h *Handler) createUploadURL(c *gin.Context) {
+	var input domain.CreateUploadURLInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		newErrorResponse(c, domain.NewValidationError(err))
 
 		return
 	}
 
-	filename := header.Filename
-
-	f, err := os.OpenFile(filename, os.O_CREATE|os.O_APPEND|os.O_RDWR, 0666)
+	uploadURL, err := h.fileService.CreateUploadURL(input)
 	if err != nil {
-		newErrorResponse(c, http.StatusInternalServerError, "failed to create temp file")
+		newErrorResponse(c, err)
 
 		return
 	}
 
-	if _, err := io.Copy(f, bytes.NewReader(buffer)); err != nil {
-		newErrorResponse(c, http.StatusInternalServerError, "failed to write chunk to temp file")
+	c.JSON(http.StatusOK, uploadURL)
+}
+
+// @Summary Complete Upload
+// @Security ApiKeyAuth
+// @Tags Upload image
+// @Description check the file the client has uploaded to the storage against the declared size and content type and queue it for the gallery of the product
+// @ID file-complete-upload
+// @Produce json
+// @Param id path string true "File ID"
+// @Success 200 {object} domain.File
+// @Failure 400,401,403,404,409,429 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/file/{id}/complete [post]
+func (h *Handler) completeUpload(c *gin.Context) {
+	file, err := h.fileService.CompleteUpload(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, err)
 
 		return
 	}
 
-	f.Close()
+	c.JSON(http.StatusOK, file)
+}
 
-	url, err := h.fileService.Upload(domain.File{
-		ProductId:   productId,
-		Type:        domain.Image,
-		ContentType: contentType,
-		Name:        filename,
-		Size:        header.Size,
-	})
+// @Summary Get Download URL
+// @Security ApiKeyAuth
+// @Tags Upload image
+// @Description presign the download of the original of a stored file
+// @ID file-get-download-url
+// @Produce json
+// @Param id path string true "File ID"
+// @Success 200 {object} domain.DownloadURL
+// @Failure 401,403,404,409 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/file/{id}/download-url [get]
+func (h *Handler) getDownloadURL(c *gin.Context) {
+	downloadURL, err := h.fileService.GetDownloadURL(c.Param("id"))
 	if err != nil {
-		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		newErrorResponse(c, err)
 
 		return
 	}
 
-	c.JSON(http.StatusOK, UploadedImageURL{
-		URL: url,
-	})
+	c.JSON(http.StatusOK, downloadURL)
+}
+
+// receiveImage streams the "file" field of the multipart form to the file
+// service, which stages it until the image is uploaded. The other fields of
+// the form are returned in values, whatever their order.
+func (h *Handler) receiveImage(c *gin.Context) (domain.File, url.Values, error) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxUploadSize)
+
+	reader, err := c.Request.MultipartReader()
+	if err != nil {
+		return domain.File{}, nil, domain.NewValidationError(err)
+	}
+
+	var file domain.File
+	values := url.Values{}
+
+	for {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err == nil {
+			err = h.receivePart(part, &file, values)
+			part.Close()
+		} else {
+			err = bodyError(err)
+		}
+
+		if err != nil {
+			if file.Name != "" {
+				h.fileService.DiscardStaged(file)
+			}
+
+			return domain.File{}, nil, err
+		}
+	}
+
+	if file.Name == "" {
+		return domain.File{}, nil, domain.NewValidationError(http.ErrMissingFile)
+	}
+
+	return file, values, nil
+}
+
+// receivePart stages the first "file" part and keeps the values of the other
+// fields, the next file parts are skipped.
+func (h *Handler) receivePart(part *multipart.Part, file *domain.File, values url.Values) error {
+	if part.FormName() != "file" {
+		value, err := io.ReadAll(io.LimitReader(part, maxFormValueSize+1))
+		if err != nil {
+			return bodyError(err)
+		}
+
+		if len(value) > maxFormValueSize {
+			return errFormValueTooLong
+		}
+
+		values.Add(part.FormName(), string(value))
+
+		return nil
+	}
+
+	if file.Name != "" {
+		return nil
+	}
+
+	staged, err := h.fileService.Stage(part)
+	if err != nil {
+		return err
+	}
+
+	*file = staged
+
+	return nil
+}
+
+// bodyError reports a failed read of the form, a form over maxUploadSize is
+// cut off by http.MaxBytesReader and is too large.
+func bodyError(err error) error {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return domain.ErrFileTooLarge
+	}
+
+	return domain.NewValidationError(err)
 }
@@ -0,0 +1,435 @@
+package handler
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"image"
+	"image/png"
+	"mime/multipart"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AndrewMislyuk/go-shop-backend/internal/domain"
+	"github.com/AndrewMislyuk/go-shop-backend/internal/service"
+	mock_service "github.com/AndrewMislyuk/go-shop-backend/internal/service/mock"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/magiconair/properties/assert"
+)
+
+func TestHandler_getFile(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockFiles, fileId string)
+
+	fileId := "0a8d4c3e-5b6f-4e7a-9c1d-2f3e4a5b6c7d"
+	timestamp := time.Date(2022, 5, 14, 10, 30, 0, 0, time.UTC)
+
+	testTable := []struct {
+		name                string
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name: "Failed Upload",
+			mockBehavior: func(s *mock_service.MockFiles, fileId string) {
+				s.EXPECT().GetFile(fileId).Return(domain.File{
+					ID:              fileId,
+					ProductId:       "f5e7d3a2-9c1b-4e8d-a6f4-3b2c1d0e9f8a",
+					Type:            domain.Image,
+					ContentType:     "image/png",
+					Size:            2048,
+					Status:          domain.StorageUploadError,
+					Attempts:        5,
+					Error:           "connection refused",
+					UpdatedAt:       &timestamp,
+					UploadStartedAt: timestamp,
+					CreatedAt:       timestamp,
+				}, nil)
+			},
+			expectedStatusCode: 200,
+			expectedRequestBody: `{"id":"0a8d4c3e-5b6f-4e7a-9c1d-2f3e4a5b6c7d","product_id":"f5e7d3a2-9c1b-4e8d-a6f4-3b2c1d0e9f8a",` +
+				`"type":"image","content_type":"image/png","size":2048,"status":"storage_upload_error","position":0,"primary":false,` +
+				`"alt_text":"","attempts":5,"error":"connection refused","upload_started_at":"2022-05-14T10:30:00Z",` +
+				`"created_at":"2022-05-14T10:30:00Z","updated_at":"2022-05-14T10:30:00Z","url":"","renditions":null}`,
+		},
+
+		{
+			name: "Not Found",
+			mockBehavior: func(s *mock_service.MockFiles, fileId string) {
+				s.EXPECT().GetFile(fileId).Return(domain.File{}, domain.ErrImageNotFound)
+			},
+			expectedStatusCode:  404,
+			expectedRequestBody: `{"code":"image_not_found","message":"image not found"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			files := mock_service.NewMockFiles(c)
+			testCase.mockBehavior(files, fileId)
+
+			services := &service.Service{Files: files}
+			handler := NewHandler(services)
+
+			// Test Server
+			r := gin.New()
+			r.Use(handler.handleErrors)
+			r.GET("/file/:id", handler.getFile)
+
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/file/"+fileId, nil)
+
+			// Perform Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedRequestBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_uploadImage(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockFiles, staging *service.FileService)
+
+	var img bytes.Buffer
+	if err := png.Encode(&img, image.NewRGBA(image.Rect(0, 0, 2, 2))); err != nil {
+		t.Fatal(err)
+	}
+
+	sum := sha256.Sum256(img.Bytes())
+	productId := "f5e7d3a2-9c1b-4e8d-a6f4-3b2c1d0e9f8a"
+
+	// the staged file must be gone once the request has failed
+	assertRemoved := func(file domain.File) {
+		_, err := os.Stat(file.Name)
+		assert.Equal(t, true, os.IsNotExist(err))
+	}
+
+	testTable := []struct {
+		name                string
+		fields              map[string]string
+		file                []byte
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name:   "OK",
+			fields: map[string]string{"productId": productId},
+			file:   img.Bytes(),
+			mockBehavior: func(s *mock_service.MockFiles, staging *service.FileService) {
+				s.EXPECT().Stage(gomock.Any()).DoAndReturn(staging.Stage)
+				s.EXPECT().Upload(gomock.Any()).DoAndReturn(func(file domain.File) (domain.File, error) {
+					data, err := os.ReadFile(file.Name)
+					defer os.Remove(file.Name)
+
+					assert.Equal(t, nil, err)
+					assert.Equal(t, img.Bytes(), data)
+					assert.Equal(t, filepath.Clean(os.TempDir()), filepath.Dir(file.Name))
+					assert.Equal(t, productId, file.ProductId)
+					assert.Equal(t, "image/png", file.ContentType)
+					assert.Equal(t, int64(img.Len()), file.Size)
+					assert.Equal(t, hex.EncodeToString(sum[:]), file.Checksum)
+
+					return domain.File{ID: "0a8d4c3e-5b6f-4e7a-9c1d-2f3e4a5b6c7d", Status: domain.UploadedByClient}, nil
+				})
+			},
+			expectedStatusCode: 200,
+			expectedRequestBody: `{"id":"0a8d4c3e-5b6f-4e7a-9c1d-2f3e4a5b6c7d","product_id":"","type":"","content_type":"","size":0,` +
+				`"status":"uploaded_by_client","position":0,"primary":false,"alt_text":"","attempts":0,` +
+				`"upload_started_at":"0001-01-01T00:00:00Z","created_at":"0001-01-01T00:00:00Z","updated_at":null,"url":"","renditions":null}`,
+		},
+
+		{
+			name:   "Unsupported Type",
+			fields: map[string]string{"productId": productId},
+			file:   []byte("#!/bin/sh\necho hello\n"),
+			mockBehavior: func(s *mock_service.MockFiles, staging *service.FileService) {
+				s.EXPECT().Stage(gomock.Any()).DoAndReturn(staging.Stage)
+			},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"code":"unsupported_file_type","message":"file type is not supported"}`,
+		},
+
+		{
+			name: "Product Id Missing",
+			file: img.Bytes(),
+			mockBehavior: func(s *mock_service.MockFiles, staging *service.FileService) {
+				s.EXPECT().Stage(gomock.Any()).DoAndReturn(staging.Stage)
+				s.EXPECT().Upload(gomock.Any()).DoAndReturn(func(file domain.File) (domain.File, error) {
+					defer assertRemoved(file)
+
+					return staging.Upload(file)
+				})
+			},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"code":"product_id_required","message":"select product id"}`,
+		},
+
+		{
+			name:   "Too Large",
+			fields: map[string]string{"productId": productId},
+			file:   append(img.Bytes(), make([]byte, maxUploadSize)...),
+			mockBehavior: func(s *mock_service.MockFiles, staging *service.FileService) {
+				s.EXPECT().Stage(gomock.Any()).DoAndReturn(staging.Stage)
+			},
+			expectedStatusCode:  413,
+			expectedRequestBody: `{"code":"file_too_large","message":"file is too large"}`,
+		},
+
+		{
+			name:   "Invalid Product Id",
+			fields: map[string]string{"productId": "{" + productId + "}"},
+			file:   img.Bytes(),
+			mockBehavior: func(s *mock_service.MockFiles, staging *service.FileService) {
+				s.EXPECT().Stage(gomock.Any()).DoAndReturn(staging.Stage)
+				s.EXPECT().DiscardStaged(gomock.Any()).Do(func(file domain.File) {
+					staging.DiscardStaged(file)
+					assertRemoved(file)
+				})
+			},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"code":"invalid_id","message":"id must be a UUID"}`,
+		},
+
+		{
+			name:   "Form Value Too Long",
+			fields: map[string]string{"productId": strings.Repeat("a", maxFormValueSize+1)},
+			file:   img.Bytes(),
+			mockBehavior: func(s *mock_service.MockFiles, staging *service.FileService) {
+				s.EXPECT().Stage(gomock.Any()).DoAndReturn(staging.Stage)
+				s.EXPECT().DiscardStaged(gomock.Any()).Do(func(file domain.File) {
+					staging.DiscardStaged(file)
+					assertRemoved(file)
+				})
+			},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"code":"form_value_too_long","message":"form value is too long"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			files := mock_service.NewMockFiles(c)
+			staging := service.NewFileService(nil, nil, nil, nil, service.UploadQueueConfig{}, service.DirectUploadConfig{})
+			testCase.mockBehavior(files, staging)
+
+			services := &service.Service{Files: files}
+			handler := NewHandler(services)
+
+			// Test Server
+			r := gin.New()
+			r.Use(handler.handleErrors)
+			r.POST("/file/upload", handler.uploadImage)
+
+			// Test Request
+			var body bytes.Buffer
+			form := multipart.NewWriter(&body)
+
+			part, err := form.CreateFormFile("file", "../../image.png")
+			if err != nil {
+				t.Fatal(err)
+			}
+			part.Write(testCase.file)
+
+			for name, value := range testCase.fields {
+				form.WriteField(name, value)
+			}
+			form.Close()
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/file/upload", &body)
+			req.Header.Set("Content-Type", form.FormDataContentType())
+
+			// Perform Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedRequestBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_createUploadURL(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockFiles, input domain.CreateUploadURLInput)
+
+	expiresAt := time.Date(2022, 5, 14, 10, 45, 0, 0, time.UTC)
+
+	testTable := []struct {
+		name                string
+		inputBody           string
+		input               domain.CreateUploadURLInput
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name:      "OK",
+			inputBody: `{"product_id":"f5e7d3a2-9c1b-4e8d-a6f4-3b2c1d0e9f8a","content_type":"image/png","size":2048}`,
+			input: domain.CreateUploadURLInput{
+				ProductId:   "f5e7d3a2-9c1b-4e8d-a6f4-3b2c1d0e9f8a",
+				ContentType: "image/png",
+				Size:        2048,
+			},
+			mockBehavior: func(s *mock_service.MockFiles, input domain.CreateUploadURLInput) {
+				s.EXPECT().CreateUploadURL(input).Return(domain.UploadURL{
+					File: domain.File{
+						ID:     "0a8d4c3e-5b6f-4e7a-9c1d-2f3e4a5b6c7d",
+						Status: domain.ClientUploadInProgress,
+					},
+					URL:       "https://bucket.storage/uploads/f5e7d3a2/0a8d4c3e?X-Amz-Signature=abc",
+					Method:    "PUT",
+					Headers:   map[string]string{"Content-Type": "image/png"},
+					ExpiresAt: expiresAt,
+				}, nil)
+			},
+			expectedStatusCode: 200,
+			expectedRequestBody: `{"file":{"id":"0a8d4c3e-5b6f-4e7a-9c1d-2f3e4a5b6c7d","product_id":"","type":"","content_type":"","size":0,` +
+				`"status":"client_upload_in_progress","position":0,"primary":false,"alt_text":"","attempts":0,` +
+				`"upload_started_at":"0001-01-01T00:00:00Z","created_at":"0001-01-01T00:00:00Z","updated_at":null,"url":"","renditions":null},` +
+				`"url":"https://bucket.storage/uploads/f5e7d3a2/0a8d4c3e?X-Amz-Signature=abc","method":"PUT",` +
+				`"headers":{"Content-Type":"image/png"},"expires_at":"2022-05-14T10:45:00Z"}`,
+		},
+
+		{
+			name:                "Empty Fields",
+			inputBody:           `{"content_type":"image/png","size":2048}`,
+			mockBehavior:        func(s *mock_service.MockFiles, input domain.CreateUploadURLInput) {},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"code":"invalid_input","message":"Key: 'CreateUploadURLInput.ProductId' Error:Field validation for 'ProductId' failed on the 'required' tag"}`,
+		},
+
+		{
+			name:      "Too Large",
+			inputBody: `{"product_id":"f5e7d3a2-9c1b-4e8d-a6f4-3b2c1d0e9f8a","content_type":"image/png","size":104857600}`,
+			input: domain.CreateUploadURLInput{
+				ProductId:   "f5e7d3a2-9c1b-4e8d-a6f4-3b2c1d0e9f8a",
+				ContentType: "image/png",
+				Size:        104857600,
+			},
+			mockBehavior: func(s *mock_service.MockFiles, input domain.CreateUploadURLInput) {
+				s.EXPECT().CreateUploadURL(input).Return(domain.UploadURL{}, domain.ErrFileTooLarge)
+			},
+			expectedStatusCode:  413,
+			expectedRequestBody: `{"code":"file_too_large","message":"file is too large"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			files := mock_service.NewMockFiles(c)
+			testCase.mockBehavior(files, testCase.input)
+
+			services := &service.Service{Files: files}
+			handler := NewHandler(services)
+
+			// Test Server
+			r := gin.New()
+			r.Use(handler.handleErrors)
+			r.POST("/file/upload-url", handler.createUploadURL)
+
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/file/upload-url", bytes.NewBufferString(testCase.inputBody))
+
+			// Perform Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedRequestBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_completeUpload(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockFiles, fileId string)
+
+	fileId := "0a8d4c3e-5b6f-4e7a-9c1d-2f3e4a5b6c7d"
+
+	testTable := []struct {
+		name                string
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name: "OK",
+			mockBehavior: func(s *mock_service.MockFiles, fileId string) {
+				s.EXPECT().CompleteUpload(fileId).Return(domain.File{ID: fileId, Status: domain.UploadedByClient}, nil)
+			},
+			expectedStatusCode: 200,
+			expectedRequestBody: `{"id":"0a8d4c3e-5b6f-4e7a-9c1d-2f3e4a5b6c7d","product_id":"","type":"","content_type":"","size":0,` +
+				`"status":"uploaded_by_client","position":0,"primary":false,"alt_text":"","attempts":0,` +
+				`"upload_started_at":"0001-01-01T00:00:00Z","created_at":"0001-01-01T00:00:00Z","updated_at":null,"url":"","renditions":null}`,
+		},
+
+		{
+			name: "Not Uploaded",
+			mockBehavior: func(s *mock_service.MockFiles, fileId string) {
+				s.EXPECT().CompleteUpload(fileId).Return(domain.File{}, domain.ErrFileNotUploaded)
+			},
+			expectedStatusCode:  409,
+			expectedRequestBody: `{"code":"file_not_uploaded","message":"file is not uploaded to the storage yet"}`,
+		},
+
+		{
+			name: "Mismatch",
+			mockBehavior: func(s *mock_service.MockFiles, fileId string) {
+				s.EXPECT().CompleteUpload(fileId).Return(domain.File{}, domain.ErrFileMismatch)
+			},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"code":"file_mismatch","message":"uploaded file does not match the declared size or content type"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			files := mock_service.NewMockFiles(c)
+			testCase.mockBehavior(files, fileId)
+
+			services := &service.Service{Files: files}
+			handler := NewHandler(services)
+
+			// Test Server
+			r := gin.New()
+			r.Use(handler.handleErrors)
+			r.POST("/file/:id/complete", handler.completeUpload)
+
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/file/"+fileId+"/complete", nil)
+
+			// Perform Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedRequestBody, w.Body.String())
+		})
+	}
+}
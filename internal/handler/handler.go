@@ -1,9 +1,12 @@
 package handler
 
 import (
+	"io"
+
 	_ "github.com/AndrewMislyuk/go-shop-backend/docs"
 	"github.com/AndrewMislyuk/go-shop-backend/internal/domain"
 	"github.com/AndrewMislyuk/go-shop-backend/internal/service"
+	"github.com/AndrewMislyuk/go-shop-backend/pkg/auth"
 	"github.com/gin-gonic/gin"
 	swaggerfiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
@@ -11,42 +14,184 @@ import (
 
 type User interface {
 	CreateUser(user domain.UserSignUp) (string, error)
-	GenerateToken(email, password string) (string, error)
+	GenerateToken(email, password, clientIP string) (domain.Tokens, error)
+	RefreshTokens(refreshToken string) (domain.Tokens, error)
+	Logout(refreshToken string) error
+	LogoutAll(userId string) error
 	GetMe(token string) (domain.User, error)
+	Identify(token string) (domain.Identity, error)
+	ChangePassword(userId, sessionId string, input domain.ChangePasswordInput) error
+	VerifyEmail(token string) error
+	ForgotPassword(email string) error
+	ResetPassword(token, newPassword string) error
+	JWKS() auth.JSONWebKeySet
+}
+
+type Users interface {
+	GetAll(filter domain.UsersFilter) (domain.UsersList, error)
+	UpdateMe(userId string, input domain.UpdateUserInput) error
+	DeleteMe(userId string) error
+	Block(adminId, userId string) error
+	Unblock(userId string) error
+}
+
+type Roles interface {
+	GetAll() ([]domain.Role, error)
+	Grant(userId, role string) error
+	Revoke(userId, role string) error
 }
 
 type Products interface {
 	Create(list domain.CreateProductInput) (string, error)
-	GetAll() ([]domain.ProductsList, error)
-	GetById(listId string) (domain.ProductsList, error)
+	GetAll(filter domain.ProductsFilter) (domain.ProductsPage, error)
+	Search(input domain.ProductsSearchInput) (domain.ProductsSearchPage, error)
+	GetById(listId, currency string) (domain.ProductsList, error)
 	Update(itemId string, input domain.UpdateProductInput) error
 	Delete(itemId string) error
 }
 
+type ProductVariants interface {
+	Create(productId string, input domain.CreateVariantInput) (string, error)
+	GetByProduct(productId string) ([]domain.ProductVariant, error)
+	Update(productId, variantId string, input domain.UpdateVariantInput) error
+	Delete(productId, variantId string) error
+}
+
+type Inventory interface {
+	GetLevels(filter domain.StockLevelsFilter) (domain.StockLevelsList, error)
+	GetMovements(filter domain.StockMovementsFilter) (domain.StockMovementsList, error)
+	RecordMovement(actorId string, input domain.StockMovementInput) (domain.StockLevel, error)
+	Reserve(input domain.CreateReservationInput) (domain.Reservation, error)
+	ConfirmReservation(actorId, reservationId string) error
+	ReleaseReservation(reservationId string) error
+}
+
+type Carts interface {
+	Get(owner domain.CartOwner) (domain.Cart, error)
+	AddItem(owner domain.CartOwner, input domain.AddCartItemInput) (domain.Cart, error)
+	UpdateItem(owner domain.CartOwner, itemId string, input domain.UpdateCartItemInput) (domain.Cart, error)
+	RemoveItem(owner domain.CartOwner, itemId string) (domain.Cart, error)
+	Merge(userId, token string) error
+}
+
+type Orders interface {
+	Checkout(userId string, input domain.CheckoutInput) (domain.Order, error)
+	GetMine(userId string, filter domain.OrdersFilter) (domain.OrdersList, error)
+	GetMineById(userId, orderId string) (domain.Order, error)
+	CancelMine(userId, orderId string) error
+	GetAll(filter domain.OrdersFilter) (domain.OrdersList, error)
+	GetById(orderId string) (domain.Order, error)
+	ChangeStatus(actorId, orderId string, input domain.ChangeOrderStatusInput) error
+}
+
+type Payments interface {
+	Pay(userId, orderId string, input domain.PayOrderInput) (domain.Payment, error)
+	Refund(actorId, orderId string) error
+	HandleWebhook(payload []byte, signature string) error
+}
+
+type Discounts interface {
+	Create(input domain.CreateDiscountInput) (string, error)
+	GetById(discountId string) (domain.Discount, error)
+	GetAll(filter domain.DiscountsFilter) (domain.DiscountsList, error)
+	Update(discountId string, input domain.UpdateDiscountInput) error
+	Delete(discountId string) error
+}
+
+type PromoCodes interface {
+	Create(input domain.CreatePromoCodeInput) (string, error)
+	GetById(codeId string) (domain.PromoCode, error)
+	GetAll(filter domain.PromoCodesFilter) (domain.PromoCodesList, error)
+	Update(codeId string, input domain.UpdatePromoCodeInput) error
+	Delete(codeId string) error
+}
+
+type Currencies interface {
+	GetRates() (domain.ExchangeRates, error)
+	SetRates(input domain.SetExchangeRatesInput) error
+}
+
+type ProductPrices interface {
+	GetByProduct(productId string) ([]domain.ProductPrice, error)
+	Set(productId, currency string, input domain.SetProductPriceInput) error
+	Delete(productId, currency string) error
+}
+
+type Categories interface {
+	Create(input domain.CreateCategoryInput) (string, error)
+	GetById(categoryId string) (domain.Category, error)
+	Tree() ([]domain.CategoryNode, error)
+	Update(categoryId string, input domain.UpdateCategoryInput) error
+	Delete(categoryId string) error
+}
+
 type Files interface {
-	Upload(file domain.File) (string, error)
+	Stage(r io.Reader) (domain.File, error)
+	DiscardStaged(file domain.File)
+	Upload(file domain.File) (domain.File, error)
+	GetFile(fileId string) (domain.File, error)
+	CreateUploadURL(input domain.CreateUploadURLInput) (domain.UploadURL, error)
+	CompleteUpload(fileId string) (domain.File, error)
+	GetDownloadURL(fileId string) (domain.DownloadURL, error)
+	GetImages(productId string) ([]domain.File, error)
+	UpdateImage(productId, imageId string, input domain.UpdateImageInput) error
+	ReorderImages(productId string, input domain.ReorderImagesInput) error
+	SetPrimaryImage(productId, imageId string) error
+	DeleteImage(productId, imageId string) error
 }
 
 type Handler struct {
-	userService     User
-	productsService Products
-	fileService     Files
+	userService          User
+	usersService         Users
+	rolesService         Roles
+	productsService      Products
+	variantsService      ProductVariants
+	inventoryService     Inventory
+	cartService          Carts
+	ordersService        Orders
+	paymentsService      Payments
+	discountsService     Discounts
+	promoCodesService    PromoCodes
+	currenciesService    Currencies
+	productPricesService ProductPrices
+	categoriesService    Categories
+	fileService          Files
 }
 
 func NewHandler(services *service.Service) *Handler {
 	return &Handler{
-		userService:     services.User,
-		productsService: services.ProductsList,
-		fileService:     services.Files,
+		userService:          services.User,
+		usersService:         services.Users,
+		rolesService:         services.Roles,
+		productsService:      services.ProductsList,
+		variantsService:      services.ProductVariants,
+		inventoryService:     services.Inventory,
+		cartService:          services.Carts,
+		ordersService:        services.Orders,
+		paymentsService:      services.Payments,
+		discountsService:     services.Discounts,
+		promoCodesService:    services.PromoCodes,
+		currenciesService:    services.Currencies,
+		productPricesService: services.ProductPrices,
+		categoriesService:    services.Categories,
+		fileService:          services.Files,
 	}
 }
 
-func (h *Handler) InitRouter() *gin.Engine {
+// InitRouter takes the client IP from X-Forwarded-For only when the request
+// comes from one of trustedProxies, IPs or CIDRs. None are trusted by default,
+// otherwise any client could pick the IP its sign-in attempts are counted on.
+func (h *Handler) InitRouter(trustedProxies []string) (*gin.Engine, error) {
 	router := gin.New()
 
-	router.Use(h.CORSMiddleware())
+	if err := router.SetTrustedProxies(trustedProxies); err != nil {
+		return nil, err
+	}
+
+	router.Use(h.requestId, h.CORSMiddleware(), h.handleErrors)
 
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerfiles.Handler))
+	router.GET("/.well-known/jwks.json", h.getJWKS)
 
 	auth := router.Group("/auth")
 	{
@@ -54,27 +199,144 @@ func (h *Handler) InitRouter() *gin.Engine {
 
 		auth.POST("/sign-up", h.signUp)
 		auth.POST("/sign-in", h.signIn)
+		auth.POST("/refresh", h.refresh)
+		auth.POST("/logout", h.logout)
+		auth.POST("/verify-email", h.verifyEmail)
+		auth.POST("/forgot-password", h.forgotPassword)
+		auth.POST("/reset-password", h.resetPassword)
+		auth.POST("/logout-all", h.userIdentify, h.logoutAll)
 		auth.GET("/get-me", h.userIdentify, h.getMe)
 	}
 
 	api := router.Group("/api")
 	{
-		api.Use(h.loggingMiddleware)
+		api.Use(h.loggingMiddleware, h.validateIds)
 
 		products := api.Group("/products")
 		{
-			products.POST("/", h.userIdentify, h.userIsAdmin, h.createProduct)
+			products.POST("/", h.userIdentify, h.requirePermission(domain.PermissionProductsWrite), h.createProduct)
 			products.GET("/", h.getAllProducts)
+			products.GET("/search", h.searchProducts)
 			products.GET("/:id", h.getProductById)
-			products.PUT("/:id", h.userIdentify, h.userIsAdmin, h.updateProduct)
-			products.DELETE("/:id", h.userIdentify, h.userIsAdmin, h.deleteProduct)
+			products.PUT("/:id", h.userIdentify, h.requirePermission(domain.PermissionProductsWrite), h.updateProduct)
+			products.DELETE("/:id", h.userIdentify, h.requirePermission(domain.PermissionProductsWrite), h.deleteProduct)
+
+			products.GET("/:id/variants", h.getProductVariants)
+			products.POST("/:id/variants", h.userIdentify, h.requirePermission(domain.PermissionProductsWrite), h.createProductVariant)
+			products.PUT("/:id/variants/:variantId", h.userIdentify, h.requirePermission(domain.PermissionProductsWrite), h.updateProductVariant)
+			products.DELETE("/:id/variants/:variantId", h.userIdentify, h.requirePermission(domain.PermissionProductsWrite), h.deleteProductVariant)
+
+			products.GET("/:id/images", h.getProductImages)
+			products.POST("/:id/images", h.userIdentify, h.requirePermission(domain.PermissionFilesUpload), h.addProductImage)
+			products.PUT("/:id/images", h.userIdentify, h.requirePermission(domain.PermissionProductsWrite), h.reorderProductImages)
+			products.PUT("/:id/images/:imageId", h.userIdentify, h.requirePermission(domain.PermissionProductsWrite), h.updateProductImage)
+			products.PUT("/:id/images/:imageId/primary", h.userIdentify, h.requirePermission(domain.PermissionProductsWrite), h.setPrimaryProductImage)
+			products.DELETE("/:id/images/:imageId", h.userIdentify, h.requirePermission(domain.PermissionProductsWrite), h.deleteProductImage)
+
+			products.GET("/:id/prices", h.userIdentify, h.requirePermission(domain.PermissionCurrenciesWrite), h.getProductPrices)
+			products.PUT("/:id/prices/:currency", h.userIdentify, h.requirePermission(domain.PermissionCurrenciesWrite), h.setProductPrice)
+			products.DELETE("/:id/prices/:currency", h.userIdentify, h.requirePermission(domain.PermissionCurrenciesWrite), h.deleteProductPrice)
+		}
+
+		inventory := api.Group("/inventory", h.userIdentify, h.requirePermission(domain.PermissionInventoryWrite))
+		{
+			inventory.GET("/levels", h.getStockLevels)
+			inventory.GET("/movements", h.getStockMovements)
+			inventory.POST("/movements", h.recordStockMovement)
+			inventory.POST("/reservations", h.createReservation)
+			inventory.POST("/reservations/:id/confirm", h.confirmReservation)
+			inventory.POST("/reservations/:id/release", h.releaseReservation)
+		}
+
+		cart := api.Group("/cart", h.optionalUserIdentify)
+		{
+			cart.GET("/", h.getCart)
+			cart.POST("/items", h.addCartItem)
+			cart.PUT("/items/:id", h.updateCartItem)
+			cart.DELETE("/items/:id", h.removeCartItem)
+		}
+
+		orders := api.Group("/orders", h.userIdentify)
+		{
+			orders.POST("/", h.checkout)
+			orders.GET("/", h.requirePermission(domain.PermissionOrdersManage), h.getAllOrders)
+			orders.GET("/:id", h.requirePermission(domain.PermissionOrdersManage), h.getOrderById)
+			orders.PUT("/:id/status", h.requirePermission(domain.PermissionOrdersManage), h.changeOrderStatus)
+			orders.POST("/:id/refund", h.requirePermission(domain.PermissionOrdersManage), h.refundOrder)
+		}
+
+		payments := api.Group("/payments")
+		{
+			payments.POST("/webhook", h.paymentWebhook)
+		}
+
+		discounts := api.Group("/discounts", h.userIdentify, h.requirePermission(domain.PermissionDiscountsWrite))
+		{
+			discounts.GET("/", h.getAllDiscounts)
+			discounts.GET("/:id", h.getDiscountById)
+			discounts.POST("/", h.createDiscount)
+			discounts.PUT("/:id", h.updateDiscount)
+			discounts.DELETE("/:id", h.deleteDiscount)
+		}
+
+		promoCodes := api.Group("/promo-codes", h.userIdentify, h.requirePermission(domain.PermissionDiscountsWrite))
+		{
+			promoCodes.GET("/", h.getAllPromoCodes)
+			promoCodes.GET("/:id", h.getPromoCodeById)
+			promoCodes.POST("/", h.createPromoCode)
+			promoCodes.PUT("/:id", h.updatePromoCode)
+			promoCodes.DELETE("/:id", h.deletePromoCode)
+		}
+
+		currencies := api.Group("/currencies")
+		{
+			currencies.GET("/", h.getExchangeRates)
+			currencies.PUT("/rates", h.userIdentify, h.requirePermission(domain.PermissionCurrenciesWrite), h.setExchangeRates)
+		}
+
+		categories := api.Group("/categories")
+		{
+			categories.GET("/tree", h.getCategoriesTree)
+			categories.GET("/:id", h.getCategoryById)
+			categories.POST("/", h.userIdentify, h.requirePermission(domain.PermissionCategoriesWrite), h.createCategory)
+			categories.PUT("/:id", h.userIdentify, h.requirePermission(domain.PermissionCategoriesWrite), h.updateCategory)
+			categories.DELETE("/:id", h.userIdentify, h.requirePermission(domain.PermissionCategoriesWrite), h.deleteCategory)
 		}
 
 		files := api.Group("/file")
 		{
-			files.POST("/upload", h.userIdentify, h.userIsAdmin, h.uploadImage)
+			files.POST("/upload", h.userIdentify, h.requirePermission(domain.PermissionFilesUpload), h.uploadImage)
+			files.POST("/upload-url", h.userIdentify, h.requirePermission(domain.PermissionFilesUpload), h.createUploadURL)
+			files.GET("/:id", h.userIdentify, h.requirePermission(domain.PermissionFilesUpload), h.getFile)
+			files.POST("/:id/complete", h.userIdentify, h.requirePermission(domain.PermissionFilesUpload), h.completeUpload)
+			files.GET("/:id/download-url", h.userIdentify, h.requirePermission(domain.PermissionFilesUpload), h.getDownloadURL)
+		}
+
+		roles := api.Group("/roles", h.userIdentify, h.requirePermission(domain.PermissionUsersManage))
+		{
+			roles.GET("/", h.getAllRoles)
+		}
+
+		users := api.Group("/users", h.userIdentify)
+		{
+			users.PATCH("/me", h.updateMe)
+			users.PUT("/me/password", h.changePassword)
+			users.DELETE("/me", h.deleteMe)
+			users.GET("/me/orders", h.getMyOrders)
+			users.GET("/me/orders/:id", h.getMyOrderById)
+			users.POST("/me/orders/:id/cancel", h.cancelMyOrder)
+			users.POST("/me/orders/:id/pay", h.payOrder)
+
+			admin := users.Group("/", h.requirePermission(domain.PermissionUsersManage))
+			{
+				admin.GET("/", h.getAllUsers)
+				admin.POST("/:id/block", h.blockUser)
+				admin.POST("/:id/unblock", h.unblockUser)
+				admin.POST("/:id/roles", h.grantRole)
+				admin.DELETE("/:id/roles/:role", h.revokeRole)
+			}
 		}
 	}
 
-	return router
+	return router, nil
 }
@@ -0,0 +1,189 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/AndrewMislyuk/go-shop-backend/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Get Stock Levels
+// @Security ApiKeyAuth
+// @Tags Inventory
+// @Description stock of products and variants
+// @ID get-stock-levels
+// @Accept  json
+// @Produce  json
+// @Param product_id query string false "Product ID"
+// @Param low_stock query int false "Only levels with at most this many available"
+// @Param limit query int false "Page size"
+// @Param offset query int false "Page offset"
+// @Success 200 {object} domain.StockLevelsList
+// @Failure 400,401,403 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/inventory/levels [get]
+func (h *Handler) getStockLevels(c *gin.Context) {
+	var filter domain.StockLevelsFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		newErrorResponse(c, domain.NewValidationError(err))
+
+		return
+	}
+
+	levels, err := h.inventoryService.GetLevels(filter)
+	if err != nil {
+		newErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, levels)
+}
+
+// @Summary Get Stock Movements
+// @Security ApiKeyAuth
+// @Tags Inventory
+// @Description history of stock movements, newest first
+// @ID get-stock-movements
+// @Accept  json
+// @Produce  json
+// @Param product_id query string false "Product ID"
+// @Param variant_id query string false "Variant ID"
+// @Param kind query string false "receipt, sale, return or adjustment"
+// @Param limit query int false "Page size"
+// @Param offset query int false "Page offset"
+// @Success 200 {object} domain.StockMovementsList
+// @Failure 400,401,403 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/inventory/movements [get]
+func (h *Handler) getStockMovements(c *gin.Context) {
+	var filter domain.StockMovementsFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		newErrorResponse(c, domain.NewValidationError(err))
+
+		return
+	}
+
+	movements, err := h.inventoryService.GetMovements(filter)
+	if err != nil {
+		newErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, movements)
+}
+
+// @Summary Record Stock Movement
+// @Security ApiKeyAuth
+// @Tags Inventory
+// @Description receive, sell, return or adjust stock, returns the new stock level
+// @ID record-stock-movement
+// @Accept  json
+// @Produce  json
+// @Param input body domain.StockMovementInput true "Movement info"
+// @Success 200 {object} domain.StockLevel
+// @Failure 400,401,403,404,409 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/inventory/movements [post]
+func (h *Handler) recordStockMovement(c *gin.Context) {
+	var input domain.StockMovementInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		newErrorResponse(c, domain.NewValidationError(err))
+
+		return
+	}
+
+	level, err := h.inventoryService.RecordMovement(c.GetString(userCtx), input)
+	if err != nil {
+		newErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, level)
+}
+
+// @Summary Create Reservation
+// @Security ApiKeyAuth
+// @Tags Inventory
+// @Description hold stock for a limited time
+// @ID create-reservation
+// @Accept  json
+// @Produce  json
+// @Param input body domain.CreateReservationInput true "Reservation info"
+// @Success 200 {object} domain.Reservation
+// @Failure 400,401,403,404,409 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/inventory/reservations [post]
+func (h *Handler) createReservation(c *gin.Context) {
+	var input domain.CreateReservationInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		newErrorResponse(c, domain.NewValidationError(err))
+
+		return
+	}
+
+	reservation, err := h.inventoryService.Reserve(input)
+	if err != nil {
+		newErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, reservation)
+}
+
+// @Summary Confirm Reservation
+// @Security ApiKeyAuth
+// @Tags Inventory
+// @Description sell the reserved stock
+// @ID confirm-reservation
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Reservation ID"
+// @Success 200 {object} statusResponse
+// @Failure 401,403,404,409 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/inventory/reservations/{id}/confirm [post]
+func (h *Handler) confirmReservation(c *gin.Context) {
+	if err := h.inventoryService.ConfirmReservation(c.GetString(userCtx), c.Param("id")); err != nil {
+		newErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{
+		Status: "ok",
+	})
+}
+
+// @Summary Release Reservation
+// @Security ApiKeyAuth
+// @Tags Inventory
+// @Description return the reserved stock
+// @ID release-reservation
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Reservation ID"
+// @Success 200 {object} statusResponse
+// @Failure 401,403,404,409 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/inventory/reservations/{id}/release [post]
+func (h *Handler) releaseReservation(c *gin.Context) {
+	if err := h.inventoryService.ReleaseReservation(c.Param("id")); err != nil {
+		newErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{
+		Status: "ok",
+	})
+}
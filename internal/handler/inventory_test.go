@@ -0,0 +1,184 @@
+package handler
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/AndrewMislyuk/go-shop-backend/internal/domain"
+	"github.com/AndrewMislyuk/go-shop-backend/internal/service"
+	mock_service "github.com/AndrewMislyuk/go-shop-backend/internal/service/mock"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/magiconair/properties/assert"
+)
+
+func TestHandler_recordStockMovement(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockInventory, actorId string, input domain.StockMovementInput)
+
+	updatedAt := time.Date(2022, 01, 12, 13, 17, 58, 0, time.UTC)
+
+	testTable := []struct {
+		name                string
+		inputBody           string
+		input               domain.StockMovementInput
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name:      "OK",
+			inputBody: `{"product_id":"453b4f0f-1f56-4c57-b43d-7b79792450a7","kind":"receipt","quantity":10}`,
+			input: domain.StockMovementInput{
+				ProductId: "453b4f0f-1f56-4c57-b43d-7b79792450a7",
+				Kind:      domain.MovementReceipt,
+				Quantity:  10,
+			},
+			mockBehavior: func(s *mock_service.MockInventory, actorId string, input domain.StockMovementInput) {
+				s.EXPECT().RecordMovement(actorId, input).Return(domain.StockLevel{
+					Id:           "0b6cbb5e-3d0a-4c3f-8f0e-6a4b7d3e2c11",
+					ProductId:    "453b4f0f-1f56-4c57-b43d-7b79792450a7",
+					ProductTitle: "Твидовый кардиган из хлопка",
+					OnHand:       12,
+					Reserved:     2,
+					Available:    10,
+					Version:      3,
+					UpdatedAt:    updatedAt,
+				}, nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"id":"0b6cbb5e-3d0a-4c3f-8f0e-6a4b7d3e2c11","product_id":"453b4f0f-1f56-4c57-b43d-7b79792450a7","product_title":"Твидовый кардиган из хлопка","variant_id":null,"on_hand":12,"reserved":2,"available":10,"version":3,"updated_at":"2022-01-12T13:17:58Z"}`,
+		},
+
+		{
+			name:                "Missing Kind",
+			inputBody:           `{"product_id":"453b4f0f-1f56-4c57-b43d-7b79792450a7","quantity":10}`,
+			mockBehavior:        func(s *mock_service.MockInventory, actorId string, input domain.StockMovementInput) {},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"code":"invalid_input","message":"Key: 'StockMovementInput.Kind' Error:Field validation for 'Kind' failed on the 'required' tag"}`,
+		},
+
+		{
+			name:      "Stock Reserved",
+			inputBody: `{"product_id":"453b4f0f-1f56-4c57-b43d-7b79792450a7","kind":"adjustment","quantity":-5,"reason":"inventory count"}`,
+			input: domain.StockMovementInput{
+				ProductId: "453b4f0f-1f56-4c57-b43d-7b79792450a7",
+				Kind:      domain.MovementAdjustment,
+				Quantity:  -5,
+				Reason:    "inventory count",
+			},
+			mockBehavior: func(s *mock_service.MockInventory, actorId string, input domain.StockMovementInput) {
+				s.EXPECT().RecordMovement(actorId, input).Return(domain.StockLevel{}, domain.ErrInsufficientStock)
+			},
+			expectedStatusCode:  409,
+			expectedRequestBody: `{"code":"insufficient_stock","message":"not enough stock available"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			actorId := "e7a9c2d4-5f3b-4a61-8c0e-2b9d7f1a3e55"
+
+			inventory := mock_service.NewMockInventory(c)
+			testCase.mockBehavior(inventory, actorId, testCase.input)
+
+			services := &service.Service{Inventory: inventory}
+			handler := NewHandler(services)
+
+			// Test Server
+			r := gin.New()
+			r.Use(handler.handleErrors)
+			r.POST("/inventory/movements", func(c *gin.Context) {
+				c.Set(userCtx, actorId)
+			}, handler.recordStockMovement)
+
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/inventory/movements", bytes.NewBufferString(testCase.inputBody))
+
+			// Perform Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedRequestBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_confirmReservation(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockInventory, actorId, reservationId string)
+
+	testTable := []struct {
+		name                string
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name: "OK",
+			mockBehavior: func(s *mock_service.MockInventory, actorId, reservationId string) {
+				s.EXPECT().ConfirmReservation(actorId, reservationId).Return(nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"status":"ok"}`,
+		},
+
+		{
+			name: "Not Active",
+			mockBehavior: func(s *mock_service.MockInventory, actorId, reservationId string) {
+				s.EXPECT().ConfirmReservation(actorId, reservationId).Return(domain.ErrReservationNotActive)
+			},
+			expectedStatusCode:  409,
+			expectedRequestBody: `{"code":"reservation_not_active","message":"reservation is already confirmed, released or expired"}`,
+		},
+
+		{
+			name: "Not Found",
+			mockBehavior: func(s *mock_service.MockInventory, actorId, reservationId string) {
+				s.EXPECT().ConfirmReservation(actorId, reservationId).Return(domain.ErrReservationNotFound)
+			},
+			expectedStatusCode:  404,
+			expectedRequestBody: `{"code":"reservation_not_found","message":"reservation not found"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			actorId := "e7a9c2d4-5f3b-4a61-8c0e-2b9d7f1a3e55"
+
+			inventory := mock_service.NewMockInventory(c)
+			testCase.mockBehavior(inventory, actorId, "5d2f8a61-0c4e-4b7a-9e13-7f6a2c8d4b90")
+
+			services := &service.Service{Inventory: inventory}
+			handler := NewHandler(services)
+
+			// Test Server
+			r := gin.New()
+			r.Use(handler.handleErrors)
+			r.POST("/inventory/reservations/:id/confirm", func(c *gin.Context) {
+				c.Set(userCtx, actorId)
+			}, handler.confirmReservation)
+
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/inventory/reservations/5d2f8a61-0c4e-4b7a-9e13-7f6a2c8d4b90/confirm", nil)
+
+			// Perform Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedRequestBody, w.Body.String())
+		})
+	}
+}
@@ -1,25 +1,49 @@
 package handler
 
 import (
-	"net/http"
+	"errors"
+	"fmt"
+	"regexp"
 	"strings"
 
+	"github.com/AndrewMislyuk/go-shop-backend/internal/domain"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 )
 
 const (
 	authorizationHeader = "Authorization"
+	requestIdHeader     = "X-Request-ID"
+	cartTokenHeader     = "X-Cart-Token"
+	requestIdCtx        = "requestId"
 	userCtx             = "userId"
-	userRole            = "userRole"
+	sessionCtx          = "sessionId"
+	userRolesCtx        = "userRoles"
+	userPermissionsCtx  = "userPermissions"
 )
 
+var (
+	errEmptyAuthHeader   = domain.NewError(domain.ErrUnauthorized, "invalid_auth_header", "empty auth header")
+	errInvalidAuthHeader = domain.NewError(domain.ErrUnauthorized, "invalid_auth_header", "invalid auth header")
+	errInvalidBearer     = domain.NewError(domain.ErrUnauthorized, "invalid_auth_header", "invalid bearer")
+	errEmptyToken        = domain.NewError(domain.ErrUnauthorized, "invalid_auth_header", "empty token")
+	errUnauthorized      = domain.NewError(domain.ErrUnauthorized, "unauthorized", "you are unauthorized")
+	errInvalidId         = domain.NewError(domain.ErrValidation, "invalid_id", "id must be a UUID")
+)
+
+// idParams are the path params holding row ids, all of them uuid columns.
+var idParams = []string{"id", "imageId", "variantId"}
+
+var requestIdRegexp = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
 func (h *Handler) CORSMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
 		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With, X-Request-ID, X-Cart-Token, Accept-Currency")
+		c.Writer.Header().Set("Access-Control-Expose-Headers", "X-Request-ID, Retry-After, X-Cart-Token")
+		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, PATCH, DELETE")
 
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
@@ -31,66 +55,120 @@ func (h *Handler) CORSMiddleware() gin.HandlerFunc {
 	}
 }
 
+// requestId keeps the id set by a proxy in front of the service or generates
+// a new one. The id is returned in the response header and in error bodies.
+func (h *Handler) requestId(c *gin.Context) {
+	id := c.GetHeader(requestIdHeader)
+	if !requestIdRegexp.MatchString(id) {
+		id = uuid.New().String()
+	}
+
+	c.Set(requestIdCtx, id)
+	c.Header(requestIdHeader, id)
+}
+
+// validateIds rejects malformed ids in the path before they reach the
+// database, which would fail on them with an internal error.
+func (h *Handler) validateIds(c *gin.Context) {
+	for _, name := range idParams {
+		value := c.Param(name)
+		if value == "" {
+			continue
+		}
+
+		if !isUUID(value) {
+			newErrorResponse(c, errInvalidId)
+
+			return
+		}
+	}
+}
+
+// isUUID reports whether value is an id in the form the uuid columns take.
+func isUUID(value string) bool {
+	// uuid.Parse also takes the urn and braced forms, Postgres doesn't
+	_, err := uuid.Parse(value)
+
+	return err == nil && len(value) == 36
+}
+
 func (h *Handler) loggingMiddleware(c *gin.Context) {
-	logrus.Infof("[%s] - %s", c.Request.Method, c.Request.RequestURI)
+	logrus.WithField("request_id", c.GetString(requestIdCtx)).Infof("[%s] - %s", c.Request.Method, c.Request.RequestURI)
 }
 
 func (h *Handler) userIdentify(c *gin.Context) {
 	header := c.GetHeader(authorizationHeader)
 	if header == "" {
-		newErrorResponse(c, http.StatusUnauthorized, "empty auth header")
+		newErrorResponse(c, errEmptyAuthHeader)
 
 		return
 	}
 
 	headerParts := strings.Split(header, " ")
 	if len(headerParts) != 2 {
-		newErrorResponse(c, http.StatusUnauthorized, "invalid auth header")
+		newErrorResponse(c, errInvalidAuthHeader)
 
 		return
 	}
 
 	if headerParts[0] != "Bearer" {
-		newErrorResponse(c, http.StatusUnauthorized, "invalid bearer")
+		newErrorResponse(c, errInvalidBearer)
 
 		return
 	}
 
 	if headerParts[1] == "" {
-		newErrorResponse(c, http.StatusUnauthorized, "empty token")
+		newErrorResponse(c, errEmptyToken)
 
 		return
 	}
 
-	user, err := h.userService.GetMe(headerParts[1])
+	identity, err := h.userService.Identify(headerParts[1])
 	if err != nil {
-		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		newErrorResponse(c, err)
 
 		return
 	}
 
-	c.Set(userCtx, user.Id)
-	c.Set(userRole, user.Role)
+	c.Set(userCtx, identity.User.Id)
+	c.Set(sessionCtx, identity.SessionId)
+	c.Set(userRolesCtx, identity.User.Roles)
+	c.Set(userPermissionsCtx, identity.User.Permissions)
 }
 
-func (h *Handler) userIsAdmin(c *gin.Context) {
-	role, exist := c.Get(userRole)
-	if !exist {
-		newErrorResponse(c, http.StatusUnauthorized, "you are unauthorized")
-
+// optionalUserIdentify identifies the user of authorized requests and lets
+// anonymous ones through.
+func (h *Handler) optionalUserIdentify(c *gin.Context) {
+	if c.GetHeader(authorizationHeader) == "" {
 		return
 	}
 
-	roleStr, ok := role.(string)
-	if !ok {
-		newErrorResponse(c, http.StatusUnauthorized, "type error")
+	h.userIdentify(c)
+}
+
+// requirePermission must run after userIdentify.
+func (h *Handler) requirePermission(permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		permissions, exist := c.Get(userPermissionsCtx)
+		if !exist {
+			newErrorResponse(c, errUnauthorized)
 
-		return
-	}
+			return
+		}
 
-	if roleStr != "ADMIN" {
-		newErrorResponse(c, http.StatusUnauthorized, "you don't have admin role")
+		granted, ok := permissions.([]string)
+		if !ok {
+			newErrorResponse(c, errors.New("user permissions have unexpected type"))
 
-		return
+			return
+		}
+
+		for _, p := range granted {
+			if p == permission {
+				return
+			}
+		}
+
+		newErrorResponse(c, domain.NewError(domain.ErrForbidden, "permission_required", fmt.Sprintf("permission %s is required", permission)))
 	}
 }
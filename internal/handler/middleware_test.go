@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -33,19 +34,23 @@ func TestHandler_userIdentify(t *testing.T) {
 			headerValue: "Bearer token",
 			token:       "token",
 			mockBehavior: func(s *mock_service.MockUser, token string) {
-				s.EXPECT().GetMe(token).Return(domain.User{
-					Id:        "34c8d3e6-b8d7-43dc-847e-5764c4114856",
-					Name:      "Test_Name",
-					Surname:   "Test_Surname",
-					Email:     "test@gmail.com",
-					Phone:     "+4456781234",
-					Role:      "ADMIN",
-					Password:  "1234QWER@",
-					CreatedAt: time.Now(),
+				s.EXPECT().Identify(token).Return(domain.Identity{
+					User: domain.User{
+						Id:          "34c8d3e6-b8d7-43dc-847e-5764c4114856",
+						Name:        "Test_Name",
+						Surname:     "Test_Surname",
+						Email:       "test@gmail.com",
+						Phone:       "+4456781234",
+						Roles:       []string{"ADMIN"},
+						Permissions: []string{"products:write"},
+						Password:    "1234QWER@",
+						CreatedAt:   time.Now(),
+					},
+					SessionId: "8d1f2e55-6a0e-4a53-8c3c-0b8f3a0f7a11",
 				}, nil)
 			},
 			expectedStatusCode:   200,
-			expectedResponseBody: "user_id:34c8d3e6-b8d7-43dc-847e-5764c4114856, user_role:ADMIN",
+			expectedResponseBody: "user_id:34c8d3e6-b8d7-43dc-847e-5764c4114856, user_roles:[ADMIN], user_permissions:[products:write]",
 		},
 
 		{
@@ -53,7 +58,7 @@ func TestHandler_userIdentify(t *testing.T) {
 			headerName:           "",
 			mockBehavior:         func(s *mock_service.MockUser, token string) {},
 			expectedStatusCode:   401,
-			expectedResponseBody: `{"message":"empty auth header"}`,
+			expectedResponseBody: `{"code":"invalid_auth_header","message":"empty auth header"}`,
 		},
 
 		{
@@ -62,7 +67,7 @@ func TestHandler_userIdentify(t *testing.T) {
 			headerValue:          "Bearr token",
 			mockBehavior:         func(s *mock_service.MockUser, token string) {},
 			expectedStatusCode:   401,
-			expectedResponseBody: `{"message":"invalid bearer"}`,
+			expectedResponseBody: `{"code":"invalid_auth_header","message":"invalid bearer"}`,
 		},
 
 		{
@@ -71,7 +76,7 @@ func TestHandler_userIdentify(t *testing.T) {
 			headerValue:          "Bearer ",
 			mockBehavior:         func(s *mock_service.MockUser, token string) {},
 			expectedStatusCode:   401,
-			expectedResponseBody: `{"message":"empty token"}`,
+			expectedResponseBody: `{"code":"invalid_auth_header","message":"empty token"}`,
 		},
 
 		{
@@ -80,10 +85,34 @@ func TestHandler_userIdentify(t *testing.T) {
 			headerValue: "Bearer token",
 			token:       "token",
 			mockBehavior: func(s *mock_service.MockUser, token string) {
-				s.EXPECT().GetMe(token).Return(domain.User{}, errors.New("service failure"))
+				s.EXPECT().Identify(token).Return(domain.Identity{}, errors.New("service failure"))
 			},
 			expectedStatusCode:   500,
-			expectedResponseBody: `{"message":"service failure"}`,
+			expectedResponseBody: `{"code":"internal_error","message":"internal server error"}`,
+		},
+
+		{
+			name:        "Expired Token",
+			headerName:  "Authorization",
+			headerValue: "Bearer token",
+			token:       "token",
+			mockBehavior: func(s *mock_service.MockUser, token string) {
+				s.EXPECT().Identify(token).Return(domain.Identity{}, fmt.Errorf("%w: token is expired", domain.ErrInvalidAccessToken))
+			},
+			expectedStatusCode:   401,
+			expectedResponseBody: `{"code":"invalid_access_token","message":"invalid access token: token is expired"}`,
+		},
+
+		{
+			name:        "Blocked User",
+			headerName:  "Authorization",
+			headerValue: "Bearer token",
+			token:       "token",
+			mockBehavior: func(s *mock_service.MockUser, token string) {
+				s.EXPECT().Identify(token).Return(domain.Identity{}, domain.ErrUserBlocked)
+			},
+			expectedStatusCode:   403,
+			expectedResponseBody: `{"code":"user_blocked","message":"user is blocked"}`,
 		},
 	}
 
@@ -101,11 +130,13 @@ func TestHandler_userIdentify(t *testing.T) {
 
 			// Test Server
 			r := gin.New()
+			r.Use(handler.handleErrors)
 			r.POST("/protected", handler.userIdentify, func(c *gin.Context) {
 				userId, _ := c.Get(userCtx)
-				role, _ := c.Get(userRole)
+				roles, _ := c.Get(userRolesCtx)
+				permissions, _ := c.Get(userPermissionsCtx)
 
-				c.String(200, fmt.Sprintf("user_id:%s, user_role:%s", userId, role))
+				c.String(200, fmt.Sprintf("user_id:%s, user_roles:%s, user_permissions:%s", userId, roles, permissions))
 			})
 
 			// Test Request
@@ -122,3 +153,232 @@ func TestHandler_userIdentify(t *testing.T) {
 		})
 	}
 }
+
+func TestHandler_requirePermission(t *testing.T) {
+	testTable := []struct {
+		name                 string
+		setPermissions       bool
+		permissions          []string
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:                 "OK",
+			setPermissions:       true,
+			permissions:          []string{"files:upload", "products:write"},
+			expectedStatusCode:   200,
+			expectedResponseBody: "ok",
+		},
+
+		{
+			name:                 "Missing Permission",
+			setPermissions:       true,
+			permissions:          []string{"files:upload"},
+			expectedStatusCode:   403,
+			expectedResponseBody: `{"code":"permission_required","message":"permission products:write is required"}`,
+		},
+
+		{
+			name:                 "Unauthorized",
+			expectedStatusCode:   401,
+			expectedResponseBody: `{"code":"unauthorized","message":"you are unauthorized"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			handler := NewHandler(&service.Service{})
+
+			// Test Server
+			r := gin.New()
+			r.Use(handler.handleErrors)
+			r.POST("/protected", func(c *gin.Context) {
+				if testCase.setPermissions {
+					c.Set(userPermissionsCtx, testCase.permissions)
+				}
+			}, handler.requirePermission(domain.PermissionProductsWrite), func(c *gin.Context) {
+				c.String(200, "ok")
+			})
+
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/protected", nil)
+
+			// Perform Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_handleErrors(t *testing.T) {
+	testTable := []struct {
+		name                 string
+		requestId            string
+		err                  error
+		expectedStatusCode   int
+		expectedRequestId    string
+		expectedResponseBody string
+	}{
+		{
+			name:                 "Domain Error",
+			requestId:            "req-1",
+			err:                  domain.ErrEmailTaken,
+			expectedStatusCode:   409,
+			expectedRequestId:    "req-1",
+			expectedResponseBody: `{"code":"email_taken","message":"email is already taken","request_id":"req-1"}`,
+		},
+
+		{
+			name:                 "Wrapped Domain Error",
+			requestId:            "req-2",
+			err:                  fmt.Errorf("%w: token is expired", domain.ErrInvalidAccessToken),
+			expectedStatusCode:   401,
+			expectedRequestId:    "req-2",
+			expectedResponseBody: `{"code":"invalid_access_token","message":"invalid access token: token is expired","request_id":"req-2"}`,
+		},
+
+		{
+			name:                 "Kind Without Code",
+			requestId:            "req-3",
+			err:                  fmt.Errorf("order: %w", domain.ErrConflict),
+			expectedStatusCode:   409,
+			expectedRequestId:    "req-3",
+			expectedResponseBody: `{"code":"conflict","message":"order: conflict","request_id":"req-3"}`,
+		},
+
+		{
+			name:                 "Internal Error",
+			requestId:            "req-4",
+			err:                  errors.New(`pq: relation "products" does not exist`),
+			expectedStatusCode:   500,
+			expectedRequestId:    "req-4",
+			expectedResponseBody: `{"code":"internal_error","message":"internal server error","request_id":"req-4"}`,
+		},
+
+		{
+			name:                 "Invalid Request Id",
+			requestId:            "bad id\n",
+			err:                  domain.ErrProductNotFound,
+			expectedStatusCode:   404,
+			expectedResponseBody: `{"code":"product_not_found","message":"product not found","request_id":"<generated>"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			handler := NewHandler(&service.Service{})
+
+			// Test Server
+			r := gin.New()
+			r.Use(handler.requestId, handler.handleErrors)
+			r.GET("/failing", func(c *gin.Context) {
+				newErrorResponse(c, testCase.err)
+			})
+
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/failing", nil)
+			req.Header.Set("X-Request-ID", testCase.requestId)
+
+			// Perform Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			requestId := w.Header().Get("X-Request-ID")
+			if testCase.expectedRequestId != "" {
+				assert.Equal(t, testCase.expectedRequestId, requestId)
+			} else {
+				assert.Equal(t, 36, len(requestId))
+			}
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, strings.Replace(testCase.expectedResponseBody, "<generated>", requestId, 1), w.Body.String())
+		})
+	}
+}
+
+func TestHandler_validateIds(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockProductsList)
+
+	testTable := []struct {
+		name                 string
+		method               string
+		path                 string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:   "OK",
+			method: "GET",
+			path:   "/api/products/453b4f0f-1f56-4c57-b43d-7b79792450a7",
+			mockBehavior: func(s *mock_service.MockProductsList) {
+				s.EXPECT().GetById("453b4f0f-1f56-4c57-b43d-7b79792450a7", "").Return(domain.ProductsList{}, domain.ErrProductNotFound)
+			},
+			expectedStatusCode:   404,
+			expectedResponseBody: `{"code":"product_not_found","message":"product not found","request_id":"test-request"}`,
+		},
+
+		{
+			name:                 "Not UUID",
+			method:               "GET",
+			path:                 "/api/products/abc",
+			mockBehavior:         func(s *mock_service.MockProductsList) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"code":"invalid_id","message":"id must be a UUID","request_id":"test-request"}`,
+		},
+
+		{
+			name:                 "Braced UUID",
+			method:               "GET",
+			path:                 "/api/products/%7B453b4f0f-1f56-4c57-b43d-7b79792450a7%7D",
+			mockBehavior:         func(s *mock_service.MockProductsList) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"code":"invalid_id","message":"id must be a UUID","request_id":"test-request"}`,
+		},
+
+		{
+			name:                 "Nested Id Not UUID",
+			method:               "DELETE",
+			path:                 "/api/products/453b4f0f-1f56-4c57-b43d-7b79792450a7/variants/1",
+			mockBehavior:         func(s *mock_service.MockProductsList) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"code":"invalid_id","message":"id must be a UUID","request_id":"test-request"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			product := mock_service.NewMockProductsList(c)
+			testCase.mockBehavior(product)
+
+			services := &service.Service{ProductsList: product}
+			handler := NewHandler(services)
+
+			// Test Server
+			r, err := handler.InitRouter(nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(testCase.method, testCase.path, nil)
+			req.Header.Set("X-Request-ID", "test-request")
+
+			// Perform Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedResponseBody, w.Body.String())
+		})
+	}
+}
@@ -0,0 +1,215 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/AndrewMislyuk/go-shop-backend/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Checkout
+// @Security ApiKeyAuth
+// @Tags Orders
+// @Description turn the cart into a pending order, its stock is reserved until the order is paid
+// @ID checkout
+// @Accept  json
+// @Produce  json
+// @Param input body domain.CheckoutInput false "Promo code"
+// @Success 200 {object} domain.Order
+// @Failure 400,401,404,409 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/orders [post]
+func (h *Handler) checkout(c *gin.Context) {
+	// The body is optional, an order without a promo code needs none.
+	var input domain.CheckoutInput
+	if err := c.ShouldBindJSON(&input); err != nil && !errors.Is(err, io.EOF) {
+		newErrorResponse(c, domain.NewValidationError(err))
+
+		return
+	}
+
+	order, err := h.ordersService.Checkout(c.GetString(userCtx), input)
+	if err != nil {
+		newErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, order)
+}
+
+// @Summary Get My Orders
+// @Security ApiKeyAuth
+// @Tags Orders
+// @Description orders of the current user, newest first
+// @ID get-my-orders
+// @Accept  json
+// @Produce  json
+// @Param status query string false "Order status"
+// @Param limit query int false "Page size"
+// @Param offset query int false "Page offset"
+// @Success 200 {object} domain.OrdersList
+// @Failure 400,401 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/users/me/orders [get]
+func (h *Handler) getMyOrders(c *gin.Context) {
+	var filter domain.OrdersFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		newErrorResponse(c, domain.NewValidationError(err))
+
+		return
+	}
+
+	orders, err := h.ordersService.GetMine(c.GetString(userCtx), filter)
+	if err != nil {
+		newErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, orders)
+}
+
+// @Summary Get My Order By Id
+// @Security ApiKeyAuth
+// @Tags Orders
+// @Description order of the current user with its items and status history
+// @ID get-my-order-by-id
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Order ID"
+// @Success 200 {object} domain.Order
+// @Failure 401,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/users/me/orders/{id} [get]
+func (h *Handler) getMyOrderById(c *gin.Context) {
+	order, err := h.ordersService.GetMineById(c.GetString(userCtx), c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, order)
+}
+
+// @Summary Cancel My Order
+// @Security ApiKeyAuth
+// @Tags Orders
+// @Description cancel an order of the current user that hasn't been paid yet
+// @ID cancel-my-order
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Order ID"
+// @Success 200 {object} statusResponse
+// @Failure 401,404,409 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/users/me/orders/{id}/cancel [post]
+func (h *Handler) cancelMyOrder(c *gin.Context) {
+	if err := h.ordersService.CancelMine(c.GetString(userCtx), c.Param("id")); err != nil {
+		newErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{
+		Status: "ok",
+	})
+}
+
+// @Summary Get All Orders
+// @Security ApiKeyAuth
+// @Tags Orders
+// @Description orders of all users, newest first
+// @ID get-all-orders
+// @Accept  json
+// @Produce  json
+// @Param user_id query string false "User ID"
+// @Param status query string false "Order status"
+// @Param limit query int false "Page size"
+// @Param offset query int false "Page offset"
+// @Success 200 {object} domain.OrdersList
+// @Failure 400,401,403 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/orders [get]
+func (h *Handler) getAllOrders(c *gin.Context) {
+	var filter domain.OrdersFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		newErrorResponse(c, domain.NewValidationError(err))
+
+		return
+	}
+
+	orders, err := h.ordersService.GetAll(filter)
+	if err != nil {
+		newErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, orders)
+}
+
+// @Summary Get Order By Id
+// @Security ApiKeyAuth
+// @Tags Orders
+// @Description order with its items and status history
+// @ID get-order-by-id
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Order ID"
+// @Success 200 {object} domain.Order
+// @Failure 401,403,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/orders/{id} [get]
+func (h *Handler) getOrderById(c *gin.Context) {
+	order, err := h.ordersService.GetById(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, order)
+}
+
+// @Summary Change Order Status
+// @Security ApiKeyAuth
+// @Tags Orders
+// @Description advance or cancel the order, only transitions of the order lifecycle are allowed. Paid and refunded are set by the payment of the order
+// @ID change-order-status
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Order ID"
+// @Param input body domain.ChangeOrderStatusInput true "New status"
+// @Success 200 {object} statusResponse
+// @Failure 400,401,403,404,409 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/orders/{id}/status [put]
+func (h *Handler) changeOrderStatus(c *gin.Context) {
+	var input domain.ChangeOrderStatusInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		newErrorResponse(c, domain.NewValidationError(err))
+
+		return
+	}
+
+	if err := h.ordersService.ChangeStatus(c.GetString(userCtx), c.Param("id"), input); err != nil {
+		newErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{
+		Status: "ok",
+	})
+}
@@ -0,0 +1,320 @@
+package handler
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/AndrewMislyuk/go-shop-backend/internal/domain"
+	"github.com/AndrewMislyuk/go-shop-backend/internal/service"
+	mock_service "github.com/AndrewMislyuk/go-shop-backend/internal/service/mock"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/magiconair/properties/assert"
+)
+
+func TestHandler_checkout(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockOrders, userId string, input domain.CheckoutInput)
+
+	createdAt := time.Date(2022, 01, 12, 13, 17, 58, 0, time.UTC)
+	expiresAt := createdAt.Add(time.Hour)
+	productId := "453b4f0f-1f56-4c57-b43d-7b79792450a7"
+
+	testTable := []struct {
+		name                string
+		inputBody           string
+		input               domain.CheckoutInput
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name: "OK",
+			mockBehavior: func(s *mock_service.MockOrders, userId string, input domain.CheckoutInput) {
+				s.EXPECT().Checkout(userId, input).Return(domain.Order{
+					Id:     "5c2f1a9e-7d3b-4e8a-9f61-0a2b3c4d5e6f",
+					Number: 1001,
+					UserId: userId,
+					Status: domain.OrderPending,
+					Items: []domain.OrderItem{
+						{
+							Id:           "a1b2c3d4-e5f6-4a7b-8c9d-0e1f2a3b4c5d",
+							ProductId:    &productId,
+							Title:        "Твидовый кардиган из хлопка",
+							Price:        domain.NewMoney(4990, "RUB"),
+							SaleOldPrice: domain.NewMoney(0, "RUB"),
+							Quantity:     2,
+							LineTotal:    domain.NewMoney(9980, "RUB"),
+						},
+					},
+					ItemsCount: 2,
+					Subtotal:   domain.NewMoney(9980, "RUB"),
+					Discount:   domain.NewMoney(0, "RUB"),
+					Total:      domain.NewMoney(9980, "RUB"),
+					ExpiresAt:  &expiresAt,
+					CreatedAt:  createdAt,
+				}, nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"id":"5c2f1a9e-7d3b-4e8a-9f61-0a2b3c4d5e6f","number":1001,"user_id":"e7a9c2d4-5f3b-4a61-8c0e-2b9d7f1a3e55","status":"pending","items":[{"id":"a1b2c3d4-e5f6-4a7b-8c9d-0e1f2a3b4c5d","product_id":"453b4f0f-1f56-4c57-b43d-7b79792450a7","variant_id":null,"title":"Твидовый кардиган из хлопка","price":{"amount":4990,"currency":"RUB"},"sale":0,"sale_old_price":{"amount":0,"currency":"RUB"},"quantity":2,"line_total":{"amount":9980,"currency":"RUB"}}],"items_count":2,"subtotal":{"amount":9980,"currency":"RUB"},"discount":{"amount":0,"currency":"RUB"},"total":{"amount":9980,"currency":"RUB"},"expires_at":"2022-01-12T14:17:58Z","created_at":"2022-01-12T13:17:58Z"}`,
+		},
+
+		{
+			name:      "Promo Code",
+			inputBody: `{"promo_code":"spring10"}`,
+			input:     domain.CheckoutInput{PromoCode: "spring10"},
+			mockBehavior: func(s *mock_service.MockOrders, userId string, input domain.CheckoutInput) {
+				s.EXPECT().Checkout(userId, input).Return(domain.Order{
+					Id:         "5c2f1a9e-7d3b-4e8a-9f61-0a2b3c4d5e6f",
+					Number:     1002,
+					UserId:     userId,
+					Status:     domain.OrderPending,
+					ItemsCount: 2,
+					Subtotal:   domain.NewMoney(9980, "RUB"),
+					Discount:   domain.NewMoney(998, "RUB"),
+					PromoCode:  "SPRING10",
+					Total:      domain.NewMoney(8982, "RUB"),
+					ExpiresAt:  &expiresAt,
+					CreatedAt:  createdAt,
+				}, nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"id":"5c2f1a9e-7d3b-4e8a-9f61-0a2b3c4d5e6f","number":1002,"user_id":"e7a9c2d4-5f3b-4a61-8c0e-2b9d7f1a3e55","status":"pending","items_count":2,"subtotal":{"amount":9980,"currency":"RUB"},"discount":{"amount":998,"currency":"RUB"},"promo_code":"SPRING10","total":{"amount":8982,"currency":"RUB"},"expires_at":"2022-01-12T14:17:58Z","created_at":"2022-01-12T13:17:58Z"}`,
+		},
+
+		{
+			name:      "Promo Code Used Up",
+			inputBody: `{"promo_code":"spring10"}`,
+			input:     domain.CheckoutInput{PromoCode: "spring10"},
+			mockBehavior: func(s *mock_service.MockOrders, userId string, input domain.CheckoutInput) {
+				s.EXPECT().Checkout(userId, input).Return(domain.Order{}, domain.ErrPromoCodeUsedUp)
+			},
+			expectedStatusCode:  409,
+			expectedRequestBody: `{"code":"promo_code_used_up","message":"promo code has been used up"}`,
+		},
+
+		{
+			name:                "Invalid Body",
+			inputBody:           `{"promo_code":1}`,
+			mockBehavior:        func(s *mock_service.MockOrders, userId string, input domain.CheckoutInput) {},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"code":"invalid_input","message":"json: cannot unmarshal number into Go struct field CheckoutInput.promo_code of type string"}`,
+		},
+
+		{
+			name: "Empty Cart",
+			mockBehavior: func(s *mock_service.MockOrders, userId string, input domain.CheckoutInput) {
+				s.EXPECT().Checkout(userId, input).Return(domain.Order{}, domain.ErrCartEmpty)
+			},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"code":"cart_empty","message":"cart is empty"}`,
+		},
+
+		{
+			name: "Prices Changed",
+			mockBehavior: func(s *mock_service.MockOrders, userId string, input domain.CheckoutInput) {
+				s.EXPECT().Checkout(userId, input).Return(domain.Order{}, domain.ErrCartPricesChanged)
+			},
+			expectedStatusCode:  409,
+			expectedRequestBody: `{"code":"cart_prices_changed","message":"prices in the cart have changed, review the cart before checkout"}`,
+		},
+
+		{
+			name: "Out Of Stock",
+			mockBehavior: func(s *mock_service.MockOrders, userId string, input domain.CheckoutInput) {
+				s.EXPECT().Checkout(userId, input).Return(domain.Order{}, domain.ErrInsufficientStock)
+			},
+			expectedStatusCode:  409,
+			expectedRequestBody: `{"code":"insufficient_stock","message":"not enough stock available"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			userId := "e7a9c2d4-5f3b-4a61-8c0e-2b9d7f1a3e55"
+
+			orders := mock_service.NewMockOrders(c)
+			testCase.mockBehavior(orders, userId, testCase.input)
+
+			services := &service.Service{Orders: orders}
+			handler := NewHandler(services)
+
+			// Test Server
+			r := gin.New()
+			r.Use(handler.handleErrors)
+			r.POST("/orders", func(c *gin.Context) {
+				c.Set(userCtx, userId)
+			}, handler.checkout)
+
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/orders", bytes.NewBufferString(testCase.inputBody))
+
+			// Perform Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedRequestBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_getMyOrderById(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockOrders, userId, orderId string)
+
+	testTable := []struct {
+		name                string
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name: "Order Of Another User",
+			mockBehavior: func(s *mock_service.MockOrders, userId, orderId string) {
+				s.EXPECT().GetMineById(userId, orderId).Return(domain.Order{}, domain.ErrOrderNotFound)
+			},
+			expectedStatusCode:  404,
+			expectedRequestBody: `{"code":"order_not_found","message":"order not found"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			userId := "e7a9c2d4-5f3b-4a61-8c0e-2b9d7f1a3e55"
+			orderId := "5c2f1a9e-7d3b-4e8a-9f61-0a2b3c4d5e6f"
+
+			orders := mock_service.NewMockOrders(c)
+			testCase.mockBehavior(orders, userId, orderId)
+
+			services := &service.Service{Orders: orders}
+			handler := NewHandler(services)
+
+			// Test Server
+			r := gin.New()
+			r.Use(handler.handleErrors)
+			r.GET("/users/me/orders/:id", func(c *gin.Context) {
+				c.Set(userCtx, userId)
+			}, handler.getMyOrderById)
+
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/users/me/orders/"+orderId, nil)
+
+			// Perform Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedRequestBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_changeOrderStatus(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockOrders, actorId, orderId string, input domain.ChangeOrderStatusInput)
+
+	testTable := []struct {
+		name                string
+		inputBody           string
+		input               domain.ChangeOrderStatusInput
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name:      "OK",
+			inputBody: `{"status":"shipped","comment":"tracking 1Z999"}`,
+			input:     domain.ChangeOrderStatusInput{Status: domain.OrderShipped, Comment: "tracking 1Z999"},
+			mockBehavior: func(s *mock_service.MockOrders, actorId, orderId string, input domain.ChangeOrderStatusInput) {
+				s.EXPECT().ChangeStatus(actorId, orderId, input).Return(nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"status":"ok"}`,
+		},
+
+		{
+			name:                "Missing Status",
+			inputBody:           `{"comment":"tracking 1Z999"}`,
+			mockBehavior:        func(s *mock_service.MockOrders, actorId, orderId string, input domain.ChangeOrderStatusInput) {},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"code":"invalid_input","message":"Key: 'ChangeOrderStatusInput.Status' Error:Field validation for 'Status' failed on the 'required' tag"}`,
+		},
+
+		{
+			name:      "Invalid Transition",
+			inputBody: `{"status":"delivered"}`,
+			input:     domain.ChangeOrderStatusInput{Status: domain.OrderDelivered},
+			mockBehavior: func(s *mock_service.MockOrders, actorId, orderId string, input domain.ChangeOrderStatusInput) {
+				s.EXPECT().ChangeStatus(actorId, orderId, input).Return(domain.ErrInvalidOrderTransition)
+			},
+			expectedStatusCode:  409,
+			expectedRequestBody: `{"code":"invalid_order_transition","message":"order can not be moved to this status"}`,
+		},
+
+		{
+			name:      "Unknown Status",
+			inputBody: `{"status":"lost"}`,
+			input:     domain.ChangeOrderStatusInput{Status: "lost"},
+			mockBehavior: func(s *mock_service.MockOrders, actorId, orderId string, input domain.ChangeOrderStatusInput) {
+				s.EXPECT().ChangeStatus(actorId, orderId, input).Return(domain.ErrUnknownOrderStatus)
+			},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"code":"unknown_order_status","message":"order status must be one of pending, paid, shipped, delivered, cancelled, refunded"}`,
+		},
+		{
+			name:      "Set By Payment",
+			inputBody: `{"status":"refunded"}`,
+			input:     domain.ChangeOrderStatusInput{Status: domain.OrderRefunded},
+			mockBehavior: func(s *mock_service.MockOrders, actorId, orderId string, input domain.ChangeOrderStatusInput) {
+				s.EXPECT().ChangeStatus(actorId, orderId, input).Return(domain.ErrOrderStatusByPayment)
+			},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"code":"order_status_by_payment","message":"paid and refunded are set by the payment of the order"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			actorId := "e7a9c2d4-5f3b-4a61-8c0e-2b9d7f1a3e55"
+			orderId := "5c2f1a9e-7d3b-4e8a-9f61-0a2b3c4d5e6f"
+
+			orders := mock_service.NewMockOrders(c)
+			testCase.mockBehavior(orders, actorId, orderId, testCase.input)
+
+			services := &service.Service{Orders: orders}
+			handler := NewHandler(services)
+
+			// Test Server
+			r := gin.New()
+			r.Use(handler.handleErrors)
+			r.PUT("/orders/:id/status", func(c *gin.Context) {
+				c.Set(userCtx, actorId)
+			}, handler.changeOrderStatus)
+
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("PUT", "/orders/"+orderId+"/status", bytes.NewBufferString(testCase.inputBody))
+
+			// Perform Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedRequestBody, w.Body.String())
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/AndrewMislyuk/go-shop-backend/internal/domain"
+	"github.com/AndrewMislyuk/go-shop-backend/pkg/payment"
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Pay Order
+// @Security ApiKeyAuth
+// @Tags Payments
+// @Description pay a pending order of the current user, the order becomes paid once the payment is captured
+// @ID pay-order
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Order ID"
+// @Param input body domain.PayOrderInput true "Payment method"
+// @Success 200 {object} domain.Payment
+// @Failure 400,401,402,404,409 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/users/me/orders/{id}/pay [post]
+func (h *Handler) payOrder(c *gin.Context) {
+	var input domain.PayOrderInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		newErrorResponse(c, domain.NewValidationError(err))
+
+		return
+	}
+
+	p, err := h.paymentsService.Pay(c.GetString(userCtx), c.Param("id"), input)
+	if err != nil {
+		newErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, p)
+}
+
+// @Summary Refund Order
+// @Security ApiKeyAuth
+// @Tags Payments
+// @Description return the captured payment of the order and refund the order
+// @ID refund-order
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Order ID"
+// @Success 200 {object} statusResponse
+// @Failure 401,403,404,409 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/orders/{id}/refund [post]
+func (h *Handler) refundOrder(c *gin.Context) {
+	if err := h.paymentsService.Refund(c.GetString(userCtx), c.Param("id")); err != nil {
+		newErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{
+		Status: "ok",
+	})
+}
+
+// @Summary Payment Webhook
+// @Tags Payments
+// @Description notification of the payment provider, signed in the X-Payment-Signature header
+// @ID payment-webhook
+// @Accept  json
+// @Produce  json
+// @Param X-Payment-Signature header string true "Signature of the payload"
+// @Success 200 {object} statusResponse
+// @Failure 400 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/payments/webhook [post]
+func (h *Handler) paymentWebhook(c *gin.Context) {
+	payload, err := c.GetRawData()
+	if err != nil {
+		newErrorResponse(c, domain.NewValidationError(err))
+
+		return
+	}
+
+	if err := h.paymentsService.HandleWebhook(payload, c.GetHeader(payment.SignatureHeader)); err != nil {
+		newErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{
+		Status: "ok",
+	})
+}
@@ -0,0 +1,181 @@
+package handler
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/AndrewMislyuk/go-shop-backend/internal/domain"
+	"github.com/AndrewMislyuk/go-shop-backend/internal/service"
+	mock_service "github.com/AndrewMislyuk/go-shop-backend/internal/service/mock"
+	"github.com/AndrewMislyuk/go-shop-backend/pkg/payment"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/magiconair/properties/assert"
+)
+
+func TestHandler_payOrder(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockPayments, userId, orderId string, input domain.PayOrderInput)
+
+	createdAt := time.Date(2022, 01, 12, 13, 17, 58, 0, time.UTC)
+
+	testTable := []struct {
+		name                string
+		inputBody           string
+		input               domain.PayOrderInput
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name:      "OK",
+			inputBody: `{"payment_method":"4242424242424242"}`,
+			input:     domain.PayOrderInput{PaymentMethod: payment.CardSucceeds},
+			mockBehavior: func(s *mock_service.MockPayments, userId, orderId string, input domain.PayOrderInput) {
+				s.EXPECT().Pay(userId, orderId, input).Return(domain.Payment{
+					Id:        "3e4f5a6b-7c8d-4e9f-a0b1-c2d3e4f5a6b7",
+					OrderId:   orderId,
+					Provider:  "fake",
+					IntentId:  "pi_1",
+					Amount:    9980,
+					Currency:  "RUB",
+					Status:    domain.PaymentCaptured,
+					CreatedAt: createdAt,
+				}, nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"id":"3e4f5a6b-7c8d-4e9f-a0b1-c2d3e4f5a6b7","order_id":"5c2f1a9e-7d3b-4e8a-9f61-0a2b3c4d5e6f","provider":"fake","intent_id":"pi_1","amount":9980,"currency":"RUB","status":"captured","created_at":"2022-01-12T13:17:58Z"}`,
+		},
+
+		{
+			name:                "Missing Payment Method",
+			inputBody:           `{}`,
+			mockBehavior:        func(s *mock_service.MockPayments, userId, orderId string, input domain.PayOrderInput) {},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"code":"invalid_input","message":"Key: 'PayOrderInput.PaymentMethod' Error:Field validation for 'PaymentMethod' failed on the 'required' tag"}`,
+		},
+
+		{
+			name:      "Declined",
+			inputBody: `{"payment_method":"4000000000000002"}`,
+			input:     domain.PayOrderInput{PaymentMethod: payment.CardDeclined},
+			mockBehavior: func(s *mock_service.MockPayments, userId, orderId string, input domain.PayOrderInput) {
+				s.EXPECT().Pay(userId, orderId, input).Return(domain.Payment{}, domain.NewPaymentDeclinedError("card_declined"))
+			},
+			expectedStatusCode:  402,
+			expectedRequestBody: `{"code":"payment_declined","message":"payment was declined: card_declined"}`,
+		},
+
+		{
+			name:      "Already Paid",
+			inputBody: `{"payment_method":"4242424242424242"}`,
+			input:     domain.PayOrderInput{PaymentMethod: payment.CardSucceeds},
+			mockBehavior: func(s *mock_service.MockPayments, userId, orderId string, input domain.PayOrderInput) {
+				s.EXPECT().Pay(userId, orderId, input).Return(domain.Payment{}, domain.ErrOrderNotPayable)
+			},
+			expectedStatusCode:  409,
+			expectedRequestBody: `{"code":"order_not_payable","message":"only pending orders can be paid before they expire"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			userId := "e7a9c2d4-5f3b-4a61-8c0e-2b9d7f1a3e55"
+			orderId := "5c2f1a9e-7d3b-4e8a-9f61-0a2b3c4d5e6f"
+
+			payments := mock_service.NewMockPayments(c)
+			testCase.mockBehavior(payments, userId, orderId, testCase.input)
+
+			services := &service.Service{Payments: payments}
+			handler := NewHandler(services)
+
+			// Test Server
+			r := gin.New()
+			r.Use(handler.handleErrors)
+			r.POST("/users/me/orders/:id/pay", func(c *gin.Context) {
+				c.Set(userCtx, userId)
+			}, handler.payOrder)
+
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/users/me/orders/"+orderId+"/pay", bytes.NewBufferString(testCase.inputBody))
+
+			// Perform Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedRequestBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_paymentWebhook(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockPayments, payload []byte, signature string)
+
+	payload := `{"id":"evt_1","type":"payment.succeeded","intent_id":"pi_1","amount":9980,"created_at":"2022-01-12T13:17:58Z"}`
+
+	testTable := []struct {
+		name                string
+		signature           string
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name:      "OK",
+			signature: "t=1642000000,v1=abc",
+			mockBehavior: func(s *mock_service.MockPayments, payload []byte, signature string) {
+				s.EXPECT().HandleWebhook(payload, signature).Return(nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"status":"ok"}`,
+		},
+
+		{
+			name:      "Invalid Signature",
+			signature: "t=1642000000,v1=bad",
+			mockBehavior: func(s *mock_service.MockPayments, payload []byte, signature string) {
+				s.EXPECT().HandleWebhook(payload, signature).Return(domain.ErrInvalidWebhook)
+			},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"code":"invalid_webhook","message":"webhook signature is invalid"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			payments := mock_service.NewMockPayments(c)
+			testCase.mockBehavior(payments, []byte(payload), testCase.signature)
+
+			services := &service.Service{Payments: payments}
+			handler := NewHandler(services)
+
+			// Test Server
+			r := gin.New()
+			r.Use(handler.handleErrors)
+			r.POST("/payments/webhook", handler.paymentWebhook)
+
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/payments/webhook", bytes.NewBufferString(payload))
+			req.Header.Set(payment.SignatureHeader, testCase.signature)
+
+			// Perform Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedRequestBody, w.Body.String())
+		})
+	}
+}
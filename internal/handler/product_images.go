@@ -0,0 +1,192 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/AndrewMislyuk/go-shop-backend/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+type getProductImagesResponse struct {
+	Data []domain.File `json:"data"`
+}
+
+// @Summary Get Product Images
+// @Tags Product
+// @Description get the gallery of the product in display order
+// @ID get-product-images
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Product ID"
+// @Success 200 {object} getProductImagesResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/products/{id}/images [get]
+func (h *Handler) getProductImages(c *gin.Context) {
+	images, err := h.fileService.GetImages(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, getProductImagesResponse{
+		Data: images,
+	})
+}
+
+// @Summary Add Product Image
+// @Security ApiKeyAuth
+// @Tags Product
+// @Description queue an image for the end of the gallery, the first image of a product becomes its primary one. The image joins the gallery once stored, see GET /api/file/{id}
+// @ID add-product-image
+// @Accept  multipart/form-data
+// @Produce  json
+// @Param id path string true "Product ID"
+// @Param file formData file true "file"
+// @Param alt_text formData string false "Alt text"
+// @Success 200 {object} domain.File
+// @Failure 400,401,403,404,413,429 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/products/{id}/images [post]
+func (h *Handler) addProductImage(c *gin.Context) {
+	file, values, err := h.receiveImage(c)
+	if err != nil {
+		newErrorResponse(c, err)
+
+		return
+	}
+
+	file.ProductId = c.Param("id")
+	file.AltText = values.Get("alt_text")
+
+	image, err := h.fileService.Upload(file)
+	if err != nil {
+		newErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, image)
+}
+
+// @Summary Reorder Product Images
+// @Security ApiKeyAuth
+// @Tags Product
+// @Description set the display order of the gallery, the ids of all the images of the product in the new order
+// @ID reorder-product-images
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Product ID"
+// @Param input body domain.ReorderImagesInput true "Image ids"
+// @Success 200 {object} statusResponse
+// @Failure 400,401,403,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/products/{id}/images [put]
+func (h *Handler) reorderProductImages(c *gin.Context) {
+	var input domain.ReorderImagesInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		newErrorResponse(c, domain.NewValidationError(err))
+
+		return
+	}
+
+	if err := h.fileService.ReorderImages(c.Param("id"), input); err != nil {
+		newErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{
+		Status: "ok",
+	})
+}
+
+// @Summary Update Product Image
+// @Security ApiKeyAuth
+// @Tags Product
+// @Description update the alt text of the image
+// @ID update-product-image
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Product ID"
+// @Param imageId path string true "Image ID"
+// @Param input body domain.UpdateImageInput true "Image info"
+// @Success 200 {object} statusResponse
+// @Failure 400,401,403,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/products/{id}/images/{imageId} [put]
+func (h *Handler) updateProductImage(c *gin.Context) {
+	var input domain.UpdateImageInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		newErrorResponse(c, domain.NewValidationError(err))
+
+		return
+	}
+
+	if err := h.fileService.UpdateImage(c.Param("id"), c.Param("imageId"), input); err != nil {
+		newErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{
+		Status: "ok",
+	})
+}
+
+// @Summary Set Primary Product Image
+// @Security ApiKeyAuth
+// @Tags Product
+// @Description make the image the primary one, it stands for the product in the lists
+// @ID set-primary-product-image
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Product ID"
+// @Param imageId path string true "Image ID"
+// @Success 200 {object} statusResponse
+// @Failure 401,403,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/products/{id}/images/{imageId}/primary [put]
+func (h *Handler) setPrimaryProductImage(c *gin.Context) {
+	if err := h.fileService.SetPrimaryImage(c.Param("id"), c.Param("imageId")); err != nil {
+		newErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{
+		Status: "ok",
+	})
+}
+
+// @Summary Delete Product Image
+// @Security ApiKeyAuth
+// @Tags Product
+// @Description remove the image from the gallery, the next image becomes primary in place of a removed primary one
+// @ID delete-product-image
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Product ID"
+// @Param imageId path string true "Image ID"
+// @Success 200 {object} statusResponse
+// @Failure 401,403,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/products/{id}/images/{imageId} [delete]
+func (h *Handler) deleteProductImage(c *gin.Context) {
+	if err := h.fileService.DeleteImage(c.Param("id"), c.Param("imageId")); err != nil {
+		newErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{
+		Status: "ok",
+	})
+}
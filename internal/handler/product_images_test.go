@@ -0,0 +1,176 @@
+package handler
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/AndrewMislyuk/go-shop-backend/internal/domain"
+	"github.com/AndrewMislyuk/go-shop-backend/internal/service"
+	mock_service "github.com/AndrewMislyuk/go-shop-backend/internal/service/mock"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/magiconair/properties/assert"
+)
+
+func TestHandler_reorderProductImages(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockFiles, productId string, input domain.ReorderImagesInput)
+
+	productId := "f5e7d3a2-9c1b-4e8d-a6f4-3b2c1d0e9f8a"
+
+	testTable := []struct {
+		name                string
+		inputBody           string
+		input               domain.ReorderImagesInput
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name:      "OK",
+			inputBody: `{"ids":["b07221f8-4133-4688-b2d6-d677f41f5b74","0a8d4c3e-5b6f-4e7a-9c1d-2f3e4a5b6c7d"]}`,
+			input: domain.ReorderImagesInput{
+				Ids: []string{"b07221f8-4133-4688-b2d6-d677f41f5b74", "0a8d4c3e-5b6f-4e7a-9c1d-2f3e4a5b6c7d"},
+			},
+			mockBehavior: func(s *mock_service.MockFiles, productId string, input domain.ReorderImagesInput) {
+				s.EXPECT().ReorderImages(productId, input).Return(nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"status":"ok"}`,
+		},
+
+		{
+			name:                "Empty Fields",
+			inputBody:           `{}`,
+			mockBehavior:        func(s *mock_service.MockFiles, productId string, input domain.ReorderImagesInput) {},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"code":"invalid_input","message":"Key: 'ReorderImagesInput.Ids' Error:Field validation for 'Ids' failed on the 'required' tag"}`,
+		},
+
+		{
+			name:                "Invalid Id",
+			inputBody:           `{"ids":["b07221f8-4133-4688-b2d6-d677f41f5b74","1"]}`,
+			mockBehavior:        func(s *mock_service.MockFiles, productId string, input domain.ReorderImagesInput) {},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"code":"invalid_input","message":"Key: 'ReorderImagesInput.Ids[1]' Error:Field validation for 'Ids[1]' failed on the 'uuid' tag"}`,
+		},
+
+		{
+			name:      "Invalid Order",
+			inputBody: `{"ids":["b07221f8-4133-4688-b2d6-d677f41f5b74"]}`,
+			input: domain.ReorderImagesInput{
+				Ids: []string{"b07221f8-4133-4688-b2d6-d677f41f5b74"},
+			},
+			mockBehavior: func(s *mock_service.MockFiles, productId string, input domain.ReorderImagesInput) {
+				s.EXPECT().ReorderImages(productId, input).Return(domain.ErrInvalidImagesOrder)
+			},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"code":"invalid_images_order","message":"the order must list every image of the product exactly once"}`,
+		},
+
+		{
+			name:      "Product Not Found",
+			inputBody: `{"ids":["b07221f8-4133-4688-b2d6-d677f41f5b74"]}`,
+			input: domain.ReorderImagesInput{
+				Ids: []string{"b07221f8-4133-4688-b2d6-d677f41f5b74"},
+			},
+			mockBehavior: func(s *mock_service.MockFiles, productId string, input domain.ReorderImagesInput) {
+				s.EXPECT().ReorderImages(productId, input).Return(domain.ErrProductNotFound)
+			},
+			expectedStatusCode:  404,
+			expectedRequestBody: `{"code":"product_not_found","message":"product not found"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			files := mock_service.NewMockFiles(c)
+			testCase.mockBehavior(files, productId, testCase.input)
+
+			services := &service.Service{Files: files}
+			handler := NewHandler(services)
+
+			// Test Server
+			r := gin.New()
+			r.Use(handler.handleErrors)
+			r.PUT("/products/:id/images", handler.reorderProductImages)
+
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("PUT", "/products/"+productId+"/images", bytes.NewBufferString(testCase.inputBody))
+
+			// Perform Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedRequestBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_deleteProductImage(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockFiles, productId, imageId string)
+
+	productId := "f5e7d3a2-9c1b-4e8d-a6f4-3b2c1d0e9f8a"
+	imageId := "0a8d4c3e-5b6f-4e7a-9c1d-2f3e4a5b6c7d"
+
+	testTable := []struct {
+		name                string
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name: "OK",
+			mockBehavior: func(s *mock_service.MockFiles, productId, imageId string) {
+				s.EXPECT().DeleteImage(productId, imageId).Return(nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"status":"ok"}`,
+		},
+
+		{
+			name: "Not Found",
+			mockBehavior: func(s *mock_service.MockFiles, productId, imageId string) {
+				s.EXPECT().DeleteImage(productId, imageId).Return(domain.ErrImageNotFound)
+			},
+			expectedStatusCode:  404,
+			expectedRequestBody: `{"code":"image_not_found","message":"image not found"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			files := mock_service.NewMockFiles(c)
+			testCase.mockBehavior(files, productId, imageId)
+
+			services := &service.Service{Files: files}
+			handler := NewHandler(services)
+
+			// Test Server
+			r := gin.New()
+			r.Use(handler.handleErrors)
+			r.DELETE("/products/:id/images/:imageId", handler.deleteProductImage)
+
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("DELETE", "/products/"+productId+"/images/"+imageId, nil)
+
+			// Perform Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedRequestBody, w.Body.String())
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/AndrewMislyuk/go-shop-backend/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+type getProductPricesResponse struct {
+	Data []domain.ProductPrice `json:"data"`
+}
+
+// @Summary Get Product Prices
+// @Security ApiKeyAuth
+// @Tags Product
+// @Description get the prices listed for the product in currencies other than the base one
+// @ID get-product-prices
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Product ID"
+// @Success 200 {object} getProductPricesResponse
+// @Failure 401,403,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/products/{id}/prices [get]
+func (h *Handler) getProductPrices(c *gin.Context) {
+	prices, err := h.productPricesService.GetByProduct(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, getProductPricesResponse{
+		Data: prices,
+	})
+}
+
+// @Summary Set Product Price
+// @Security ApiKeyAuth
+// @Tags Product
+// @Description list the regular price of the product in a currency, it is used instead of the exchange rate
+// @ID set-product-price
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Product ID"
+// @Param currency path string true "ISO 4217 code"
+// @Param input body domain.SetProductPriceInput true "Price in minor units"
+// @Success 200 {object} statusResponse
+// @Failure 400,401,403,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/products/{id}/prices/{currency} [put]
+func (h *Handler) setProductPrice(c *gin.Context) {
+	var input domain.SetProductPriceInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		newErrorResponse(c, domain.NewValidationError(err))
+
+		return
+	}
+
+	if err := h.productPricesService.Set(c.Param("id"), c.Param("currency"), input); err != nil {
+		newErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{
+		Status: "ok",
+	})
+}
+
+// @Summary Delete Product Price
+// @Security ApiKeyAuth
+// @Tags Product
+// @Description remove the price of the product in a currency, it is converted at the exchange rate again
+// @ID delete-product-price
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Product ID"
+// @Param currency path string true "ISO 4217 code"
+// @Success 200 {object} statusResponse
+// @Failure 400,401,403,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/products/{id}/prices/{currency} [delete]
+func (h *Handler) deleteProductPrice(c *gin.Context) {
+	if err := h.productPricesService.Delete(c.Param("id"), c.Param("currency")); err != nil {
+		newErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{
+		Status: "ok",
+	})
+}
@@ -0,0 +1,131 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/AndrewMislyuk/go-shop-backend/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+type getProductVariantsResponse struct {
+	Data []domain.ProductVariant `json:"data"`
+}
+
+// @Summary Get Product Variants
+// @Tags Product
+// @Description get variants of the product
+// @ID get-product-variants
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Product ID"
+// @Success 200 {object} getProductVariantsResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/products/{id}/variants [get]
+func (h *Handler) getProductVariants(c *gin.Context) {
+	variants, err := h.variantsService.GetByProduct(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, getProductVariantsResponse{
+		Data: variants,
+	})
+}
+
+// @Summary Create Product Variant
+// @Security ApiKeyAuth
+// @Tags Product
+// @Description add a variant (size, colour, ...) to the product
+// @ID create-product-variant
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Product ID"
+// @Param input body domain.CreateVariantInput true "Variant info"
+// @Success 200 {object} getCreationId
+// @Failure 400,401,403,404,409 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/products/{id}/variants [post]
+func (h *Handler) createProductVariant(c *gin.Context) {
+	var input domain.CreateVariantInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		newErrorResponse(c, domain.NewValidationError(err))
+
+		return
+	}
+
+	id, err := h.variantsService.Create(c.Param("id"), input)
+	if err != nil {
+		newErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, getCreationId{
+		Id: id,
+	})
+}
+
+// @Summary Update Product Variant
+// @Security ApiKeyAuth
+// @Tags Product
+// @Description update SKU, options, price or stock of the variant
+// @ID update-product-variant
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Product ID"
+// @Param variantId path string true "Variant ID"
+// @Param input body domain.UpdateVariantInput true "Variant info"
+// @Success 200 {object} statusResponse
+// @Failure 400,401,403,404,409 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/products/{id}/variants/{variantId} [put]
+func (h *Handler) updateProductVariant(c *gin.Context) {
+	var input domain.UpdateVariantInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		newErrorResponse(c, domain.NewValidationError(err))
+
+		return
+	}
+
+	if err := h.variantsService.Update(c.Param("id"), c.Param("variantId"), input); err != nil {
+		newErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{
+		Status: "ok",
+	})
+}
+
+// @Summary Delete Product Variant
+// @Security ApiKeyAuth
+// @Tags Product
+// @Description delete the variant
+// @ID delete-product-variant
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Product ID"
+// @Param variantId path string true "Variant ID"
+// @Success 200 {object} statusResponse
+// @Failure 401,403,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/products/{id}/variants/{variantId} [delete]
+func (h *Handler) deleteProductVariant(c *gin.Context) {
+	if err := h.variantsService.Delete(c.Param("id"), c.Param("variantId")); err != nil {
+		newErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{
+		Status: "ok",
+	})
+}
@@ -0,0 +1,168 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/AndrewMislyuk/go-shop-backend/internal/domain"
+	"github.com/AndrewMislyuk/go-shop-backend/internal/service"
+	mock_service "github.com/AndrewMislyuk/go-shop-backend/internal/service/mock"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/magiconair/properties/assert"
+)
+
+func TestHandler_getProductVariants(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockProductVariants, productId string)
+
+	testTable := []struct {
+		name                string
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name: "OK",
+			mockBehavior: func(s *mock_service.MockProductVariants, productId string) {
+				s.EXPECT().GetByProduct(productId).Return([]domain.ProductVariant{
+					{Id: "c1a5f0de-7c1b-4f55-9f0e-3c2a1b0d9e01", ProductId: productId, Sku: "JEANS-30-BLUE", Options: map[string]string{"colour": "blue", "size": "30"}, SalePrice: domain.NewMoney(159000, "RUB"), Stock: 4},
+				}, nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"data":[{"id":"c1a5f0de-7c1b-4f55-9f0e-3c2a1b0d9e01","product_id":"72c07fab-dd14-47fa-b478-d59255dcf8dd","sku":"JEANS-30-BLUE","options":{"colour":"blue","size":"30"},"price":null,"sale_price":{"amount":159000,"currency":"RUB"},"stock":4,"created_at":"0001-01-01T00:00:00Z"}]}`,
+		},
+
+		{
+			name: "Product Not Found",
+			mockBehavior: func(s *mock_service.MockProductVariants, productId string) {
+				s.EXPECT().GetByProduct(productId).Return(nil, domain.ErrProductNotFound)
+			},
+			expectedStatusCode:  404,
+			expectedRequestBody: `{"code":"product_not_found","message":"product not found"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			variants := mock_service.NewMockProductVariants(c)
+			testCase.mockBehavior(variants, "72c07fab-dd14-47fa-b478-d59255dcf8dd")
+
+			services := &service.Service{ProductVariants: variants}
+			handler := NewHandler(services)
+
+			// Test Server
+			r := gin.New()
+			r.Use(handler.handleErrors)
+			r.GET("/products/:id/variants", handler.getProductVariants)
+
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/products/72c07fab-dd14-47fa-b478-d59255dcf8dd/variants", nil)
+
+			// Perform Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedRequestBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_createProductVariant(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockProductVariants, productId string, input domain.CreateVariantInput)
+
+	testTable := []struct {
+		name                string
+		inputBody           string
+		input               domain.CreateVariantInput
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name:      "OK",
+			inputBody: `{"sku":"jeans-32-blue","options":{"size":"32","colour":"blue"},"price":{"amount":179000}}`,
+			input: domain.CreateVariantInput{
+				Sku:     "jeans-32-blue",
+				Options: map[string]string{"size": "32", "colour": "blue"},
+				Price:   &domain.PriceInput{Amount: 179000},
+			},
+			mockBehavior: func(s *mock_service.MockProductVariants, productId string, input domain.CreateVariantInput) {
+				s.EXPECT().Create(productId, input).Return("c1a5f0de-7c1b-4f55-9f0e-3c2a1b0d9e01", nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"id":"c1a5f0de-7c1b-4f55-9f0e-3c2a1b0d9e01"}`,
+		},
+
+		{
+			name:                "Empty SKU",
+			inputBody:           `{"options":{"size":"32"}}`,
+			mockBehavior:        func(s *mock_service.MockProductVariants, productId string, input domain.CreateVariantInput) {},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"code":"invalid_input","message":"Key: 'CreateVariantInput.Sku' Error:Field validation for 'Sku' failed on the 'required' tag"}`,
+		},
+
+		{
+			name:      "SKU Taken",
+			inputBody: `{"sku":"jeans-32-blue"}`,
+			input: domain.CreateVariantInput{
+				Sku: "jeans-32-blue",
+			},
+			mockBehavior: func(s *mock_service.MockProductVariants, productId string, input domain.CreateVariantInput) {
+				s.EXPECT().Create(productId, input).Return("", domain.ErrSkuTaken)
+			},
+			expectedStatusCode:  409,
+			expectedRequestBody: `{"code":"sku_taken","message":"variant with this SKU already exists"}`,
+		},
+
+		{
+			name:      "Service Failure",
+			inputBody: `{"sku":"jeans-32-blue"}`,
+			input: domain.CreateVariantInput{
+				Sku: "jeans-32-blue",
+			},
+			mockBehavior: func(s *mock_service.MockProductVariants, productId string, input domain.CreateVariantInput) {
+				s.EXPECT().Create(productId, input).Return("", errors.New("service failure"))
+			},
+			expectedStatusCode:  500,
+			expectedRequestBody: `{"code":"internal_error","message":"internal server error"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			variants := mock_service.NewMockProductVariants(c)
+			testCase.mockBehavior(variants, "72c07fab-dd14-47fa-b478-d59255dcf8dd", testCase.input)
+
+			services := &service.Service{ProductVariants: variants}
+			handler := NewHandler(services)
+
+			// Test Server
+			r := gin.New()
+			r.Use(handler.handleErrors)
+			r.POST("/products/:id/variants", handler.createProductVariant)
+
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/products/72c07fab-dd14-47fa-b478-d59255dcf8dd/variants", bytes.NewBufferString(testCase.inputBody))
+
+			// Perform Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedRequestBody, w.Body.String())
+		})
+	}
+}
@@ -8,13 +8,32 @@ import (
 )
 
 type getAllProductsListsResponse struct {
-	Data []domain.ProductsList `json:"data"`
+	Data       []domain.ProductsList `json:"data"`
+	Total      int                   `json:"total"`
+	NextCursor string                `json:"next_cursor,omitempty"`
+}
+
+type searchProductsResponse struct {
+	Data  []domain.ProductSearchResult `json:"data"`
+	Total int                          `json:"total"`
 }
 
 type getProductResponse struct {
 	Data domain.ProductsList `json:"data"`
 }
 
+// acceptCurrencyHeader asks for the prices of the products in a currency, the
+// currency query parameter takes precedence over it.
+const acceptCurrencyHeader = "Accept-Currency"
+
+func requestCurrency(c *gin.Context, query string) string {
+	if query != "" {
+		return query
+	}
+
+	return c.GetHeader(acceptCurrencyHeader)
+}
+
 // @Summary Create Product
 // @Security ApiKeyAuth
 // @Tags Product
@@ -30,15 +49,15 @@ type getProductResponse struct {
 // @Router /api/products/ [post]
 func (h *Handler) createProduct(c *gin.Context) {
 	var input domain.CreateProductInput
-	if err := c.BindJSON(&input); err != nil {
-		newErrorResponse(c, http.StatusBadRequest, err.Error())
+	if err := c.ShouldBindJSON(&input); err != nil {
+		newErrorResponse(c, domain.NewValidationError(err))
 
 		return
 	}
 
 	id, err := h.productsService.Create(input)
 	if err != nil {
-		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		newErrorResponse(c, err)
 
 		return
 	}
@@ -50,25 +69,94 @@ func (h *Handler) createProduct(c *gin.Context) {
 
 // @Summary Get Products List
 // @Tags Product
-// @Description get products list
+// @Description get a page of products, filtered and sorted
 // @ID get-products
 // @Accept  json
 // @Produce  json
+// @Param category query string false "Category slug"
+// @Param type query string false "Type slug"
+// @Param subtype query string false "Subtype slug"
+// @Param min_price query int false "Minimal price"
+// @Param max_price query int false "Maximal price"
+// @Param on_sale query bool false "Only products on sale or only without sale"
+// @Param sort query string false "price, created_at or title, prefixed with - for descending order" default(-created_at)
+// @Param limit query int false "Page size"
+// @Param offset query int false "Page offset"
+// @Param cursor query string false "next_cursor of the previous page"
+// @Param currency query string false "Currency of the prices, ISO 4217 code"
+// @Param Accept-Currency header string false "Currency of the prices unless given in the query"
 // @Success 200 {object} getAllProductsListsResponse
-// @Failure 400,404 {object} errorResponse
+// @Failure 400 {object} errorResponse
 // @Failure 500 {object} errorResponse
 // @Failure default {object} errorResponse
 // @Router /api/products/ [get]
 func (h *Handler) getAllProducts(c *gin.Context) {
-	products, err := h.productsService.GetAll()
+	var filter domain.ProductsFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		newErrorResponse(c, domain.NewValidationError(err))
+
+		return
+	}
+
+	filter.Currency = requestCurrency(c, filter.Currency)
+
+	page, err := h.productsService.GetAll(filter)
 	if err != nil {
-		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		newErrorResponse(c, err)
 
 		return
 	}
 
 	c.JSON(http.StatusOK, getAllProductsListsResponse{
-		Data: products,
+		Data:       page.Products,
+		Total:      page.Total,
+		NextCursor: page.NextCursor,
+	})
+}
+
+// @Summary Search Products
+// @Tags Product
+// @Description full-text search by title and description, ranked by relevance
+// @ID search-products
+// @Accept  json
+// @Produce  json
+// @Param q query string true "Search query"
+// @Param category query string false "Category slug"
+// @Param type query string false "Type slug"
+// @Param subtype query string false "Subtype slug"
+// @Param min_price query int false "Minimal price"
+// @Param max_price query int false "Maximal price"
+// @Param on_sale query bool false "Only products on sale or only without sale"
+// @Param sort query string false "price, created_at or title, prefixed with - for descending order; relevance by default"
+// @Param limit query int false "Page size"
+// @Param offset query int false "Page offset"
+// @Param currency query string false "Currency of the prices, ISO 4217 code"
+// @Param Accept-Currency header string false "Currency of the prices unless given in the query"
+// @Success 200 {object} searchProductsResponse
+// @Failure 400 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/products/search [get]
+func (h *Handler) searchProducts(c *gin.Context) {
+	var input domain.ProductsSearchInput
+	if err := c.ShouldBindQuery(&input); err != nil {
+		newErrorResponse(c, domain.NewValidationError(err))
+
+		return
+	}
+
+	input.Currency = requestCurrency(c, input.Currency)
+
+	page, err := h.productsService.Search(input)
+	if err != nil {
+		newErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, searchProductsResponse{
+		Data:  page.Results,
+		Total: page.Total,
 	})
 }
 
@@ -79,6 +167,8 @@ func (h *Handler) getAllProducts(c *gin.Context) {
 // @Accept  json
 // @Produce  json
 // @Param id path string true "Product ID"
+// @Param currency query string false "Currency of the prices, ISO 4217 code"
+// @Param Accept-Currency header string false "Currency of the prices unless given in the query"
 // @Success 200 {object} getProductResponse
 // @Failure 400,404 {object} errorResponse
 // @Failure 500 {object} errorResponse
@@ -87,9 +177,9 @@ func (h *Handler) getAllProducts(c *gin.Context) {
 func (h *Handler) getProductById(c *gin.Context) {
 	product_id := c.Param("id")
 
-	product, err := h.productsService.GetById(product_id)
+	product, err := h.productsService.GetById(product_id, requestCurrency(c, c.Query("currency")))
 	if err != nil {
-		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		newErrorResponse(c, err)
 
 		return
 	}
@@ -117,14 +207,14 @@ func (h *Handler) updateProduct(c *gin.Context) {
 	product_id := c.Param("id")
 
 	var input domain.UpdateProductInput
-	if err := c.BindJSON(&input); err != nil {
-		newErrorResponse(c, http.StatusBadRequest, err.Error())
+	if err := c.ShouldBindJSON(&input); err != nil {
+		newErrorResponse(c, domain.NewValidationError(err))
 
 		return
 	}
 
 	if err := h.productsService.Update(product_id, input); err != nil {
-		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		newErrorResponse(c, err)
 
 		return
 	}
@@ -152,7 +242,7 @@ func (h *Handler) deleteProduct(c *gin.Context) {
 
 	err := h.productsService.Delete(product_id)
 	if err != nil {
-		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		newErrorResponse(c, err)
 
 		return
 	}
@@ -27,16 +27,12 @@ func TestHandler_createProduct(t *testing.T) {
 	}{
 		{
 			name:      "OK",
-			inputBody: `{"title":"test_title","image":"test_image","price":70000,"sale":0,"sale_old_price":0,"category":"test_category","type":"test_type","subtype":"test_subtype","description":"test_description"}`,
+			inputBody: `{"title":"test_title","image":"test_image","price":{"amount":70000},"category_id":"6b1e7f7e-2f4b-4c1e-9d65-0d3f3c9b8a11","description":"test_description"}`,
 			inputUser: domain.CreateProductInput{
-				Title:        "test_title",
-				Price:        70000,
-				Sale:         0,
-				SaleOldPrice: 0,
-				Category:     "test_category",
-				Type:         "test_type",
-				Subtype:      "test_subtype",
-				Description:  "test_description",
+				Title:       "test_title",
+				Price:       domain.PriceInput{Amount: 70000},
+				CategoryId:  "6b1e7f7e-2f4b-4c1e-9d65-0d3f3c9b8a11",
+				Description: "test_description",
 			},
 			mockBehavior: func(s *mock_service.MockProductsList, input domain.CreateProductInput) {
 				s.EXPECT().Create(input).Return("34c8d3e6-b8d7-43dc-847e-5764c4114856", nil)
@@ -50,27 +46,38 @@ func TestHandler_createProduct(t *testing.T) {
 			inputBody:           `{}`,
 			mockBehavior:        func(s *mock_service.MockProductsList, input domain.CreateProductInput) {},
 			expectedStatusCode:  400,
-			expectedRequestBody: `{"message":"Key: 'CreateProductInput.Title' Error:Field validation for 'Title' failed on the 'required' tag\nKey: 'CreateProductInput.Price' Error:Field validation for 'Price' failed on the 'required' tag\nKey: 'CreateProductInput.Category' Error:Field validation for 'Category' failed on the 'required' tag\nKey: 'CreateProductInput.Type' Error:Field validation for 'Type' failed on the 'required' tag\nKey: 'CreateProductInput.Subtype' Error:Field validation for 'Subtype' failed on the 'required' tag"}`,
+			expectedRequestBody: `{"code":"invalid_input","message":"Key: 'CreateProductInput.Title' Error:Field validation for 'Title' failed on the 'required' tag\nKey: 'CreateProductInput.Price.Amount' Error:Field validation for 'Amount' failed on the 'required' tag\nKey: 'CreateProductInput.CategoryId' Error:Field validation for 'CategoryId' failed on the 'required' tag"}`,
+		},
+
+		{
+			name:      "Not A Subtype",
+			inputBody: `{"title":"test_title","price":{"amount":70000},"category_id":"6b1e7f7e-2f4b-4c1e-9d65-0d3f3c9b8a11"}`,
+			inputUser: domain.CreateProductInput{
+				Title:      "test_title",
+				Price:      domain.PriceInput{Amount: 70000},
+				CategoryId: "6b1e7f7e-2f4b-4c1e-9d65-0d3f3c9b8a11",
+			},
+			mockBehavior: func(s *mock_service.MockProductsList, input domain.CreateProductInput) {
+				s.EXPECT().Create(input).Return("", domain.ErrCategoryNotLeaf)
+			},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"code":"category_not_subtype","message":"products can only be assigned to a subtype"}`,
 		},
 
 		{
 			name:      "Service Failure",
-			inputBody: `{"title":"test_title","image":"test_image","price":70000,"sale":0,"sale_old_price":0,"category":"test_category","type":"test_type","subtype":"test_subtype","description":"test_description"}`,
+			inputBody: `{"title":"test_title","image":"test_image","price":{"amount":70000},"category_id":"6b1e7f7e-2f4b-4c1e-9d65-0d3f3c9b8a11","description":"test_description"}`,
 			inputUser: domain.CreateProductInput{
-				Title:        "test_title",
-				Price:        70000,
-				Sale:         0,
-				SaleOldPrice: 0,
-				Category:     "test_category",
-				Type:         "test_type",
-				Subtype:      "test_subtype",
-				Description:  "test_description",
+				Title:       "test_title",
+				Price:       domain.PriceInput{Amount: 70000},
+				CategoryId:  "6b1e7f7e-2f4b-4c1e-9d65-0d3f3c9b8a11",
+				Description: "test_description",
 			},
 			mockBehavior: func(s *mock_service.MockProductsList, input domain.CreateProductInput) {
 				s.EXPECT().Create(input).Return("", errors.New("service failure"))
 			},
 			expectedStatusCode:  500,
-			expectedRequestBody: `{"message":"service failure"}`,
+			expectedRequestBody: `{"code":"internal_error","message":"internal server error"}`,
 		},
 	}
 
@@ -88,6 +95,7 @@ func TestHandler_createProduct(t *testing.T) {
 
 			// Test Server
 			r := gin.New()
+			r.Use(handler.handleErrors)
 			r.POST("/create-product", handler.createProduct)
 
 			// Test Request
@@ -105,55 +113,90 @@ func TestHandler_createProduct(t *testing.T) {
 }
 
 func TestHandler_getAllProducts(t *testing.T) {
-	type mockBehavior func(s *mock_service.MockProductsList)
+	type mockBehavior func(s *mock_service.MockProductsList, filter domain.ProductsFilter)
 
 	testTable := []struct {
 		name                string
+		query               string
+		filter              domain.ProductsFilter
 		mockBehavior        mockBehavior
 		expectedStatusCode  int
 		expectedRequestBody string
 	}{
 		{
-			name: "OK",
-			mockBehavior: func(s *mock_service.MockProductsList) {
-				s.EXPECT().GetAll().Return([]domain.ProductsList{
+			name:  "OK",
+			query: "?category=zhenshchinam&min_price=100000&on_sale=true&sort=-price&limit=2",
+			filter: domain.ProductsFilter{
+				Category: "zhenshchinam",
+				MinPrice: uintPointer(100000),
+				OnSale:   boolPointer(true),
+				Sort:     "-price",
+				Limit:    2,
+			},
+			mockBehavior: func(s *mock_service.MockProductsList, filter domain.ProductsFilter) {
+				s.EXPECT().GetAll(filter).Return(domain.ProductsPage{Products: []domain.ProductsList{
 					{
 						Id:           "453b4f0f-1f56-4c57-b43d-7b79792450a7",
 						Title:        "Твидовый кардиган из хлопка",
 						Image:        "w1.webp",
-						Price:        749000,
+						Price:        domain.NewMoney(749000, "RUB"),
 						Sale:         0,
-						SaleOldPrice: 0,
-						Category:     "Женщинам",
-						Type:         "Одежда",
-						Subtype:      "Старые-коллекции",
+						SaleOldPrice: domain.NewMoney(0, "RUB"),
+						Category:     "zhenshchinam",
+						Type:         "odezhda",
+						Subtype:      "starye-kollekcii",
 						Description:  "",
+						MinPrice:     domain.NewMoney(749000, "RUB"),
+						MaxPrice:     domain.NewMoney(749000, "RUB"),
 					},
 					{
 						Id:           "b07221f8-4133-4688-b2d6-d677f41f5b74",
 						Title:        "Объемный водоотталкивающий тренч",
 						Image:        "w2.webp",
-						Price:        499000,
+						Price:        domain.NewMoney(499000, "RUB"),
 						Sale:         50,
-						SaleOldPrice: 999000,
-						Category:     "Женщинам",
-						Type:         "Одежда",
-						Subtype:      "Старые-коллекции",
+						SaleOldPrice: domain.NewMoney(999000, "RUB"),
+						Category:     "zhenshchinam",
+						Type:         "odezhda",
+						Subtype:      "starye-kollekcii",
 						Description:  "",
+						MinPrice:     domain.NewMoney(499000, "RUB"),
+						MaxPrice:     domain.NewMoney(499000, "RUB"),
 					},
-				}, nil)
+				}, Total: 5, NextCursor: "eyJzIjoiLXByaWNlIn0"}, nil)
 			},
 			expectedStatusCode:  200,
-			expectedRequestBody: `{"data":[{"id":"453b4f0f-1f56-4c57-b43d-7b79792450a7","title":"Твидовый кардиган из хлопка","image":"w1.webp","price":749000,"sale":0,"sale_old_price":0,"category":"Женщинам","type":"Одежда","subtype":"Старые-коллекции","description":"","created_at":"0001-01-01T00:00:00Z"},{"id":"b07221f8-4133-4688-b2d6-d677f41f5b74","title":"Объемный водоотталкивающий тренч","image":"w2.webp","price":499000,"sale":50,"sale_old_price":999000,"category":"Женщинам","type":"Одежда","subtype":"Старые-коллекции","description":"","created_at":"0001-01-01T00:00:00Z"}]}`,
+			expectedRequestBody: `{"data":[{"id":"453b4f0f-1f56-4c57-b43d-7b79792450a7","title":"Твидовый кардиган из хлопка","image":"w1.webp","price":{"amount":749000,"currency":"RUB"},"sale":0,"sale_old_price":{"amount":0,"currency":"RUB"},"category_id":"","category":"zhenshchinam","type":"odezhda","subtype":"starye-kollekcii","description":"","created_at":"0001-01-01T00:00:00Z","min_price":{"amount":749000,"currency":"RUB"},"max_price":{"amount":749000,"currency":"RUB"},"in_stock":false},{"id":"b07221f8-4133-4688-b2d6-d677f41f5b74","title":"Объемный водоотталкивающий тренч","image":"w2.webp","price":{"amount":499000,"currency":"RUB"},"sale":50,"sale_old_price":{"amount":999000,"currency":"RUB"},"category_id":"","category":"zhenshchinam","type":"odezhda","subtype":"starye-kollekcii","description":"","created_at":"0001-01-01T00:00:00Z","min_price":{"amount":499000,"currency":"RUB"},"max_price":{"amount":499000,"currency":"RUB"},"in_stock":false}],"total":5,"next_cursor":"eyJzIjoiLXByaWNlIn0"}`,
+		},
+
+		{
+			name:                "Invalid Limit",
+			query:               "?limit=1000",
+			mockBehavior:        func(s *mock_service.MockProductsList, filter domain.ProductsFilter) {},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"code":"invalid_input","message":"Key: 'ProductsFilter.Limit' Error:Field validation for 'Limit' failed on the 'max' tag"}`,
+		},
+
+		{
+			name:  "Invalid Cursor",
+			query: "?cursor=broken",
+			filter: domain.ProductsFilter{
+				Cursor: "broken",
+			},
+			mockBehavior: func(s *mock_service.MockProductsList, filter domain.ProductsFilter) {
+				s.EXPECT().GetAll(filter).Return(domain.ProductsPage{}, domain.ErrInvalidCursor)
+			},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"code":"invalid_cursor","message":"invalid cursor"}`,
 		},
 
 		{
 			name: "Service Failure",
-			mockBehavior: func(s *mock_service.MockProductsList) {
-				s.EXPECT().GetAll().Return([]domain.ProductsList{}, errors.New("service failure"))
+			mockBehavior: func(s *mock_service.MockProductsList, filter domain.ProductsFilter) {
+				s.EXPECT().GetAll(filter).Return(domain.ProductsPage{}, errors.New("service failure"))
 			},
 			expectedStatusCode:  500,
-			expectedRequestBody: `{"message":"service failure"}`,
+			expectedRequestBody: `{"code":"internal_error","message":"internal server error"}`,
 		},
 	}
 
@@ -164,18 +207,119 @@ func TestHandler_getAllProducts(t *testing.T) {
 			defer c.Finish()
 
 			product := mock_service.NewMockProductsList(c)
-			testCase.mockBehavior(product)
+			testCase.mockBehavior(product, testCase.filter)
 
 			services := &service.Service{ProductsList: product}
 			handler := NewHandler(services)
 
 			// Test Server
 			r := gin.New()
+			r.Use(handler.handleErrors)
 			r.GET("/get-all-product", handler.getAllProducts)
 
 			// Test Request
 			w := httptest.NewRecorder()
-			req := httptest.NewRequest("GET", "/get-all-product", nil)
+			req := httptest.NewRequest("GET", "/get-all-product"+testCase.query, nil)
+
+			// Perform Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedRequestBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_searchProducts(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockProductsList, input domain.ProductsSearchInput)
+
+	testTable := []struct {
+		name                string
+		query               string
+		input               domain.ProductsSearchInput
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name:  "OK",
+			query: "?q=trench&category=Women&limit=10",
+			input: domain.ProductsSearchInput{
+				Query: "trench",
+				ProductsFilter: domain.ProductsFilter{
+					Category: "Women",
+					Limit:    10,
+				},
+			},
+			mockBehavior: func(s *mock_service.MockProductsList, input domain.ProductsSearchInput) {
+				s.EXPECT().Search(input).Return(domain.ProductsSearchPage{
+					Results: []domain.ProductSearchResult{
+						{
+							ProductsList: domain.ProductsList{
+								Id:           "b07221f8-4133-4688-b2d6-d677f41f5b74",
+								Title:        "Waterproof trench",
+								Price:        domain.NewMoney(499000, "RUB"),
+								SaleOldPrice: domain.NewMoney(0, "RUB"),
+								Category:     "Women",
+								Type:         "Clothes",
+								Subtype:      "Old collections",
+								MinPrice:     domain.NewMoney(499000, "RUB"),
+								MaxPrice:     domain.NewMoney(499000, "RUB"),
+							},
+							Rank:      0.6,
+							Highlight: "Waterproof <b>trench</b>",
+						},
+					},
+					Total: 1,
+				}, nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"data":[{"id":"b07221f8-4133-4688-b2d6-d677f41f5b74","title":"Waterproof trench","image":"","price":{"amount":499000,"currency":"RUB"},"sale":0,"sale_old_price":{"amount":0,"currency":"RUB"},"category_id":"","category":"Women","type":"Clothes","subtype":"Old collections","description":"","created_at":"0001-01-01T00:00:00Z","min_price":{"amount":499000,"currency":"RUB"},"max_price":{"amount":499000,"currency":"RUB"},"in_stock":false,"rank":0.6,"highlight":"Waterproof \u003cb\u003etrench\u003c/b\u003e","snippet":""}],"total":1}`,
+		},
+
+		{
+			name:                "Empty Query",
+			query:               "?category=Women",
+			mockBehavior:        func(s *mock_service.MockProductsList, input domain.ProductsSearchInput) {},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"code":"invalid_input","message":"Key: 'ProductsSearchInput.Query' Error:Field validation for 'Query' failed on the 'required' tag"}`,
+		},
+
+		{
+			name:  "Service Failure",
+			query: "?q=trench",
+			input: domain.ProductsSearchInput{
+				Query: "trench",
+			},
+			mockBehavior: func(s *mock_service.MockProductsList, input domain.ProductsSearchInput) {
+				s.EXPECT().Search(input).Return(domain.ProductsSearchPage{}, errors.New("service failure"))
+			},
+			expectedStatusCode:  500,
+			expectedRequestBody: `{"code":"internal_error","message":"internal server error"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			product := mock_service.NewMockProductsList(c)
+			testCase.mockBehavior(product, testCase.input)
+
+			services := &service.Service{ProductsList: product}
+			handler := NewHandler(services)
+
+			// Test Server
+			r := gin.New()
+			r.Use(handler.handleErrors)
+			r.GET("/search", handler.searchProducts)
+
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/search"+testCase.query, nil)
 
 			// Perform Request
 			r.ServeHTTP(w, req)
@@ -192,6 +336,8 @@ func TestHandler_getProductById(t *testing.T) {
 
 	testTable := []struct {
 		name                string
+		query               string
+		acceptCurrency      string
 		mockBehavior        mockBehavior
 		expectedStatusCode  int
 		expectedRequestBody string
@@ -199,30 +345,73 @@ func TestHandler_getProductById(t *testing.T) {
 		{
 			name: "OK",
 			mockBehavior: func(s *mock_service.MockProductsList, productId string) {
-				s.EXPECT().GetById(productId).Return(domain.ProductsList{
+				s.EXPECT().GetById(productId, "").Return(domain.ProductsList{
 					Id:           "453b4f0f-1f56-4c57-b43d-7b79792450a7",
 					Title:        "Твидовый кардиган из хлопка",
 					Image:        "w1.webp",
-					Price:        749000,
+					Price:        domain.NewMoney(749000, "RUB"),
 					Sale:         0,
-					SaleOldPrice: 0,
-					Category:     "Женщинам",
-					Type:         "Одежда",
-					Subtype:      "Старые-коллекции",
+					SaleOldPrice: domain.NewMoney(0, "RUB"),
+					Category:     "zhenshchinam",
+					Type:         "odezhda",
+					Subtype:      "starye-kollekcii",
 					Description:  "",
+					MinPrice:     domain.NewMoney(749000, "RUB"),
+					MaxPrice:     domain.NewMoney(799000, "RUB"),
+					InStock:      true,
+					Variants: []domain.ProductVariant{
+						{Id: "c1a5f0de-7c1b-4f55-9f0e-3c2a1b0d9e01", ProductId: "453b4f0f-1f56-4c57-b43d-7b79792450a7", Sku: "CARDIGAN-M", Options: map[string]string{"size": "M"}, SalePrice: domain.NewMoney(749000, "RUB"), Stock: 3},
+						{Id: "c1a5f0de-7c1b-4f55-9f0e-3c2a1b0d9e02", ProductId: "453b4f0f-1f56-4c57-b43d-7b79792450a7", Sku: "CARDIGAN-XL", Options: map[string]string{"size": "XL"}, Price: &domain.Money{Amount: 799000, Currency: "RUB"}, SalePrice: domain.NewMoney(799000, "RUB")},
+					},
+				}, nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"data":{"id":"453b4f0f-1f56-4c57-b43d-7b79792450a7","title":"Твидовый кардиган из хлопка","image":"w1.webp","price":{"amount":749000,"currency":"RUB"},"sale":0,"sale_old_price":{"amount":0,"currency":"RUB"},"category_id":"","category":"zhenshchinam","type":"odezhda","subtype":"starye-kollekcii","description":"","created_at":"0001-01-01T00:00:00Z","min_price":{"amount":749000,"currency":"RUB"},"max_price":{"amount":799000,"currency":"RUB"},"in_stock":true,"variants":[{"id":"c1a5f0de-7c1b-4f55-9f0e-3c2a1b0d9e01","product_id":"453b4f0f-1f56-4c57-b43d-7b79792450a7","sku":"CARDIGAN-M","options":{"size":"M"},"price":null,"sale_price":{"amount":749000,"currency":"RUB"},"stock":3,"created_at":"0001-01-01T00:00:00Z"},{"id":"c1a5f0de-7c1b-4f55-9f0e-3c2a1b0d9e02","product_id":"453b4f0f-1f56-4c57-b43d-7b79792450a7","sku":"CARDIGAN-XL","options":{"size":"XL"},"price":{"amount":799000,"currency":"RUB"},"sale_price":{"amount":799000,"currency":"RUB"},"stock":0,"created_at":"0001-01-01T00:00:00Z"}]}}`,
+		},
+
+		{
+			name:           "Query Currency",
+			query:          "?currency=usd",
+			acceptCurrency: "EUR",
+			mockBehavior: func(s *mock_service.MockProductsList, productId string) {
+				s.EXPECT().GetById(productId, "usd").Return(domain.ProductsList{
+					Id:       "453b4f0f-1f56-4c57-b43d-7b79792450a7",
+					Title:    "Твидовый кардиган из хлопка",
+					Price:    domain.NewMoney(8089, "USD"),
+					MinPrice: domain.NewMoney(8089, "USD"),
+					MaxPrice: domain.NewMoney(8089, "USD"),
 				}, nil)
 			},
 			expectedStatusCode:  200,
-			expectedRequestBody: `{"data":{"id":"453b4f0f-1f56-4c57-b43d-7b79792450a7","title":"Твидовый кардиган из хлопка","image":"w1.webp","price":749000,"sale":0,"sale_old_price":0,"category":"Женщинам","type":"Одежда","subtype":"Старые-коллекции","description":"","created_at":"0001-01-01T00:00:00Z"}}`,
+			expectedRequestBody: `{"data":{"id":"453b4f0f-1f56-4c57-b43d-7b79792450a7","title":"Твидовый кардиган из хлопка","image":"","price":{"amount":8089,"currency":"USD"},"sale":0,"sale_old_price":{"amount":0,"currency":""},"category_id":"","category":"","type":"","subtype":"","description":"","created_at":"0001-01-01T00:00:00Z","min_price":{"amount":8089,"currency":"USD"},"max_price":{"amount":8089,"currency":"USD"},"in_stock":false}}`,
+		},
+
+		{
+			name:           "Unknown Currency",
+			acceptCurrency: "XYZ",
+			mockBehavior: func(s *mock_service.MockProductsList, productId string) {
+				s.EXPECT().GetById(productId, "XYZ").Return(domain.ProductsList{}, domain.ErrUnknownCurrency)
+			},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"code":"unknown_currency","message":"currency must be a supported ISO 4217 code"}`,
 		},
 
 		{
 			name: "Service Failure",
 			mockBehavior: func(s *mock_service.MockProductsList, productId string) {
-				s.EXPECT().GetById(productId).Return(domain.ProductsList{}, errors.New("service failure"))
+				s.EXPECT().GetById(productId, "").Return(domain.ProductsList{}, errors.New("service failure"))
 			},
 			expectedStatusCode:  500,
-			expectedRequestBody: `{"message":"service failure"}`,
+			expectedRequestBody: `{"code":"internal_error","message":"internal server error"}`,
+		},
+
+		{
+			name: "Not Found",
+			mockBehavior: func(s *mock_service.MockProductsList, productId string) {
+				s.EXPECT().GetById(productId, "").Return(domain.ProductsList{}, domain.ErrProductNotFound)
+			},
+			expectedStatusCode:  404,
+			expectedRequestBody: `{"code":"product_not_found","message":"product not found"}`,
 		},
 	}
 
@@ -240,11 +429,15 @@ func TestHandler_getProductById(t *testing.T) {
 
 			// Test Server
 			r := gin.New()
+			r.Use(handler.handleErrors)
 			r.GET("/get-product/:id", handler.getProductById)
 
 			// Test Request
 			w := httptest.NewRecorder()
-			req := httptest.NewRequest("GET", "/get-product/453b4f0f-1f56-4c57-b43d-7b79792450a7", nil)
+			req := httptest.NewRequest("GET", "/get-product/453b4f0f-1f56-4c57-b43d-7b79792450a7"+testCase.query, nil)
+			if testCase.acceptCurrency != "" {
+				req.Header.Set("Accept-Currency", testCase.acceptCurrency)
+			}
 
 			// Perform Request
 			r.ServeHTTP(w, req)
@@ -269,16 +462,12 @@ func TestHandler_updateProduct(t *testing.T) {
 	}{
 		{
 			name:      "OK",
-			inputBody: `{"title":"new_title","price":70000,"sale":0,"sale_old_price":0,"category":"new_category","type":"new_type","subtype":"new_subtype","description":"new_description"}`,
+			inputBody: `{"title":"new_title","price":{"amount":70000},"category_id":"6b1e7f7e-2f4b-4c1e-9d65-0d3f3c9b8a11","description":"new_description"}`,
 			inputUser: domain.UpdateProductInput{
-				Title:        stringPointer("new_title"),
-				Price:        uintPointer(70000),
-				Sale:         uintPointer(0),
-				SaleOldPrice: uintPointer(0),
-				Category:     stringPointer("new_category"),
-				Type:         stringPointer("new_type"),
-				Subtype:      stringPointer("new_subtype"),
-				Description:  stringPointer("new_description"),
+				Title:       stringPointer("new_title"),
+				Price:       &domain.PriceInput{Amount: 70000},
+				CategoryId:  stringPointer("6b1e7f7e-2f4b-4c1e-9d65-0d3f3c9b8a11"),
+				Description: stringPointer("new_description"),
 			},
 			mockBehavior: func(s *mock_service.MockProductsList, productId string, product domain.UpdateProductInput) {
 				s.EXPECT().Update(productId, product).Return(nil)
@@ -289,22 +478,18 @@ func TestHandler_updateProduct(t *testing.T) {
 
 		{
 			name:      "Service Failure",
-			inputBody: `{"title":"new_title","price":70000,"sale":0,"sale_old_price":0,"category":"new_category","type":"new_type","subtype":"new_subtype","description":"new_description"}`,
+			inputBody: `{"title":"new_title","price":{"amount":70000},"category_id":"6b1e7f7e-2f4b-4c1e-9d65-0d3f3c9b8a11","description":"new_description"}`,
 			inputUser: domain.UpdateProductInput{
-				Title:        stringPointer("new_title"),
-				Price:        uintPointer(70000),
-				Sale:         uintPointer(0),
-				SaleOldPrice: uintPointer(0),
-				Category:     stringPointer("new_category"),
-				Type:         stringPointer("new_type"),
-				Subtype:      stringPointer("new_subtype"),
-				Description:  stringPointer("new_description"),
+				Title:       stringPointer("new_title"),
+				Price:       &domain.PriceInput{Amount: 70000},
+				CategoryId:  stringPointer("6b1e7f7e-2f4b-4c1e-9d65-0d3f3c9b8a11"),
+				Description: stringPointer("new_description"),
 			},
 			mockBehavior: func(s *mock_service.MockProductsList, productId string, product domain.UpdateProductInput) {
 				s.EXPECT().Update(productId, product).Return(errors.New("service failure"))
 			},
 			expectedStatusCode:  500,
-			expectedRequestBody: `{"message":"service failure"}`,
+			expectedRequestBody: `{"code":"internal_error","message":"internal server error"}`,
 		},
 	}
 
@@ -322,6 +507,7 @@ func TestHandler_updateProduct(t *testing.T) {
 
 			// Test Server
 			r := gin.New()
+			r.Use(handler.handleErrors)
 			r.PUT("/update-product/:id", handler.updateProduct)
 
 			// Test Request
@@ -362,7 +548,7 @@ func TestHandler_deleteProduct(t *testing.T) {
 				s.EXPECT().Delete(productId).Return(errors.New("service failure"))
 			},
 			expectedStatusCode:  500,
-			expectedRequestBody: `{"message":"service failure"}`,
+			expectedRequestBody: `{"code":"internal_error","message":"internal server error"}`,
 		},
 	}
 
@@ -380,6 +566,7 @@ func TestHandler_deleteProduct(t *testing.T) {
 
 			// Test Server
 			r := gin.New()
+			r.Use(handler.handleErrors)
 			r.DELETE("/delete-product/:id", handler.deleteProduct)
 
 			// Test Request
@@ -403,3 +590,7 @@ func stringPointer(s string) *string {
 func uintPointer(b uint) *uint {
 	return &b
 }
+
+func boolPointer(b bool) *bool {
+	return &b
+}
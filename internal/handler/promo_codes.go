@@ -0,0 +1,155 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/AndrewMislyuk/go-shop-backend/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Get All Promo Codes
+// @Security ApiKeyAuth
+// @Tags Promo Codes
+// @Description promo codes with the number of their uses, newest first
+// @ID get-all-promo-codes
+// @Accept  json
+// @Produce  json
+// @Param limit query int false "Page size"
+// @Param offset query int false "Page offset"
+// @Success 200 {object} domain.PromoCodesList
+// @Failure 400,401,403 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/promo-codes [get]
+func (h *Handler) getAllPromoCodes(c *gin.Context) {
+	var filter domain.PromoCodesFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		newErrorResponse(c, domain.NewValidationError(err))
+
+		return
+	}
+
+	codes, err := h.promoCodesService.GetAll(filter)
+	if err != nil {
+		newErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, codes)
+}
+
+// @Summary Get Promo Code By Id
+// @Security ApiKeyAuth
+// @Tags Promo Codes
+// @Description get promo code by id
+// @ID get-promo-code-by-id
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Promo Code ID"
+// @Success 200 {object} domain.PromoCode
+// @Failure 401,403,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/promo-codes/{id} [get]
+func (h *Handler) getPromoCodeById(c *gin.Context) {
+	code, err := h.promoCodesService.GetById(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, code)
+}
+
+// @Summary Create Promo Code
+// @Security ApiKeyAuth
+// @Tags Promo Codes
+// @Description create a promo code, it is case-insensitive and applies to the subtotal of an order at checkout
+// @ID create-promo-code
+// @Accept  json
+// @Produce  json
+// @Param input body domain.CreatePromoCodeInput true "Promo code info"
+// @Success 200 {object} getCreationId
+// @Failure 400,401,403,409 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/promo-codes [post]
+func (h *Handler) createPromoCode(c *gin.Context) {
+	var input domain.CreatePromoCodeInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		newErrorResponse(c, domain.NewValidationError(err))
+
+		return
+	}
+
+	id, err := h.promoCodesService.Create(input)
+	if err != nil {
+		newErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, getCreationId{
+		Id: id,
+	})
+}
+
+// @Summary Update Promo Code
+// @Security ApiKeyAuth
+// @Tags Promo Codes
+// @Description change the value, the limits, the period or the state of the promo code
+// @ID update-promo-code
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Promo Code ID"
+// @Param input body domain.UpdatePromoCodeInput true "Promo code info"
+// @Success 200 {object} statusResponse
+// @Failure 400,401,403,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/promo-codes/{id} [put]
+func (h *Handler) updatePromoCode(c *gin.Context) {
+	var input domain.UpdatePromoCodeInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		newErrorResponse(c, domain.NewValidationError(err))
+
+		return
+	}
+
+	if err := h.promoCodesService.Update(c.Param("id"), input); err != nil {
+		newErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{
+		Status: "ok",
+	})
+}
+
+// @Summary Delete Promo Code
+// @Security ApiKeyAuth
+// @Tags Promo Codes
+// @Description delete promo code
+// @ID delete-promo-code
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Promo Code ID"
+// @Success 200 {object} statusResponse
+// @Failure 401,403,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/promo-codes/{id} [delete]
+func (h *Handler) deletePromoCode(c *gin.Context) {
+	if err := h.promoCodesService.Delete(c.Param("id")); err != nil {
+		newErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{
+		Status: "ok",
+	})
+}
@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/AndrewMislyuk/go-shop-backend/internal/domain"
+	"github.com/AndrewMislyuk/go-shop-backend/internal/service"
+	mock_service "github.com/AndrewMislyuk/go-shop-backend/internal/service/mock"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/magiconair/properties/assert"
+)
+
+func TestHandler_updatePromoCode(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockPromoCodes, codeId string, input domain.UpdatePromoCodeInput)
+
+	usageLimit := 100
+	active := false
+
+	testTable := []struct {
+		name                string
+		inputBody           string
+		input               domain.UpdatePromoCodeInput
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name:      "OK",
+			inputBody: `{"usage_limit":100,"active":false}`,
+			input: domain.UpdatePromoCodeInput{
+				UsageLimit: &usageLimit,
+				Active:     &active,
+			},
+			mockBehavior: func(s *mock_service.MockPromoCodes, codeId string, input domain.UpdatePromoCodeInput) {
+				s.EXPECT().Update(codeId, input).Return(nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"status":"ok"}`,
+		},
+
+		{
+			name:                "Invalid Limit",
+			inputBody:           `{"usage_limit":0}`,
+			mockBehavior:        func(s *mock_service.MockPromoCodes, codeId string, input domain.UpdatePromoCodeInput) {},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"code":"invalid_input","message":"Key: 'UpdatePromoCodeInput.UsageLimit' Error:Field validation for 'UsageLimit' failed on the 'min' tag"}`,
+		},
+
+		{
+			name:      "Not Found",
+			inputBody: `{"active":false}`,
+			input: domain.UpdatePromoCodeInput{
+				Active: &active,
+			},
+			mockBehavior: func(s *mock_service.MockPromoCodes, codeId string, input domain.UpdatePromoCodeInput) {
+				s.EXPECT().Update(codeId, input).Return(domain.ErrPromoCodeNotFound)
+			},
+			expectedStatusCode:  404,
+			expectedRequestBody: `{"code":"promo_code_not_found","message":"promo code not found"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			codeId := "1e2d3c4b-5a69-4788-96a5-b4c3d2e1f0a9"
+
+			promoCodes := mock_service.NewMockPromoCodes(c)
+			testCase.mockBehavior(promoCodes, codeId, testCase.input)
+
+			services := &service.Service{PromoCodes: promoCodes}
+			handler := NewHandler(services)
+
+			// Test Server
+			r := gin.New()
+			r.Use(handler.handleErrors)
+			r.PUT("/promo-codes/:id", handler.updatePromoCode)
+
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("PUT", "/promo-codes/"+codeId, bytes.NewBufferString(testCase.inputBody))
+
+			// Perform Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedRequestBody, w.Body.String())
+		})
+	}
+}
@@ -1,19 +1,88 @@
 package handler
 
 import (
+	"errors"
+	"net/http"
+
+	"github.com/AndrewMislyuk/go-shop-backend/internal/domain"
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
 )
 
 type errorResponse struct {
-	Message string `json:"message"`
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+	RequestId string `json:"request_id,omitempty"`
 }
 
 type statusResponse struct {
 	Status string `json:"status"`
 }
 
-func newErrorResponse(c *gin.Context, statusCode int, message string) {
-	logrus.Error(message)
-	c.AbortWithStatusJSON(statusCode, errorResponse{Message: message})
+var errorStatuses = []struct {
+	kind   error
+	status int
+	code   string
+}{
+	{kind: domain.ErrValidation, status: http.StatusBadRequest, code: "validation_error"},
+	{kind: domain.ErrUnauthorized, status: http.StatusUnauthorized, code: "unauthorized"},
+	{kind: domain.ErrForbidden, status: http.StatusForbidden, code: "forbidden"},
+	{kind: domain.ErrNotFound, status: http.StatusNotFound, code: "not_found"},
+	{kind: domain.ErrConflict, status: http.StatusConflict, code: "conflict"},
+	{kind: domain.ErrTooManyRequests, status: http.StatusTooManyRequests, code: "too_many_requests"},
+	{kind: domain.ErrPaymentRequired, status: http.StatusPaymentRequired, code: "payment_required"},
+	{kind: domain.ErrTooLarge, status: http.StatusRequestEntityTooLarge, code: "too_large"},
+}
+
+// newErrorResponse aborts the request, the response itself is written by
+// handleErrors.
+func newErrorResponse(c *gin.Context, err error) {
+	_ = c.Error(err)
+	c.Abort()
+}
+
+// handleErrors writes the last error added to the context. Errors that are
+// not domain errors are logged and reported as internal without details,
+// so database and other internal messages never reach the client.
+func (h *Handler) handleErrors(c *gin.Context) {
+	c.Next()
+
+	if len(c.Errors) == 0 || c.Writer.Written() {
+		return
+	}
+
+	err := c.Errors.Last().Err
+	requestId := c.GetString(requestIdCtx)
+	status, response := http.StatusInternalServerError, errorResponse{
+		Code:      "internal_error",
+		Message:   "internal server error",
+		RequestId: requestId,
+	}
+
+	for _, s := range errorStatuses {
+		if errors.Is(err, s.kind) {
+			status = s.status
+			response.Code = s.code
+			response.Message = err.Error()
+
+			break
+		}
+	}
+
+	var domainErr *domain.Error
+	if status != http.StatusInternalServerError && errors.As(err, &domainErr) {
+		response.Code = domainErr.Code
+	}
+
+	entry := logrus.WithFields(logrus.Fields{
+		"request_id": requestId,
+		"status":     status,
+	})
+	if status == http.StatusInternalServerError {
+		entry.Error(err.Error())
+	} else {
+		entry.Info(err.Error())
+	}
+
+	c.JSON(status, response)
 }
@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/AndrewMislyuk/go-shop-backend/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+type getAllRolesResponse struct {
+	Data []domain.Role `json:"data"`
+}
+
+// @Summary Get Roles
+// @Security ApiKeyAuth
+// @Tags Roles
+// @Description get roles with their permissions
+// @ID get-roles
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} getAllRolesResponse
+// @Failure 401,403 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/roles/ [get]
+func (h *Handler) getAllRoles(c *gin.Context) {
+	roles, err := h.rolesService.GetAll()
+	if err != nil {
+		newErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, getAllRolesResponse{
+		Data: roles,
+	})
+}
+
+// @Summary Grant Role
+// @Security ApiKeyAuth
+// @Tags Roles
+// @Description grant a role to the user
+// @ID grant-role
+// @Accept  json
+// @Produce  json
+// @Param id path string true "User ID"
+// @Param input body domain.RoleInput true "Role"
+// @Success 200 {object} statusResponse
+// @Failure 400,401,403,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/users/{id}/roles [post]
+func (h *Handler) grantRole(c *gin.Context) {
+	userId := c.Param("id")
+
+	var input domain.RoleInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		newErrorResponse(c, domain.NewValidationError(err))
+
+		return
+	}
+
+	if err := h.rolesService.Grant(userId, input.Role); err != nil {
+		newErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{
+		Status: "ok",
+	})
+}
+
+// @Summary Revoke Role
+// @Security ApiKeyAuth
+// @Tags Roles
+// @Description revoke a role from the user
+// @ID revoke-role
+// @Accept  json
+// @Produce  json
+// @Param id path string true "User ID"
+// @Param role path string true "Role"
+// @Success 200 {object} statusResponse
+// @Failure 401,403 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/users/{id}/roles/{role} [delete]
+func (h *Handler) revokeRole(c *gin.Context) {
+	if err := h.rolesService.Revoke(c.Param("id"), c.Param("role")); err != nil {
+		newErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{
+		Status: "ok",
+	})
+}
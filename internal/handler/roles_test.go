@@ -0,0 +1,172 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/AndrewMislyuk/go-shop-backend/internal/domain"
+	"github.com/AndrewMislyuk/go-shop-backend/internal/service"
+	mock_service "github.com/AndrewMislyuk/go-shop-backend/internal/service/mock"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/magiconair/properties/assert"
+)
+
+func TestHandler_getAllRoles(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockRoles)
+
+	testTable := []struct {
+		name                string
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name: "OK",
+			mockBehavior: func(s *mock_service.MockRoles) {
+				s.EXPECT().GetAll().Return([]domain.Role{
+					{Name: "ADMIN", Description: "Full access to the shop administration", Permissions: []string{"files:upload", "products:write", "users:manage"}},
+					{Name: "CUSTOMER", Description: "Default role assigned at sign-up", Permissions: []string{}},
+				}, nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"data":[{"name":"ADMIN","description":"Full access to the shop administration","permissions":["files:upload","products:write","users:manage"]},{"name":"CUSTOMER","description":"Default role assigned at sign-up","permissions":[]}]}`,
+		},
+
+		{
+			name: "Service Failure",
+			mockBehavior: func(s *mock_service.MockRoles) {
+				s.EXPECT().GetAll().Return(nil, errors.New("service failure"))
+			},
+			expectedStatusCode:  500,
+			expectedRequestBody: `{"code":"internal_error","message":"internal server error"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			roles := mock_service.NewMockRoles(c)
+			testCase.mockBehavior(roles)
+
+			services := &service.Service{Roles: roles}
+			handler := NewHandler(services)
+
+			// Test Server
+			r := gin.New()
+			r.Use(handler.handleErrors)
+			r.GET("/roles", handler.getAllRoles)
+
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/roles", nil)
+
+			// Perform Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedRequestBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_grantRole(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockRoles, userId, role string)
+
+	testTable := []struct {
+		name                string
+		inputBody           string
+		role                string
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name:      "OK",
+			inputBody: `{"role":"ADMIN"}`,
+			role:      "ADMIN",
+			mockBehavior: func(s *mock_service.MockRoles, userId, role string) {
+				s.EXPECT().Grant(userId, role).Return(nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"status":"ok"}`,
+		},
+
+		{
+			name:                "Empty Fields",
+			inputBody:           `{}`,
+			mockBehavior:        func(s *mock_service.MockRoles, userId, role string) {},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"code":"invalid_input","message":"Key: 'RoleInput.Role' Error:Field validation for 'Role' failed on the 'required' tag"}`,
+		},
+
+		{
+			name:      "Unknown Role",
+			inputBody: `{"role":"SUPERUSER"}`,
+			role:      "SUPERUSER",
+			mockBehavior: func(s *mock_service.MockRoles, userId, role string) {
+				s.EXPECT().Grant(userId, role).Return(domain.ErrRoleNotFound)
+			},
+			expectedStatusCode:  404,
+			expectedRequestBody: `{"code":"role_not_found","message":"role not found"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			roles := mock_service.NewMockRoles(c)
+			testCase.mockBehavior(roles, "34c8d3e6-b8d7-43dc-847e-5764c4114856", testCase.role)
+
+			services := &service.Service{Roles: roles}
+			handler := NewHandler(services)
+
+			// Test Server
+			r := gin.New()
+			r.Use(handler.handleErrors)
+			r.POST("/users/:id/roles", handler.grantRole)
+
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/users/34c8d3e6-b8d7-43dc-847e-5764c4114856/roles", bytes.NewBufferString(testCase.inputBody))
+
+			// Perform Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedRequestBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_revokeRole(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	roles := mock_service.NewMockRoles(c)
+	roles.EXPECT().Revoke("34c8d3e6-b8d7-43dc-847e-5764c4114856", "ADMIN").Return(nil)
+
+	handler := NewHandler(&service.Service{Roles: roles})
+
+	r := gin.New()
+	r.Use(handler.handleErrors)
+	r.DELETE("/users/:id/roles/:role", handler.revokeRole)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("DELETE", "/users/34c8d3e6-b8d7-43dc-847e-5764c4114856/roles/ADMIN", nil)
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, `{"status":"ok"}`, w.Body.String())
+}
@@ -0,0 +1,180 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/AndrewMislyuk/go-shop-backend/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Update Me
+// @Security ApiKeyAuth
+// @Tags Users
+// @Description update profile of the current user
+// @ID update-me
+// @Accept  json
+// @Produce  json
+// @Param input body domain.UpdateUserInput true "Profile fields to update"
+// @Success 200 {object} statusResponse
+// @Failure 400,401,409 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/users/me [patch]
+func (h *Handler) updateMe(c *gin.Context) {
+	var input domain.UpdateUserInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		newErrorResponse(c, domain.NewValidationError(err))
+
+		return
+	}
+
+	if err := h.usersService.UpdateMe(c.GetString(userCtx), input); err != nil {
+		newErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{
+		Status: "ok",
+	})
+}
+
+// @Summary Change Password
+// @Security ApiKeyAuth
+// @Tags Users
+// @Description change password of the current user and sign out other sessions
+// @ID change-password
+// @Accept  json
+// @Produce  json
+// @Param input body domain.ChangePasswordInput true "Current and new password"
+// @Success 200 {object} statusResponse
+// @Failure 400,401 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/users/me/password [put]
+func (h *Handler) changePassword(c *gin.Context) {
+	var input domain.ChangePasswordInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		newErrorResponse(c, domain.NewValidationError(err))
+
+		return
+	}
+
+	if err := h.userService.ChangePassword(c.GetString(userCtx), c.GetString(sessionCtx), input); err != nil {
+		newErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{
+		Status: "ok",
+	})
+}
+
+// @Summary Delete Me
+// @Security ApiKeyAuth
+// @Tags Users
+// @Description delete the current account, personal data is anonymized
+// @ID delete-me
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} statusResponse
+// @Failure 401 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/users/me [delete]
+func (h *Handler) deleteMe(c *gin.Context) {
+	if err := h.usersService.DeleteMe(c.GetString(userCtx)); err != nil {
+		newErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{
+		Status: "ok",
+	})
+}
+
+// @Summary Get Users
+// @Security ApiKeyAuth
+// @Tags Users
+// @Description list and search users
+// @ID get-users
+// @Accept  json
+// @Produce  json
+// @Param search query string false "Search by name, surname or email"
+// @Param blocked query bool false "Only blocked or only active users"
+// @Param limit query int false "Page size"
+// @Param offset query int false "Page offset"
+// @Success 200 {object} domain.UsersList
+// @Failure 400,401,403 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/users/ [get]
+func (h *Handler) getAllUsers(c *gin.Context) {
+	var filter domain.UsersFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		newErrorResponse(c, domain.NewValidationError(err))
+
+		return
+	}
+
+	users, err := h.usersService.GetAll(filter)
+	if err != nil {
+		newErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, users)
+}
+
+// @Summary Block User
+// @Security ApiKeyAuth
+// @Tags Users
+// @Description block the user and revoke all of their sessions
+// @ID block-user
+// @Accept  json
+// @Produce  json
+// @Param id path string true "User ID"
+// @Success 200 {object} statusResponse
+// @Failure 400,401,403,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/users/{id}/block [post]
+func (h *Handler) blockUser(c *gin.Context) {
+	if err := h.usersService.Block(c.GetString(userCtx), c.Param("id")); err != nil {
+		newErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{
+		Status: "ok",
+	})
+}
+
+// @Summary Unblock User
+// @Security ApiKeyAuth
+// @Tags Users
+// @Description unblock the user
+// @ID unblock-user
+// @Accept  json
+// @Produce  json
+// @Param id path string true "User ID"
+// @Success 200 {object} statusResponse
+// @Failure 401,403,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/users/{id}/unblock [post]
+func (h *Handler) unblockUser(c *gin.Context) {
+	if err := h.usersService.Unblock(c.Param("id")); err != nil {
+		newErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{
+		Status: "ok",
+	})
+}
@@ -0,0 +1,326 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/AndrewMislyuk/go-shop-backend/internal/domain"
+	"github.com/AndrewMislyuk/go-shop-backend/internal/service"
+	mock_service "github.com/AndrewMislyuk/go-shop-backend/internal/service/mock"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/magiconair/properties/assert"
+)
+
+func TestHandler_updateMe(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockUsers, userId string, input domain.UpdateUserInput)
+
+	name := "New_Name"
+
+	testTable := []struct {
+		name                string
+		inputBody           string
+		input               domain.UpdateUserInput
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name:      "OK",
+			inputBody: `{"name":"New_Name"}`,
+			input:     domain.UpdateUserInput{Name: &name},
+			mockBehavior: func(s *mock_service.MockUsers, userId string, input domain.UpdateUserInput) {
+				s.EXPECT().UpdateMe(userId, input).Return(nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"status":"ok"}`,
+		},
+
+		{
+			name:                "Invalid Email",
+			inputBody:           `{"email":"not-an-email"}`,
+			mockBehavior:        func(s *mock_service.MockUsers, userId string, input domain.UpdateUserInput) {},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"code":"invalid_input","message":"Key: 'UpdateUserInput.Email' Error:Field validation for 'Email' failed on the 'email' tag"}`,
+		},
+
+		{
+			name:      "Empty Update",
+			inputBody: `{}`,
+			mockBehavior: func(s *mock_service.MockUsers, userId string, input domain.UpdateUserInput) {
+				s.EXPECT().UpdateMe(userId, input).Return(domain.ErrEmptyUpdate)
+			},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"code":"empty_update","message":"update structure has no values"}`,
+		},
+
+		{
+			name:      "Service Failure",
+			inputBody: `{"name":"New_Name"}`,
+			input:     domain.UpdateUserInput{Name: &name},
+			mockBehavior: func(s *mock_service.MockUsers, userId string, input domain.UpdateUserInput) {
+				s.EXPECT().UpdateMe(userId, input).Return(errors.New("service failure"))
+			},
+			expectedStatusCode:  500,
+			expectedRequestBody: `{"code":"internal_error","message":"internal server error"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			userId := "34c8d3e6-b8d7-43dc-847e-5764c4114856"
+
+			users := mock_service.NewMockUsers(c)
+			testCase.mockBehavior(users, userId, testCase.input)
+
+			services := &service.Service{Users: users}
+			handler := NewHandler(services)
+
+			// Test Server
+			r := gin.New()
+			r.Use(handler.handleErrors)
+			r.PATCH("/users/me", func(c *gin.Context) {
+				c.Set(userCtx, userId)
+			}, handler.updateMe)
+
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("PATCH", "/users/me", bytes.NewBufferString(testCase.inputBody))
+
+			// Perform Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedRequestBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_changePassword(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockUser, userId, sessionId string, input domain.ChangePasswordInput)
+
+	testTable := []struct {
+		name                string
+		inputBody           string
+		input               domain.ChangePasswordInput
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name:      "OK",
+			inputBody: `{"current_password":"qwerty","new_password":"1234QWER@"}`,
+			input:     domain.ChangePasswordInput{CurrentPassword: "qwerty", NewPassword: "1234QWER@"},
+			mockBehavior: func(s *mock_service.MockUser, userId, sessionId string, input domain.ChangePasswordInput) {
+				s.EXPECT().ChangePassword(userId, sessionId, input).Return(nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"status":"ok"}`,
+		},
+
+		{
+			name:                "Short Password",
+			inputBody:           `{"current_password":"qwerty","new_password":"1234"}`,
+			mockBehavior:        func(s *mock_service.MockUser, userId, sessionId string, input domain.ChangePasswordInput) {},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"code":"invalid_input","message":"Key: 'ChangePasswordInput.NewPassword' Error:Field validation for 'NewPassword' failed on the 'min' tag"}`,
+		},
+
+		{
+			name:      "Wrong Password",
+			inputBody: `{"current_password":"wrong","new_password":"1234QWER@"}`,
+			input:     domain.ChangePasswordInput{CurrentPassword: "wrong", NewPassword: "1234QWER@"},
+			mockBehavior: func(s *mock_service.MockUser, userId, sessionId string, input domain.ChangePasswordInput) {
+				s.EXPECT().ChangePassword(userId, sessionId, input).Return(domain.ErrWrongPassword)
+			},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"code":"wrong_password","message":"current password is incorrect"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			userId := "34c8d3e6-b8d7-43dc-847e-5764c4114856"
+			sessionId := "8d1f2e55-6a0e-4a53-8c3c-0b8f3a0f7a11"
+
+			auth := mock_service.NewMockUser(c)
+			testCase.mockBehavior(auth, userId, sessionId, testCase.input)
+
+			services := &service.Service{User: auth}
+			handler := NewHandler(services)
+
+			// Test Server
+			r := gin.New()
+			r.Use(handler.handleErrors)
+			r.PUT("/users/me/password", func(c *gin.Context) {
+				c.Set(userCtx, userId)
+				c.Set(sessionCtx, sessionId)
+			}, handler.changePassword)
+
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("PUT", "/users/me/password", bytes.NewBufferString(testCase.inputBody))
+
+			// Perform Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedRequestBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_getAllUsers(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockUsers, filter domain.UsersFilter)
+
+	blocked := true
+
+	testTable := []struct {
+		name                string
+		query               string
+		filter              domain.UsersFilter
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name:   "OK",
+			query:  "?search=test&blocked=true&limit=10",
+			filter: domain.UsersFilter{Search: "test", Blocked: &blocked, Limit: 10},
+			mockBehavior: func(s *mock_service.MockUsers, filter domain.UsersFilter) {
+				s.EXPECT().GetAll(filter).Return(domain.UsersList{
+					Users: []domain.User{},
+					Total: 0,
+				}, nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"users":[],"total":0}`,
+		},
+
+		{
+			name:                "Invalid Limit",
+			query:               "?limit=1000",
+			mockBehavior:        func(s *mock_service.MockUsers, filter domain.UsersFilter) {},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"code":"invalid_input","message":"Key: 'UsersFilter.Limit' Error:Field validation for 'Limit' failed on the 'max' tag"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			users := mock_service.NewMockUsers(c)
+			testCase.mockBehavior(users, testCase.filter)
+
+			services := &service.Service{Users: users}
+			handler := NewHandler(services)
+
+			// Test Server
+			r := gin.New()
+			r.Use(handler.handleErrors)
+			r.GET("/users", handler.getAllUsers)
+
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/users"+testCase.query, nil)
+
+			// Perform Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedRequestBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_blockUser(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockUsers, adminId, userId string)
+
+	testTable := []struct {
+		name                string
+		userId              string
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name:   "OK",
+			userId: "34c8d3e6-b8d7-43dc-847e-5764c4114856",
+			mockBehavior: func(s *mock_service.MockUsers, adminId, userId string) {
+				s.EXPECT().Block(adminId, userId).Return(nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"status":"ok"}`,
+		},
+
+		{
+			name:   "Block Yourself",
+			userId: "5f1e3c6d-a5c2-4a4e-9b5e-2f9a2b6b1c01",
+			mockBehavior: func(s *mock_service.MockUsers, adminId, userId string) {
+				s.EXPECT().Block(adminId, userId).Return(domain.ErrBlockYourself)
+			},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"code":"block_yourself","message":"you can't block yourself"}`,
+		},
+
+		{
+			name:   "Not Found",
+			userId: "34c8d3e6-b8d7-43dc-847e-5764c4114856",
+			mockBehavior: func(s *mock_service.MockUsers, adminId, userId string) {
+				s.EXPECT().Block(adminId, userId).Return(domain.ErrUserNotFound)
+			},
+			expectedStatusCode:  404,
+			expectedRequestBody: `{"code":"user_not_found","message":"user not found"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			adminId := "5f1e3c6d-a5c2-4a4e-9b5e-2f9a2b6b1c01"
+
+			users := mock_service.NewMockUsers(c)
+			testCase.mockBehavior(users, adminId, testCase.userId)
+
+			services := &service.Service{Users: users}
+			handler := NewHandler(services)
+
+			// Test Server
+			r := gin.New()
+			r.Use(handler.handleErrors)
+			r.POST("/users/:id/block", func(c *gin.Context) {
+				c.Set(userCtx, adminId)
+			}, handler.blockUser)
+
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/users/"+testCase.userId+"/block", nil)
+
+			// Perform Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedRequestBody, w.Body.String())
+		})
+	}
+}
@@ -6,9 +6,19 @@ import (
 	"time"
 
 	"github.com/AndrewMislyuk/go-shop-backend/internal/domain"
+	"github.com/lib/pq"
 	"github.com/sirupsen/logrus"
 )
 
+// selectUserQuery loads a user together with the roles and the permissions
+// granted by them. It has to be completed with a WHERE and GROUP BY u.id.
+const selectUserQuery = `SELECT u.id, u.name, u.surname, u.email, u.phone, u.password_hash, u.created_at, u.email_verified_at, u.blocked_at, u.deleted_at,
+	COALESCE(array_agg(DISTINCT ur.role) FILTER (WHERE ur.role IS NOT NULL), '{}'),
+	COALESCE(array_agg(DISTINCT rp.permission) FILTER (WHERE rp.permission IS NOT NULL), '{}')
+	FROM users u
+	LEFT JOIN user_roles ur ON ur.user_id = u.id
+	LEFT JOIN role_permissions rp ON rp.role = ur.role`
+
 type AuthPostgres struct {
 	db *sql.DB
 }
@@ -17,14 +27,14 @@ func NewAuthPostgres(db *sql.DB) *AuthPostgres {
 	return &AuthPostgres{db: db}
 }
 
-func (r *AuthPostgres) CreateUser(user domain.UserSignUp, dataId string, timestamp time.Time) (string, error) {
+func (r *AuthPostgres) CreateUser(user domain.UserSignUp, dataId, role string, timestamp time.Time) (string, error) {
 	tx, err := r.db.Begin()
 	if err != nil {
 		return "", err
 	}
 
 	var userId string
-	row, err := tx.Prepare("INSERT INTO users(id, name, surname, email, phone, role, password_hash, created_at) values($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id")
+	row, err := tx.Prepare("INSERT INTO users(id, name, surname, email, phone, password_hash, created_at) values($1, $2, $3, $4, $5, $6, $7) RETURNING id")
 	if err != nil {
 		if rb := tx.Rollback(); rb != nil {
 			logrus.Fatalf("query failed: %v, unable to abort: %v", err, rb)
@@ -35,7 +45,15 @@ func (r *AuthPostgres) CreateUser(user domain.UserSignUp, dataId string, timesta
 
 	defer row.Close()
 
-	if err = row.QueryRow(dataId, user.Name, user.Surname, user.Email, user.Phone, user.Role, user.Password, timestamp).Scan(&userId); err != nil {
+	if err = row.QueryRow(dataId, user.Name, user.Surname, user.Email, user.Phone, user.Password, timestamp).Scan(&userId); err != nil {
+		if rb := tx.Rollback(); rb != nil {
+			logrus.Fatalf("query failed: %v, unable to abort: %v", err, rb)
+		}
+
+		return "", mapEmailTaken(err)
+	}
+
+	if _, err = tx.Exec("INSERT INTO user_roles(user_id, role, granted_at) values($1, $2, $3)", userId, role, timestamp); err != nil {
 		if rb := tx.Rollback(); rb != nil {
 			logrus.Fatalf("query failed: %v, unable to abort: %v", err, rb)
 		}
@@ -46,23 +64,53 @@ func (r *AuthPostgres) CreateUser(user domain.UserSignUp, dataId string, timesta
 	return userId, tx.Commit()
 }
 
-func (r *AuthPostgres) GetUser(email, password string) (domain.User, error) {
-	var userData domain.User
-	rows, err := r.db.Query("SELECT * FROM users WHERE email = $1 AND password_hash = $2", email, password)
-	if err != nil {
-		return userData, err
-	}
-	defer rows.Close()
+func (r *AuthPostgres) GetUserByEmail(email string) (domain.User, error) {
+	return r.getUser(selectUserQuery+" WHERE lower(u.email) = lower($1) GROUP BY u.id", email)
+}
 
-	for rows.Next() {
-		if err := rows.Scan(&userData.Id, &userData.Name, &userData.Surname, &userData.Email, &userData.Phone, &userData.Role, &userData.Password, &userData.CreatedAt); err != nil {
-			return userData, err
-		}
+func (r *AuthPostgres) GetUserById(userId string) (domain.User, error) {
+	return r.getUser(selectUserQuery+" WHERE u.id = $1 GROUP BY u.id", userId)
+}
+
+func (r *AuthPostgres) SetEmailVerified(userId string, timestamp time.Time) error {
+	_, err := r.db.Exec("UPDATE users SET email_verified_at = $1 WHERE id = $2 AND email_verified_at IS NULL", timestamp, userId)
+
+	return err
+}
+
+func (r *AuthPostgres) UpdatePasswordHash(userId, passwordHash string) error {
+	_, err := r.db.Exec("UPDATE users SET password_hash = $1 WHERE id = $2", passwordHash, userId)
+
+	return err
+}
+
+func (r *AuthPostgres) getUser(query string, args ...interface{}) (domain.User, error) {
+	userData, err := scanUser(r.db.QueryRow(query, args...))
+	if errors.Is(err, sql.ErrNoRows) {
+		return userData, domain.ErrUserNotFound
 	}
 
-	if userData.Email == "" || userData.Password == "" {
-		return userData, errors.New("user not register")
+	return userData, err
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanUser(row rowScanner) (domain.User, error) {
+	var userData domain.User
+
+	err := row.Scan(&userData.Id, &userData.Name, &userData.Surname, &userData.Email, &userData.Phone, &userData.Password, &userData.CreatedAt,
+		&userData.EmailVerifiedAt, &userData.BlockedAt, &userData.DeletedAt, pq.Array(&userData.Roles), pq.Array(&userData.Permissions))
+
+	return userData, err
+}
+
+func mapEmailTaken(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation && pqErr.Constraint == "users_email_key" {
+		return domain.ErrEmailTaken
 	}
 
-	return userData, rows.Err()
+	return err
 }
@@ -8,6 +8,7 @@ import (
 
 	"github.com/AndrewMislyuk/go-shop-backend/internal/domain"
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 )
@@ -34,6 +35,7 @@ func TestAuthPostgres_CreateUser(t *testing.T) {
 		mockBehavior mockBehavior
 		args         args
 		wantErr      bool
+		wantErrIs    error
 	}{
 		{
 			name: "OK",
@@ -45,7 +47,6 @@ func TestAuthPostgres_CreateUser(t *testing.T) {
 					Email:    "test@gmail.com",
 					Phone:    "+4456781234",
 					Password: "_9Z9sL~i3H4Kb33jcKJ9-8rZ+&-uRk#",
-					Role:     "ADMIN",
 				},
 				createdAt: time.Now(),
 			},
@@ -55,9 +56,13 @@ func TestAuthPostgres_CreateUser(t *testing.T) {
 				rows := sqlmock.NewRows([]string{"id"}).AddRow(args.dataId)
 				prep := mock.ExpectPrepare("INSERT INTO users")
 				prep.ExpectQuery().
-					WithArgs(args.dataId, args.item.Name, args.item.Surname, args.item.Email, args.item.Phone, args.item.Role, args.item.Password, args.createdAt).
+					WithArgs(args.dataId, args.item.Name, args.item.Surname, args.item.Email, args.item.Phone, args.item.Password, args.createdAt).
 					WillReturnRows(rows)
 
+				mock.ExpectExec("INSERT INTO user_roles").
+					WithArgs(args.dataId, "CUSTOMER", args.createdAt).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
 				mock.ExpectCommit()
 			},
 		},
@@ -72,7 +77,6 @@ func TestAuthPostgres_CreateUser(t *testing.T) {
 					Email:    "",
 					Phone:    "",
 					Password: "",
-					Role:     "",
 				},
 				createdAt: time.Now(),
 			},
@@ -82,22 +86,52 @@ func TestAuthPostgres_CreateUser(t *testing.T) {
 				rows := sqlmock.NewRows([]string{"id"}).AddRow(args.dataId).RowError(0, errors.New("insert error"))
 				prep := mock.ExpectPrepare("INSERT INTO users")
 				prep.ExpectQuery().
-					WithArgs(args.dataId, args.item.Name, args.item.Surname, args.item.Email, args.item.Phone, args.item.Role, args.item.Password, args.createdAt).
+					WithArgs(args.dataId, args.item.Name, args.item.Surname, args.item.Email, args.item.Phone, args.item.Password, args.createdAt).
 					WillReturnRows(rows)
 
 				mock.ExpectRollback()
 			},
 			wantErr: true,
 		},
+
+		{
+			name: "Email Taken",
+			args: args{
+				dataId: "34c8d3e6-b8d7-43dc-847e-5764c4114856",
+				item: domain.UserSignUp{
+					Name:     "Test_User",
+					Surname:  "Test_Surname",
+					Email:    "test@gmail.com",
+					Phone:    "+4456781234",
+					Password: "_9Z9sL~i3H4Kb33jcKJ9-8rZ+&-uRk#",
+				},
+				createdAt: time.Now(),
+			},
+			mockBehavior: func(args args) {
+				mock.ExpectBegin()
+
+				prep := mock.ExpectPrepare("INSERT INTO users")
+				prep.ExpectQuery().
+					WithArgs(args.dataId, args.item.Name, args.item.Surname, args.item.Email, args.item.Phone, args.item.Password, args.createdAt).
+					WillReturnError(&pq.Error{Code: "23505", Constraint: "users_email_key"})
+
+				mock.ExpectRollback()
+			},
+			wantErr:   true,
+			wantErrIs: domain.ErrEmailTaken,
+		},
 	}
 
 	for _, testCase := range testTable {
 		t.Run(testCase.name, func(t *testing.T) {
 			testCase.mockBehavior(testCase.args)
 
-			got, err := a.CreateUser(testCase.args.item, testCase.args.dataId, testCase.args.createdAt)
+			got, err := a.CreateUser(testCase.args.item, testCase.args.dataId, "CUSTOMER", testCase.args.createdAt)
 			if testCase.wantErr {
 				assert.Error(t, err)
+				if testCase.wantErrIs != nil {
+					assert.ErrorIs(t, err, testCase.wantErrIs)
+				}
 			} else {
 				assert.NoError(t, err)
 				assert.Equal(t, testCase.args.dataId, got)
@@ -107,7 +141,7 @@ func TestAuthPostgres_CreateUser(t *testing.T) {
 	}
 }
 
-func TestAuthPostgres_GetUser(t *testing.T) {
+func TestAuthPostgres_GetUserByEmail(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
 		logrus.Fatal(err)
@@ -116,58 +150,48 @@ func TestAuthPostgres_GetUser(t *testing.T) {
 
 	a := NewAuthPostgres(db)
 
-	type args struct {
-		email    string
-		password string
-	}
-
 	testTable := []struct {
 		name    string
 		mock    func()
-		args    args
+		email   string
 		want    domain.User
-		wantErr bool
+		wantErr error
 	}{
 		{
 			name: "OK",
 			mock: func() {
-				rows := sqlmock.NewRows([]string{"id", "name", "surname", "email", "phone", "role", "password_hash", "created_at"}).
-					AddRow("34c8d3e6-b8d7-43dc-847e-5764c4114856", "Test_Name", "Test_Surname", "test@gmail.com", "+4412345678", "ADMIN", "_9Z9sL~i3H4Kb33jcKJ9-8rZ+&-uRk#", time.Date(2022, 01, 12, 13, 8, 21, 32963, time.Local))
+				rows := sqlmock.NewRows([]string{"id", "name", "surname", "email", "phone", "password_hash", "created_at", "email_verified_at", "blocked_at", "deleted_at", "roles", "permissions"}).
+					AddRow("34c8d3e6-b8d7-43dc-847e-5764c4114856", "Test_Name", "Test_Surname", "test@gmail.com", "+4412345678", "_9Z9sL~i3H4Kb33jcKJ9-8rZ+&-uRk#", time.Date(2022, 01, 12, 13, 8, 21, 32963, time.Local), nil, nil, nil, "{ADMIN,CUSTOMER}", "{files:upload,products:write}")
 
-				mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM users WHERE email = $1 AND password_hash = $2")).
-					WithArgs("test@gmail.com", "_9Z9sL~i3H4Kb33jcKJ9-8rZ+&-uRk#").
+				mock.ExpectQuery(regexp.QuoteMeta(selectUserQuery + " WHERE lower(u.email) = lower($1) GROUP BY u.id")).
+					WithArgs("test@gmail.com").
 					WillReturnRows(rows)
 			},
-			args: args{
-				email:    "test@gmail.com",
-				password: "_9Z9sL~i3H4Kb33jcKJ9-8rZ+&-uRk#",
-			},
+			email: "test@gmail.com",
 			want: domain.User{
-				Id:        "34c8d3e6-b8d7-43dc-847e-5764c4114856",
-				Name:      "Test_Name",
-				Surname:   "Test_Surname",
-				Email:     "test@gmail.com",
-				Phone:     "+4412345678",
-				Role:      "ADMIN",
-				Password:  "_9Z9sL~i3H4Kb33jcKJ9-8rZ+&-uRk#",
-				CreatedAt: time.Date(2022, 01, 12, 13, 8, 21, 32963, time.Local),
+				Id:          "34c8d3e6-b8d7-43dc-847e-5764c4114856",
+				Name:        "Test_Name",
+				Surname:     "Test_Surname",
+				Email:       "test@gmail.com",
+				Phone:       "+4412345678",
+				Roles:       []string{"ADMIN", "CUSTOMER"},
+				Permissions: []string{"files:upload", "products:write"},
+				Password:    "_9Z9sL~i3H4Kb33jcKJ9-8rZ+&-uRk#",
+				CreatedAt:   time.Date(2022, 01, 12, 13, 8, 21, 32963, time.Local),
 			},
 		},
 
 		{
 			name: "Not Found",
 			mock: func() {
-				rows := sqlmock.NewRows([]string{"id", "name", "surname", "email", "phone", "role", "password_hash", "created_at"})
+				rows := sqlmock.NewRows([]string{"id", "name", "surname", "email", "phone", "password_hash", "created_at", "email_verified_at", "blocked_at", "deleted_at", "roles", "permissions"})
 
-				mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM users WHERE email = $1 AND password_hash = $2")).
-					WithArgs("test@gmail.com", "_9Z9sL~i3H4Kb33jcKJ9-8rZ+&-uRk#").
+				mock.ExpectQuery(regexp.QuoteMeta(selectUserQuery + " WHERE lower(u.email) = lower($1) GROUP BY u.id")).
+					WithArgs("test@gmail.com").
 					WillReturnRows(rows)
 			},
-			args: args{
-				email:    "test@gmail.com",
-				password: "_9Z9sL~i3H4Kb33jcKJ9-8rZ+&-uRk#",
-			},
-			wantErr: true,
+			email:   "test@gmail.com",
+			wantErr: domain.ErrUserNotFound,
 		},
 	}
 
@@ -175,9 +199,9 @@ func TestAuthPostgres_GetUser(t *testing.T) {
 		t.Run(testCase.name, func(t *testing.T) {
 			testCase.mock()
 
-			got, err := a.GetUser(testCase.args.email, testCase.args.password)
-			if testCase.wantErr {
-				assert.Error(t, err)
+			got, err := a.GetUserByEmail(testCase.email)
+			if testCase.wantErr != nil {
+				assert.ErrorIs(t, err, testCase.wantErr)
 			} else {
 				assert.NoError(t, err)
 				assert.Equal(t, testCase.want, got)
@@ -186,3 +210,21 @@ func TestAuthPostgres_GetUser(t *testing.T) {
 		})
 	}
 }
+
+func TestAuthPostgres_UpdatePasswordHash(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	a := NewAuthPostgres(db)
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE users SET password_hash = $1 WHERE id = $2")).
+		WithArgs("$argon2id$v=19$m=65536,t=3,p=2$c2FsdA$a2V5", "34c8d3e6-b8d7-43dc-847e-5764c4114856").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = a.UpdatePasswordHash("34c8d3e6-b8d7-43dc-847e-5764c4114856", "$argon2id$v=19$m=65536,t=3,p=2$c2FsdA$a2V5")
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
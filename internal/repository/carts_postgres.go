@@ -0,0 +1,244 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/AndrewMislyuk/go-shop-backend/internal/domain"
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+)
+
+const selectCartQuery = "SELECT id, user_id, COALESCE(token_hash, ''), expires_at, created_at, updated_at FROM carts"
+
+// cartItemPrice is the current price of a line after the sales of its
+// product.
+const cartItemPrice = "discounted_price(COALESCE(v.price, p.price), p.sale_percent, p.sale_fixed)"
+
+// selectCartItemsQuery joins the current price and the available stock of
+// every line.
+const selectCartItemsQuery = `SELECT i.id, i.cart_id, i.product_id, i.variant_id, p.title, COALESCE(p.image, ''), COALESCE(v.sku, ''), v.options, i.quantity,
+	` + cartItemPrice + `, p.currency, CASE WHEN ` + cartItemPrice + ` < COALESCE(v.price, p.price) THEN round((COALESCE(v.price, p.price) - ` + cartItemPrice + `) * 100.0 / COALESCE(v.price, p.price))::bigint ELSE 0 END,
+	i.price, COALESCE(l.on_hand - l.reserved, 0), i.created_at
+	FROM cart_items i
+	JOIN products p ON p.id = i.product_id
+	LEFT JOIN product_variants v ON v.id = i.variant_id
+	LEFT JOIN stock_levels l ON l.product_id = i.product_id AND l.variant_id IS NOT DISTINCT FROM i.variant_id`
+
+type CartsPostgres struct {
+	db *sql.DB
+}
+
+func NewCartsPostgres(db *sql.DB) *CartsPostgres {
+	return &CartsPostgres{
+		db: db,
+	}
+}
+
+func (r *CartsPostgres) Create(cart domain.Cart) error {
+	var tokenHash *string
+	if cart.TokenHash != "" {
+		tokenHash = &cart.TokenHash
+	}
+
+	_, err := r.db.Exec("INSERT INTO carts(id, user_id, token_hash, expires_at, created_at, updated_at) values($1, $2, $3, $4, $5, $6)",
+		cart.Id, cart.UserId, tokenHash, cart.ExpiresAt, cart.CreatedAt, cart.UpdatedAt)
+
+	return err
+}
+
+func (r *CartsPostgres) GetByUser(userId string) (domain.Cart, error) {
+	return scanCart(r.db.QueryRow(selectCartQuery+" WHERE user_id = $1", userId))
+}
+
+// GetByToken returns the guest cart unless it has expired by timestamp.
+func (r *CartsPostgres) GetByToken(tokenHash string, timestamp time.Time) (domain.Cart, error) {
+	return scanCart(r.db.QueryRow(selectCartQuery+" WHERE token_hash = $1 AND expires_at > $2", tokenHash, timestamp))
+}
+
+func (r *CartsPostgres) GetItems(cartId string) ([]domain.CartItem, error) {
+	rows, err := r.db.Query(selectCartItemsQuery+" WHERE i.cart_id = $1 ORDER BY i.created_at, i.id", cartId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make([]domain.CartItem, 0)
+	for rows.Next() {
+		var (
+			item    domain.CartItem
+			options []byte
+		)
+
+		if err := rows.Scan(&item.Id, &item.CartId, &item.ProductId, &item.VariantId, &item.Title, &item.Image, &item.Sku, &options, &item.Quantity,
+			&item.UnitPrice.Amount, &item.UnitPrice.Currency, &item.Sale, &item.SeenPrice.Amount, &item.Available, &item.CreatedAt); err != nil {
+			return nil, err
+		}
+
+		item.SeenPrice.Currency = item.UnitPrice.Currency
+
+		if options != nil {
+			if err := json.Unmarshal(options, &item.Options); err != nil {
+				return nil, err
+			}
+		}
+
+		items = append(items, item)
+	}
+
+	return items, rows.Err()
+}
+
+// AddItem adds the line to the cart or increases the quantity of the same
+// product or variant already there, up to maxQuantity.
+func (r *CartsPostgres) AddItem(item domain.CartItem, maxQuantity int) error {
+	_, err := r.db.Exec(`INSERT INTO cart_items(id, cart_id, product_id, variant_id, quantity, price, created_at) values($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (cart_id, product_id, COALESCE(variant_id, product_id))
+		DO UPDATE SET quantity = LEAST(cart_items.quantity + EXCLUDED.quantity, $8), price = EXCLUDED.price, updated_at = EXCLUDED.created_at`,
+		item.Id, item.CartId, item.ProductId, item.VariantId, item.Quantity, item.SeenPrice.Amount, item.CreatedAt, maxQuantity)
+
+	return mapCartItemError(err)
+}
+
+func (r *CartsPostgres) UpdateItemQuantity(cartId, itemId string, quantity int, timestamp time.Time) error {
+	return r.execAffectingCartItem("UPDATE cart_items SET quantity = $1, updated_at = $2 WHERE id = $3 AND cart_id = $4",
+		quantity, timestamp, itemId, cartId)
+}
+
+func (r *CartsPostgres) RemoveItem(cartId, itemId string) error {
+	return r.execAffectingCartItem("DELETE FROM cart_items WHERE id = $1 AND cart_id = $2", itemId, cartId)
+}
+
+// AcceptPrices remembers the current prices as seen by the customer.
+func (r *CartsPostgres) AcceptPrices(cartId string, timestamp time.Time) error {
+	_, err := r.db.Exec(`UPDATE cart_items i SET price = `+cartItemPrice+`, updated_at = $1
+		FROM products p LEFT JOIN product_variants v ON v.product_id = p.id
+		WHERE i.cart_id = $2 AND p.id = i.product_id AND v.id IS NOT DISTINCT FROM i.variant_id AND i.price <> `+cartItemPrice,
+		timestamp, cartId)
+
+	return err
+}
+
+// Touch marks the cart as updated and extends the life of a guest cart.
+func (r *CartsPostgres) Touch(cartId string, timestamp, expiresAt time.Time) error {
+	_, err := r.db.Exec("UPDATE carts SET updated_at = $1, expires_at = CASE WHEN user_id IS NULL THEN $2::timestamp END WHERE id = $3",
+		timestamp, expiresAt, cartId)
+
+	return err
+}
+
+// AssignToUser turns the guest cart into the cart of the user.
+func (r *CartsPostgres) AssignToUser(cartId, userId string, timestamp time.Time) error {
+	_, err := r.db.Exec("UPDATE carts SET user_id = $1, token_hash = NULL, expires_at = NULL, updated_at = $2 WHERE id = $3",
+		userId, timestamp, cartId)
+
+	return err
+}
+
+// Merge moves the lines of the cart fromCartId into toCartId, adding up the
+// quantities of the same products, and deletes the emptied cart.
+func (r *CartsPostgres) Merge(fromCartId, toCartId string, maxQuantity int, timestamp time.Time) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(`INSERT INTO cart_items(id, cart_id, product_id, variant_id, quantity, price, created_at)
+		SELECT gen_random_uuid(), $1, product_id, variant_id, quantity, price, created_at FROM cart_items WHERE cart_id = $2
+		ON CONFLICT (cart_id, product_id, COALESCE(variant_id, product_id))
+		DO UPDATE SET quantity = LEAST(cart_items.quantity + EXCLUDED.quantity, $3), updated_at = $4`,
+		toCartId, fromCartId, maxQuantity, timestamp); err != nil {
+		if rb := tx.Rollback(); rb != nil {
+			logrus.Fatalf("query failed: %v, unable to abort: %v", err, rb)
+		}
+
+		return err
+	}
+
+	if _, err := tx.Exec("DELETE FROM carts WHERE id = $1", fromCartId); err != nil {
+		if rb := tx.Rollback(); rb != nil {
+			logrus.Fatalf("query failed: %v, unable to abort: %v", err, rb)
+		}
+
+		return err
+	}
+
+	if _, err := tx.Exec("UPDATE carts SET updated_at = $1 WHERE id = $2", timestamp, toCartId); err != nil {
+		if rb := tx.Rollback(); rb != nil {
+			logrus.Fatalf("query failed: %v, unable to abort: %v", err, rb)
+		}
+
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Clear removes every line from the cart.
+func (r *CartsPostgres) Clear(cartId string) error {
+	_, err := r.db.Exec("DELETE FROM cart_items WHERE cart_id = $1", cartId)
+
+	return err
+}
+
+// DeleteExpired deletes guest carts abandoned until timestamp.
+func (r *CartsPostgres) DeleteExpired(timestamp time.Time) (int, error) {
+	res, err := r.db.Exec("DELETE FROM carts WHERE user_id IS NULL AND expires_at <= $1", timestamp)
+	if err != nil {
+		return 0, err
+	}
+
+	deleted, err := res.RowsAffected()
+
+	return int(deleted), err
+}
+
+func (r *CartsPostgres) execAffectingCartItem(query string, args ...interface{}) error {
+	res, err := r.db.Exec(query, args...)
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return domain.ErrCartItemNotFound
+	}
+
+	return nil
+}
+
+func scanCart(row rowScanner) (domain.Cart, error) {
+	var cart domain.Cart
+
+	if err := row.Scan(&cart.Id, &cart.UserId, &cart.TokenHash, &cart.ExpiresAt, &cart.CreatedAt, &cart.UpdatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return cart, domain.ErrCartNotFound
+		}
+
+		return cart, err
+	}
+
+	return cart, nil
+}
+
+func mapCartItemError(err error) error {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) || pqErr.Code != foreignKeyViolation {
+		return err
+	}
+
+	switch pqErr.Constraint {
+	case "cart_items_variant_id_fkey":
+		return domain.ErrVariantNotFound
+	case "cart_items_cart_id_fkey":
+		return domain.ErrCartNotFound
+	default:
+		return domain.ErrProductNotFound
+	}
+}
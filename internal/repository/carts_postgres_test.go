@@ -0,0 +1,192 @@
+package repository
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/AndrewMislyuk/go-shop-backend/internal/domain"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCartsPostgres_GetByToken(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	r := NewCartsPostgres(db)
+
+	timestamp := time.Now()
+	expiresAt := timestamp.Add(time.Hour)
+
+	testTable := []struct {
+		name    string
+		mock    func()
+		want    domain.Cart
+		wantErr error
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id", "user_id", "token_hash", "expires_at", "created_at", "updated_at"}).
+					AddRow("8f1e2d3c-4b5a-4697-8877-665544332211", nil, "hash", expiresAt, timestamp, timestamp)
+				mock.ExpectQuery(regexp.QuoteMeta(selectCartQuery+" WHERE token_hash = $1 AND expires_at > $2")).
+					WithArgs("hash", timestamp).
+					WillReturnRows(rows)
+			},
+			want: domain.Cart{Id: "8f1e2d3c-4b5a-4697-8877-665544332211", TokenHash: "hash", ExpiresAt: &expiresAt, CreatedAt: timestamp, UpdatedAt: timestamp},
+		},
+
+		{
+			name: "Expired Or Unknown",
+			mock: func() {
+				mock.ExpectQuery(regexp.QuoteMeta(selectCartQuery+" WHERE token_hash = $1 AND expires_at > $2")).
+					WithArgs("hash", timestamp).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "token_hash", "expires_at", "created_at", "updated_at"}))
+			},
+			wantErr: domain.ErrCartNotFound,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mock()
+
+			got, err := r.GetByToken("hash", timestamp)
+			if testCase.wantErr != nil {
+				assert.ErrorIs(t, err, testCase.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, testCase.want, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestCartsPostgres_AddItem(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	r := NewCartsPostgres(db)
+
+	variantId := "c1a5f0de-7c1b-4f55-9f0e-3c2a1b0d9e01"
+	item := domain.CartItem{
+		Id:        "1a2b3c4d-5e6f-4789-8abc-def012345678",
+		CartId:    "8f1e2d3c-4b5a-4697-8877-665544332211",
+		ProductId: "72c07fab-dd14-47fa-b478-d59255dcf8dd",
+		VariantId: &variantId,
+		Quantity:  2,
+		SeenPrice: domain.NewMoney(179000, "RUB"),
+		CreatedAt: time.Now(),
+	}
+
+	testTable := []struct {
+		name    string
+		mock    func()
+		wantErr error
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				mock.ExpectExec("INSERT INTO cart_items(.+) ON CONFLICT (.+) DO UPDATE SET quantity = LEAST").
+					WithArgs(item.Id, item.CartId, item.ProductId, variantId, 2, uint(179000), item.CreatedAt, domain.MaxCartItemQuantity).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+
+		{
+			name: "Variant Deleted",
+			mock: func() {
+				mock.ExpectExec("INSERT INTO cart_items").
+					WillReturnError(&pq.Error{Code: foreignKeyViolation, Constraint: "cart_items_variant_id_fkey"})
+			},
+			wantErr: domain.ErrVariantNotFound,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mock()
+
+			err := r.AddItem(item, domain.MaxCartItemQuantity)
+			if testCase.wantErr != nil {
+				assert.ErrorIs(t, err, testCase.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestCartsPostgres_Merge(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	r := NewCartsPostgres(db)
+
+	timestamp := time.Now()
+	guestId := "8f1e2d3c-4b5a-4697-8877-665544332211"
+	userCartId := "2c3d4e5f-6a7b-4c8d-9e0f-112233445566"
+
+	testTable := []struct {
+		name    string
+		mock    func()
+		wantErr bool
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("INSERT INTO cart_items(.+) SELECT (.+) FROM cart_items WHERE cart_id = \\$2").
+					WithArgs(userCartId, guestId, domain.MaxCartItemQuantity, timestamp).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectExec(regexp.QuoteMeta("DELETE FROM carts WHERE id = $1")).
+					WithArgs(guestId).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE carts SET updated_at = $1 WHERE id = $2")).
+					WithArgs(timestamp, userCartId).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+
+		{
+			name: "Failed Move",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("INSERT INTO cart_items").
+					WillReturnError(errors.New("insert error"))
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mock()
+
+			err := r.Merge(guestId, userCartId, domain.MaxCartItemQuantity, timestamp)
+			if testCase.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/AndrewMislyuk/go-shop-backend/internal/domain"
+	"github.com/lib/pq"
+)
+
+const selectCategoryQuery = "SELECT id, parent_id, level, slug, names, position, created_at, updated_at FROM categories"
+
+type CategoriesPostgres struct {
+	db *sql.DB
+}
+
+func NewCategoriesPostgres(db *sql.DB) *CategoriesPostgres {
+	return &CategoriesPostgres{
+		db: db,
+	}
+}
+
+func (r *CategoriesPostgres) Create(category domain.Category) error {
+	names, err := json.Marshal(category.Names)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.Exec("INSERT INTO categories(id, parent_id, level, slug, names, position, created_at) values($1, $2, $3, $4, $5, $6, $7)",
+		category.Id, category.ParentId, category.Level, category.Slug, string(names), category.Position, category.CreatedAt)
+
+	// The parent is checked by the service, this only happens if it was
+	// deleted in the meantime.
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == foreignKeyViolation {
+		return domain.ErrCategoryNotFound
+	}
+
+	return mapCategoryError(err)
+}
+
+func (r *CategoriesPostgres) GetById(categoryId string) (domain.Category, error) {
+	category, err := scanCategory(r.db.QueryRow(selectCategoryQuery+" WHERE id = $1", categoryId))
+	if errors.Is(err, sql.ErrNoRows) {
+		return category, domain.ErrCategoryNotFound
+	}
+
+	return category, err
+}
+
+// GetAllWithCounts returns all categories ordered for display with the number
+// of products assigned directly to each of them.
+func (r *CategoriesPostgres) GetAllWithCounts() ([]domain.CategoryNode, error) {
+	rows, err := r.db.Query(`SELECT c.id, c.parent_id, c.level, c.slug, c.names, c.position, c.created_at, c.updated_at, count(p.id)
+		FROM categories c LEFT JOIN products p ON p.category_id = c.id
+		GROUP BY c.id ORDER BY c.level, c.position, c.slug`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	nodes := make([]domain.CategoryNode, 0)
+	for rows.Next() {
+		var (
+			node  domain.CategoryNode
+			names []byte
+		)
+
+		if err := rows.Scan(&node.Id, &node.ParentId, &node.Level, &node.Slug, &names, &node.Position, &node.CreatedAt, &node.UpdatedAt, &node.ProductsCount); err != nil {
+			return nil, err
+		}
+
+		if err := json.Unmarshal(names, &node.Names); err != nil {
+			return nil, err
+		}
+
+		nodes = append(nodes, node)
+	}
+
+	return nodes, rows.Err()
+}
+
+func (r *CategoriesPostgres) Update(categoryId string, input domain.UpdateCategoryInput, timestamp time.Time) error {
+	setValues := make([]string, 0)
+	args := make([]interface{}, 0)
+	argId := 1
+
+	if input.Slug != nil {
+		setValues = append(setValues, fmt.Sprintf("slug=$%d", argId))
+		args = append(args, *input.Slug)
+		argId++
+	}
+
+	if input.Names != nil {
+		names, err := json.Marshal(input.Names)
+		if err != nil {
+			return err
+		}
+
+		setValues = append(setValues, fmt.Sprintf("names=$%d", argId))
+		args = append(args, string(names))
+		argId++
+	}
+
+	if input.Position != nil {
+		setValues = append(setValues, fmt.Sprintf("position=$%d", argId))
+		args = append(args, *input.Position)
+		argId++
+	}
+
+	setValues = append(setValues, fmt.Sprintf("updated_at=$%d", argId))
+	args = append(args, timestamp)
+	argId++
+
+	query := fmt.Sprintf("UPDATE categories SET %s WHERE id = $%d", strings.Join(setValues, ", "), argId)
+	args = append(args, categoryId)
+
+	return mapCategoryError(r.execAffectingCategory(query, args...))
+}
+
+// Delete removes a category without subcategories and products.
+func (r *CategoriesPostgres) Delete(categoryId string) error {
+	return mapCategoryError(r.execAffectingCategory("DELETE FROM categories WHERE id = $1", categoryId))
+}
+
+func (r *CategoriesPostgres) execAffectingCategory(query string, args ...interface{}) error {
+	res, err := r.db.Exec(query, args...)
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return domain.ErrCategoryNotFound
+	}
+
+	return nil
+}
+
+func scanCategory(row rowScanner) (domain.Category, error) {
+	var (
+		category domain.Category
+		names    []byte
+	)
+
+	if err := row.Scan(&category.Id, &category.ParentId, &category.Level, &category.Slug, &names, &category.Position, &category.CreatedAt, &category.UpdatedAt); err != nil {
+		return category, err
+	}
+
+	return category, json.Unmarshal(names, &category.Names)
+}
+
+// mapCategoryError translates constraint violations: a taken slug or a
+// category that is still referenced by subcategories or products.
+func mapCategoryError(err error) error {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return err
+	}
+
+	switch pqErr.Code {
+	case uniqueViolation:
+		return domain.ErrCategorySlugTaken
+	case foreignKeyViolation:
+		return domain.ErrCategoryNotEmpty
+	default:
+		return err
+	}
+}
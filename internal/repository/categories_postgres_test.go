@@ -0,0 +1,168 @@
+package repository
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/AndrewMislyuk/go-shop-backend/internal/domain"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCategoriesPostgres_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	r := NewCategoriesPostgres(db)
+
+	parentId := "0b5a3c1e-6a43-4b5e-8a1d-0f7f2d9a1c01"
+	category := domain.Category{
+		Id:        "5c2d9e7a-1f0b-4a3c-9e8d-7b6a5c4d3e02",
+		ParentId:  &parentId,
+		Level:     domain.CategoryLevelType,
+		Slug:      "obuv",
+		Names:     map[string]string{"ru": "Обувь"},
+		Position:  2,
+		CreatedAt: time.Now(),
+	}
+
+	testTable := []struct {
+		name    string
+		mock    func()
+		wantErr error
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO categories(id, parent_id, level, slug, names, position, created_at) values($1, $2, $3, $4, $5, $6, $7)")).
+					WithArgs(category.Id, category.ParentId, category.Level, category.Slug, `{"ru":"Обувь"}`, category.Position, category.CreatedAt).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+
+		{
+			name: "Slug Taken",
+			mock: func() {
+				mock.ExpectExec("INSERT INTO categories").
+					WillReturnError(&pq.Error{Code: uniqueViolation, Constraint: "categories_parent_id_slug_key"})
+			},
+			wantErr: domain.ErrCategorySlugTaken,
+		},
+
+		{
+			name: "Parent Deleted",
+			mock: func() {
+				mock.ExpectExec("INSERT INTO categories").
+					WillReturnError(&pq.Error{Code: foreignKeyViolation, Constraint: "categories_parent_id_fkey"})
+			},
+			wantErr: domain.ErrCategoryNotFound,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mock()
+
+			err := r.Create(category)
+			if testCase.wantErr != nil {
+				assert.ErrorIs(t, err, testCase.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestCategoriesPostgres_GetAllWithCounts(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	r := NewCategoriesPostgres(db)
+
+	createdAt := time.Date(2022, 01, 12, 13, 8, 21, 0, time.Local)
+	rootId := "0b5a3c1e-6a43-4b5e-8a1d-0f7f2d9a1c01"
+
+	rows := sqlmock.NewRows([]string{"id", "parent_id", "level", "slug", "names", "position", "created_at", "updated_at", "count"}).
+		AddRow(rootId, nil, 1, "zhenshchinam", []byte(`{"ru": "Женщинам", "en": "Women"}`), 0, createdAt, nil, 0).
+		AddRow("5c2d9e7a-1f0b-4a3c-9e8d-7b6a5c4d3e02", rootId, 2, "odezhda", []byte(`{"ru": "Одежда"}`), 0, createdAt, nil, 0)
+
+	mock.ExpectQuery("SELECT (.+) FROM categories c LEFT JOIN products p ON p.category_id = c.id GROUP BY c.id ORDER BY c.level, c.position, c.slug").
+		WillReturnRows(rows)
+
+	got, err := r.GetAllWithCounts()
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.CategoryNode{
+		{Category: domain.Category{Id: rootId, Level: 1, Slug: "zhenshchinam", Names: map[string]string{"ru": "Женщинам", "en": "Women"}, CreatedAt: createdAt}},
+		{Category: domain.Category{Id: "5c2d9e7a-1f0b-4a3c-9e8d-7b6a5c4d3e02", ParentId: &rootId, Level: 2, Slug: "odezhda", Names: map[string]string{"ru": "Одежда"}, CreatedAt: createdAt}},
+	}, got)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCategoriesPostgres_Delete(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	r := NewCategoriesPostgres(db)
+
+	testTable := []struct {
+		name    string
+		mock    func()
+		wantErr error
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				mock.ExpectExec(regexp.QuoteMeta("DELETE FROM categories WHERE id = $1")).
+					WithArgs("0b5a3c1e-6a43-4b5e-8a1d-0f7f2d9a1c01").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+
+		{
+			name: "Not Found",
+			mock: func() {
+				mock.ExpectExec(regexp.QuoteMeta("DELETE FROM categories WHERE id = $1")).
+					WithArgs("0b5a3c1e-6a43-4b5e-8a1d-0f7f2d9a1c01").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			wantErr: domain.ErrCategoryNotFound,
+		},
+
+		{
+			name: "Has Products",
+			mock: func() {
+				mock.ExpectExec(regexp.QuoteMeta("DELETE FROM categories WHERE id = $1")).
+					WithArgs("0b5a3c1e-6a43-4b5e-8a1d-0f7f2d9a1c01").
+					WillReturnError(&pq.Error{Code: foreignKeyViolation, Constraint: "products_category_id_fkey"})
+			},
+			wantErr: domain.ErrCategoryNotEmpty,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mock()
+
+			err := r.Delete("0b5a3c1e-6a43-4b5e-8a1d-0f7f2d9a1c01")
+			if testCase.wantErr != nil {
+				assert.ErrorIs(t, err, testCase.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/AndrewMislyuk/go-shop-backend/internal/domain"
+	"github.com/lib/pq"
+)
+
+const selectDiscountQuery = "SELECT id, name, kind, value, product_id, category_id, starts_at, ends_at, active, created_at, updated_at FROM discounts"
+
+type DiscountsPostgres struct {
+	db *sql.DB
+}
+
+func NewDiscountsPostgres(db *sql.DB) *DiscountsPostgres {
+	return &DiscountsPostgres{
+		db: db,
+	}
+}
+
+func (r *DiscountsPostgres) Create(discount domain.Discount) error {
+	_, err := r.db.Exec(`INSERT INTO discounts(id, name, kind, value, product_id, category_id, starts_at, ends_at, active, created_at)
+		values($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`,
+		discount.Id, discount.Name, discount.Kind, discount.Value, discount.ProductId, discount.CategoryId, discount.StartsAt, discount.EndsAt,
+		discount.Active, discount.CreatedAt)
+
+	return mapDiscountError(err)
+}
+
+func (r *DiscountsPostgres) GetById(discountId string) (domain.Discount, error) {
+	discount, err := scanDiscount(r.db.QueryRow(selectDiscountQuery+" WHERE id = $1", discountId))
+	if errors.Is(err, sql.ErrNoRows) {
+		return discount, domain.ErrDiscountNotFound
+	}
+
+	return discount, err
+}
+
+// GetAll returns the discounts, newest first.
+func (r *DiscountsPostgres) GetAll(filter domain.DiscountsFilter) (domain.DiscountsList, error) {
+	conditions := make([]string, 0)
+	args := make([]interface{}, 0)
+	argId := 1
+
+	if filter.ProductId != "" {
+		conditions = append(conditions, fmt.Sprintf("product_id = $%d", argId))
+		args = append(args, filter.ProductId)
+		argId++
+	}
+
+	if filter.CategoryId != "" {
+		conditions = append(conditions, fmt.Sprintf("category_id = $%d", argId))
+		args = append(args, filter.CategoryId)
+		argId++
+	}
+
+	list := domain.DiscountsList{
+		Discounts: make([]domain.Discount, 0),
+	}
+
+	if err := r.db.QueryRow("SELECT count(*) FROM discounts"+whereClause(conditions), args...).Scan(&list.Total); err != nil {
+		return list, err
+	}
+
+	query := fmt.Sprintf("%s%s ORDER BY created_at DESC, id DESC LIMIT $%d OFFSET $%d", selectDiscountQuery, whereClause(conditions), argId, argId+1)
+	args = append(args, filter.Limit, filter.Offset)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return list, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		discount, err := scanDiscount(rows)
+		if err != nil {
+			return list, err
+		}
+
+		list.Discounts = append(list.Discounts, discount)
+	}
+
+	return list, rows.Err()
+}
+
+// Update saves the changeable fields of the discount.
+func (r *DiscountsPostgres) Update(discount domain.Discount) error {
+	return r.execAffectingDiscount("UPDATE discounts SET name = $1, kind = $2, value = $3, starts_at = $4, ends_at = $5, active = $6, updated_at = $7 WHERE id = $8",
+		discount.Name, discount.Kind, discount.Value, discount.StartsAt, discount.EndsAt, discount.Active, discount.UpdatedAt, discount.Id)
+}
+
+func (r *DiscountsPostgres) Delete(discountId string) error {
+	return r.execAffectingDiscount("DELETE FROM discounts WHERE id = $1", discountId)
+}
+
+// RefreshSales copies the sales that have started or ended since the last
+// refresh to the products, changes of the discounts themselves are copied by
+// the triggers at once. It returns the number of products refreshed.
+func (r *DiscountsPostgres) RefreshSales() (int, error) {
+	var refreshed int
+	err := r.db.QueryRow("SELECT refresh_product_sales()").Scan(&refreshed)
+
+	return refreshed, err
+}
+
+func (r *DiscountsPostgres) execAffectingDiscount(query string, args ...interface{}) error {
+	res, err := r.db.Exec(query, args...)
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return domain.ErrDiscountNotFound
+	}
+
+	return nil
+}
+
+func scanDiscount(row rowScanner) (domain.Discount, error) {
+	var discount domain.Discount
+
+	err := row.Scan(&discount.Id, &discount.Name, &discount.Kind, &discount.Value, &discount.ProductId, &discount.CategoryId, &discount.StartsAt,
+		&discount.EndsAt, &discount.Active, &discount.CreatedAt, &discount.UpdatedAt)
+
+	return discount, err
+}
+
+// mapDiscountError reports a missing product or category of a new discount.
+func mapDiscountError(err error) error {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) || pqErr.Code != foreignKeyViolation {
+		return err
+	}
+
+	if pqErr.Constraint == "discounts_product_id_fkey" {
+		return domain.ErrProductNotFound
+	}
+
+	return domain.ErrCategoryNotFound
+}
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/AndrewMislyuk/go-shop-backend/internal/domain"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiscountsPostgres_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	r := NewDiscountsPostgres(db)
+
+	productId := "453b4f0f-1f56-4c57-b43d-7b79792450a7"
+	timestamp := time.Now()
+	discount := domain.Discount{
+		Id:        "3f1c2b4a-8d7e-4a69-b5c3-2e1d0f9a8b7c",
+		Name:      "Скидка 20%",
+		Kind:      domain.DiscountPercent,
+		Value:     20,
+		ProductId: &productId,
+		StartsAt:  timestamp,
+		Active:    true,
+		CreatedAt: timestamp,
+	}
+
+	testTable := []struct {
+		name    string
+		mock    func()
+		wantErr error
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				mock.ExpectExec("INSERT INTO discounts").
+					WithArgs(discount.Id, "Скидка 20%", domain.DiscountPercent, uint(20), &productId, nil, timestamp, nil, true, timestamp).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+
+		{
+			name: "Product Not Found",
+			mock: func() {
+				mock.ExpectExec("INSERT INTO discounts").
+					WillReturnError(&pq.Error{Code: foreignKeyViolation, Constraint: "discounts_product_id_fkey"})
+			},
+			wantErr: domain.ErrProductNotFound,
+		},
+
+		{
+			name: "Category Not Found",
+			mock: func() {
+				mock.ExpectExec("INSERT INTO discounts").
+					WillReturnError(&pq.Error{Code: foreignKeyViolation, Constraint: "discounts_category_id_fkey"})
+			},
+			wantErr: domain.ErrCategoryNotFound,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mock()
+
+			err := r.Create(discount)
+			if testCase.wantErr != nil {
+				assert.ErrorIs(t, err, testCase.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestDiscountsPostgres_RefreshSales(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	r := NewDiscountsPostgres(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT refresh_product_sales()")).
+		WillReturnRows(sqlmock.NewRows([]string{"refresh_product_sales"}).AddRow(2))
+
+	refreshed, err := r.RefreshSales()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, refreshed)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
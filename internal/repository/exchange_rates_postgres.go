@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/AndrewMislyuk/go-shop-backend/internal/domain"
+	"github.com/sirupsen/logrus"
+)
+
+const selectExchangeRateQuery = "SELECT currency, rate, updated_at FROM exchange_rates"
+
+type ExchangeRatesPostgres struct {
+	db *sql.DB
+}
+
+func NewExchangeRatesPostgres(db *sql.DB) *ExchangeRatesPostgres {
+	return &ExchangeRatesPostgres{
+		db: db,
+	}
+}
+
+func (r *ExchangeRatesPostgres) GetAll() ([]domain.ExchangeRate, error) {
+	rows, err := r.db.Query(selectExchangeRateQuery + " ORDER BY currency")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rates := make([]domain.ExchangeRate, 0)
+	for rows.Next() {
+		var rate domain.ExchangeRate
+		if err := rows.Scan(&rate.Currency, &rate.Rate, &rate.UpdatedAt); err != nil {
+			return nil, err
+		}
+
+		rates = append(rates, rate)
+	}
+
+	return rates, rows.Err()
+}
+
+func (r *ExchangeRatesPostgres) Get(currency string) (domain.ExchangeRate, error) {
+	var rate domain.ExchangeRate
+
+	err := r.db.QueryRow(selectExchangeRateQuery+" WHERE currency = $1", currency).Scan(&rate.Currency, &rate.Rate, &rate.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return rate, domain.ErrExchangeRateNotFound
+	}
+
+	return rate, err
+}
+
+// Set replaces the rates of the given currencies at once, the rates of other
+// currencies are kept.
+func (r *ExchangeRatesPostgres) Set(rates []domain.ExchangeRate) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	if err := setExchangeRates(tx, rates); err != nil {
+		if rb := tx.Rollback(); rb != nil {
+			logrus.Fatalf("query failed: %v, unable to abort: %v", err, rb)
+		}
+
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func setExchangeRates(tx *sql.Tx, rates []domain.ExchangeRate) error {
+	for _, rate := range rates {
+		if _, err := tx.Exec(`INSERT INTO exchange_rates(currency, rate, updated_at) values($1, $2, $3)
+			ON CONFLICT (currency) DO UPDATE SET rate = EXCLUDED.rate, updated_at = EXCLUDED.updated_at`,
+			rate.Currency, rate.Rate, rate.UpdatedAt); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/AndrewMislyuk/go-shop-backend/internal/domain"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExchangeRatesPostgres_Get(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	r := NewExchangeRatesPostgres(db)
+
+	updatedAt := time.Date(2022, 03, 01, 0, 0, 0, 0, time.Local)
+
+	mock.ExpectQuery(regexp.QuoteMeta(selectExchangeRateQuery + " WHERE currency = $1")).
+		WithArgs("USD").
+		WillReturnRows(sqlmock.NewRows([]string{"currency", "rate", "updated_at"}).AddRow("USD", "0.0108000000", updatedAt))
+	mock.ExpectQuery(regexp.QuoteMeta(selectExchangeRateQuery + " WHERE currency = $1")).
+		WithArgs("JPY").
+		WillReturnRows(sqlmock.NewRows([]string{"currency", "rate", "updated_at"}))
+
+	got, err := r.Get("USD")
+	assert.NoError(t, err)
+	assert.Equal(t, domain.ExchangeRate{Currency: "USD", Rate: "0.0108000000", UpdatedAt: updatedAt}, got)
+
+	_, err = r.Get("JPY")
+	assert.ErrorIs(t, err, domain.ErrExchangeRateNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestExchangeRatesPostgres_Set(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	r := NewExchangeRatesPostgres(db)
+
+	updatedAt := time.Now()
+	rates := []domain.ExchangeRate{
+		{Currency: "USD", Rate: "0.0108", UpdatedAt: updatedAt},
+		{Currency: "EUR", Rate: "0.0100", UpdatedAt: updatedAt},
+	}
+
+	testTable := []struct {
+		name    string
+		mock    func()
+		wantErr bool
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("INSERT INTO exchange_rates(.+) ON CONFLICT \\(currency\\) DO UPDATE").
+					WithArgs("USD", "0.0108", updatedAt).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("INSERT INTO exchange_rates").
+					WithArgs("EUR", "0.0100", updatedAt).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+
+		{
+			name: "Rolled Back",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("INSERT INTO exchange_rates").
+					WithArgs("USD", "0.0108", updatedAt).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("INSERT INTO exchange_rates").
+					WithArgs("EUR", "0.0100", updatedAt).
+					WillReturnError(errors.New("numeric field overflow"))
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mock()
+
+			err := r.Set(rates)
+			if testCase.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,428 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/AndrewMislyuk/go-shop-backend/internal/domain"
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+)
+
+const selectFileQuery = `SELECT id, product_id, object_name, url, content_type, size, checksum, status, position, is_primary, alt_text, attempts, error,
+	upload_started_at, created_at, updated_at, renditions FROM product_images`
+
+// uploaded limits the queries of the galleries to the images pushed to the
+// storage.
+const uploaded = "status = 4"
+
+// rendition is the stored form of domain.FileRendition, which hides the
+// object name from the API.
+type rendition struct {
+	Name        string `json:"name"`
+	Format      string `json:"format"`
+	ContentType string `json:"content_type"`
+	Width       int    `json:"width"`
+	Height      int    `json:"height"`
+	Size        int64  `json:"size"`
+	URL         string `json:"url"`
+	ObjectName  string `json:"object_name"`
+}
+
+type FilesPostgres struct {
+	db *sql.DB
+}
+
+func NewFilesPostgres(db *sql.DB) *FilesPostgres {
+	return &FilesPostgres{
+		db: db,
+	}
+}
+
+// Create records the upload of an image, it joins the end of the gallery of
+// its product once it is pushed to the storage.
+func (r *FilesPostgres) Create(file domain.File) (domain.File, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return file, err
+	}
+
+	file, err = createFile(tx, file)
+	if err != nil {
+		if rb := tx.Rollback(); rb != nil {
+			logrus.Fatalf("query failed: %v, unable to abort: %v", err, rb)
+		}
+
+		return file, err
+	}
+
+	return file, tx.Commit()
+}
+
+func createFile(tx *sql.Tx, file domain.File) (domain.File, error) {
+	if err := lockProduct(tx, file.ProductId); err != nil {
+		return file, err
+	}
+
+	row := tx.QueryRow("SELECT COALESCE(MAX(position) + 1, 0) FROM product_images WHERE product_id = $1", file.ProductId)
+	if err := row.Scan(&file.Position); err != nil {
+		return file, err
+	}
+
+	file.Primary = false
+
+	_, err := tx.Exec(`INSERT INTO product_images(id, product_id, object_name, content_type, size, checksum, status, position, alt_text, upload_started_at, created_at)
+		values($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`,
+		file.ID, file.ProductId, file.ObjectName, file.ContentType, file.Size, file.Checksum, file.Status, file.Position, file.AltText, file.UploadStartedAt, file.CreatedAt)
+
+	return file, err
+}
+
+// GetById returns the file whatever its status.
+func (r *FilesPostgres) GetById(fileId string) (domain.File, error) {
+	file, err := scanFile(r.db.QueryRow(selectFileQuery+" WHERE id = $1", fileId))
+	if errors.Is(err, sql.ErrNoRows) {
+		return file, domain.ErrImageNotFound
+	}
+
+	return file, err
+}
+
+func (r *FilesPostgres) GetByProduct(productId string) ([]domain.File, error) {
+	rows, err := r.db.Query(selectFileQuery+" WHERE product_id = $1 AND "+uploaded+" ORDER BY position, created_at", productId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	files := make([]domain.File, 0)
+	for rows.Next() {
+		file, err := scanFile(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		files = append(files, file)
+	}
+
+	return files, rows.Err()
+}
+
+// GetByProducts returns the galleries of the products by product id, products
+// without images are left out.
+func (r *FilesPostgres) GetByProducts(productIds []string) (map[string][]domain.File, error) {
+	rows, err := r.db.Query(selectFileQuery+" WHERE product_id = ANY($1) AND "+uploaded+" ORDER BY position, created_at", pq.Array(productIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	files := make(map[string][]domain.File)
+	for rows.Next() {
+		file, err := scanFile(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		files[file.ProductId] = append(files[file.ProductId], file)
+	}
+
+	return files, rows.Err()
+}
+
+func (r *FilesPostgres) Update(productId, fileId string, input domain.UpdateImageInput) error {
+	res, err := r.db.Exec("UPDATE product_images SET alt_text=$1 WHERE id = $2 AND product_id = $3", *input.AltText, fileId, productId)
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return domain.ErrImageNotFound
+	}
+
+	return nil
+}
+
+// Reorder moves the images to the positions of their ids in fileIds, which
+// has to list every image of the product.
+func (r *FilesPostgres) Reorder(productId string, fileIds []string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	if err := reorderFiles(tx, productId, fileIds); err != nil {
+		if rb := tx.Rollback(); rb != nil {
+			logrus.Fatalf("query failed: %v, unable to abort: %v", err, rb)
+		}
+
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func reorderFiles(tx *sql.Tx, productId string, fileIds []string) error {
+	if err := lockProduct(tx, productId); err != nil {
+		return err
+	}
+
+	var count int
+	if err := tx.QueryRow("SELECT COUNT(*) FROM product_images WHERE product_id = $1 AND "+uploaded, productId).Scan(&count); err != nil {
+		return err
+	}
+
+	if count != len(fileIds) {
+		return domain.ErrInvalidImagesOrder
+	}
+
+	for position, id := range fileIds {
+		res, err := tx.Exec("UPDATE product_images SET position=$1 WHERE id = $2 AND product_id = $3 AND "+uploaded, position, id, productId)
+		if err != nil {
+			return err
+		}
+
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		if affected == 0 {
+			return domain.ErrInvalidImagesOrder
+		}
+	}
+
+	return nil
+}
+
+// SetPrimary makes the image the primary one of its product, the image of
+// the product in the lists.
+func (r *FilesPostgres) SetPrimary(productId, fileId string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	if err := setPrimaryFile(tx, productId, fileId); err != nil {
+		if rb := tx.Rollback(); rb != nil {
+			logrus.Fatalf("query failed: %v, unable to abort: %v", err, rb)
+		}
+
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func setPrimaryFile(tx *sql.Tx, productId, fileId string) error {
+	if err := lockProduct(tx, productId); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec("UPDATE product_images SET is_primary=false WHERE product_id = $1 AND is_primary", productId); err != nil {
+		return err
+	}
+
+	var url string
+	err := tx.QueryRow("UPDATE product_images SET is_primary=true WHERE id = $1 AND product_id = $2 AND "+uploaded+" RETURNING url", fileId, productId).Scan(&url)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.ErrImageNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec("UPDATE products SET image=$1 WHERE id = $2", url, productId)
+
+	return err
+}
+
+// Delete removes the image from the gallery. When it was the primary image,
+// the first of the remaining ones takes its place.
+func (r *FilesPostgres) Delete(productId, fileId string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	if err := deleteFile(tx, productId, fileId); err != nil {
+		if rb := tx.Rollback(); rb != nil {
+			logrus.Fatalf("query failed: %v, unable to abort: %v", err, rb)
+		}
+
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func deleteFile(tx *sql.Tx, productId, fileId string) error {
+	if err := lockProduct(tx, productId); err != nil {
+		return err
+	}
+
+	var primary bool
+	err := tx.QueryRow("DELETE FROM product_images WHERE id = $1 AND product_id = $2 RETURNING is_primary", fileId, productId).Scan(&primary)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.ErrImageNotFound
+	}
+	if err != nil || !primary {
+		return err
+	}
+
+	var url sql.NullString
+	err = tx.QueryRow(`UPDATE product_images SET is_primary=true
+		WHERE id = (SELECT id FROM product_images WHERE product_id = $1 AND `+uploaded+` ORDER BY position, created_at LIMIT 1) RETURNING url`, productId).Scan(&url)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+
+	_, err = tx.Exec("UPDATE products SET image=$1 WHERE id = $2", url, productId)
+
+	return err
+}
+
+// UpdateStatus moves the file from status from to file.Status, recording its
+// attempts and error. The file must still be in from.
+func (r *FilesPostgres) UpdateStatus(file domain.File, from domain.FileStatus) error {
+	res, err := r.db.Exec("UPDATE product_images SET status=$1, attempts=$2, error=$3, updated_at=$4 WHERE id = $5 AND status = $6",
+		file.Status, file.Attempts, file.Error, file.UpdatedAt, file.ID, from)
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return domain.ErrFileStatusChanged
+	}
+
+	return nil
+}
+
+// Complete adds the file pushed to the storage to the gallery. It becomes
+// the primary image when its product has none.
+func (r *FilesPostgres) Complete(file domain.File) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	if err := completeFile(tx, file); err != nil {
+		if rb := tx.Rollback(); rb != nil {
+			logrus.Fatalf("query failed: %v, unable to abort: %v", err, rb)
+		}
+
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func completeFile(tx *sql.Tx, file domain.File) error {
+	if err := lockProduct(tx, file.ProductId); err != nil {
+		return err
+	}
+
+	renditions := make([]rendition, len(file.Renditions))
+	for i, r := range file.Renditions {
+		renditions[i] = rendition(r)
+	}
+
+	data, err := json.Marshal(renditions)
+	if err != nil {
+		return err
+	}
+
+	var primary bool
+	err = tx.QueryRow(`UPDATE product_images SET status=$1, object_name=$2, url=$3, renditions=$4, attempts=$5, error='', updated_at=$6,
+		is_primary = NOT EXISTS (SELECT 1 FROM product_images WHERE product_id = $7 AND is_primary)
+		WHERE id = $8 AND status = $9 RETURNING is_primary`,
+		domain.UploadedToStorage, file.ObjectName, file.URL, string(data), file.Attempts, file.UpdatedAt, file.ProductId,
+		file.ID, domain.StorageUploadInProgress).Scan(&primary)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.ErrFileStatusChanged
+	}
+	if err != nil || !primary {
+		return err
+	}
+
+	_, err = tx.Exec("UPDATE products SET image=$1 WHERE id = $2", file.URL, file.ProductId)
+
+	return err
+}
+
+// FailStale fails the uploads that haven't moved since before, their
+// workers are gone, and the uploads of the clients that haven't completed
+// since clientBefore. It returns the keys of the objects they left in the
+// storage.
+func (r *FilesPostgres) FailStale(before, clientBefore time.Time, reason string) ([]string, error) {
+	rows, err := r.db.Query(`UPDATE product_images SET error=$1, updated_at=$2,
+		status = CASE WHEN status = $3 THEN $4 ELSE $5 END
+		WHERE (status = $3 AND COALESCE(updated_at, created_at) < $6) OR (status IN ($7, $8) AND COALESCE(updated_at, created_at) < $9)
+		RETURNING object_name`,
+		reason, time.Now(), domain.ClientUploadInProgress, domain.ClientUploadError, domain.StorageUploadError, clientBefore,
+		domain.UploadedByClient, domain.StorageUploadInProgress, before)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+
+		names = append(names, name)
+	}
+
+	return names, rows.Err()
+}
+
+// lockProduct serializes the changes of the gallery of the product.
+func lockProduct(tx *sql.Tx, productId string) error {
+	var id string
+
+	err := tx.QueryRow("SELECT id FROM products WHERE id = $1 FOR UPDATE", productId).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.ErrProductNotFound
+	}
+
+	return err
+}
+
+func scanFile(row rowScanner) (domain.File, error) {
+	var (
+		file       = domain.File{Type: domain.Image}
+		data       []byte
+		renditions []rendition
+	)
+
+	if err := row.Scan(&file.ID, &file.ProductId, &file.ObjectName, &file.URL, &file.ContentType, &file.Size, &file.Checksum, &file.Status, &file.Position,
+		&file.Primary, &file.AltText, &file.Attempts, &file.Error, &file.UploadStartedAt, &file.CreatedAt, &file.UpdatedAt, &data); err != nil {
+		return file, err
+	}
+
+	if err := json.Unmarshal(data, &renditions); err != nil {
+		return file, err
+	}
+
+	file.Renditions = make([]domain.FileRendition, len(renditions))
+	for i, r := range renditions {
+		file.Renditions[i] = domain.FileRendition(r)
+	}
+
+	return file, nil
+}
@@ -0,0 +1,390 @@
+package repository
+
+import (
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/AndrewMislyuk/go-shop-backend/internal/domain"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilesPostgres_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	r := NewFilesPostgres(db)
+
+	productId := "453b4f0f-1f56-4c57-b43d-7b79792450a7"
+	timestamp := time.Now()
+	file := domain.File{
+		ID:              "0a8d4c3e-5b6f-4e7a-9c1d-2f3e4a5b6c7d",
+		ProductId:       productId,
+		Type:            domain.Image,
+		ContentType:     "image/webp",
+		Size:            2048,
+		Checksum:        "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08",
+		Status:          domain.UploadedByClient,
+		AltText:         "Платье, вид спереди",
+		UploadStartedAt: timestamp,
+		CreatedAt:       timestamp,
+	}
+
+	testTable := []struct {
+		name    string
+		mock    func()
+		want    domain.File
+		wantErr error
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM products WHERE id = $1 FOR UPDATE")).
+					WithArgs(productId).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(productId))
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT COALESCE(MAX(position) + 1, 0) FROM product_images")).
+					WithArgs(productId).
+					WillReturnRows(sqlmock.NewRows([]string{"position"}).AddRow(3))
+				mock.ExpectExec("INSERT INTO product_images").
+					WithArgs(file.ID, productId, file.ObjectName, file.ContentType, file.Size, file.Checksum, file.Status, 3, file.AltText, timestamp, timestamp).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			want: func() domain.File {
+				want := file
+				want.Position = 3
+
+				return want
+			}(),
+		},
+
+		{
+			name: "Product Not Found",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT id FROM products").
+					WithArgs(productId).
+					WillReturnError(sql.ErrNoRows)
+				mock.ExpectRollback()
+			},
+			wantErr: domain.ErrProductNotFound,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mock()
+
+			got, err := r.Create(file)
+			if testCase.wantErr != nil {
+				assert.ErrorIs(t, err, testCase.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, testCase.want, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestFilesPostgres_Complete(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	r := NewFilesPostgres(db)
+
+	productId := "453b4f0f-1f56-4c57-b43d-7b79792450a7"
+	timestamp := time.Now()
+	file := domain.File{
+		ID:         "0a8d4c3e-5b6f-4e7a-9c1d-2f3e4a5b6c7d",
+		ProductId:  productId,
+		ObjectName: "images/p/i/original.webp",
+		URL:        "https://bucket.storage/images/p/i/original.webp",
+		Status:     domain.StorageUploadInProgress,
+		Attempts:   2,
+		UpdatedAt:  &timestamp,
+		Renditions: []domain.FileRendition{
+			{Name: "thumbnail", Format: "webp", ContentType: "image/webp", Width: 320, Height: 480, Size: 512,
+				URL: "https://bucket.storage/images/p/i/thumbnail.webp", ObjectName: "images/p/i/thumbnail.webp"},
+		},
+	}
+	renditions := `[{"name":"thumbnail","format":"webp","content_type":"image/webp","width":320,"height":480,"size":512,` +
+		`"url":"https://bucket.storage/images/p/i/thumbnail.webp","object_name":"images/p/i/thumbnail.webp"}]`
+
+	testTable := []struct {
+		name    string
+		mock    func()
+		wantErr error
+	}{
+		{
+			name: "First Image",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT id FROM products").
+					WithArgs(productId).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(productId))
+				mock.ExpectQuery("UPDATE product_images SET status").
+					WithArgs(domain.UploadedToStorage, file.ObjectName, file.URL, renditions, 2, &timestamp, productId,
+						file.ID, domain.StorageUploadInProgress).
+					WillReturnRows(sqlmock.NewRows([]string{"is_primary"}).AddRow(true))
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE products SET image=$1 WHERE id = $2")).
+					WithArgs(file.URL, productId).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+
+		{
+			name: "Appended To Gallery",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT id FROM products").
+					WithArgs(productId).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(productId))
+				mock.ExpectQuery("UPDATE product_images SET status").
+					WithArgs(domain.UploadedToStorage, file.ObjectName, file.URL, renditions, 2, &timestamp, productId,
+						file.ID, domain.StorageUploadInProgress).
+					WillReturnRows(sqlmock.NewRows([]string{"is_primary"}).AddRow(false))
+				mock.ExpectCommit()
+			},
+		},
+
+		{
+			name: "Image Deleted",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT id FROM products").
+					WithArgs(productId).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(productId))
+				mock.ExpectQuery("UPDATE product_images SET status").
+					WithArgs(domain.UploadedToStorage, file.ObjectName, file.URL, renditions, 2, &timestamp, productId,
+						file.ID, domain.StorageUploadInProgress).
+					WillReturnError(sql.ErrNoRows)
+				mock.ExpectRollback()
+			},
+			wantErr: domain.ErrFileStatusChanged,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mock()
+
+			err := r.Complete(file)
+			if testCase.wantErr != nil {
+				assert.ErrorIs(t, err, testCase.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestFilesPostgres_Reorder(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	r := NewFilesPostgres(db)
+
+	productId := "453b4f0f-1f56-4c57-b43d-7b79792450a7"
+	ids := []string{"b07221f8-4133-4688-b2d6-d677f41f5b74", "0a8d4c3e-5b6f-4e7a-9c1d-2f3e4a5b6c7d"}
+
+	testTable := []struct {
+		name    string
+		mock    func()
+		wantErr error
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT id FROM products").
+					WithArgs(productId).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(productId))
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM product_images WHERE product_id = $1")).
+					WithArgs(productId).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE product_images SET position=$1 WHERE id = $2 AND product_id = $3")).
+					WithArgs(0, ids[0], productId).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE product_images SET position=$1 WHERE id = $2 AND product_id = $3")).
+					WithArgs(1, ids[1], productId).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+
+		{
+			name: "Image Missing From Order",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT id FROM products").
+					WithArgs(productId).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(productId))
+				mock.ExpectQuery("SELECT COUNT").
+					WithArgs(productId).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+				mock.ExpectRollback()
+			},
+			wantErr: domain.ErrInvalidImagesOrder,
+		},
+
+		{
+			name: "Image Of Another Product",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT id FROM products").
+					WithArgs(productId).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(productId))
+				mock.ExpectQuery("SELECT COUNT").
+					WithArgs(productId).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+				mock.ExpectExec("UPDATE product_images SET position").
+					WithArgs(0, ids[0], productId).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+			},
+			wantErr: domain.ErrInvalidImagesOrder,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mock()
+
+			err := r.Reorder(productId, ids)
+			if testCase.wantErr != nil {
+				assert.ErrorIs(t, err, testCase.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestFilesPostgres_Delete(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	r := NewFilesPostgres(db)
+
+	productId := "453b4f0f-1f56-4c57-b43d-7b79792450a7"
+	imageId := "0a8d4c3e-5b6f-4e7a-9c1d-2f3e4a5b6c7d"
+
+	testTable := []struct {
+		name    string
+		mock    func()
+		wantErr error
+	}{
+		{
+			name: "Primary Replaced",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT id FROM products").
+					WithArgs(productId).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(productId))
+				mock.ExpectQuery(regexp.QuoteMeta("DELETE FROM product_images WHERE id = $1 AND product_id = $2 RETURNING is_primary")).
+					WithArgs(imageId, productId).
+					WillReturnRows(sqlmock.NewRows([]string{"is_primary"}).AddRow(true))
+				mock.ExpectQuery("UPDATE product_images SET is_primary=true").
+					WithArgs(productId).
+					WillReturnRows(sqlmock.NewRows([]string{"url"}).AddRow("https://bucket.storage/images/w2.webp"))
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE products SET image=$1 WHERE id = $2")).
+					WithArgs("https://bucket.storage/images/w2.webp", productId).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+
+		{
+			name: "Last Image",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT id FROM products").
+					WithArgs(productId).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(productId))
+				mock.ExpectQuery("DELETE FROM product_images").
+					WithArgs(imageId, productId).
+					WillReturnRows(sqlmock.NewRows([]string{"is_primary"}).AddRow(true))
+				mock.ExpectQuery("UPDATE product_images SET is_primary=true").
+					WithArgs(productId).
+					WillReturnError(sql.ErrNoRows)
+				mock.ExpectExec("UPDATE products SET image").
+					WithArgs(nil, productId).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+
+		{
+			name: "Not Found",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT id FROM products").
+					WithArgs(productId).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(productId))
+				mock.ExpectQuery("DELETE FROM product_images").
+					WithArgs(imageId, productId).
+					WillReturnError(sql.ErrNoRows)
+				mock.ExpectRollback()
+			},
+			wantErr: domain.ErrImageNotFound,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mock()
+
+			err := r.Delete(productId, imageId)
+			if testCase.wantErr != nil {
+				assert.ErrorIs(t, err, testCase.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestFilesPostgres_FailStale(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	r := NewFilesPostgres(db)
+
+	before := time.Now().Add(-30 * time.Minute)
+	clientBefore := before.Add(-15 * time.Minute)
+
+	mock.ExpectQuery("UPDATE product_images SET error").
+		WithArgs("upload interrupted", sqlmock.AnyArg(), domain.ClientUploadInProgress, domain.ClientUploadError, domain.StorageUploadError,
+			clientBefore, domain.UploadedByClient, domain.StorageUploadInProgress, before).
+		WillReturnRows(sqlmock.NewRows([]string{"object_name"}).AddRow("uploads/p/i").AddRow(""))
+
+	names, err := r.FailStale(before, clientBefore, "upload interrupted")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"uploads/p/i", ""}, names)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,403 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/AndrewMislyuk/go-shop-backend/internal/domain"
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	stockLevelsFrom          = " FROM stock_levels l JOIN products p ON p.id = l.product_id LEFT JOIN product_variants v ON v.id = l.variant_id"
+	selectStockLevelQuery    = "SELECT l.id, l.product_id, p.title, l.variant_id, COALESCE(v.sku, ''), l.on_hand, l.reserved, l.version, l.updated_at" + stockLevelsFrom
+	stockMovementsFrom       = " FROM stock_movements m JOIN stock_levels l ON l.id = m.stock_level_id"
+	selectStockMovementQuery = "SELECT m.id, m.stock_level_id, l.product_id, l.variant_id, m.kind, m.quantity, m.reason, m.actor_id, m.reservation_id, m.created_at" + stockMovementsFrom
+	selectReservationQuery   = "SELECT r.id, r.stock_level_id, l.product_id, l.variant_id, r.quantity, r.status, r.expires_at, r.created_at, r.updated_at FROM stock_reservations r JOIN stock_levels l ON l.id = r.stock_level_id"
+)
+
+type InventoryPostgres struct {
+	db *sql.DB
+}
+
+func NewInventoryPostgres(db *sql.DB) *InventoryPostgres {
+	return &InventoryPostgres{
+		db: db,
+	}
+}
+
+func (r *InventoryPostgres) GetLevels(filter domain.StockLevelsFilter) (domain.StockLevelsList, error) {
+	conditions := make([]string, 0)
+	args := make([]interface{}, 0)
+	argId := 1
+
+	if filter.ProductId != "" {
+		conditions = append(conditions, fmt.Sprintf("l.product_id = $%d", argId))
+		args = append(args, filter.ProductId)
+		argId++
+	}
+
+	if filter.LowStock != nil {
+		conditions = append(conditions, fmt.Sprintf("l.on_hand - l.reserved <= $%d", argId))
+		args = append(args, *filter.LowStock)
+		argId++
+	}
+
+	list := domain.StockLevelsList{
+		Levels: make([]domain.StockLevel, 0),
+	}
+
+	if err := r.db.QueryRow("SELECT count(*) FROM stock_levels l"+whereClause(conditions), args...).Scan(&list.Total); err != nil {
+		return list, err
+	}
+
+	query := fmt.Sprintf("%s%s ORDER BY p.title, v.sku, l.id LIMIT $%d OFFSET $%d", selectStockLevelQuery, whereClause(conditions), argId, argId+1)
+	args = append(args, filter.Limit, filter.Offset)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return list, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		level, err := scanStockLevel(rows)
+		if err != nil {
+			return list, err
+		}
+
+		list.Levels = append(list.Levels, level)
+	}
+
+	return list, rows.Err()
+}
+
+func (r *InventoryPostgres) GetMovements(filter domain.StockMovementsFilter) (domain.StockMovementsList, error) {
+	conditions := make([]string, 0)
+	args := make([]interface{}, 0)
+	argId := 1
+
+	if filter.ProductId != "" {
+		conditions = append(conditions, fmt.Sprintf("l.product_id = $%d", argId))
+		args = append(args, filter.ProductId)
+		argId++
+	}
+
+	if filter.VariantId != "" {
+		conditions = append(conditions, fmt.Sprintf("l.variant_id = $%d", argId))
+		args = append(args, filter.VariantId)
+		argId++
+	}
+
+	if filter.Kind != "" {
+		conditions = append(conditions, fmt.Sprintf("m.kind = $%d", argId))
+		args = append(args, filter.Kind)
+		argId++
+	}
+
+	list := domain.StockMovementsList{
+		Movements: make([]domain.StockMovement, 0),
+	}
+
+	if err := r.db.QueryRow("SELECT count(*)"+stockMovementsFrom+whereClause(conditions), args...).Scan(&list.Total); err != nil {
+		return list, err
+	}
+
+	query := fmt.Sprintf("%s%s ORDER BY m.created_at DESC, m.id DESC LIMIT $%d OFFSET $%d", selectStockMovementQuery, whereClause(conditions), argId, argId+1)
+	args = append(args, filter.Limit, filter.Offset)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return list, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var movement domain.StockMovement
+		if err := rows.Scan(&movement.Id, &movement.StockLevelId, &movement.ProductId, &movement.VariantId, &movement.Kind, &movement.Quantity,
+			&movement.Reason, &movement.ActorId, &movement.ReservationId, &movement.CreatedAt); err != nil {
+			return list, err
+		}
+
+		list.Movements = append(list.Movements, movement)
+	}
+
+	return list, rows.Err()
+}
+
+// RecordMovement applies the movement to the stock level of the product or
+// variant, creating the level on first use, and appends it to the ledger.
+// The stock can't drop below what is reserved. A non-nil version must match
+// the version of the level.
+func (r *InventoryPostgres) RecordMovement(movement domain.StockMovement, version *int) (domain.StockLevel, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return domain.StockLevel{}, err
+	}
+
+	level, err := recordMovement(tx, movement, version)
+	if err != nil {
+		if rb := tx.Rollback(); rb != nil {
+			logrus.Fatalf("query failed: %v, unable to abort: %v", err, rb)
+		}
+
+		return level, err
+	}
+
+	return level, tx.Commit()
+}
+
+func recordMovement(tx *sql.Tx, movement domain.StockMovement, version *int) (domain.StockLevel, error) {
+	level, err := lockStockLevel(tx, movement.ProductId, movement.VariantId, movement.CreatedAt)
+	if err != nil {
+		return level, err
+	}
+
+	if version != nil && *version != level.Version {
+		return level, domain.ErrStockVersionConflict
+	}
+
+	if level.OnHand+movement.Quantity < level.Reserved {
+		return level, domain.ErrInsufficientStock
+	}
+
+	if _, err := tx.Exec("UPDATE stock_levels SET on_hand = on_hand + $1, version = version + 1, updated_at = $2 WHERE id = $3",
+		movement.Quantity, movement.CreatedAt, level.Id); err != nil {
+		return level, err
+	}
+
+	movement.StockLevelId = level.Id
+	if err := insertStockMovement(tx, movement); err != nil {
+		return level, err
+	}
+
+	level.OnHand += movement.Quantity
+	level.Available += movement.Quantity
+	level.Version++
+	level.UpdatedAt = movement.CreatedAt
+
+	return level, nil
+}
+
+// Reserve holds the quantity of the reservation until it expires, is
+// confirmed or released.
+func (r *InventoryPostgres) Reserve(reservation domain.Reservation) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	if err := reserve(tx, reservation); err != nil {
+		if rb := tx.Rollback(); rb != nil {
+			logrus.Fatalf("query failed: %v, unable to abort: %v", err, rb)
+		}
+
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func reserve(tx *sql.Tx, reservation domain.Reservation) error {
+	level, err := lockStockLevel(tx, reservation.ProductId, reservation.VariantId, reservation.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	if level.Available < reservation.Quantity {
+		return domain.ErrInsufficientStock
+	}
+
+	if _, err := tx.Exec("UPDATE stock_levels SET reserved = reserved + $1, version = version + 1, updated_at = $2 WHERE id = $3",
+		reservation.Quantity, reservation.CreatedAt, level.Id); err != nil {
+		return err
+	}
+
+	_, err = tx.Exec("INSERT INTO stock_reservations(id, stock_level_id, quantity, status, expires_at, created_at) values($1, $2, $3, $4, $5, $6)",
+		reservation.Id, level.Id, reservation.Quantity, domain.ReservationActive, reservation.ExpiresAt, reservation.CreatedAt)
+
+	return err
+}
+
+// ConfirmReservation turns the active reservation into a sale recorded with
+// the id, actor and time of the movement.
+func (r *InventoryPostgres) ConfirmReservation(reservationId string, movement domain.StockMovement) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	if err := confirmReservation(tx, reservationId, movement); err != nil {
+		if rb := tx.Rollback(); rb != nil {
+			logrus.Fatalf("query failed: %v, unable to abort: %v", err, rb)
+		}
+
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// ConfirmReservations sells the reservations of the movements, each pointing
+// to its reservation, all of them or none.
+func (r *InventoryPostgres) ConfirmReservations(movements []domain.StockMovement) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	for _, movement := range movements {
+		if err := confirmReservation(tx, *movement.ReservationId, movement); err != nil {
+			if rb := tx.Rollback(); rb != nil {
+				logrus.Fatalf("query failed: %v, unable to abort: %v", err, rb)
+			}
+
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func confirmReservation(tx *sql.Tx, reservationId string, movement domain.StockMovement) error {
+	reservation, err := closeReservation(tx, reservationId, domain.ReservationConfirmed, movement.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec("UPDATE stock_levels SET on_hand = on_hand - $1, reserved = reserved - $1, version = version + 1, updated_at = $2 WHERE id = $3",
+		reservation.Quantity, movement.CreatedAt, reservation.StockLevelId); err != nil {
+		return err
+	}
+
+	movement.StockLevelId = reservation.StockLevelId
+	movement.Kind = domain.MovementSale
+	movement.Quantity = -reservation.Quantity
+	movement.ReservationId = &reservation.Id
+
+	return insertStockMovement(tx, movement)
+}
+
+// ReleaseReservation returns the reserved quantity to the available stock.
+func (r *InventoryPostgres) ReleaseReservation(reservationId string, timestamp time.Time) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	if err := releaseReservation(tx, reservationId, timestamp); err != nil {
+		if rb := tx.Rollback(); rb != nil {
+			logrus.Fatalf("query failed: %v, unable to abort: %v", err, rb)
+		}
+
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func releaseReservation(tx *sql.Tx, reservationId string, timestamp time.Time) error {
+	reservation, err := closeReservation(tx, reservationId, domain.ReservationReleased, timestamp)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec("UPDATE stock_levels SET reserved = reserved - $1, version = version + 1, updated_at = $2 WHERE id = $3",
+		reservation.Quantity, timestamp, reservation.StockLevelId)
+
+	return err
+}
+
+// ExpireReservations releases the active reservations that expired by
+// timestamp and returns how many there were.
+func (r *InventoryPostgres) ExpireReservations(timestamp time.Time) (int, error) {
+	var expired int
+
+	err := r.db.QueryRow(`WITH expired AS (
+		UPDATE stock_reservations SET status = $1, updated_at = $2 WHERE status = $3 AND expires_at <= $2 RETURNING stock_level_id, quantity
+	), released AS (
+		UPDATE stock_levels l SET reserved = l.reserved - e.quantity, version = l.version + 1, updated_at = $2
+		FROM (SELECT stock_level_id, sum(quantity) AS quantity FROM expired GROUP BY stock_level_id) e WHERE l.id = e.stock_level_id
+	)
+	SELECT count(*) FROM expired`, domain.ReservationExpired, timestamp, domain.ReservationActive).Scan(&expired)
+
+	return expired, err
+}
+
+// lockStockLevel creates the stock level if it doesn't exist and locks it
+// until the end of the transaction.
+func lockStockLevel(tx *sql.Tx, productId string, variantId *string, timestamp time.Time) (domain.StockLevel, error) {
+	if _, err := tx.Exec("INSERT INTO stock_levels(id, product_id, variant_id, updated_at) values(gen_random_uuid(), $1, $2, $3) ON CONFLICT DO NOTHING",
+		productId, variantId, timestamp); err != nil {
+		return domain.StockLevel{}, mapStockLevelError(err)
+	}
+
+	row := tx.QueryRow(selectStockLevelQuery+" WHERE l.product_id = $1 AND l.variant_id IS NOT DISTINCT FROM $2 FOR UPDATE OF l", productId, variantId)
+
+	level, err := scanStockLevel(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		// the variant exists but belongs to another product
+		return level, domain.ErrVariantNotFound
+	}
+
+	return level, err
+}
+
+// closeReservation moves the active reservation to the status. Expired
+// reservations can't be closed even if they haven't been swept yet.
+func closeReservation(tx *sql.Tx, reservationId, status string, timestamp time.Time) (domain.Reservation, error) {
+	var reservation domain.Reservation
+
+	row := tx.QueryRow(selectReservationQuery+" WHERE r.id = $1 FOR UPDATE OF r", reservationId)
+	if err := row.Scan(&reservation.Id, &reservation.StockLevelId, &reservation.ProductId, &reservation.VariantId, &reservation.Quantity,
+		&reservation.Status, &reservation.ExpiresAt, &reservation.CreatedAt, &reservation.UpdatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return reservation, domain.ErrReservationNotFound
+		}
+
+		return reservation, err
+	}
+
+	if reservation.Status != domain.ReservationActive || !reservation.ExpiresAt.After(timestamp) {
+		return reservation, domain.ErrReservationNotActive
+	}
+
+	_, err := tx.Exec("UPDATE stock_reservations SET status = $1, updated_at = $2 WHERE id = $3", status, timestamp, reservationId)
+
+	return reservation, err
+}
+
+func insertStockMovement(tx *sql.Tx, movement domain.StockMovement) error {
+	_, err := tx.Exec("INSERT INTO stock_movements(id, stock_level_id, kind, quantity, reason, actor_id, reservation_id, created_at) values($1, $2, $3, $4, $5, $6, $7, $8)",
+		movement.Id, movement.StockLevelId, movement.Kind, movement.Quantity, movement.Reason, movement.ActorId, movement.ReservationId, movement.CreatedAt)
+
+	return err
+}
+
+func scanStockLevel(row rowScanner) (domain.StockLevel, error) {
+	var level domain.StockLevel
+
+	if err := row.Scan(&level.Id, &level.ProductId, &level.ProductTitle, &level.VariantId, &level.Sku, &level.OnHand, &level.Reserved, &level.Version, &level.UpdatedAt); err != nil {
+		return level, err
+	}
+
+	level.Available = level.OnHand - level.Reserved
+
+	return level, nil
+}
+
+func mapStockLevelError(err error) error {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) || pqErr.Code != foreignKeyViolation {
+		return err
+	}
+
+	if pqErr.Constraint == "stock_levels_variant_id_fkey" {
+		return domain.ErrVariantNotFound
+	}
+
+	return domain.ErrProductNotFound
+}
@@ -0,0 +1,334 @@
+package repository
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/AndrewMislyuk/go-shop-backend/internal/domain"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInventoryPostgres_RecordMovement(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	r := NewInventoryPostgres(db)
+
+	createdAt := time.Now()
+	actorId := "e7a9c2d4-5f3b-4a61-8c0e-2b9d7f1a3e55"
+	levelColumns := []string{"id", "product_id", "title", "variant_id", "sku", "on_hand", "reserved", "version", "updated_at"}
+
+	movement := func(quantity int) domain.StockMovement {
+		return domain.StockMovement{
+			Id:        "9a3c1f7e-2b6d-4e8a-b5c0-1d7f3e9a2c64",
+			ProductId: "453b4f0f-1f56-4c57-b43d-7b79792450a7",
+			Kind:      domain.MovementAdjustment,
+			Quantity:  quantity,
+			Reason:    "inventory count",
+			ActorId:   &actorId,
+			CreatedAt: createdAt,
+		}
+	}
+
+	expectLock := func(onHand, reserved, version int) {
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO stock_levels(id, product_id, variant_id, updated_at) values(gen_random_uuid(), $1, $2, $3) ON CONFLICT DO NOTHING")).
+			WithArgs("453b4f0f-1f56-4c57-b43d-7b79792450a7", nil, createdAt).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(regexp.QuoteMeta(selectStockLevelQuery+" WHERE l.product_id = $1 AND l.variant_id IS NOT DISTINCT FROM $2 FOR UPDATE OF l")).
+			WithArgs("453b4f0f-1f56-4c57-b43d-7b79792450a7", nil).
+			WillReturnRows(sqlmock.NewRows(levelColumns).
+				AddRow("0b6cbb5e-3d0a-4c3f-8f0e-6a4b7d3e2c11", "453b4f0f-1f56-4c57-b43d-7b79792450a7", "Твидовый кардиган из хлопка", nil, "", onHand, reserved, version, createdAt))
+	}
+
+	testTable := []struct {
+		name      string
+		mock      func()
+		movement  domain.StockMovement
+		version   *int
+		wantLevel domain.StockLevel
+		wantErr   error
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				mock.ExpectBegin()
+				expectLock(10, 2, 4)
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE stock_levels SET on_hand = on_hand + $1, version = version + 1, updated_at = $2 WHERE id = $3")).
+					WithArgs(-5, createdAt, "0b6cbb5e-3d0a-4c3f-8f0e-6a4b7d3e2c11").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO stock_movements(id, stock_level_id, kind, quantity, reason, actor_id, reservation_id, created_at) values($1, $2, $3, $4, $5, $6, $7, $8)")).
+					WithArgs("9a3c1f7e-2b6d-4e8a-b5c0-1d7f3e9a2c64", "0b6cbb5e-3d0a-4c3f-8f0e-6a4b7d3e2c11", domain.MovementAdjustment, -5, "inventory count", actorId, nil, createdAt).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			movement: movement(-5),
+			wantLevel: domain.StockLevel{
+				Id:           "0b6cbb5e-3d0a-4c3f-8f0e-6a4b7d3e2c11",
+				ProductId:    "453b4f0f-1f56-4c57-b43d-7b79792450a7",
+				ProductTitle: "Твидовый кардиган из хлопка",
+				OnHand:       5,
+				Reserved:     2,
+				Available:    3,
+				Version:      5,
+				UpdatedAt:    createdAt,
+			},
+		},
+
+		{
+			name: "Below Reserved",
+			mock: func() {
+				mock.ExpectBegin()
+				expectLock(10, 6, 4)
+				mock.ExpectRollback()
+			},
+			movement: movement(-5),
+			wantErr:  domain.ErrInsufficientStock,
+		},
+
+		{
+			name: "Version Changed",
+			mock: func() {
+				mock.ExpectBegin()
+				expectLock(10, 0, 4)
+				mock.ExpectRollback()
+			},
+			movement: movement(3),
+			version:  intPointer(3),
+			wantErr:  domain.ErrStockVersionConflict,
+		},
+
+		{
+			name: "Product Not Found",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("INSERT INTO stock_levels").
+					WillReturnError(&pq.Error{Code: foreignKeyViolation, Constraint: "stock_levels_product_id_fkey"})
+				mock.ExpectRollback()
+			},
+			movement: movement(3),
+			wantErr:  domain.ErrProductNotFound,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mock()
+
+			got, err := r.RecordMovement(testCase.movement, testCase.version)
+			if testCase.wantErr != nil {
+				assert.ErrorIs(t, err, testCase.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, testCase.wantLevel, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestInventoryPostgres_ConfirmReservation(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	r := NewInventoryPostgres(db)
+
+	timestamp := time.Now()
+	reservationColumns := []string{"id", "stock_level_id", "product_id", "variant_id", "quantity", "status", "expires_at", "created_at", "updated_at"}
+	reservationId := "5d2f8a61-0c4e-4b7a-9e13-7f6a2c8d4b90"
+
+	expectReservation := func(status string, expiresAt time.Time) {
+		mock.ExpectQuery(regexp.QuoteMeta(selectReservationQuery + " WHERE r.id = $1 FOR UPDATE OF r")).
+			WithArgs(reservationId).
+			WillReturnRows(sqlmock.NewRows(reservationColumns).
+				AddRow(reservationId, "0b6cbb5e-3d0a-4c3f-8f0e-6a4b7d3e2c11", "453b4f0f-1f56-4c57-b43d-7b79792450a7", nil, 2, status, expiresAt, timestamp.Add(-time.Minute), nil))
+	}
+
+	testTable := []struct {
+		name    string
+		mock    func()
+		wantErr error
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				mock.ExpectBegin()
+				expectReservation(domain.ReservationActive, timestamp.Add(time.Minute))
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE stock_reservations SET status = $1, updated_at = $2 WHERE id = $3")).
+					WithArgs(domain.ReservationConfirmed, timestamp, reservationId).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE stock_levels SET on_hand = on_hand - $1, reserved = reserved - $1, version = version + 1, updated_at = $2 WHERE id = $3")).
+					WithArgs(2, timestamp, "0b6cbb5e-3d0a-4c3f-8f0e-6a4b7d3e2c11").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("INSERT INTO stock_movements").
+					WithArgs("9a3c1f7e-2b6d-4e8a-b5c0-1d7f3e9a2c64", "0b6cbb5e-3d0a-4c3f-8f0e-6a4b7d3e2c11", domain.MovementSale, -2, "", nil, reservationId, timestamp).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+
+		{
+			name: "Expired",
+			mock: func() {
+				mock.ExpectBegin()
+				expectReservation(domain.ReservationActive, timestamp.Add(-time.Second))
+				mock.ExpectRollback()
+			},
+			wantErr: domain.ErrReservationNotActive,
+		},
+
+		{
+			name: "Released",
+			mock: func() {
+				mock.ExpectBegin()
+				expectReservation(domain.ReservationReleased, timestamp.Add(time.Minute))
+				mock.ExpectRollback()
+			},
+			wantErr: domain.ErrReservationNotActive,
+		},
+
+		{
+			name: "Not Found",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT (.+) FROM stock_reservations").
+					WithArgs(reservationId).
+					WillReturnRows(sqlmock.NewRows(reservationColumns))
+				mock.ExpectRollback()
+			},
+			wantErr: domain.ErrReservationNotFound,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mock()
+
+			err := r.ConfirmReservation(reservationId, domain.StockMovement{
+				Id:        "9a3c1f7e-2b6d-4e8a-b5c0-1d7f3e9a2c64",
+				CreatedAt: timestamp,
+			})
+			if testCase.wantErr != nil {
+				assert.ErrorIs(t, err, testCase.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestInventoryPostgres_ConfirmReservations(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	r := NewInventoryPostgres(db)
+
+	timestamp := time.Now()
+	reservationColumns := []string{"id", "stock_level_id", "product_id", "variant_id", "quantity", "status", "expires_at", "created_at", "updated_at"}
+	firstId, secondId := "5d2f8a61-0c4e-4b7a-9e13-7f6a2c8d4b90", "6e3a9b72-1d5f-4c8b-af24-8a7b3d9e5ca1"
+	levelId := "0b6cbb5e-3d0a-4c3f-8f0e-6a4b7d3e2c11"
+
+	expectReservation := func(reservationId, status string) {
+		mock.ExpectQuery(regexp.QuoteMeta(selectReservationQuery + " WHERE r.id = $1 FOR UPDATE OF r")).
+			WithArgs(reservationId).
+			WillReturnRows(sqlmock.NewRows(reservationColumns).
+				AddRow(reservationId, levelId, "453b4f0f-1f56-4c57-b43d-7b79792450a7", nil, 2, status, timestamp.Add(time.Minute), timestamp.Add(-time.Minute), nil))
+	}
+
+	expectSale := func(reservationId, movementId string) {
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE stock_reservations SET status = $1, updated_at = $2 WHERE id = $3")).
+			WithArgs(domain.ReservationConfirmed, timestamp, reservationId).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE stock_levels SET on_hand = on_hand - $1")).
+			WithArgs(2, timestamp, levelId).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("INSERT INTO stock_movements").
+			WithArgs(movementId, levelId, domain.MovementSale, -2, "", nil, reservationId, timestamp).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+
+	testTable := []struct {
+		name    string
+		mock    func()
+		wantErr error
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				mock.ExpectBegin()
+				expectReservation(firstId, domain.ReservationActive)
+				expectSale(firstId, "9a3c1f7e-2b6d-4e8a-b5c0-1d7f3e9a2c64")
+				expectReservation(secondId, domain.ReservationActive)
+				expectSale(secondId, "ab4d2e8f-3c7e-4f9b-86d1-2e8a4fab3d75")
+				mock.ExpectCommit()
+			},
+		},
+
+		{
+			name: "One Released",
+			mock: func() {
+				mock.ExpectBegin()
+				expectReservation(firstId, domain.ReservationActive)
+				expectSale(firstId, "9a3c1f7e-2b6d-4e8a-b5c0-1d7f3e9a2c64")
+				expectReservation(secondId, domain.ReservationReleased)
+				mock.ExpectRollback()
+			},
+			wantErr: domain.ErrReservationNotActive,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mock()
+
+			err := r.ConfirmReservations([]domain.StockMovement{
+				{Id: "9a3c1f7e-2b6d-4e8a-b5c0-1d7f3e9a2c64", ReservationId: &firstId, CreatedAt: timestamp},
+				{Id: "ab4d2e8f-3c7e-4f9b-86d1-2e8a4fab3d75", ReservationId: &secondId, CreatedAt: timestamp},
+			})
+			if testCase.wantErr != nil {
+				assert.ErrorIs(t, err, testCase.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestInventoryPostgres_ExpireReservations(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	r := NewInventoryPostgres(db)
+
+	timestamp := time.Now()
+
+	mock.ExpectQuery("WITH expired AS (.+) UPDATE stock_reservations").
+		WithArgs(domain.ReservationExpired, timestamp, domain.ReservationActive).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+
+	expired, err := r.ExpireReservations(timestamp)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, expired)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func intPointer(i int) *int {
+	return &i
+}
@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"sync"
+	"time"
+
+	"github.com/AndrewMislyuk/go-shop-backend/internal/domain"
+)
+
+// pruneThreshold bounds the memory used by keys of clients that never came
+// back: once exceeded, records without recent failures or lockouts are dropped.
+const pruneThreshold = 10000
+
+// LoginAttemptsMemory keeps counters in the process memory. They are lost on
+// restart and not shared between instances, use LoginAttemptsPostgres when
+// the service runs behind a load balancer.
+type LoginAttemptsMemory struct {
+	mu       sync.Mutex
+	attempts map[string]domain.LoginAttempt
+}
+
+func NewLoginAttemptsMemory() *LoginAttemptsMemory {
+	return &LoginAttemptsMemory{
+		attempts: make(map[string]domain.LoginAttempt),
+	}
+}
+
+func (r *LoginAttemptsMemory) Get(key string) (domain.LoginAttempt, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	attempt, ok := r.attempts[key]
+	if !ok {
+		return domain.LoginAttempt{Key: key}, nil
+	}
+
+	return attempt, nil
+}
+
+func (r *LoginAttemptsMemory) RegisterFailure(key string, timestamp time.Time, window time.Duration) (domain.LoginAttempt, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.attempts) >= pruneThreshold {
+		r.prune(timestamp, window)
+	}
+
+	attempt, ok := r.attempts[key]
+	if !ok || attempt.LastFailureAt.Before(timestamp.Add(-window)) {
+		attempt.Key = key
+		attempt.Failures = 0
+	}
+
+	attempt.Failures++
+	attempt.LastFailureAt = timestamp
+	r.attempts[key] = attempt
+
+	return attempt, nil
+}
+
+func (r *LoginAttemptsMemory) Lock(key string, until time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if attempt, ok := r.attempts[key]; ok {
+		attempt.LockedUntil = &until
+		r.attempts[key] = attempt
+	}
+
+	return nil
+}
+
+func (r *LoginAttemptsMemory) Reset(key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.attempts, key)
+
+	return nil
+}
+
+func (r *LoginAttemptsMemory) DeleteExpired(now time.Time, window time.Duration) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.prune(now, window), nil
+}
+
+func (r *LoginAttemptsMemory) prune(timestamp time.Time, window time.Duration) int {
+	deleted := 0
+	for key, attempt := range r.attempts {
+		locked := attempt.LockedUntil != nil && attempt.LockedUntil.After(timestamp)
+		if !locked && attempt.LastFailureAt.Before(timestamp.Add(-window)) {
+			delete(r.attempts, key)
+			deleted++
+		}
+	}
+
+	return deleted
+}
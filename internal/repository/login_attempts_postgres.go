@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/AndrewMislyuk/go-shop-backend/internal/domain"
+)
+
+type LoginAttemptsPostgres struct {
+	db *sql.DB
+}
+
+func NewLoginAttemptsPostgres(db *sql.DB) *LoginAttemptsPostgres {
+	return &LoginAttemptsPostgres{
+		db: db,
+	}
+}
+
+func (r *LoginAttemptsPostgres) Get(key string) (domain.LoginAttempt, error) {
+	attempt := domain.LoginAttempt{Key: key}
+
+	row := r.db.QueryRow("SELECT key, failures, last_failure_at, locked_until FROM login_attempts WHERE key = $1", key)
+	if err := row.Scan(&attempt.Key, &attempt.Failures, &attempt.LastFailureAt, &attempt.LockedUntil); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return attempt, nil
+		}
+
+		return attempt, err
+	}
+
+	return attempt, nil
+}
+
+func (r *LoginAttemptsPostgres) RegisterFailure(key string, timestamp time.Time, window time.Duration) (domain.LoginAttempt, error) {
+	var attempt domain.LoginAttempt
+
+	row := r.db.QueryRow(`INSERT INTO login_attempts(key, failures, last_failure_at) values($1, 1, $2)
+		ON CONFLICT (key) DO UPDATE SET failures = CASE WHEN login_attempts.last_failure_at < $3 THEN 1 ELSE login_attempts.failures + 1 END, last_failure_at = $2
+		RETURNING key, failures, last_failure_at, locked_until`, key, timestamp, timestamp.Add(-window))
+	if err := row.Scan(&attempt.Key, &attempt.Failures, &attempt.LastFailureAt, &attempt.LockedUntil); err != nil {
+		return attempt, err
+	}
+
+	return attempt, nil
+}
+
+func (r *LoginAttemptsPostgres) Lock(key string, until time.Time) error {
+	_, err := r.db.Exec("UPDATE login_attempts SET locked_until = $1 WHERE key = $2", until, key)
+
+	return err
+}
+
+// DeleteExpired deletes the records without failures within window before
+// now nor a running lockout.
+func (r *LoginAttemptsPostgres) DeleteExpired(now time.Time, window time.Duration) (int, error) {
+	res, err := r.db.Exec("DELETE FROM login_attempts WHERE last_failure_at < $1 AND (locked_until IS NULL OR locked_until < $2)",
+		now.Add(-window), now)
+	if err != nil {
+		return 0, err
+	}
+
+	affected, err := res.RowsAffected()
+
+	return int(affected), err
+}
+
+func (r *LoginAttemptsPostgres) Reset(key string) error {
+	_, err := r.db.Exec("DELETE FROM login_attempts WHERE key = $1", key)
+
+	return err
+}
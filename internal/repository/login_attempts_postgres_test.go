@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/AndrewMislyuk/go-shop-backend/internal/domain"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoginAttemptsPostgres_RegisterFailure(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	r := NewLoginAttemptsPostgres(db)
+
+	timestamp := time.Date(2022, 07, 12, 13, 8, 21, 0, time.UTC)
+
+	rows := sqlmock.NewRows([]string{"key", "failures", "last_failure_at", "locked_until"}).
+		AddRow("account:test@gmail.com", 3, timestamp, nil)
+
+	mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO login_attempts(key, failures, last_failure_at) values($1, 1, $2)")).
+		WithArgs("account:test@gmail.com", timestamp, timestamp.Add(-15*time.Minute)).
+		WillReturnRows(rows)
+
+	got, err := r.RegisterFailure("account:test@gmail.com", timestamp, 15*time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.LoginAttempt{
+		Key:           "account:test@gmail.com",
+		Failures:      3,
+		LastFailureAt: timestamp,
+	}, got)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestLoginAttemptsPostgres_Get(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	r := NewLoginAttemptsPostgres(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT key, failures, last_failure_at, locked_until FROM login_attempts WHERE key = $1")).
+		WithArgs("ip:192.0.2.1").
+		WillReturnRows(sqlmock.NewRows([]string{"key", "failures", "last_failure_at", "locked_until"}))
+
+	got, err := r.Get("ip:192.0.2.1")
+	assert.NoError(t, err)
+	assert.Equal(t, domain.LoginAttempt{Key: "ip:192.0.2.1"}, got)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestLoginAttemptsMemory(t *testing.T) {
+	r := NewLoginAttemptsMemory()
+
+	timestamp := time.Date(2022, 07, 12, 13, 8, 21, 0, time.UTC)
+	window := 15 * time.Minute
+	key := "account:test@gmail.com"
+
+	for i := 1; i <= 3; i++ {
+		attempt, err := r.RegisterFailure(key, timestamp.Add(time.Duration(i)*time.Minute), window)
+		assert.NoError(t, err)
+		assert.Equal(t, i, attempt.Failures)
+	}
+
+	until := timestamp.Add(time.Hour)
+	assert.NoError(t, r.Lock(key, until))
+
+	got, err := r.Get(key)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.LoginAttempt{
+		Key:           key,
+		Failures:      3,
+		LastFailureAt: timestamp.Add(3 * time.Minute),
+		LockedUntil:   &until,
+	}, got)
+
+	// A failure after the window starts the count over.
+	attempt, err := r.RegisterFailure(key, timestamp.Add(time.Hour), window)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, attempt.Failures)
+
+	assert.NoError(t, r.Reset(key))
+
+	got, err = r.Get(key)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.LoginAttempt{Key: key}, got)
+}
+
+func TestLoginAttemptsPostgres_DeleteExpired(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	r := NewLoginAttemptsPostgres(db)
+
+	timestamp := time.Date(2022, 07, 12, 13, 8, 21, 0, time.UTC)
+
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM login_attempts WHERE last_failure_at < $1 AND (locked_until IS NULL OR locked_until < $2)")).
+		WithArgs(timestamp.Add(-15*time.Minute), timestamp).
+		WillReturnResult(sqlmock.NewResult(0, 4))
+
+	got, err := r.DeleteExpired(timestamp, 15*time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, got)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
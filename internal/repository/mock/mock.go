@@ -0,0 +1,1918 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: repository.go
+
+// Package mock_repository is a generated GoMock package.
+package mock_repository
+
+import (
+	reflect "reflect"
+	time "time"
+
+	domain "github.com/AndrewMislyuk/go-shop-backend/internal/domain"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockAuthorization is a mock of Authorization interface.
+type MockAuthorization struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuthorizationMockRecorder
+}
+
+// MockAuthorizationMockRecorder is the mock recorder for MockAuthorization.
+type MockAuthorizationMockRecorder struct {
+	mock *MockAuthorization
+}
+
+// NewMockAuthorization creates a new mock instance.
+func NewMockAuthorization(ctrl *gomock.Controller) *MockAuthorization {
+	mock := &MockAuthorization{ctrl: ctrl}
+	mock.recorder = &MockAuthorizationMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuthorization) EXPECT() *MockAuthorizationMockRecorder {
+	return m.recorder
+}
+
+// CreateUser mocks base method.
+func (m *MockAuthorization) CreateUser(user domain.UserSignUp, dataId, role string, timestamp time.Time) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUser", user, dataId, role, timestamp)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUser indicates an expected call of CreateUser.
+func (mr *MockAuthorizationMockRecorder) CreateUser(user, dataId, role, timestamp interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockAuthorization)(nil).CreateUser), user, dataId, role, timestamp)
+}
+
+// GetUserByEmail mocks base method.
+func (m *MockAuthorization) GetUserByEmail(email string) (domain.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserByEmail", email)
+	ret0, _ := ret[0].(domain.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserByEmail indicates an expected call of GetUserByEmail.
+func (mr *MockAuthorizationMockRecorder) GetUserByEmail(email interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByEmail", reflect.TypeOf((*MockAuthorization)(nil).GetUserByEmail), email)
+}
+
+// GetUserById mocks base method.
+func (m *MockAuthorization) GetUserById(userId string) (domain.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserById", userId)
+	ret0, _ := ret[0].(domain.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserById indicates an expected call of GetUserById.
+func (mr *MockAuthorizationMockRecorder) GetUserById(userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserById", reflect.TypeOf((*MockAuthorization)(nil).GetUserById), userId)
+}
+
+// SetEmailVerified mocks base method.
+func (m *MockAuthorization) SetEmailVerified(userId string, timestamp time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetEmailVerified", userId, timestamp)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetEmailVerified indicates an expected call of SetEmailVerified.
+func (mr *MockAuthorizationMockRecorder) SetEmailVerified(userId, timestamp interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetEmailVerified", reflect.TypeOf((*MockAuthorization)(nil).SetEmailVerified), userId, timestamp)
+}
+
+// UpdatePasswordHash mocks base method.
+func (m *MockAuthorization) UpdatePasswordHash(userId, passwordHash string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePasswordHash", userId, passwordHash)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePasswordHash indicates an expected call of UpdatePasswordHash.
+func (mr *MockAuthorizationMockRecorder) UpdatePasswordHash(userId, passwordHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePasswordHash", reflect.TypeOf((*MockAuthorization)(nil).UpdatePasswordHash), userId, passwordHash)
+}
+
+// MockUserTokens is a mock of UserTokens interface.
+type MockUserTokens struct {
+	ctrl     *gomock.Controller
+	recorder *MockUserTokensMockRecorder
+}
+
+// MockUserTokensMockRecorder is the mock recorder for MockUserTokens.
+type MockUserTokensMockRecorder struct {
+	mock *MockUserTokens
+}
+
+// NewMockUserTokens creates a new mock instance.
+func NewMockUserTokens(ctrl *gomock.Controller) *MockUserTokens {
+	mock := &MockUserTokens{ctrl: ctrl}
+	mock.recorder = &MockUserTokensMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUserTokens) EXPECT() *MockUserTokensMockRecorder {
+	return m.recorder
+}
+
+// Consume mocks base method.
+func (m *MockUserTokens) Consume(tokenHash, purpose string, timestamp time.Time) (domain.UserToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Consume", tokenHash, purpose, timestamp)
+	ret0, _ := ret[0].(domain.UserToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Consume indicates an expected call of Consume.
+func (mr *MockUserTokensMockRecorder) Consume(tokenHash, purpose, timestamp interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Consume", reflect.TypeOf((*MockUserTokens)(nil).Consume), tokenHash, purpose, timestamp)
+}
+
+// Create mocks base method.
+func (m *MockUserTokens) Create(token domain.UserToken) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockUserTokensMockRecorder) Create(token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockUserTokens)(nil).Create), token)
+}
+
+// MockUsers is a mock of Users interface.
+type MockUsers struct {
+	ctrl     *gomock.Controller
+	recorder *MockUsersMockRecorder
+}
+
+// MockUsersMockRecorder is the mock recorder for MockUsers.
+type MockUsersMockRecorder struct {
+	mock *MockUsers
+}
+
+// NewMockUsers creates a new mock instance.
+func NewMockUsers(ctrl *gomock.Controller) *MockUsers {
+	mock := &MockUsers{ctrl: ctrl}
+	mock.recorder = &MockUsersMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUsers) EXPECT() *MockUsersMockRecorder {
+	return m.recorder
+}
+
+// Anonymize mocks base method.
+func (m *MockUsers) Anonymize(userId string, timestamp time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Anonymize", userId, timestamp)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Anonymize indicates an expected call of Anonymize.
+func (mr *MockUsersMockRecorder) Anonymize(userId, timestamp interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Anonymize", reflect.TypeOf((*MockUsers)(nil).Anonymize), userId, timestamp)
+}
+
+// GetAll mocks base method.
+func (m *MockUsers) GetAll(filter domain.UsersFilter) (domain.UsersList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", filter)
+	ret0, _ := ret[0].(domain.UsersList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockUsersMockRecorder) GetAll(filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockUsers)(nil).GetAll), filter)
+}
+
+// SetBlocked mocks base method.
+func (m *MockUsers) SetBlocked(userId string, blockedAt *time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetBlocked", userId, blockedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetBlocked indicates an expected call of SetBlocked.
+func (mr *MockUsersMockRecorder) SetBlocked(userId, blockedAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetBlocked", reflect.TypeOf((*MockUsers)(nil).SetBlocked), userId, blockedAt)
+}
+
+// Update mocks base method.
+func (m *MockUsers) Update(userId string, input domain.UpdateUserInput, timestamp time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", userId, input, timestamp)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockUsersMockRecorder) Update(userId, input, timestamp interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockUsers)(nil).Update), userId, input, timestamp)
+}
+
+// MockSessions is a mock of Sessions interface.
+type MockSessions struct {
+	ctrl     *gomock.Controller
+	recorder *MockSessionsMockRecorder
+}
+
+// MockSessionsMockRecorder is the mock recorder for MockSessions.
+type MockSessionsMockRecorder struct {
+	mock *MockSessions
+}
+
+// NewMockSessions creates a new mock instance.
+func NewMockSessions(ctrl *gomock.Controller) *MockSessions {
+	mock := &MockSessions{ctrl: ctrl}
+	mock.recorder = &MockSessionsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSessions) EXPECT() *MockSessionsMockRecorder {
+	return m.recorder
+}
+
+// CreateRefreshToken mocks base method.
+func (m *MockSessions) CreateRefreshToken(token domain.RefreshToken) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRefreshToken", token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateRefreshToken indicates an expected call of CreateRefreshToken.
+func (mr *MockSessionsMockRecorder) CreateRefreshToken(token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRefreshToken", reflect.TypeOf((*MockSessions)(nil).CreateRefreshToken), token)
+}
+
+// GetRefreshToken mocks base method.
+func (m *MockSessions) GetRefreshToken(tokenHash string) (domain.RefreshToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRefreshToken", tokenHash)
+	ret0, _ := ret[0].(domain.RefreshToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRefreshToken indicates an expected call of GetRefreshToken.
+func (mr *MockSessionsMockRecorder) GetRefreshToken(tokenHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRefreshToken", reflect.TypeOf((*MockSessions)(nil).GetRefreshToken), tokenHash)
+}
+
+// IsSessionActive mocks base method.
+func (m *MockSessions) IsSessionActive(sessionId string, timestamp time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsSessionActive", sessionId, timestamp)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsSessionActive indicates an expected call of IsSessionActive.
+func (mr *MockSessionsMockRecorder) IsSessionActive(sessionId, timestamp interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsSessionActive", reflect.TypeOf((*MockSessions)(nil).IsSessionActive), sessionId, timestamp)
+}
+
+// RevokeOtherSessions mocks base method.
+func (m *MockSessions) RevokeOtherSessions(userId, keepSessionId string, timestamp time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeOtherSessions", userId, keepSessionId, timestamp)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeOtherSessions indicates an expected call of RevokeOtherSessions.
+func (mr *MockSessionsMockRecorder) RevokeOtherSessions(userId, keepSessionId, timestamp interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeOtherSessions", reflect.TypeOf((*MockSessions)(nil).RevokeOtherSessions), userId, keepSessionId, timestamp)
+}
+
+// RevokeSession mocks base method.
+func (m *MockSessions) RevokeSession(sessionId string, timestamp time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeSession", sessionId, timestamp)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeSession indicates an expected call of RevokeSession.
+func (mr *MockSessionsMockRecorder) RevokeSession(sessionId, timestamp interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeSession", reflect.TypeOf((*MockSessions)(nil).RevokeSession), sessionId, timestamp)
+}
+
+// RevokeUserSessions mocks base method.
+func (m *MockSessions) RevokeUserSessions(userId string, timestamp time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeUserSessions", userId, timestamp)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeUserSessions indicates an expected call of RevokeUserSessions.
+func (mr *MockSessionsMockRecorder) RevokeUserSessions(userId, timestamp interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeUserSessions", reflect.TypeOf((*MockSessions)(nil).RevokeUserSessions), userId, timestamp)
+}
+
+// RotateRefreshToken mocks base method.
+func (m *MockSessions) RotateRefreshToken(usedId string, next domain.RefreshToken) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RotateRefreshToken", usedId, next)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RotateRefreshToken indicates an expected call of RotateRefreshToken.
+func (mr *MockSessionsMockRecorder) RotateRefreshToken(usedId, next interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RotateRefreshToken", reflect.TypeOf((*MockSessions)(nil).RotateRefreshToken), usedId, next)
+}
+
+// MockLoginAttempts is a mock of LoginAttempts interface.
+type MockLoginAttempts struct {
+	ctrl     *gomock.Controller
+	recorder *MockLoginAttemptsMockRecorder
+}
+
+// MockLoginAttemptsMockRecorder is the mock recorder for MockLoginAttempts.
+type MockLoginAttemptsMockRecorder struct {
+	mock *MockLoginAttempts
+}
+
+// NewMockLoginAttempts creates a new mock instance.
+func NewMockLoginAttempts(ctrl *gomock.Controller) *MockLoginAttempts {
+	mock := &MockLoginAttempts{ctrl: ctrl}
+	mock.recorder = &MockLoginAttemptsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLoginAttempts) EXPECT() *MockLoginAttemptsMockRecorder {
+	return m.recorder
+}
+
+// DeleteExpired mocks base method.
+func (m *MockLoginAttempts) DeleteExpired(now time.Time, window time.Duration) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpired", now, window)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpired indicates an expected call of DeleteExpired.
+func (mr *MockLoginAttemptsMockRecorder) DeleteExpired(now, window interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpired", reflect.TypeOf((*MockLoginAttempts)(nil).DeleteExpired), now, window)
+}
+
+// Get mocks base method.
+func (m *MockLoginAttempts) Get(key string) (domain.LoginAttempt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", key)
+	ret0, _ := ret[0].(domain.LoginAttempt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockLoginAttemptsMockRecorder) Get(key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockLoginAttempts)(nil).Get), key)
+}
+
+// Lock mocks base method.
+func (m *MockLoginAttempts) Lock(key string, until time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Lock", key, until)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Lock indicates an expected call of Lock.
+func (mr *MockLoginAttemptsMockRecorder) Lock(key, until interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Lock", reflect.TypeOf((*MockLoginAttempts)(nil).Lock), key, until)
+}
+
+// RegisterFailure mocks base method.
+func (m *MockLoginAttempts) RegisterFailure(key string, timestamp time.Time, window time.Duration) (domain.LoginAttempt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegisterFailure", key, timestamp, window)
+	ret0, _ := ret[0].(domain.LoginAttempt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RegisterFailure indicates an expected call of RegisterFailure.
+func (mr *MockLoginAttemptsMockRecorder) RegisterFailure(key, timestamp, window interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterFailure", reflect.TypeOf((*MockLoginAttempts)(nil).RegisterFailure), key, timestamp, window)
+}
+
+// Reset mocks base method.
+func (m *MockLoginAttempts) Reset(key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reset", key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reset indicates an expected call of Reset.
+func (mr *MockLoginAttemptsMockRecorder) Reset(key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reset", reflect.TypeOf((*MockLoginAttempts)(nil).Reset), key)
+}
+
+// MockRoles is a mock of Roles interface.
+type MockRoles struct {
+	ctrl     *gomock.Controller
+	recorder *MockRolesMockRecorder
+}
+
+// MockRolesMockRecorder is the mock recorder for MockRoles.
+type MockRolesMockRecorder struct {
+	mock *MockRoles
+}
+
+// NewMockRoles creates a new mock instance.
+func NewMockRoles(ctrl *gomock.Controller) *MockRoles {
+	mock := &MockRoles{ctrl: ctrl}
+	mock.recorder = &MockRolesMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRoles) EXPECT() *MockRolesMockRecorder {
+	return m.recorder
+}
+
+// GetAll mocks base method.
+func (m *MockRoles) GetAll() ([]domain.Role, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll")
+	ret0, _ := ret[0].([]domain.Role)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockRolesMockRecorder) GetAll() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockRoles)(nil).GetAll))
+}
+
+// Grant mocks base method.
+func (m *MockRoles) Grant(userId, role string, timestamp time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Grant", userId, role, timestamp)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Grant indicates an expected call of Grant.
+func (mr *MockRolesMockRecorder) Grant(userId, role, timestamp interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Grant", reflect.TypeOf((*MockRoles)(nil).Grant), userId, role, timestamp)
+}
+
+// Revoke mocks base method.
+func (m *MockRoles) Revoke(userId, role string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revoke", userId, role)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Revoke indicates an expected call of Revoke.
+func (mr *MockRolesMockRecorder) Revoke(userId, role interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*MockRoles)(nil).Revoke), userId, role)
+}
+
+// MockProductsList is a mock of ProductsList interface.
+type MockProductsList struct {
+	ctrl     *gomock.Controller
+	recorder *MockProductsListMockRecorder
+}
+
+// MockProductsListMockRecorder is the mock recorder for MockProductsList.
+type MockProductsListMockRecorder struct {
+	mock *MockProductsList
+}
+
+// NewMockProductsList creates a new mock instance.
+func NewMockProductsList(ctrl *gomock.Controller) *MockProductsList {
+	mock := &MockProductsList{ctrl: ctrl}
+	mock.recorder = &MockProductsListMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProductsList) EXPECT() *MockProductsListMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockProductsList) Create(list domain.CreateProductInput, productId string, timestamp time.Time) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", list, productId, timestamp)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockProductsListMockRecorder) Create(list, productId, timestamp interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockProductsList)(nil).Create), list, productId, timestamp)
+}
+
+// Delete mocks base method.
+func (m *MockProductsList) Delete(itemId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", itemId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockProductsListMockRecorder) Delete(itemId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockProductsList)(nil).Delete), itemId)
+}
+
+// GetAll mocks base method.
+func (m *MockProductsList) GetAll(filter domain.ProductsFilter, after *domain.ProductsCursor) (domain.ProductsPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", filter, after)
+	ret0, _ := ret[0].(domain.ProductsPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockProductsListMockRecorder) GetAll(filter, after interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockProductsList)(nil).GetAll), filter, after)
+}
+
+// GetById mocks base method.
+func (m *MockProductsList) GetById(listId string) (domain.ProductsList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetById", listId)
+	ret0, _ := ret[0].(domain.ProductsList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetById indicates an expected call of GetById.
+func (mr *MockProductsListMockRecorder) GetById(listId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetById", reflect.TypeOf((*MockProductsList)(nil).GetById), listId)
+}
+
+// Search mocks base method.
+func (m *MockProductsList) Search(input domain.ProductsSearchInput) (domain.ProductsSearchPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", input)
+	ret0, _ := ret[0].(domain.ProductsSearchPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Search indicates an expected call of Search.
+func (mr *MockProductsListMockRecorder) Search(input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockProductsList)(nil).Search), input)
+}
+
+// Update mocks base method.
+func (m *MockProductsList) Update(itemId string, input domain.UpdateProductInput) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", itemId, input)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockProductsListMockRecorder) Update(itemId, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockProductsList)(nil).Update), itemId, input)
+}
+
+// MockProductVariants is a mock of ProductVariants interface.
+type MockProductVariants struct {
+	ctrl     *gomock.Controller
+	recorder *MockProductVariantsMockRecorder
+}
+
+// MockProductVariantsMockRecorder is the mock recorder for MockProductVariants.
+type MockProductVariantsMockRecorder struct {
+	mock *MockProductVariants
+}
+
+// NewMockProductVariants creates a new mock instance.
+func NewMockProductVariants(ctrl *gomock.Controller) *MockProductVariants {
+	mock := &MockProductVariants{ctrl: ctrl}
+	mock.recorder = &MockProductVariantsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProductVariants) EXPECT() *MockProductVariantsMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockProductVariants) Create(variant domain.ProductVariant) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", variant)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockProductVariantsMockRecorder) Create(variant interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockProductVariants)(nil).Create), variant)
+}
+
+// Delete mocks base method.
+func (m *MockProductVariants) Delete(productId, variantId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", productId, variantId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockProductVariantsMockRecorder) Delete(productId, variantId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockProductVariants)(nil).Delete), productId, variantId)
+}
+
+// GetByProduct mocks base method.
+func (m *MockProductVariants) GetByProduct(productId string) ([]domain.ProductVariant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByProduct", productId)
+	ret0, _ := ret[0].([]domain.ProductVariant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByProduct indicates an expected call of GetByProduct.
+func (mr *MockProductVariantsMockRecorder) GetByProduct(productId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByProduct", reflect.TypeOf((*MockProductVariants)(nil).GetByProduct), productId)
+}
+
+// Update mocks base method.
+func (m *MockProductVariants) Update(productId, variantId string, input domain.UpdateVariantInput, timestamp time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", productId, variantId, input, timestamp)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockProductVariantsMockRecorder) Update(productId, variantId, input, timestamp interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockProductVariants)(nil).Update), productId, variantId, input, timestamp)
+}
+
+// MockInventory is a mock of Inventory interface.
+type MockInventory struct {
+	ctrl     *gomock.Controller
+	recorder *MockInventoryMockRecorder
+}
+
+// MockInventoryMockRecorder is the mock recorder for MockInventory.
+type MockInventoryMockRecorder struct {
+	mock *MockInventory
+}
+
+// NewMockInventory creates a new mock instance.
+func NewMockInventory(ctrl *gomock.Controller) *MockInventory {
+	mock := &MockInventory{ctrl: ctrl}
+	mock.recorder = &MockInventoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockInventory) EXPECT() *MockInventoryMockRecorder {
+	return m.recorder
+}
+
+// ConfirmReservation mocks base method.
+func (m *MockInventory) ConfirmReservation(reservationId string, movement domain.StockMovement) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfirmReservation", reservationId, movement)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ConfirmReservation indicates an expected call of ConfirmReservation.
+func (mr *MockInventoryMockRecorder) ConfirmReservation(reservationId, movement interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfirmReservation", reflect.TypeOf((*MockInventory)(nil).ConfirmReservation), reservationId, movement)
+}
+
+// ConfirmReservations mocks base method.
+func (m *MockInventory) ConfirmReservations(movements []domain.StockMovement) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfirmReservations", movements)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ConfirmReservations indicates an expected call of ConfirmReservations.
+func (mr *MockInventoryMockRecorder) ConfirmReservations(movements interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfirmReservations", reflect.TypeOf((*MockInventory)(nil).ConfirmReservations), movements)
+}
+
+// ExpireReservations mocks base method.
+func (m *MockInventory) ExpireReservations(timestamp time.Time) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpireReservations", timestamp)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpireReservations indicates an expected call of ExpireReservations.
+func (mr *MockInventoryMockRecorder) ExpireReservations(timestamp interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpireReservations", reflect.TypeOf((*MockInventory)(nil).ExpireReservations), timestamp)
+}
+
+// GetLevels mocks base method.
+func (m *MockInventory) GetLevels(filter domain.StockLevelsFilter) (domain.StockLevelsList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLevels", filter)
+	ret0, _ := ret[0].(domain.StockLevelsList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLevels indicates an expected call of GetLevels.
+func (mr *MockInventoryMockRecorder) GetLevels(filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLevels", reflect.TypeOf((*MockInventory)(nil).GetLevels), filter)
+}
+
+// GetMovements mocks base method.
+func (m *MockInventory) GetMovements(filter domain.StockMovementsFilter) (domain.StockMovementsList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMovements", filter)
+	ret0, _ := ret[0].(domain.StockMovementsList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMovements indicates an expected call of GetMovements.
+func (mr *MockInventoryMockRecorder) GetMovements(filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMovements", reflect.TypeOf((*MockInventory)(nil).GetMovements), filter)
+}
+
+// RecordMovement mocks base method.
+func (m *MockInventory) RecordMovement(movement domain.StockMovement, version *int) (domain.StockLevel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordMovement", movement, version)
+	ret0, _ := ret[0].(domain.StockLevel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecordMovement indicates an expected call of RecordMovement.
+func (mr *MockInventoryMockRecorder) RecordMovement(movement, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordMovement", reflect.TypeOf((*MockInventory)(nil).RecordMovement), movement, version)
+}
+
+// ReleaseReservation mocks base method.
+func (m *MockInventory) ReleaseReservation(reservationId string, timestamp time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseReservation", reservationId, timestamp)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReleaseReservation indicates an expected call of ReleaseReservation.
+func (mr *MockInventoryMockRecorder) ReleaseReservation(reservationId, timestamp interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseReservation", reflect.TypeOf((*MockInventory)(nil).ReleaseReservation), reservationId, timestamp)
+}
+
+// Reserve mocks base method.
+func (m *MockInventory) Reserve(reservation domain.Reservation) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reserve", reservation)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reserve indicates an expected call of Reserve.
+func (mr *MockInventoryMockRecorder) Reserve(reservation interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reserve", reflect.TypeOf((*MockInventory)(nil).Reserve), reservation)
+}
+
+// MockCarts is a mock of Carts interface.
+type MockCarts struct {
+	ctrl     *gomock.Controller
+	recorder *MockCartsMockRecorder
+}
+
+// MockCartsMockRecorder is the mock recorder for MockCarts.
+type MockCartsMockRecorder struct {
+	mock *MockCarts
+}
+
+// NewMockCarts creates a new mock instance.
+func NewMockCarts(ctrl *gomock.Controller) *MockCarts {
+	mock := &MockCarts{ctrl: ctrl}
+	mock.recorder = &MockCartsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCarts) EXPECT() *MockCartsMockRecorder {
+	return m.recorder
+}
+
+// AcceptPrices mocks base method.
+func (m *MockCarts) AcceptPrices(cartId string, timestamp time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AcceptPrices", cartId, timestamp)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AcceptPrices indicates an expected call of AcceptPrices.
+func (mr *MockCartsMockRecorder) AcceptPrices(cartId, timestamp interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcceptPrices", reflect.TypeOf((*MockCarts)(nil).AcceptPrices), cartId, timestamp)
+}
+
+// AddItem mocks base method.
+func (m *MockCarts) AddItem(item domain.CartItem, maxQuantity int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddItem", item, maxQuantity)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddItem indicates an expected call of AddItem.
+func (mr *MockCartsMockRecorder) AddItem(item, maxQuantity interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddItem", reflect.TypeOf((*MockCarts)(nil).AddItem), item, maxQuantity)
+}
+
+// AssignToUser mocks base method.
+func (m *MockCarts) AssignToUser(cartId, userId string, timestamp time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AssignToUser", cartId, userId, timestamp)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AssignToUser indicates an expected call of AssignToUser.
+func (mr *MockCartsMockRecorder) AssignToUser(cartId, userId, timestamp interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignToUser", reflect.TypeOf((*MockCarts)(nil).AssignToUser), cartId, userId, timestamp)
+}
+
+// Clear mocks base method.
+func (m *MockCarts) Clear(cartId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Clear", cartId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Clear indicates an expected call of Clear.
+func (mr *MockCartsMockRecorder) Clear(cartId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Clear", reflect.TypeOf((*MockCarts)(nil).Clear), cartId)
+}
+
+// Create mocks base method.
+func (m *MockCarts) Create(cart domain.Cart) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", cart)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockCartsMockRecorder) Create(cart interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockCarts)(nil).Create), cart)
+}
+
+// DeleteExpired mocks base method.
+func (m *MockCarts) DeleteExpired(timestamp time.Time) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpired", timestamp)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpired indicates an expected call of DeleteExpired.
+func (mr *MockCartsMockRecorder) DeleteExpired(timestamp interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpired", reflect.TypeOf((*MockCarts)(nil).DeleteExpired), timestamp)
+}
+
+// GetByToken mocks base method.
+func (m *MockCarts) GetByToken(tokenHash string, timestamp time.Time) (domain.Cart, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByToken", tokenHash, timestamp)
+	ret0, _ := ret[0].(domain.Cart)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByToken indicates an expected call of GetByToken.
+func (mr *MockCartsMockRecorder) GetByToken(tokenHash, timestamp interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByToken", reflect.TypeOf((*MockCarts)(nil).GetByToken), tokenHash, timestamp)
+}
+
+// GetByUser mocks base method.
+func (m *MockCarts) GetByUser(userId string) (domain.Cart, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByUser", userId)
+	ret0, _ := ret[0].(domain.Cart)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByUser indicates an expected call of GetByUser.
+func (mr *MockCartsMockRecorder) GetByUser(userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUser", reflect.TypeOf((*MockCarts)(nil).GetByUser), userId)
+}
+
+// GetItems mocks base method.
+func (m *MockCarts) GetItems(cartId string) ([]domain.CartItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetItems", cartId)
+	ret0, _ := ret[0].([]domain.CartItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetItems indicates an expected call of GetItems.
+func (mr *MockCartsMockRecorder) GetItems(cartId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetItems", reflect.TypeOf((*MockCarts)(nil).GetItems), cartId)
+}
+
+// Merge mocks base method.
+func (m *MockCarts) Merge(fromCartId, toCartId string, maxQuantity int, timestamp time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Merge", fromCartId, toCartId, maxQuantity, timestamp)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Merge indicates an expected call of Merge.
+func (mr *MockCartsMockRecorder) Merge(fromCartId, toCartId, maxQuantity, timestamp interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Merge", reflect.TypeOf((*MockCarts)(nil).Merge), fromCartId, toCartId, maxQuantity, timestamp)
+}
+
+// RemoveItem mocks base method.
+func (m *MockCarts) RemoveItem(cartId, itemId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveItem", cartId, itemId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveItem indicates an expected call of RemoveItem.
+func (mr *MockCartsMockRecorder) RemoveItem(cartId, itemId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveItem", reflect.TypeOf((*MockCarts)(nil).RemoveItem), cartId, itemId)
+}
+
+// Touch mocks base method.
+func (m *MockCarts) Touch(cartId string, timestamp, expiresAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Touch", cartId, timestamp, expiresAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Touch indicates an expected call of Touch.
+func (mr *MockCartsMockRecorder) Touch(cartId, timestamp, expiresAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Touch", reflect.TypeOf((*MockCarts)(nil).Touch), cartId, timestamp, expiresAt)
+}
+
+// UpdateItemQuantity mocks base method.
+func (m *MockCarts) UpdateItemQuantity(cartId, itemId string, quantity int, timestamp time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateItemQuantity", cartId, itemId, quantity, timestamp)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateItemQuantity indicates an expected call of UpdateItemQuantity.
+func (mr *MockCartsMockRecorder) UpdateItemQuantity(cartId, itemId, quantity, timestamp interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateItemQuantity", reflect.TypeOf((*MockCarts)(nil).UpdateItemQuantity), cartId, itemId, quantity, timestamp)
+}
+
+// MockOrders is a mock of Orders interface.
+type MockOrders struct {
+	ctrl     *gomock.Controller
+	recorder *MockOrdersMockRecorder
+}
+
+// MockOrdersMockRecorder is the mock recorder for MockOrders.
+type MockOrdersMockRecorder struct {
+	mock *MockOrders
+}
+
+// NewMockOrders creates a new mock instance.
+func NewMockOrders(ctrl *gomock.Controller) *MockOrders {
+	mock := &MockOrders{ctrl: ctrl}
+	mock.recorder = &MockOrdersMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOrders) EXPECT() *MockOrdersMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockOrders) Create(order domain.Order, created domain.OrderStatusChange, redemption *domain.PromoCodeRedemption) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", order, created, redemption)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockOrdersMockRecorder) Create(order, created, redemption interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockOrders)(nil).Create), order, created, redemption)
+}
+
+// GetAll mocks base method.
+func (m *MockOrders) GetAll(filter domain.OrdersFilter) (domain.OrdersList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", filter)
+	ret0, _ := ret[0].(domain.OrdersList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockOrdersMockRecorder) GetAll(filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockOrders)(nil).GetAll), filter)
+}
+
+// GetById mocks base method.
+func (m *MockOrders) GetById(orderId string) (domain.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetById", orderId)
+	ret0, _ := ret[0].(domain.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetById indicates an expected call of GetById.
+func (mr *MockOrdersMockRecorder) GetById(orderId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetById", reflect.TypeOf((*MockOrders)(nil).GetById), orderId)
+}
+
+// GetExpired mocks base method.
+func (m *MockOrders) GetExpired(timestamp time.Time) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExpired", timestamp)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExpired indicates an expected call of GetExpired.
+func (mr *MockOrdersMockRecorder) GetExpired(timestamp interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExpired", reflect.TypeOf((*MockOrders)(nil).GetExpired), timestamp)
+}
+
+// UpdateStatus mocks base method.
+func (m *MockOrders) UpdateStatus(change domain.OrderStatusChange) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateStatus", change)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateStatus indicates an expected call of UpdateStatus.
+func (mr *MockOrdersMockRecorder) UpdateStatus(change interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatus", reflect.TypeOf((*MockOrders)(nil).UpdateStatus), change)
+}
+
+// MockPayments is a mock of Payments interface.
+type MockPayments struct {
+	ctrl     *gomock.Controller
+	recorder *MockPaymentsMockRecorder
+}
+
+// MockPaymentsMockRecorder is the mock recorder for MockPayments.
+type MockPaymentsMockRecorder struct {
+	mock *MockPayments
+}
+
+// NewMockPayments creates a new mock instance.
+func NewMockPayments(ctrl *gomock.Controller) *MockPayments {
+	mock := &MockPayments{ctrl: ctrl}
+	mock.recorder = &MockPaymentsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPayments) EXPECT() *MockPaymentsMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockPayments) Create(payment domain.Payment) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", payment)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockPaymentsMockRecorder) Create(payment interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockPayments)(nil).Create), payment)
+}
+
+// GetByIntent mocks base method.
+func (m *MockPayments) GetByIntent(provider, intentId string) (domain.Payment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByIntent", provider, intentId)
+	ret0, _ := ret[0].(domain.Payment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByIntent indicates an expected call of GetByIntent.
+func (mr *MockPaymentsMockRecorder) GetByIntent(provider, intentId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByIntent", reflect.TypeOf((*MockPayments)(nil).GetByIntent), provider, intentId)
+}
+
+// GetByOrder mocks base method.
+func (m *MockPayments) GetByOrder(orderId, status string) (domain.Payment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByOrder", orderId, status)
+	ret0, _ := ret[0].(domain.Payment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByOrder indicates an expected call of GetByOrder.
+func (mr *MockPaymentsMockRecorder) GetByOrder(orderId, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByOrder", reflect.TypeOf((*MockPayments)(nil).GetByOrder), orderId, status)
+}
+
+// HasEvent mocks base method.
+func (m *MockPayments) HasEvent(provider, eventId string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasEvent", provider, eventId)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HasEvent indicates an expected call of HasEvent.
+func (mr *MockPaymentsMockRecorder) HasEvent(provider, eventId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasEvent", reflect.TypeOf((*MockPayments)(nil).HasEvent), provider, eventId)
+}
+
+// RecordEvent mocks base method.
+func (m *MockPayments) RecordEvent(provider, eventId, eventType string, paymentId *string, timestamp time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordEvent", provider, eventId, eventType, paymentId, timestamp)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordEvent indicates an expected call of RecordEvent.
+func (mr *MockPaymentsMockRecorder) RecordEvent(provider, eventId, eventType, paymentId, timestamp interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordEvent", reflect.TypeOf((*MockPayments)(nil).RecordEvent), provider, eventId, eventType, paymentId, timestamp)
+}
+
+// UpdateStatus mocks base method.
+func (m *MockPayments) UpdateStatus(paymentId, from, to, failureCode string, timestamp time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateStatus", paymentId, from, to, failureCode, timestamp)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateStatus indicates an expected call of UpdateStatus.
+func (mr *MockPaymentsMockRecorder) UpdateStatus(paymentId, from, to, failureCode, timestamp interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatus", reflect.TypeOf((*MockPayments)(nil).UpdateStatus), paymentId, from, to, failureCode, timestamp)
+}
+
+// MockDiscounts is a mock of Discounts interface.
+type MockDiscounts struct {
+	ctrl     *gomock.Controller
+	recorder *MockDiscountsMockRecorder
+}
+
+// MockDiscountsMockRecorder is the mock recorder for MockDiscounts.
+type MockDiscountsMockRecorder struct {
+	mock *MockDiscounts
+}
+
+// NewMockDiscounts creates a new mock instance.
+func NewMockDiscounts(ctrl *gomock.Controller) *MockDiscounts {
+	mock := &MockDiscounts{ctrl: ctrl}
+	mock.recorder = &MockDiscountsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDiscounts) EXPECT() *MockDiscountsMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockDiscounts) Create(discount domain.Discount) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", discount)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockDiscountsMockRecorder) Create(discount interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockDiscounts)(nil).Create), discount)
+}
+
+// Delete mocks base method.
+func (m *MockDiscounts) Delete(discountId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", discountId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockDiscountsMockRecorder) Delete(discountId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockDiscounts)(nil).Delete), discountId)
+}
+
+// GetAll mocks base method.
+func (m *MockDiscounts) GetAll(filter domain.DiscountsFilter) (domain.DiscountsList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", filter)
+	ret0, _ := ret[0].(domain.DiscountsList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockDiscountsMockRecorder) GetAll(filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockDiscounts)(nil).GetAll), filter)
+}
+
+// GetById mocks base method.
+func (m *MockDiscounts) GetById(discountId string) (domain.Discount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetById", discountId)
+	ret0, _ := ret[0].(domain.Discount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetById indicates an expected call of GetById.
+func (mr *MockDiscountsMockRecorder) GetById(discountId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetById", reflect.TypeOf((*MockDiscounts)(nil).GetById), discountId)
+}
+
+// RefreshSales mocks base method.
+func (m *MockDiscounts) RefreshSales() (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefreshSales")
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RefreshSales indicates an expected call of RefreshSales.
+func (mr *MockDiscountsMockRecorder) RefreshSales() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshSales", reflect.TypeOf((*MockDiscounts)(nil).RefreshSales))
+}
+
+// Update mocks base method.
+func (m *MockDiscounts) Update(discount domain.Discount) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", discount)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockDiscountsMockRecorder) Update(discount interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockDiscounts)(nil).Update), discount)
+}
+
+// MockPromoCodes is a mock of PromoCodes interface.
+type MockPromoCodes struct {
+	ctrl     *gomock.Controller
+	recorder *MockPromoCodesMockRecorder
+}
+
+// MockPromoCodesMockRecorder is the mock recorder for MockPromoCodes.
+type MockPromoCodesMockRecorder struct {
+	mock *MockPromoCodes
+}
+
+// NewMockPromoCodes creates a new mock instance.
+func NewMockPromoCodes(ctrl *gomock.Controller) *MockPromoCodes {
+	mock := &MockPromoCodes{ctrl: ctrl}
+	mock.recorder = &MockPromoCodesMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPromoCodes) EXPECT() *MockPromoCodesMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockPromoCodes) Create(code domain.PromoCode) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", code)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockPromoCodesMockRecorder) Create(code interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockPromoCodes)(nil).Create), code)
+}
+
+// Delete mocks base method.
+func (m *MockPromoCodes) Delete(codeId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", codeId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockPromoCodesMockRecorder) Delete(codeId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockPromoCodes)(nil).Delete), codeId)
+}
+
+// GetAll mocks base method.
+func (m *MockPromoCodes) GetAll(filter domain.PromoCodesFilter) (domain.PromoCodesList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", filter)
+	ret0, _ := ret[0].(domain.PromoCodesList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockPromoCodesMockRecorder) GetAll(filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockPromoCodes)(nil).GetAll), filter)
+}
+
+// GetByCode mocks base method.
+func (m *MockPromoCodes) GetByCode(code string) (domain.PromoCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByCode", code)
+	ret0, _ := ret[0].(domain.PromoCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByCode indicates an expected call of GetByCode.
+func (mr *MockPromoCodesMockRecorder) GetByCode(code interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByCode", reflect.TypeOf((*MockPromoCodes)(nil).GetByCode), code)
+}
+
+// GetById mocks base method.
+func (m *MockPromoCodes) GetById(codeId string) (domain.PromoCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetById", codeId)
+	ret0, _ := ret[0].(domain.PromoCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetById indicates an expected call of GetById.
+func (mr *MockPromoCodesMockRecorder) GetById(codeId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetById", reflect.TypeOf((*MockPromoCodes)(nil).GetById), codeId)
+}
+
+// Update mocks base method.
+func (m *MockPromoCodes) Update(code domain.PromoCode) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", code)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockPromoCodesMockRecorder) Update(code interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockPromoCodes)(nil).Update), code)
+}
+
+// MockExchangeRates is a mock of ExchangeRates interface.
+type MockExchangeRates struct {
+	ctrl     *gomock.Controller
+	recorder *MockExchangeRatesMockRecorder
+}
+
+// MockExchangeRatesMockRecorder is the mock recorder for MockExchangeRates.
+type MockExchangeRatesMockRecorder struct {
+	mock *MockExchangeRates
+}
+
+// NewMockExchangeRates creates a new mock instance.
+func NewMockExchangeRates(ctrl *gomock.Controller) *MockExchangeRates {
+	mock := &MockExchangeRates{ctrl: ctrl}
+	mock.recorder = &MockExchangeRatesMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExchangeRates) EXPECT() *MockExchangeRatesMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method.
+func (m *MockExchangeRates) Get(currency string) (domain.ExchangeRate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", currency)
+	ret0, _ := ret[0].(domain.ExchangeRate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockExchangeRatesMockRecorder) Get(currency interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockExchangeRates)(nil).Get), currency)
+}
+
+// GetAll mocks base method.
+func (m *MockExchangeRates) GetAll() ([]domain.ExchangeRate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll")
+	ret0, _ := ret[0].([]domain.ExchangeRate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockExchangeRatesMockRecorder) GetAll() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockExchangeRates)(nil).GetAll))
+}
+
+// Set mocks base method.
+func (m *MockExchangeRates) Set(rates []domain.ExchangeRate) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Set", rates)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Set indicates an expected call of Set.
+func (mr *MockExchangeRatesMockRecorder) Set(rates interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockExchangeRates)(nil).Set), rates)
+}
+
+// MockProductPrices is a mock of ProductPrices interface.
+type MockProductPrices struct {
+	ctrl     *gomock.Controller
+	recorder *MockProductPricesMockRecorder
+}
+
+// MockProductPricesMockRecorder is the mock recorder for MockProductPrices.
+type MockProductPricesMockRecorder struct {
+	mock *MockProductPrices
+}
+
+// NewMockProductPrices creates a new mock instance.
+func NewMockProductPrices(ctrl *gomock.Controller) *MockProductPrices {
+	mock := &MockProductPrices{ctrl: ctrl}
+	mock.recorder = &MockProductPricesMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProductPrices) EXPECT() *MockProductPricesMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockProductPrices) Delete(productId, currency string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", productId, currency)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockProductPricesMockRecorder) Delete(productId, currency interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockProductPrices)(nil).Delete), productId, currency)
+}
+
+// GetByProduct mocks base method.
+func (m *MockProductPrices) GetByProduct(productId string) ([]domain.ProductPrice, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByProduct", productId)
+	ret0, _ := ret[0].([]domain.ProductPrice)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByProduct indicates an expected call of GetByProduct.
+func (mr *MockProductPricesMockRecorder) GetByProduct(productId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByProduct", reflect.TypeOf((*MockProductPrices)(nil).GetByProduct), productId)
+}
+
+// GetByProducts mocks base method.
+func (m *MockProductPrices) GetByProducts(productIds []string, currency string) (map[string]domain.Money, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByProducts", productIds, currency)
+	ret0, _ := ret[0].(map[string]domain.Money)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByProducts indicates an expected call of GetByProducts.
+func (mr *MockProductPricesMockRecorder) GetByProducts(productIds, currency interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByProducts", reflect.TypeOf((*MockProductPrices)(nil).GetByProducts), productIds, currency)
+}
+
+// Set mocks base method.
+func (m *MockProductPrices) Set(price domain.ProductPrice) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Set", price)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Set indicates an expected call of Set.
+func (mr *MockProductPricesMockRecorder) Set(price interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockProductPrices)(nil).Set), price)
+}
+
+// MockCategories is a mock of Categories interface.
+type MockCategories struct {
+	ctrl     *gomock.Controller
+	recorder *MockCategoriesMockRecorder
+}
+
+// MockCategoriesMockRecorder is the mock recorder for MockCategories.
+type MockCategoriesMockRecorder struct {
+	mock *MockCategories
+}
+
+// NewMockCategories creates a new mock instance.
+func NewMockCategories(ctrl *gomock.Controller) *MockCategories {
+	mock := &MockCategories{ctrl: ctrl}
+	mock.recorder = &MockCategoriesMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCategories) EXPECT() *MockCategoriesMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockCategories) Create(category domain.Category) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", category)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockCategoriesMockRecorder) Create(category interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockCategories)(nil).Create), category)
+}
+
+// Delete mocks base method.
+func (m *MockCategories) Delete(categoryId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", categoryId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockCategoriesMockRecorder) Delete(categoryId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockCategories)(nil).Delete), categoryId)
+}
+
+// GetAllWithCounts mocks base method.
+func (m *MockCategories) GetAllWithCounts() ([]domain.CategoryNode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllWithCounts")
+	ret0, _ := ret[0].([]domain.CategoryNode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllWithCounts indicates an expected call of GetAllWithCounts.
+func (mr *MockCategoriesMockRecorder) GetAllWithCounts() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllWithCounts", reflect.TypeOf((*MockCategories)(nil).GetAllWithCounts))
+}
+
+// GetById mocks base method.
+func (m *MockCategories) GetById(categoryId string) (domain.Category, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetById", categoryId)
+	ret0, _ := ret[0].(domain.Category)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetById indicates an expected call of GetById.
+func (mr *MockCategoriesMockRecorder) GetById(categoryId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetById", reflect.TypeOf((*MockCategories)(nil).GetById), categoryId)
+}
+
+// Update mocks base method.
+func (m *MockCategories) Update(categoryId string, input domain.UpdateCategoryInput, timestamp time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", categoryId, input, timestamp)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockCategoriesMockRecorder) Update(categoryId, input, timestamp interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockCategories)(nil).Update), categoryId, input, timestamp)
+}
+
+// MockFiles is a mock of Files interface.
+type MockFiles struct {
+	ctrl     *gomock.Controller
+	recorder *MockFilesMockRecorder
+}
+
+// MockFilesMockRecorder is the mock recorder for MockFiles.
+type MockFilesMockRecorder struct {
+	mock *MockFiles
+}
+
+// NewMockFiles creates a new mock instance.
+func NewMockFiles(ctrl *gomock.Controller) *MockFiles {
+	mock := &MockFiles{ctrl: ctrl}
+	mock.recorder = &MockFilesMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFiles) EXPECT() *MockFilesMockRecorder {
+	return m.recorder
+}
+
+// Complete mocks base method.
+func (m *MockFiles) Complete(file domain.File) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Complete", file)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Complete indicates an expected call of Complete.
+func (mr *MockFilesMockRecorder) Complete(file interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Complete", reflect.TypeOf((*MockFiles)(nil).Complete), file)
+}
+
+// Create mocks base method.
+func (m *MockFiles) Create(file domain.File) (domain.File, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", file)
+	ret0, _ := ret[0].(domain.File)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockFilesMockRecorder) Create(file interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockFiles)(nil).Create), file)
+}
+
+// Delete mocks base method.
+func (m *MockFiles) Delete(productId, fileId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", productId, fileId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockFilesMockRecorder) Delete(productId, fileId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockFiles)(nil).Delete), productId, fileId)
+}
+
+// FailStale mocks base method.
+func (m *MockFiles) FailStale(before, clientBefore time.Time, reason string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FailStale", before, clientBefore, reason)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FailStale indicates an expected call of FailStale.
+func (mr *MockFilesMockRecorder) FailStale(before, clientBefore, reason interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FailStale", reflect.TypeOf((*MockFiles)(nil).FailStale), before, clientBefore, reason)
+}
+
+// GetById mocks base method.
+func (m *MockFiles) GetById(fileId string) (domain.File, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetById", fileId)
+	ret0, _ := ret[0].(domain.File)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetById indicates an expected call of GetById.
+func (mr *MockFilesMockRecorder) GetById(fileId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetById", reflect.TypeOf((*MockFiles)(nil).GetById), fileId)
+}
+
+// GetByProduct mocks base method.
+func (m *MockFiles) GetByProduct(productId string) ([]domain.File, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByProduct", productId)
+	ret0, _ := ret[0].([]domain.File)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByProduct indicates an expected call of GetByProduct.
+func (mr *MockFilesMockRecorder) GetByProduct(productId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByProduct", reflect.TypeOf((*MockFiles)(nil).GetByProduct), productId)
+}
+
+// GetByProducts mocks base method.
+func (m *MockFiles) GetByProducts(productIds []string) (map[string][]domain.File, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByProducts", productIds)
+	ret0, _ := ret[0].(map[string][]domain.File)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByProducts indicates an expected call of GetByProducts.
+func (mr *MockFilesMockRecorder) GetByProducts(productIds interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByProducts", reflect.TypeOf((*MockFiles)(nil).GetByProducts), productIds)
+}
+
+// Reorder mocks base method.
+func (m *MockFiles) Reorder(productId string, fileIds []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reorder", productId, fileIds)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reorder indicates an expected call of Reorder.
+func (mr *MockFilesMockRecorder) Reorder(productId, fileIds interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reorder", reflect.TypeOf((*MockFiles)(nil).Reorder), productId, fileIds)
+}
+
+// SetPrimary mocks base method.
+func (m *MockFiles) SetPrimary(productId, fileId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetPrimary", productId, fileId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetPrimary indicates an expected call of SetPrimary.
+func (mr *MockFilesMockRecorder) SetPrimary(productId, fileId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPrimary", reflect.TypeOf((*MockFiles)(nil).SetPrimary), productId, fileId)
+}
+
+// Update mocks base method.
+func (m *MockFiles) Update(productId, fileId string, input domain.UpdateImageInput) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", productId, fileId, input)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockFilesMockRecorder) Update(productId, fileId, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockFiles)(nil).Update), productId, fileId, input)
+}
+
+// UpdateStatus mocks base method.
+func (m *MockFiles) UpdateStatus(file domain.File, from domain.FileStatus) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateStatus", file, from)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateStatus indicates an expected call of UpdateStatus.
+func (mr *MockFilesMockRecorder) UpdateStatus(file, from interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatus", reflect.TypeOf((*MockFiles)(nil).UpdateStatus), file, from)
+}
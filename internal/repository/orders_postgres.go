@@ -0,0 +1,327 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/AndrewMislyuk/go-shop-backend/internal/domain"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	selectOrderQuery         = "SELECT id, number, user_id, status, items_count, subtotal, discount, COALESCE(promo_code, ''), total, currency, expires_at, created_at, updated_at FROM orders"
+	selectOrderItemsQuery    = "SELECT id, order_id, product_id, variant_id, title, sku, options, price, sale, sale_old_price, quantity, reservation_id FROM order_items"
+	selectOrderHistoryQuery  = "SELECT id, order_id, from_status, to_status, actor_id, comment, created_at FROM order_status_history"
+	insertOrderHistoryQuery  = "INSERT INTO order_status_history(id, order_id, from_status, to_status, actor_id, comment, created_at) values($1, $2, $3, $4, $5, $6, $7)"
+	insertOrderItemQuery     = "INSERT INTO order_items(id, order_id, position, product_id, variant_id, title, sku, options, price, sale, sale_old_price, quantity, reservation_id) values($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)"
+	insertOrderQueryTemplate = "INSERT INTO orders(id, user_id, status, items_count, subtotal, discount, promo_code, total, currency, expires_at, created_at) values($1, $2, $3, $4, $5, $6, NULLIF($7, ''), $8, $9, $10, $11) RETURNING number"
+	insertRedemptionQuery    = "INSERT INTO promo_code_redemptions(id, promo_code_id, user_id, order_id, amount, created_at) values($1, $2, $3, $4, $5, $6)"
+	// releasePromoCodeQuery gives the use of the promo code back to the
+	// code and to the user.
+	releasePromoCodeQuery = `WITH r AS (DELETE FROM promo_code_redemptions WHERE order_id = $1 RETURNING promo_code_id)
+		UPDATE promo_codes SET used_count = used_count - 1 FROM r WHERE promo_codes.id = r.promo_code_id`
+)
+
+type OrdersPostgres struct {
+	db *sql.DB
+}
+
+func NewOrdersPostgres(db *sql.DB) *OrdersPostgres {
+	return &OrdersPostgres{
+		db: db,
+	}
+}
+
+// Create stores the order with its items and the first history entry and
+// returns the number of the order. The promo code of the order, if any, is
+// redeemed in the same transaction, so its usage limits hold under
+// concurrent checkouts.
+func (r *OrdersPostgres) Create(order domain.Order, created domain.OrderStatusChange, redemption *domain.PromoCodeRedemption) (int64, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+
+	number, err := createOrder(tx, order, created, redemption)
+	if err != nil {
+		if rb := tx.Rollback(); rb != nil {
+			logrus.Fatalf("query failed: %v, unable to abort: %v", err, rb)
+		}
+
+		return 0, err
+	}
+
+	return number, tx.Commit()
+}
+
+func createOrder(tx *sql.Tx, order domain.Order, created domain.OrderStatusChange, redemption *domain.PromoCodeRedemption) (int64, error) {
+	var number int64
+
+	if err := tx.QueryRow(insertOrderQueryTemplate, order.Id, order.UserId, order.Status, order.ItemsCount, order.Subtotal.Amount, order.Discount.Amount, order.PromoCode,
+		order.Total.Amount, order.Total.Currency, order.ExpiresAt, order.CreatedAt).Scan(&number); err != nil {
+		return 0, err
+	}
+
+	for position, item := range order.Items {
+		options, err := json.Marshal(item.Options)
+		if err != nil {
+			return 0, err
+		}
+
+		if _, err := tx.Exec(insertOrderItemQuery, item.Id, order.Id, position, item.ProductId, item.VariantId, item.Title, item.Sku, string(options),
+			item.Price.Amount, item.Sale, item.SaleOldPrice.Amount, item.Quantity, item.ReservationId); err != nil {
+			return 0, err
+		}
+	}
+
+	if _, err := tx.Exec(insertOrderHistoryQuery, created.Id, order.Id, created.FromStatus, created.ToStatus, created.ActorId, created.Comment, created.CreatedAt); err != nil {
+		return 0, err
+	}
+
+	if redemption != nil {
+		if err := redeemPromoCode(tx, *redemption); err != nil {
+			return 0, err
+		}
+	}
+
+	return number, nil
+}
+
+// redeemPromoCode counts the use of the code against its global limit and
+// then against the limit of the user.
+func redeemPromoCode(tx *sql.Tx, redemption domain.PromoCodeRedemption) error {
+	var perUserLimit *int
+	err := tx.QueryRow("UPDATE promo_codes SET used_count = used_count + 1 WHERE id = $1 AND (usage_limit IS NULL OR used_count < usage_limit) RETURNING per_user_limit",
+		redemption.PromoCodeId).Scan(&perUserLimit)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.ErrPromoCodeUsedUp
+		}
+
+		return err
+	}
+
+	if perUserLimit != nil {
+		var used int
+		if err := tx.QueryRow("SELECT count(*) FROM promo_code_redemptions WHERE promo_code_id = $1 AND user_id = $2",
+			redemption.PromoCodeId, redemption.UserId).Scan(&used); err != nil {
+			return err
+		}
+
+		if used >= *perUserLimit {
+			return domain.ErrPromoCodeUserLimit
+		}
+	}
+
+	_, err = tx.Exec(insertRedemptionQuery, redemption.Id, redemption.PromoCodeId, redemption.UserId, redemption.OrderId, redemption.Amount, redemption.CreatedAt)
+
+	return err
+}
+
+// GetById returns the order with its items and history.
+func (r *OrdersPostgres) GetById(orderId string) (domain.Order, error) {
+	order, err := scanOrder(r.db.QueryRow(selectOrderQuery+" WHERE id = $1", orderId))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return order, domain.ErrOrderNotFound
+		}
+
+		return order, err
+	}
+
+	if order.Items, err = r.getItems(orderId, order.Total.Currency); err != nil {
+		return order, err
+	}
+
+	order.History, err = r.getHistory(orderId)
+
+	return order, err
+}
+
+// GetAll returns the orders without items and history, newest first.
+func (r *OrdersPostgres) GetAll(filter domain.OrdersFilter) (domain.OrdersList, error) {
+	conditions := make([]string, 0)
+	args := make([]interface{}, 0)
+	argId := 1
+
+	if filter.UserId != "" {
+		conditions = append(conditions, fmt.Sprintf("user_id = $%d", argId))
+		args = append(args, filter.UserId)
+		argId++
+	}
+
+	if filter.Status != "" {
+		conditions = append(conditions, fmt.Sprintf("status = $%d", argId))
+		args = append(args, filter.Status)
+		argId++
+	}
+
+	list := domain.OrdersList{
+		Orders: make([]domain.Order, 0),
+	}
+
+	if err := r.db.QueryRow("SELECT count(*) FROM orders"+whereClause(conditions), args...).Scan(&list.Total); err != nil {
+		return list, err
+	}
+
+	query := fmt.Sprintf("%s%s ORDER BY created_at DESC, id DESC LIMIT $%d OFFSET $%d", selectOrderQuery, whereClause(conditions), argId, argId+1)
+	args = append(args, filter.Limit, filter.Offset)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return list, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		order, err := scanOrder(rows)
+		if err != nil {
+			return list, err
+		}
+
+		list.Orders = append(list.Orders, order)
+	}
+
+	return list, rows.Err()
+}
+
+// UpdateStatus moves the order from change.FromStatus to change.ToStatus and
+// records the change. The order must still be in FromStatus, so of two
+// concurrent changes only the first one wins. A cancelled order gives its
+// promo code back.
+func (r *OrdersPostgres) UpdateStatus(change domain.OrderStatusChange) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	if err := updateOrderStatus(tx, change); err != nil {
+		if rb := tx.Rollback(); rb != nil {
+			logrus.Fatalf("query failed: %v, unable to abort: %v", err, rb)
+		}
+
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func updateOrderStatus(tx *sql.Tx, change domain.OrderStatusChange) error {
+	res, err := tx.Exec("UPDATE orders SET status = $1, expires_at = NULL, updated_at = $2 WHERE id = $3 AND status = $4",
+		change.ToStatus, change.CreatedAt, change.OrderId, change.FromStatus)
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return domain.ErrOrderStatusChanged
+	}
+
+	if _, err := tx.Exec(insertOrderHistoryQuery, change.Id, change.OrderId, change.FromStatus, change.ToStatus, change.ActorId, change.Comment, change.CreatedAt); err != nil {
+		return err
+	}
+
+	if change.ToStatus != domain.OrderCancelled {
+		return nil
+	}
+
+	_, err = tx.Exec(releasePromoCodeQuery, change.OrderId)
+
+	return err
+}
+
+// GetExpired returns the ids of pending orders not paid by timestamp.
+func (r *OrdersPostgres) GetExpired(timestamp time.Time) ([]string, error) {
+	rows, err := r.db.Query("SELECT id FROM orders WHERE status = $1 AND expires_at <= $2 ORDER BY expires_at", domain.OrderPending, timestamp)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := make([]string, 0)
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+// getItems reads the items of the order, their prices are in the currency of
+// the order.
+func (r *OrdersPostgres) getItems(orderId, currency string) ([]domain.OrderItem, error) {
+	rows, err := r.db.Query(selectOrderItemsQuery+" WHERE order_id = $1 ORDER BY position", orderId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make([]domain.OrderItem, 0)
+	for rows.Next() {
+		var (
+			item    domain.OrderItem
+			options []byte
+		)
+
+		if err := rows.Scan(&item.Id, &item.OrderId, &item.ProductId, &item.VariantId, &item.Title, &item.Sku, &options,
+			&item.Price.Amount, &item.Sale, &item.SaleOldPrice.Amount, &item.Quantity, &item.ReservationId); err != nil {
+			return nil, err
+		}
+
+		item.Price.Currency = currency
+		item.SaleOldPrice.Currency = currency
+
+		if err := json.Unmarshal(options, &item.Options); err != nil {
+			return nil, err
+		}
+
+		item.LineTotal = item.Price.Mul(item.Quantity)
+		items = append(items, item)
+	}
+
+	return items, rows.Err()
+}
+
+func (r *OrdersPostgres) getHistory(orderId string) ([]domain.OrderStatusChange, error) {
+	rows, err := r.db.Query(selectOrderHistoryQuery+" WHERE order_id = $1 ORDER BY created_at, id", orderId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	history := make([]domain.OrderStatusChange, 0)
+	for rows.Next() {
+		var change domain.OrderStatusChange
+		if err := rows.Scan(&change.Id, &change.OrderId, &change.FromStatus, &change.ToStatus, &change.ActorId, &change.Comment, &change.CreatedAt); err != nil {
+			return nil, err
+		}
+
+		history = append(history, change)
+	}
+
+	return history, rows.Err()
+}
+
+func scanOrder(row rowScanner) (domain.Order, error) {
+	var order domain.Order
+
+	err := row.Scan(&order.Id, &order.Number, &order.UserId, &order.Status, &order.ItemsCount, &order.Subtotal.Amount, &order.Discount.Amount, &order.PromoCode,
+		&order.Total.Amount, &order.Total.Currency, &order.ExpiresAt, &order.CreatedAt, &order.UpdatedAt)
+
+	order.Subtotal.Currency = order.Total.Currency
+	order.Discount.Currency = order.Total.Currency
+
+	return order, err
+}
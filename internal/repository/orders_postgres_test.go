@@ -0,0 +1,280 @@
+package repository
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/AndrewMislyuk/go-shop-backend/internal/domain"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrdersPostgres_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	r := NewOrdersPostgres(db)
+
+	timestamp := time.Now()
+	expiresAt := timestamp.Add(time.Hour)
+	productId := "453b4f0f-1f56-4c57-b43d-7b79792450a7"
+	reservationId := "0b6cbb5e-3d0a-4c3f-8f0e-6a4b7d3e2c11"
+	userId := "e7a9c2d4-5f3b-4a61-8c0e-2b9d7f1a3e55"
+
+	order := domain.Order{
+		Id:     "5c2f1a9e-7d3b-4e8a-9f61-0a2b3c4d5e6f",
+		UserId: userId,
+		Status: domain.OrderPending,
+		Items: []domain.OrderItem{
+			{
+				Id:            "a1b2c3d4-e5f6-4a7b-8c9d-0e1f2a3b4c5d",
+				ProductId:     &productId,
+				Title:         "Твидовый кардиган из хлопка",
+				Options:       map[string]string{"size": "M"},
+				Price:         domain.NewMoney(4990, "RUB"),
+				Quantity:      2,
+				ReservationId: &reservationId,
+			},
+		},
+		ItemsCount: 2,
+		Subtotal:   domain.NewMoney(9980, "RUB"),
+		Discount:   domain.NewMoney(0, "RUB"),
+		Total:      domain.NewMoney(9980, "RUB"),
+		ExpiresAt:  &expiresAt,
+		CreatedAt:  timestamp,
+	}
+	created := domain.OrderStatusChange{
+		Id:        "9d8c7b6a-5f4e-4d3c-8b2a-1f0e9d8c7b6a",
+		OrderId:   order.Id,
+		ToStatus:  domain.OrderPending,
+		ActorId:   &userId,
+		CreatedAt: timestamp,
+	}
+	perUserLimit := 1
+	redemption := domain.PromoCodeRedemption{
+		Id:          "7f3e2d1c-0b9a-4876-a5b4-c3d2e1f0a9b8",
+		PromoCodeId: "1e2d3c4b-5a69-4788-96a5-b4c3d2e1f0a9",
+		UserId:      userId,
+		OrderId:     order.Id,
+		Amount:      998,
+		CreatedAt:   timestamp,
+	}
+
+	testTable := []struct {
+		name       string
+		mock       func()
+		redemption *domain.PromoCodeRedemption
+		want       int64
+		wantErr    bool
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta(insertOrderQueryTemplate)).
+					WithArgs(order.Id, userId, domain.OrderPending, 2, uint(9980), uint(0), "", uint(9980), "RUB", &expiresAt, timestamp).
+					WillReturnRows(sqlmock.NewRows([]string{"number"}).AddRow(1001))
+				mock.ExpectExec(regexp.QuoteMeta(insertOrderItemQuery)).
+					WithArgs(order.Items[0].Id, order.Id, 0, &productId, nil, "Твидовый кардиган из хлопка", "", `{"size":"M"}`,
+						uint(4990), uint(0), uint(0), 2, &reservationId).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta(insertOrderHistoryQuery)).
+					WithArgs(created.Id, order.Id, nil, domain.OrderPending, &userId, "", timestamp).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			want: 1001,
+		},
+
+		{
+			name: "Promo Code",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta(insertOrderQueryTemplate)).
+					WillReturnRows(sqlmock.NewRows([]string{"number"}).AddRow(1002))
+				mock.ExpectExec(regexp.QuoteMeta(insertOrderItemQuery)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta(insertOrderHistoryQuery)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery(regexp.QuoteMeta("UPDATE promo_codes SET used_count = used_count + 1")).
+					WithArgs(redemption.PromoCodeId).
+					WillReturnRows(sqlmock.NewRows([]string{"per_user_limit"}).AddRow(perUserLimit))
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM promo_code_redemptions WHERE promo_code_id = $1 AND user_id = $2")).
+					WithArgs(redemption.PromoCodeId, userId).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+				mock.ExpectExec(regexp.QuoteMeta(insertRedemptionQuery)).
+					WithArgs(redemption.Id, redemption.PromoCodeId, userId, order.Id, uint(998), timestamp).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			redemption: &redemption,
+			want:       1002,
+		},
+
+		{
+			name: "Promo Code Used Up",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta(insertOrderQueryTemplate)).
+					WillReturnRows(sqlmock.NewRows([]string{"number"}).AddRow(1003))
+				mock.ExpectExec(regexp.QuoteMeta(insertOrderItemQuery)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta(insertOrderHistoryQuery)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery(regexp.QuoteMeta("UPDATE promo_codes SET used_count = used_count + 1")).
+					WithArgs(redemption.PromoCodeId).
+					WillReturnRows(sqlmock.NewRows([]string{"per_user_limit"}))
+				mock.ExpectRollback()
+			},
+			redemption: &redemption,
+			wantErr:    true,
+		},
+
+		{
+			name: "Item Failed",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta(insertOrderQueryTemplate)).
+					WillReturnRows(sqlmock.NewRows([]string{"number"}).AddRow(1002))
+				mock.ExpectExec(regexp.QuoteMeta(insertOrderItemQuery)).
+					WillReturnError(errors.New("some error"))
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mock()
+
+			got, err := r.Create(order, created, testCase.redemption)
+			if testCase.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, testCase.want, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestOrdersPostgres_UpdateStatus(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	r := NewOrdersPostgres(db)
+
+	from := domain.OrderPaid
+	actorId := "e7a9c2d4-5f3b-4a61-8c0e-2b9d7f1a3e55"
+	change := domain.OrderStatusChange{
+		Id:         "9d8c7b6a-5f4e-4d3c-8b2a-1f0e9d8c7b6a",
+		OrderId:    "5c2f1a9e-7d3b-4e8a-9f61-0a2b3c4d5e6f",
+		FromStatus: &from,
+		ToStatus:   domain.OrderShipped,
+		ActorId:    &actorId,
+		Comment:    "tracking 1Z999",
+		CreatedAt:  time.Now(),
+	}
+
+	pending := domain.OrderPending
+	cancel := change
+	cancel.FromStatus, cancel.ToStatus, cancel.Comment = &pending, domain.OrderCancelled, ""
+
+	updateQuery := "UPDATE orders SET status = $1, expires_at = NULL, updated_at = $2 WHERE id = $3 AND status = $4"
+
+	testTable := []struct {
+		name    string
+		mock    func()
+		change  domain.OrderStatusChange
+		wantErr error
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta(updateQuery)).
+					WithArgs(domain.OrderShipped, change.CreatedAt, change.OrderId, &from).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta(insertOrderHistoryQuery)).
+					WithArgs(change.Id, change.OrderId, &from, domain.OrderShipped, &actorId, "tracking 1Z999", change.CreatedAt).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			change: change,
+		},
+
+		{
+			name: "Cancelled",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta(updateQuery)).
+					WithArgs(domain.OrderCancelled, cancel.CreatedAt, cancel.OrderId, &pending).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta(insertOrderHistoryQuery)).
+					WithArgs(cancel.Id, cancel.OrderId, &pending, domain.OrderCancelled, &actorId, "", cancel.CreatedAt).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta(releasePromoCodeQuery)).
+					WithArgs(cancel.OrderId).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			change: cancel,
+		},
+
+		{
+			name: "Changed Concurrently",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta(updateQuery)).
+					WithArgs(domain.OrderShipped, change.CreatedAt, change.OrderId, &from).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+			},
+			change:  change,
+			wantErr: domain.ErrOrderStatusChanged,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mock()
+
+			err := r.UpdateStatus(testCase.change)
+			if testCase.wantErr != nil {
+				assert.ErrorIs(t, err, testCase.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestOrdersPostgres_GetById(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	r := NewOrdersPostgres(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(selectOrderQuery + " WHERE id = $1")).
+		WithArgs("5c2f1a9e-7d3b-4e8a-9f61-0a2b3c4d5e6f").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "number", "user_id", "status", "items_count", "subtotal", "discount", "promo_code", "total", "currency", "expires_at", "created_at", "updated_at"}))
+
+	_, err = r.GetById("5c2f1a9e-7d3b-4e8a-9f61-0a2b3c4d5e6f")
+	assert.ErrorIs(t, err, domain.ErrOrderNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/AndrewMislyuk/go-shop-backend/internal/domain"
+	"github.com/lib/pq"
+)
+
+const selectPaymentQuery = "SELECT id, order_id, provider, intent_id, amount, currency, status, failure_code, created_at, updated_at FROM payments"
+
+type PaymentsPostgres struct {
+	db *sql.DB
+}
+
+func NewPaymentsPostgres(db *sql.DB) *PaymentsPostgres {
+	return &PaymentsPostgres{
+		db: db,
+	}
+}
+
+// Create stores the payment, an order that already has a payment that hasn't
+// failed can't get another one.
+func (r *PaymentsPostgres) Create(payment domain.Payment) error {
+	_, err := r.db.Exec(`INSERT INTO payments(id, order_id, provider, intent_id, amount, currency, status, failure_code, created_at)
+		values($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+		payment.Id, payment.OrderId, payment.Provider, payment.IntentId, payment.Amount, payment.Currency, payment.Status, payment.FailureCode, payment.CreatedAt)
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation && pqErr.Constraint == "payments_order_id_active_key" {
+		return domain.ErrOrderNotPayable
+	}
+
+	return err
+}
+
+func (r *PaymentsPostgres) GetByIntent(provider, intentId string) (domain.Payment, error) {
+	return scanPayment(r.db.QueryRow(selectPaymentQuery+" WHERE provider = $1 AND intent_id = $2", provider, intentId))
+}
+
+// GetByOrder returns the payment of the order in status.
+func (r *PaymentsPostgres) GetByOrder(orderId, status string) (domain.Payment, error) {
+	return scanPayment(r.db.QueryRow(selectPaymentQuery+" WHERE order_id = $1 AND status = $2", orderId, status))
+}
+
+// UpdateStatus moves the payment from one status to the other and tells
+// whether it was still in the status from.
+func (r *PaymentsPostgres) UpdateStatus(paymentId, from, to, failureCode string, timestamp time.Time) (bool, error) {
+	res, err := r.db.Exec("UPDATE payments SET status = $1, failure_code = $2, updated_at = $3 WHERE id = $4 AND status = $5",
+		to, failureCode, timestamp, paymentId, from)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := res.RowsAffected()
+
+	return affected != 0, err
+}
+
+func (r *PaymentsPostgres) HasEvent(provider, eventId string) (bool, error) {
+	var exists bool
+	err := r.db.QueryRow("SELECT EXISTS (SELECT 1 FROM payment_events WHERE provider = $1 AND event_id = $2)", provider, eventId).Scan(&exists)
+
+	return exists, err
+}
+
+// RecordEvent remembers the webhook as processed, recording it twice is not
+// an error.
+func (r *PaymentsPostgres) RecordEvent(provider, eventId, eventType string, paymentId *string, timestamp time.Time) error {
+	_, err := r.db.Exec(`INSERT INTO payment_events(provider, event_id, type, payment_id, created_at) values($1, $2, $3, $4, $5)
+		ON CONFLICT (provider, event_id) DO NOTHING`,
+		provider, eventId, eventType, paymentId, timestamp)
+
+	return err
+}
+
+func scanPayment(row rowScanner) (domain.Payment, error) {
+	var payment domain.Payment
+
+	if err := row.Scan(&payment.Id, &payment.OrderId, &payment.Provider, &payment.IntentId, &payment.Amount, &payment.Currency,
+		&payment.Status, &payment.FailureCode, &payment.CreatedAt, &payment.UpdatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return payment, domain.ErrPaymentNotFound
+		}
+
+		return payment, err
+	}
+
+	return payment, nil
+}
@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/AndrewMislyuk/go-shop-backend/internal/domain"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPaymentsPostgres_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	r := NewPaymentsPostgres(db)
+
+	payment := domain.Payment{
+		Id:        "3e4f5a6b-7c8d-4e9f-a0b1-c2d3e4f5a6b7",
+		OrderId:   "5c2f1a9e-7d3b-4e8a-9f61-0a2b3c4d5e6f",
+		Provider:  "fake",
+		IntentId:  "pi_1",
+		Amount:    9980,
+		Currency:  "RUB",
+		Status:    domain.PaymentAuthorized,
+		CreatedAt: time.Now(),
+	}
+
+	testTable := []struct {
+		name    string
+		mock    func()
+		wantErr error
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				mock.ExpectExec("INSERT INTO payments").
+					WithArgs(payment.Id, payment.OrderId, "fake", "pi_1", uint(9980), "RUB", domain.PaymentAuthorized, "", payment.CreatedAt).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+
+		{
+			name: "Order Already Paid",
+			mock: func() {
+				mock.ExpectExec("INSERT INTO payments").
+					WillReturnError(&pq.Error{Code: uniqueViolation, Constraint: "payments_order_id_active_key"})
+			},
+			wantErr: domain.ErrOrderNotPayable,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mock()
+
+			err := r.Create(payment)
+			if testCase.wantErr != nil {
+				assert.ErrorIs(t, err, testCase.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestPaymentsPostgres_UpdateStatus(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	r := NewPaymentsPostgres(db)
+
+	timestamp := time.Now()
+	query := "UPDATE payments SET status = $1, failure_code = $2, updated_at = $3 WHERE id = $4 AND status = $5"
+
+	testTable := []struct {
+		name string
+		mock func()
+		want bool
+	}{
+		{
+			name: "Changed",
+			mock: func() {
+				mock.ExpectExec(regexp.QuoteMeta(query)).
+					WithArgs(domain.PaymentCaptured, "", timestamp, "3e4f5a6b-7c8d-4e9f-a0b1-c2d3e4f5a6b7", domain.PaymentAuthorized).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			want: true,
+		},
+
+		{
+			name: "Already Changed",
+			mock: func() {
+				mock.ExpectExec(regexp.QuoteMeta(query)).
+					WithArgs(domain.PaymentCaptured, "", timestamp, "3e4f5a6b-7c8d-4e9f-a0b1-c2d3e4f5a6b7", domain.PaymentAuthorized).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			want: false,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mock()
+
+			got, err := r.UpdateStatus("3e4f5a6b-7c8d-4e9f-a0b1-c2d3e4f5a6b7", domain.PaymentAuthorized, domain.PaymentCaptured, "", timestamp)
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.want, got)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/AndrewMislyuk/go-shop-backend/internal/domain"
+	"github.com/lib/pq"
+)
+
+type ProductPricesPostgres struct {
+	db *sql.DB
+}
+
+func NewProductPricesPostgres(db *sql.DB) *ProductPricesPostgres {
+	return &ProductPricesPostgres{
+		db: db,
+	}
+}
+
+// Set lists the price of the product in the currency of the price, replacing
+// the one listed before.
+func (r *ProductPricesPostgres) Set(price domain.ProductPrice) error {
+	_, err := r.db.Exec(`INSERT INTO product_prices(product_id, currency, price, updated_at) values($1, $2, $3, $4)
+		ON CONFLICT (product_id, currency) DO UPDATE SET price = EXCLUDED.price, updated_at = EXCLUDED.updated_at`,
+		price.ProductId, price.Price.Currency, price.Price.Amount, price.UpdatedAt)
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == foreignKeyViolation {
+		return domain.ErrProductNotFound
+	}
+
+	return err
+}
+
+func (r *ProductPricesPostgres) Delete(productId, currency string) error {
+	res, err := r.db.Exec("DELETE FROM product_prices WHERE product_id = $1 AND currency = $2", productId, currency)
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return domain.ErrProductPriceNotFound
+	}
+
+	return nil
+}
+
+func (r *ProductPricesPostgres) GetByProduct(productId string) ([]domain.ProductPrice, error) {
+	rows, err := r.db.Query("SELECT product_id, currency, price, updated_at FROM product_prices WHERE product_id = $1 ORDER BY currency", productId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	prices := make([]domain.ProductPrice, 0)
+	for rows.Next() {
+		var price domain.ProductPrice
+		if err := rows.Scan(&price.ProductId, &price.Price.Currency, &price.Price.Amount, &price.UpdatedAt); err != nil {
+			return nil, err
+		}
+
+		prices = append(prices, price)
+	}
+
+	return prices, rows.Err()
+}
+
+// GetByProducts returns the prices listed in currency by product id, products
+// without one are left out.
+func (r *ProductPricesPostgres) GetByProducts(productIds []string, currency string) (map[string]domain.Money, error) {
+	rows, err := r.db.Query("SELECT product_id, price FROM product_prices WHERE product_id = ANY($1) AND currency = $2", pq.Array(productIds), currency)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	prices := make(map[string]domain.Money)
+	for rows.Next() {
+		var (
+			productId string
+			amount    uint
+		)
+
+		if err := rows.Scan(&productId, &amount); err != nil {
+			return nil, err
+		}
+
+		prices[productId] = domain.NewMoney(amount, currency)
+	}
+
+	return prices, rows.Err()
+}
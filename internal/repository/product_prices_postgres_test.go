@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/AndrewMislyuk/go-shop-backend/internal/domain"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProductPricesPostgres_Set(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	r := NewProductPricesPostgres(db)
+
+	price := domain.ProductPrice{
+		ProductId: "453b4f0f-1f56-4c57-b43d-7b79792450a7",
+		Price:     domain.NewMoney(8900, "USD"),
+		UpdatedAt: time.Now(),
+	}
+
+	testTable := []struct {
+		name    string
+		mock    func()
+		wantErr error
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				mock.ExpectExec("INSERT INTO product_prices(.+) ON CONFLICT \\(product_id, currency\\) DO UPDATE").
+					WithArgs(price.ProductId, "USD", uint(8900), price.UpdatedAt).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+
+		{
+			name: "Product Not Found",
+			mock: func() {
+				mock.ExpectExec("INSERT INTO product_prices").
+					WillReturnError(&pq.Error{Code: foreignKeyViolation, Constraint: "product_prices_product_id_fkey"})
+			},
+			wantErr: domain.ErrProductNotFound,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mock()
+
+			err := r.Set(price)
+			if testCase.wantErr != nil {
+				assert.ErrorIs(t, err, testCase.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestProductPricesPostgres_GetByProducts(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	r := NewProductPricesPostgres(db)
+
+	ids := []string{"453b4f0f-1f56-4c57-b43d-7b79792450a7", "b07221f8-4133-4688-b2d6-d677f41f5b74"}
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT product_id, price FROM product_prices WHERE product_id = ANY($1) AND currency = $2")).
+		WithArgs(pq.Array(ids), "USD").
+		WillReturnRows(sqlmock.NewRows([]string{"product_id", "price"}).AddRow("453b4f0f-1f56-4c57-b43d-7b79792450a7", 8900))
+
+	got, err := r.GetByProducts(ids, "USD")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]domain.Money{
+		"453b4f0f-1f56-4c57-b43d-7b79792450a7": domain.NewMoney(8900, "USD"),
+	}, got)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProductPricesPostgres_Delete(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	r := NewProductPricesPostgres(db)
+
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM product_prices WHERE product_id = $1 AND currency = $2")).
+		WithArgs("453b4f0f-1f56-4c57-b43d-7b79792450a7", "USD").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = r.Delete("453b4f0f-1f56-4c57-b43d-7b79792450a7", "USD")
+	assert.ErrorIs(t, err, domain.ErrProductPriceNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
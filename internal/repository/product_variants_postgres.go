@@ -0,0 +1,167 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/AndrewMislyuk/go-shop-backend/internal/domain"
+	"github.com/lib/pq"
+)
+
+// selectVariantQuery reports the price of the variant after the sales of its
+// product and the available stock, variants without a stock level have none.
+const selectVariantQuery = `SELECT v.id, v.product_id, v.sku, v.options, v.price, discounted_price(COALESCE(v.price, p.price), p.sale_percent, p.sale_fixed), p.currency,
+	COALESCE(l.on_hand - l.reserved, 0), v.created_at, v.updated_at
+	FROM product_variants v JOIN products p ON p.id = v.product_id
+	LEFT JOIN stock_levels l ON l.variant_id = v.id`
+
+type ProductVariantsPostgres struct {
+	db *sql.DB
+}
+
+func NewProductVariantsPostgres(db *sql.DB) *ProductVariantsPostgres {
+	return &ProductVariantsPostgres{
+		db: db,
+	}
+}
+
+func (r *ProductVariantsPostgres) Create(variant domain.ProductVariant) error {
+	options, err := json.Marshal(variant.Options)
+	if err != nil {
+		return err
+	}
+
+	var price *uint
+	if variant.Price != nil {
+		price = &variant.Price.Amount
+	}
+
+	_, err = r.db.Exec("INSERT INTO product_variants(id, product_id, sku, options, price, created_at) values($1, $2, $3, $4, $5, $6)",
+		variant.Id, variant.ProductId, variant.Sku, string(options), price, variant.CreatedAt)
+
+	return mapVariantError(err)
+}
+
+func (r *ProductVariantsPostgres) GetByProduct(productId string) ([]domain.ProductVariant, error) {
+	rows, err := r.db.Query(selectVariantQuery+" WHERE v.product_id = $1 ORDER BY v.created_at, v.sku", productId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	variants := make([]domain.ProductVariant, 0)
+	for rows.Next() {
+		variant, err := scanVariant(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		variants = append(variants, variant)
+	}
+
+	return variants, rows.Err()
+}
+
+func (r *ProductVariantsPostgres) Update(productId, variantId string, input domain.UpdateVariantInput, timestamp time.Time) error {
+	setValues := make([]string, 0)
+	args := make([]interface{}, 0)
+	argId := 1
+
+	if input.Sku != nil {
+		setValues = append(setValues, fmt.Sprintf("sku=$%d", argId))
+		args = append(args, *input.Sku)
+		argId++
+	}
+
+	if input.Options != nil {
+		options, err := json.Marshal(input.Options)
+		if err != nil {
+			return err
+		}
+
+		setValues = append(setValues, fmt.Sprintf("options=$%d", argId))
+		args = append(args, string(options))
+		argId++
+	}
+
+	if input.UseProductPrice {
+		setValues = append(setValues, "price=NULL")
+	} else if input.Price != nil {
+		setValues = append(setValues, fmt.Sprintf("price=$%d", argId))
+		args = append(args, input.Price.Amount)
+		argId++
+	}
+
+	setValues = append(setValues, fmt.Sprintf("updated_at=$%d", argId))
+	args = append(args, timestamp)
+	argId++
+
+	query := fmt.Sprintf("UPDATE product_variants SET %s WHERE id = $%d AND product_id = $%d", strings.Join(setValues, ", "), argId, argId+1)
+	args = append(args, variantId, productId)
+
+	return mapVariantError(r.execAffectingVariant(query, args...))
+}
+
+func (r *ProductVariantsPostgres) Delete(productId, variantId string) error {
+	return r.execAffectingVariant("DELETE FROM product_variants WHERE id = $1 AND product_id = $2", variantId, productId)
+}
+
+func (r *ProductVariantsPostgres) execAffectingVariant(query string, args ...interface{}) error {
+	res, err := r.db.Exec(query, args...)
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return domain.ErrVariantNotFound
+	}
+
+	return nil
+}
+
+func scanVariant(row rowScanner) (domain.ProductVariant, error) {
+	var (
+		variant domain.ProductVariant
+		options []byte
+		price   *uint
+	)
+
+	if err := row.Scan(&variant.Id, &variant.ProductId, &variant.Sku, &options, &price, &variant.SalePrice.Amount, &variant.SalePrice.Currency,
+		&variant.Stock, &variant.CreatedAt, &variant.UpdatedAt); err != nil {
+		return variant, err
+	}
+
+	if price != nil {
+		money := domain.NewMoney(*price, variant.SalePrice.Currency)
+		variant.Price = &money
+	}
+
+	return variant, json.Unmarshal(options, &variant.Options)
+}
+
+func mapVariantError(err error) error {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return err
+	}
+
+	switch {
+	case pqErr.Code == foreignKeyViolation:
+		return domain.ErrProductNotFound
+	case pqErr.Code == uniqueViolation && pqErr.Constraint == "product_variants_sku_key":
+		return domain.ErrSkuTaken
+	case pqErr.Code == uniqueViolation:
+		return domain.ErrVariantOptionsTaken
+	default:
+		return err
+	}
+}
@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/AndrewMislyuk/go-shop-backend/internal/domain"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProductVariantsPostgres_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	r := NewProductVariantsPostgres(db)
+
+	variant := domain.ProductVariant{
+		Id:        "c1a5f0de-7c1b-4f55-9f0e-3c2a1b0d9e01",
+		ProductId: "72c07fab-dd14-47fa-b478-d59255dcf8dd",
+		Sku:       "JEANS-32-BLUE",
+		Options:   map[string]string{"size": "32"},
+		CreatedAt: time.Now(),
+	}
+
+	testTable := []struct {
+		name    string
+		mock    func()
+		wantErr error
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO product_variants(id, product_id, sku, options, price, created_at) values($1, $2, $3, $4, $5, $6)")).
+					WithArgs(variant.Id, variant.ProductId, variant.Sku, `{"size":"32"}`, nil, variant.CreatedAt).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+
+		{
+			name: "SKU Taken",
+			mock: func() {
+				mock.ExpectExec("INSERT INTO product_variants").
+					WillReturnError(&pq.Error{Code: uniqueViolation, Constraint: "product_variants_sku_key"})
+			},
+			wantErr: domain.ErrSkuTaken,
+		},
+
+		{
+			name: "Options Taken",
+			mock: func() {
+				mock.ExpectExec("INSERT INTO product_variants").
+					WillReturnError(&pq.Error{Code: uniqueViolation, Constraint: "product_variants_product_id_options_key"})
+			},
+			wantErr: domain.ErrVariantOptionsTaken,
+		},
+
+		{
+			name: "Product Not Found",
+			mock: func() {
+				mock.ExpectExec("INSERT INTO product_variants").
+					WillReturnError(&pq.Error{Code: foreignKeyViolation, Constraint: "product_variants_product_id_fkey"})
+			},
+			wantErr: domain.ErrProductNotFound,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mock()
+
+			err := r.Create(variant)
+			if testCase.wantErr != nil {
+				assert.ErrorIs(t, err, testCase.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestProductVariantsPostgres_GetByProduct(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	r := NewProductVariantsPostgres(db)
+
+	createdAt := time.Date(2022, 01, 12, 13, 17, 58, 0, time.Local)
+
+	rows := sqlmock.NewRows([]string{"id", "product_id", "sku", "options", "price", "sale_price", "currency", "stock", "created_at", "updated_at"}).
+		AddRow("c1a5f0de-7c1b-4f55-9f0e-3c2a1b0d9e01", "72c07fab-dd14-47fa-b478-d59255dcf8dd", "JEANS-30", []byte(`{"size": "30"}`), nil, 159000, "RUB", 4, createdAt, nil).
+		AddRow("c1a5f0de-7c1b-4f55-9f0e-3c2a1b0d9e02", "72c07fab-dd14-47fa-b478-d59255dcf8dd", "JEANS-34", []byte(`{"size": "34"}`), 189000, 189000, "RUB", 0, createdAt, nil)
+
+	mock.ExpectQuery(regexp.QuoteMeta(selectVariantQuery + " WHERE v.product_id = $1 ORDER BY v.created_at, v.sku")).
+		WithArgs("72c07fab-dd14-47fa-b478-d59255dcf8dd").
+		WillReturnRows(rows)
+
+	got, err := r.GetByProduct("72c07fab-dd14-47fa-b478-d59255dcf8dd")
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.ProductVariant{
+		{Id: "c1a5f0de-7c1b-4f55-9f0e-3c2a1b0d9e01", ProductId: "72c07fab-dd14-47fa-b478-d59255dcf8dd", Sku: "JEANS-30", Options: map[string]string{"size": "30"}, SalePrice: domain.NewMoney(159000, "RUB"), Stock: 4, CreatedAt: createdAt},
+		{Id: "c1a5f0de-7c1b-4f55-9f0e-3c2a1b0d9e02", ProductId: "72c07fab-dd14-47fa-b478-d59255dcf8dd", Sku: "JEANS-34", Options: map[string]string{"size": "34"}, Price: &domain.Money{Amount: 189000, Currency: "RUB"}, SalePrice: domain.NewMoney(189000, "RUB"), CreatedAt: createdAt},
+	}, got)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProductVariantsPostgres_Update(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	r := NewProductVariantsPostgres(db)
+
+	updatedAt := time.Now()
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE product_variants SET sku=$1, price=NULL, updated_at=$2 WHERE id = $3 AND product_id = $4")).
+		WithArgs("JEANS-32", updatedAt, "c1a5f0de-7c1b-4f55-9f0e-3c2a1b0d9e01", "72c07fab-dd14-47fa-b478-d59255dcf8dd").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	sku := "JEANS-32"
+	err = r.Update("72c07fab-dd14-47fa-b478-d59255dcf8dd", "c1a5f0de-7c1b-4f55-9f0e-3c2a1b0d9e01", domain.UpdateVariantInput{
+		Sku:             &sku,
+		Price:           &domain.PriceInput{Amount: 1},
+		UseProductPrice: true,
+	}, updatedAt)
+	assert.ErrorIs(t, err, domain.ErrVariantNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -2,11 +2,13 @@ package repository
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
 
 	"github.com/AndrewMislyuk/go-shop-backend/internal/domain"
+	"github.com/lib/pq"
 	"github.com/sirupsen/logrus"
 )
 
@@ -20,6 +22,43 @@ func NewProductsListPostgres(db *sql.DB) *ProductsListPostgres {
 	}
 }
 
+// productsFrom joins the product's subtype (s) with its type (t) and
+// category (c).
+const productsFrom = " FROM products p JOIN categories s ON s.id = p.category_id JOIN categories t ON t.id = s.parent_id JOIN categories c ON c.id = t.parent_id"
+
+// productPrice is the price of the product after its sales.
+const productPrice = "p.sale_price"
+
+// productAggregatesJoin aggregates the variant prices (pv) and the stock
+// levels (sl) of the product.
+const productAggregatesJoin = ` CROSS JOIN LATERAL (SELECT min(discounted_price(COALESCE(v.price, p.price), p.sale_percent, p.sale_fixed)) AS min_price,
+	max(discounted_price(COALESCE(v.price, p.price), p.sale_percent, p.sale_fixed)) AS max_price
+	FROM product_variants v WHERE v.product_id = p.id) pv
+	CROSS JOIN LATERAL (SELECT bool_or(l.on_hand > l.reserved) AS in_stock FROM stock_levels l WHERE l.product_id = p.id) sl`
+
+// productColumns read the sale percent and the price before the sale only
+// for products whose price has been lowered.
+const productColumns = "p.id, p.title, COALESCE(p.image, ''), " + productPrice + ", p.currency, " +
+	"CASE WHEN " + productPrice + " < p.price THEN round((p.price - " + productPrice + ") * 100.0 / p.price)::bigint ELSE 0 END, " +
+	"CASE WHEN " + productPrice + " < p.price THEN p.price ELSE 0 END, " +
+	"p.category_id, c.slug, t.slug, s.slug, COALESCE(p.description, ''), p.created_at, COALESCE(pv.min_price, " + productPrice + "), COALESCE(pv.max_price, " + productPrice + "), COALESCE(sl.in_stock, false)"
+
+const selectProductQuery = "SELECT " + productColumns + productsFrom + productAggregatesJoin
+
+const (
+	// searchQuery is the user query ($1) parsed with both configurations of
+	// the search_vector column.
+	searchQuery     = "(websearch_to_tsquery('russian', $1) || websearch_to_tsquery('english', $1))"
+	headlineOptions = "StartSel=<b>, StopSel=</b>"
+)
+
+// productSortColumns are covered by the indexes on (column, id).
+var productSortColumns = map[string]string{
+	domain.ProductsSortPrice:     productPrice,
+	domain.ProductsSortCreatedAt: "p.created_at",
+	domain.ProductsSortTitle:     "p.title",
+}
+
 func (r *ProductsListPostgres) Create(list domain.CreateProductInput, productId string, timestamp time.Time) (string, error) {
 	tx, err := r.db.Begin()
 	if err != nil {
@@ -27,7 +66,7 @@ func (r *ProductsListPostgres) Create(list domain.CreateProductInput, productId
 	}
 
 	var returnedId string
-	row, err := tx.Prepare("INSERT INTO products(id, title, price, sale, sale_old_price, category, type, subtype, description, created_at) values($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) RETURNING id")
+	row, err := tx.Prepare("INSERT INTO products(id, title, price, currency, category_id, description, created_at) values($1, $2, $3, $4, $5, $6, $7) RETURNING id")
 	if err != nil {
 		if rb := tx.Rollback(); rb != nil {
 			logrus.Fatalf("query failed: %v, unable to abort: %v", err, rb)
@@ -38,53 +77,148 @@ func (r *ProductsListPostgres) Create(list domain.CreateProductInput, productId
 
 	defer row.Close()
 
-	if err = row.QueryRow(productId, list.Title, list.Price, list.Sale, list.SaleOldPrice, list.Category, list.Type, list.Subtype, list.Description, timestamp).Scan(&returnedId); err != nil {
+	if err = row.QueryRow(productId, list.Title, list.Price.Amount, list.Price.Currency, list.CategoryId, list.Description, timestamp).Scan(&returnedId); err != nil {
 		if rb := tx.Rollback(); rb != nil {
 			logrus.Fatalf("query failed: %v, unable to abort: %v", err, rb)
 		}
 
-		return "", err
+		return "", mapCategoryNotFound(err)
 	}
 
 	return returnedId, tx.Commit()
 }
 
-func (r *ProductsListPostgres) GetAll() ([]domain.ProductsList, error) {
-	rows, err := r.db.Query("SELECT * FROM products")
+// GetAll returns a page of products matching the filter. The page starts
+// after the cursor when one is given, otherwise at filter.Offset. Total counts
+// all matching products regardless of pagination.
+func (r *ProductsListPostgres) GetAll(filter domain.ProductsFilter, after *domain.ProductsCursor) (domain.ProductsPage, error) {
+	page := domain.ProductsPage{
+		Products: make([]domain.ProductsList, 0),
+	}
+
+	field, desc, err := filter.SortField()
+	if err != nil {
+		return page, err
+	}
+
+	column := productSortColumns[field]
+
+	conditions, args := productConditions(filter, make([]interface{}, 0))
+	argId := len(args) + 1
+
+	if err := r.db.QueryRow("SELECT count(*)"+productsFrom+whereClause(conditions), args...).Scan(&page.Total); err != nil {
+		return page, err
+	}
+
+	direction, comparison := "ASC", ">"
+	if desc {
+		direction, comparison = "DESC", "<"
+	}
+
+	if after != nil {
+		conditions = append(conditions, fmt.Sprintf("(%s, p.id) %s ($%d, $%d)", column, comparison, argId, argId+1))
+		args = append(args, cursorValue(field, *after), after.Id)
+		argId += 2
+	}
+
+	// One extra row tells whether there is a next page.
+	query := fmt.Sprintf("%s%s ORDER BY %s %s, p.id %s LIMIT $%d OFFSET $%d",
+		selectProductQuery, whereClause(conditions), column, direction, direction, argId, argId+1)
+	args = append(args, filter.Limit+1, filter.Offset)
+
+	rows, err := r.db.Query(query, args...)
 	if err != nil {
-		return nil, err
+		return page, err
 	}
 	defer rows.Close()
 
-	products := make([]domain.ProductsList, 0)
 	for rows.Next() {
-		var product domain.ProductsList
-		if err := rows.Scan(&product.Id, &product.Title, &product.Image, &product.Price, &product.Sale, &product.SaleOldPrice, &product.Category, &product.Type, &product.Subtype, &product.Description, &product.CreatedAt); err != nil {
-			return nil, err
+		product, err := scanProduct(rows)
+		if err != nil {
+			return page, err
 		}
 
-		products = append(products, product)
+		page.Products = append(page.Products, product)
+	}
+
+	if err := rows.Err(); err != nil {
+		return page, err
 	}
 
-	return products, rows.Err()
+	if len(page.Products) > filter.Limit {
+		page.Products = page.Products[:filter.Limit]
+		page.NextCursor = domain.NewProductsCursor(filter.Sort, page.Products[filter.Limit-1]).Encode()
+	}
+
+	return page, nil
 }
 
-func (r *ProductsListPostgres) GetById(listId string) (domain.ProductsList, error) {
-	var product domain.ProductsList
+// Search matches the query against the title and description in Russian and
+// English. Products whose title only resembles the query (typos) are found
+// through trigram similarity and ranked after the full-text matches.
+func (r *ProductsListPostgres) Search(input domain.ProductsSearchInput) (domain.ProductsSearchPage, error) {
+	page := domain.ProductsSearchPage{
+		Results: make([]domain.ProductSearchResult, 0),
+	}
+
+	conditions, args := productConditions(input.ProductsFilter, []interface{}{input.Query})
+	conditions = append(conditions, "(p.search_vector @@ "+searchQuery+" OR $1 <% p.title)")
+
+	if err := r.db.QueryRow("SELECT count(*)"+productsFrom+whereClause(conditions), args...).Scan(&page.Total); err != nil {
+		return page, err
+	}
+
+	order := "p.search_vector @@ " + searchQuery + " DESC, rank DESC, word_similarity($1, p.title) DESC, p.id"
+	if input.Sort != "" {
+		field, desc, err := input.SortField()
+		if err != nil {
+			return page, err
+		}
+
+		direction := "ASC"
+		if desc {
+			direction = "DESC"
+		}
+
+		order = fmt.Sprintf("%s %s, p.id %s", productSortColumns[field], direction, direction)
+	}
+
+	argId := len(args) + 1
+	query := fmt.Sprintf(`SELECT %[1]s,
+		ts_rank(p.search_vector, %[2]s) AS rank,
+		ts_headline('russian', p.title, %[2]s, '%[3]s'),
+		ts_headline('russian', COALESCE(p.description, ''), %[2]s, '%[3]s, MaxFragments=2')
+		%[4]s%[5]s ORDER BY %[6]s LIMIT $%[7]d OFFSET $%[8]d`,
+		productColumns, searchQuery, headlineOptions, productsFrom+productAggregatesJoin, whereClause(conditions), order, argId, argId+1)
+	args = append(args, input.Limit, input.Offset)
 
-	rows, err := r.db.Query("SELECT * FROM products WHERE id = $1", listId)
+	rows, err := r.db.Query(query, args...)
 	if err != nil {
-		return product, err
+		return page, err
 	}
 	defer rows.Close()
 
 	for rows.Next() {
-		if err := rows.Scan(&product.Id, &product.Title, &product.Image, &product.Price, &product.Sale, &product.SaleOldPrice, &product.Category, &product.Type, &product.Subtype, &product.Description, &product.CreatedAt); err != nil {
-			return product, err
+		var result domain.ProductSearchResult
+		if err := rows.Scan(append(productFields(&result.ProductsList), &result.Rank, &result.Highlight, &result.Snippet)...); err != nil {
+			return page, err
 		}
+
+		setProductCurrency(&result.ProductsList)
+
+		page.Results = append(page.Results, result)
 	}
 
-	return product, rows.Err()
+	return page, rows.Err()
+}
+
+func (r *ProductsListPostgres) GetById(listId string) (domain.ProductsList, error) {
+	product, err := scanProduct(r.db.QueryRow(selectProductQuery+" WHERE p.id = $1", listId))
+	if errors.Is(err, sql.ErrNoRows) {
+		return product, domain.ErrProductNotFound
+	}
+
+	return product, err
 }
 
 func (r *ProductsListPostgres) Update(itemId string, input domain.UpdateProductInput) error {
@@ -99,60 +233,149 @@ func (r *ProductsListPostgres) Update(itemId string, input domain.UpdateProductI
 	}
 
 	if input.Price != nil {
-		setValues = append(setValues, fmt.Sprintf("price=$%d", argId))
-		args = append(args, *input.Price)
+		setValues = append(setValues, fmt.Sprintf("price=$%d, currency=$%d", argId, argId+1))
+		args = append(args, input.Price.Amount, input.Price.Currency)
+		argId += 2
+	}
+
+	if input.CategoryId != nil {
+		setValues = append(setValues, fmt.Sprintf("category_id=$%d", argId))
+		args = append(args, *input.CategoryId)
 		argId++
 	}
 
-	if input.Sale != nil {
-		setValues = append(setValues, fmt.Sprintf("sale=$%d", argId))
-		args = append(args, *input.Sale)
+	if input.Description != nil {
+		setValues = append(setValues, fmt.Sprintf("description=$%d", argId))
+		args = append(args, *input.Description)
 		argId++
 	}
 
-	if input.SaleOldPrice != nil {
-		setValues = append(setValues, fmt.Sprintf("sale_old_price=$%d", argId))
-		args = append(args, *input.SaleOldPrice)
+	setQuery := strings.Join(setValues, ", ")
+
+	query := fmt.Sprintf("UPDATE products SET %s WHERE id = $%d", setQuery, argId)
+
+	args = append(args, itemId)
+
+	return mapCategoryNotFound(execAffectingProduct(r.db, query, args...))
+}
+
+func (r *ProductsListPostgres) Delete(itemId string) error {
+	return execAffectingProduct(r.db, "DELETE FROM products WHERE id = $1", itemId)
+}
+
+func execAffectingProduct(db *sql.DB, query string, args ...interface{}) error {
+	res, err := db.Exec(query, args...)
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return domain.ErrProductNotFound
+	}
+
+	return nil
+}
+
+// productConditions builds the WHERE conditions of the listing filters,
+// placeholders are numbered after the given args.
+func productConditions(filter domain.ProductsFilter, args []interface{}) ([]string, []interface{}) {
+	conditions := make([]string, 0)
+	argId := len(args) + 1
+
+	if filter.Category != "" {
+		conditions = append(conditions, fmt.Sprintf("c.slug = $%d", argId))
+		args = append(args, filter.Category)
 		argId++
 	}
 
-	if input.Category != nil {
-		setValues = append(setValues, fmt.Sprintf("category=$%d", argId))
-		args = append(args, *input.Category)
+	if filter.Type != "" {
+		conditions = append(conditions, fmt.Sprintf("t.slug = $%d", argId))
+		args = append(args, filter.Type)
 		argId++
 	}
 
-	if input.Type != nil {
-		setValues = append(setValues, fmt.Sprintf("type=$%d", argId))
-		args = append(args, *input.Type)
+	if filter.Subtype != "" {
+		conditions = append(conditions, fmt.Sprintf("s.slug = $%d", argId))
+		args = append(args, filter.Subtype)
 		argId++
 	}
 
-	if input.Subtype != nil {
-		setValues = append(setValues, fmt.Sprintf("subtype=$%d", argId))
-		args = append(args, *input.Subtype)
+	if filter.MinPrice != nil {
+		conditions = append(conditions, fmt.Sprintf("%s >= $%d", productPrice, argId))
+		args = append(args, *filter.MinPrice)
 		argId++
 	}
 
-	if input.Description != nil {
-		setValues = append(setValues, fmt.Sprintf("description=$%d", argId))
-		args = append(args, *input.Description)
+	if filter.MaxPrice != nil {
+		conditions = append(conditions, fmt.Sprintf("%s <= $%d", productPrice, argId))
+		args = append(args, *filter.MaxPrice)
 		argId++
 	}
 
-	setQuery := strings.Join(setValues, ", ")
+	if filter.OnSale != nil {
+		if *filter.OnSale {
+			conditions = append(conditions, productPrice+" < p.price")
+		} else {
+			conditions = append(conditions, productPrice+" = p.price")
+		}
+	}
 
-	query := fmt.Sprintf("UPDATE products SET %s WHERE id = $%d", setQuery, argId)
+	return conditions, args
+}
 
-	args = append(args, itemId)
+func scanProduct(row rowScanner) (domain.ProductsList, error) {
+	var product domain.ProductsList
 
-	_, err := r.db.Exec(query, args...)
+	err := row.Scan(productFields(&product)...)
+	setProductCurrency(&product)
 
-	return err
+	return product, err
 }
 
-func (r *ProductsListPostgres) Delete(itemId string) error {
-	_, err := r.db.Exec("DELETE FROM products WHERE id = $1", itemId)
+// productFields are the scan destinations of productColumns, the currency is
+// read into Price only.
+func productFields(product *domain.ProductsList) []interface{} {
+	return []interface{}{&product.Id, &product.Title, &product.Image, &product.Price.Amount, &product.Price.Currency, &product.Sale, &product.SaleOldPrice.Amount, &product.CategoryId, &product.Category, &product.Type, &product.Subtype, &product.Description, &product.CreatedAt,
+		&product.MinPrice.Amount, &product.MaxPrice.Amount, &product.InStock}
+}
+
+// setProductCurrency copies the currency of the price to the other prices of
+// the product.
+func setProductCurrency(product *domain.ProductsList) {
+	product.SaleOldPrice.Currency = product.Price.Currency
+	product.MinPrice.Currency = product.Price.Currency
+	product.MaxPrice.Currency = product.Price.Currency
+}
+
+func cursorValue(field string, cursor domain.ProductsCursor) interface{} {
+	switch field {
+	case domain.ProductsSortPrice:
+		return cursor.Price
+	case domain.ProductsSortTitle:
+		return cursor.Title
+	default:
+		return cursor.CreatedAt
+	}
+}
+
+func whereClause(conditions []string) string {
+	if len(conditions) == 0 {
+		return ""
+	}
+
+	return " WHERE " + strings.Join(conditions, " AND ")
+}
+
+func mapCategoryNotFound(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == foreignKeyViolation {
+		return domain.ErrCategoryNotFound
+	}
 
 	return err
 }
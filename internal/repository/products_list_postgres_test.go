@@ -40,14 +40,10 @@ func TestProductsListPostgres_Create(t *testing.T) {
 			args: args{
 				productId: "34c8d3e6-b8d7-43dc-847e-5764c4114856",
 				item: domain.CreateProductInput{
-					Title:        "Твидовый кардиган из хлопка",
-					Price:        749000,
-					Sale:         0,
-					SaleOldPrice: 0,
-					Category:     "Женщинам",
-					Type:         "Одежда",
-					Subtype:      "Старые-коллекции",
-					Description:  "",
+					Title:       "Твидовый кардиган из хлопка",
+					Price:       domain.PriceInput{Amount: 749000, Currency: "RUB"},
+					CategoryId:  "6b1e7f7e-2f4b-4c1e-9d65-0d3f3c9b8a11",
+					Description: "",
 				},
 				createdAt: time.Now(),
 			},
@@ -57,7 +53,7 @@ func TestProductsListPostgres_Create(t *testing.T) {
 				rows := sqlmock.NewRows([]string{"id"}).AddRow(args.productId)
 				prep := mock.ExpectPrepare("INSERT INTO products")
 				prep.ExpectQuery().
-					WithArgs(args.productId, args.item.Title, args.item.Price, args.item.Sale, args.item.SaleOldPrice, args.item.Category, args.item.Type, args.item.Subtype, args.item.Description, args.createdAt).
+					WithArgs(args.productId, args.item.Title, args.item.Price.Amount, args.item.Price.Currency, args.item.CategoryId, args.item.Description, args.createdAt).
 					WillReturnRows(rows)
 
 				mock.ExpectCommit()
@@ -69,14 +65,10 @@ func TestProductsListPostgres_Create(t *testing.T) {
 			args: args{
 				productId: "",
 				item: domain.CreateProductInput{
-					Title:        "",
-					Price:        0,
-					Sale:         0,
-					SaleOldPrice: 0,
-					Category:     "",
-					Type:         "",
-					Subtype:      "",
-					Description:  "",
+					Title:       "",
+					Price:       domain.PriceInput{},
+					CategoryId:  "",
+					Description: "",
 				},
 				createdAt: time.Now(),
 			},
@@ -86,7 +78,7 @@ func TestProductsListPostgres_Create(t *testing.T) {
 				rows := sqlmock.NewRows([]string{"id"}).AddRow(args.productId).RowError(0, errors.New("insert error"))
 				prep := mock.ExpectPrepare("INSERT INTO products")
 				prep.ExpectQuery().
-					WithArgs(args.productId, args.item.Title, args.item.Price, args.item.Sale, args.item.SaleOldPrice, args.item.Category, args.item.Type, args.item.Subtype, args.item.Description, args.createdAt).
+					WithArgs(args.productId, args.item.Title, args.item.Price.Amount, args.item.Price.Currency, args.item.CategoryId, args.item.Description, args.createdAt).
 					WillReturnRows(rows)
 
 				mock.ExpectRollback()
@@ -120,36 +112,101 @@ func TestProductsListPostgres_GetAll(t *testing.T) {
 
 	r := NewProductsListPostgres(db)
 
+	columns := []string{"id", "title", "image", "price", "currency", "sale", "sale_old_price", "category_id", "category", "type", "subtype", "description", "created_at", "min_price", "max_price", "in_stock"}
+	cursor := domain.ProductsCursor{Sort: "price", Id: "b07221f8-4133-4688-b2d6-d677f41f5b74", Price: 499000}
+
 	testTable := []struct {
 		name    string
 		mock    func()
-		want    []domain.ProductsList
+		filter  domain.ProductsFilter
+		after   *domain.ProductsCursor
+		want    domain.ProductsPage
 		wantErr bool
 	}{
 		{
 			name: "OK",
 			mock: func() {
-				rows := sqlmock.NewRows([]string{"id", "title", "image", "price", "sale", "sale_old_price", "category", "type", "subtype", "description", "created_at"}).
-					AddRow("453b4f0f-1f56-4c57-b43d-7b79792450a7", "Твидовый кардиган из хлопка", "w1.webp", 749000, 0, 0, "Женщинам", "Одежда", "Старые-коллекции", "", time.Date(2022, 01, 12, 13, 8, 21, 32963, time.Local)).
-					AddRow("b07221f8-4133-4688-b2d6-d677f41f5b74", "Объемный водоотталкивающий тренч", "w2.webp", 499000, 50, 999000, "Женщинам", "Одежда", "Старые-коллекции", "", time.Date(2022, 01, 12, 13, 10, 18, 882593, time.Local)).
-					AddRow("96a7193a-403d-4e01-94e6-c02c5bcb61f1", "Хлопковая рубашка в полоску", "w4.webp", 359000, 0, 0, "Женщинам", "Одежда", "Вышевка", "", time.Date(2022, 01, 12, 13, 16, 55, 558842, time.Local))
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT count(*)" + productsFrom)).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+
+				rows := sqlmock.NewRows(columns).
+					AddRow("453b4f0f-1f56-4c57-b43d-7b79792450a7", "Твидовый кардиган из хлопка", "w1.webp", 749000, "RUB", 0, 0, "6b1e7f7e-2f4b-4c1e-9d65-0d3f3c9b8a11", "zhenshchinam", "odezhda", "starye-kollekcii", "", time.Date(2022, 01, 12, 13, 8, 21, 32963, time.Local), 749000, 749000, true).
+					AddRow("b07221f8-4133-4688-b2d6-d677f41f5b74", "Объемный водоотталкивающий тренч", "w2.webp", 499000, "RUB", 50, 999000, "6b1e7f7e-2f4b-4c1e-9d65-0d3f3c9b8a11", "zhenshchinam", "odezhda", "starye-kollekcii", "", time.Date(2022, 01, 12, 13, 10, 18, 882593, time.Local), 499000, 499000, true).
+					AddRow("96a7193a-403d-4e01-94e6-c02c5bcb61f1", "Хлопковая рубашка в полоску", "w4.webp", 359000, "RUB", 0, 0, "6b1e7f7e-2f4b-4c1e-9d65-0d3f3c9b8a11", "zhenshchinam", "odezhda", "vyshevka", "", time.Date(2022, 01, 12, 13, 16, 55, 558842, time.Local), 359000, 359000, true)
+
+				mock.ExpectQuery(regexp.QuoteMeta(selectProductQuery+" ORDER BY "+productPrice+" DESC, p.id DESC LIMIT $1 OFFSET $2")).
+					WithArgs(3, 0).
+					WillReturnRows(rows)
+			},
+			filter: domain.ProductsFilter{Sort: "-price", Limit: 2},
+			want: domain.ProductsPage{
+				Products: []domain.ProductsList{
+					{Id: "453b4f0f-1f56-4c57-b43d-7b79792450a7", Title: "Твидовый кардиган из хлопка", Image: "w1.webp", Price: domain.NewMoney(749000, "RUB"), Sale: 0, SaleOldPrice: domain.NewMoney(0, "RUB"), CategoryId: "6b1e7f7e-2f4b-4c1e-9d65-0d3f3c9b8a11", Category: "zhenshchinam", Type: "odezhda", Subtype: "starye-kollekcii", Description: "", CreatedAt: time.Date(2022, 01, 12, 13, 8, 21, 32963, time.Local), MinPrice: domain.NewMoney(749000, "RUB"), MaxPrice: domain.NewMoney(749000, "RUB"), InStock: true},
+					{Id: "b07221f8-4133-4688-b2d6-d677f41f5b74", Title: "Объемный водоотталкивающий тренч", Image: "w2.webp", Price: domain.NewMoney(499000, "RUB"), Sale: 50, SaleOldPrice: domain.NewMoney(999000, "RUB"), CategoryId: "6b1e7f7e-2f4b-4c1e-9d65-0d3f3c9b8a11", Category: "zhenshchinam", Type: "odezhda", Subtype: "starye-kollekcii", Description: "", CreatedAt: time.Date(2022, 01, 12, 13, 10, 18, 882593, time.Local), MinPrice: domain.NewMoney(499000, "RUB"), MaxPrice: domain.NewMoney(499000, "RUB"), InStock: true},
+				},
+				Total: 3,
+				NextCursor: domain.NewProductsCursor("-price", domain.ProductsList{
+					Id: "b07221f8-4133-4688-b2d6-d677f41f5b74", Title: "Объемный водоотталкивающий тренч", Price: domain.NewMoney(499000, "RUB"), CreatedAt: time.Date(2022, 01, 12, 13, 10, 18, 882593, time.Local), MinPrice: domain.NewMoney(499000, "RUB"), MaxPrice: domain.NewMoney(499000, "RUB"), InStock: true,
+				}).Encode(),
+			},
+		},
+
+		{
+			name: "Filters And Cursor",
+			mock: func() {
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT count(*)"+productsFrom+" WHERE c.slug = $1 AND s.slug = $2 AND "+productPrice+" >= $3 AND "+productPrice+" <= $4 AND "+productPrice+" < p.price")).
+					WithArgs("zhenshchinam", "vyshevka", uint(100000), uint(500000)).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
 
-				mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM products")).WillReturnRows(rows)
+				rows := sqlmock.NewRows(columns).
+					AddRow("96a7193a-403d-4e01-94e6-c02c5bcb61f1", "Хлопковая рубашка в полоску", "w4.webp", 359000, "RUB", 10, 399000, "6b1e7f7e-2f4b-4c1e-9d65-0d3f3c9b8a11", "zhenshchinam", "odezhda", "vyshevka", "", time.Date(2022, 01, 12, 13, 16, 55, 558842, time.Local), 359000, 359000, true)
+
+				mock.ExpectQuery(regexp.QuoteMeta(selectProductQuery+" WHERE c.slug = $1 AND s.slug = $2 AND "+productPrice+" >= $3 AND "+productPrice+" <= $4 AND "+productPrice+" < p.price AND ("+productPrice+", p.id) > ($5, $6) ORDER BY "+productPrice+" ASC, p.id ASC LIMIT $7 OFFSET $8")).
+					WithArgs("zhenshchinam", "vyshevka", uint(100000), uint(500000), uint(499000), "b07221f8-4133-4688-b2d6-d677f41f5b74", 21, 0).
+					WillReturnRows(rows)
+			},
+			filter: domain.ProductsFilter{
+				Category: "zhenshchinam",
+				Subtype:  "vyshevka",
+				MinPrice: uintPointer(100000),
+				MaxPrice: uintPointer(500000),
+				OnSale:   boolPointer(true),
+				Sort:     "price",
+				Limit:    20,
 			},
-			want: []domain.ProductsList{
-				{Id: "453b4f0f-1f56-4c57-b43d-7b79792450a7", Title: "Твидовый кардиган из хлопка", Image: "w1.webp", Price: 749000, Sale: 0, SaleOldPrice: 0, Category: "Женщинам", Type: "Одежда", Subtype: "Старые-коллекции", Description: "", CreatedAt: time.Date(2022, 01, 12, 13, 8, 21, 32963, time.Local)},
-				{Id: "b07221f8-4133-4688-b2d6-d677f41f5b74", Title: "Объемный водоотталкивающий тренч", Image: "w2.webp", Price: 499000, Sale: 50, SaleOldPrice: 999000, Category: "Женщинам", Type: "Одежда", Subtype: "Старые-коллекции", Description: "", CreatedAt: time.Date(2022, 01, 12, 13, 10, 18, 882593, time.Local)},
-				{Id: "96a7193a-403d-4e01-94e6-c02c5bcb61f1", Title: "Хлопковая рубашка в полоску", Image: "w4.webp", Price: 359000, Sale: 0, SaleOldPrice: 0, Category: "Женщинам", Type: "Одежда", Subtype: "Вышевка", Description: "", CreatedAt: time.Date(2022, 01, 12, 13, 16, 55, 558842, time.Local)},
+			after: &cursor,
+			want: domain.ProductsPage{
+				Products: []domain.ProductsList{
+					{Id: "96a7193a-403d-4e01-94e6-c02c5bcb61f1", Title: "Хлопковая рубашка в полоску", Image: "w4.webp", Price: domain.NewMoney(359000, "RUB"), Sale: 10, SaleOldPrice: domain.NewMoney(399000, "RUB"), CategoryId: "6b1e7f7e-2f4b-4c1e-9d65-0d3f3c9b8a11", Category: "zhenshchinam", Type: "odezhda", Subtype: "vyshevka", Description: "", CreatedAt: time.Date(2022, 01, 12, 13, 16, 55, 558842, time.Local), MinPrice: domain.NewMoney(359000, "RUB"), MaxPrice: domain.NewMoney(359000, "RUB"), InStock: true},
+				},
+				Total: 1,
 			},
 		},
+
 		{
 			name: "No Records",
 			mock: func() {
-				rows := sqlmock.NewRows([]string{"id", "title", "image", "price", "sale", "sale_old_price", "category", "type", "subtype", "description", "created_at"})
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT count(*)" + productsFrom)).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
 
-				mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM products")).WillReturnRows(rows)
+				mock.ExpectQuery(regexp.QuoteMeta(selectProductQuery+" ORDER BY p.created_at DESC, p.id DESC LIMIT $1 OFFSET $2")).
+					WithArgs(21, 40).
+					WillReturnRows(sqlmock.NewRows(columns))
+			},
+			filter: domain.ProductsFilter{Sort: "-created_at", Limit: 20, Offset: 40},
+			want: domain.ProductsPage{
+				Products: []domain.ProductsList{},
 			},
-			want: []domain.ProductsList{},
+		},
+
+		{
+			name:   "Unknown Sort",
+			mock:   func() {},
+			filter: domain.ProductsFilter{Sort: "id; DROP TABLE products", Limit: 20},
+			want: domain.ProductsPage{
+				Products: []domain.ProductsList{},
+			},
+			wantErr: true,
 		},
 	}
 
@@ -157,7 +214,7 @@ func TestProductsListPostgres_GetAll(t *testing.T) {
 		t.Run(testCase.name, func(t *testing.T) {
 			testCase.mock()
 
-			got, err := r.GetAll()
+			got, err := r.GetAll(testCase.filter, testCase.after)
 			if testCase.wantErr {
 				assert.Error(t, err)
 			} else {
@@ -169,6 +226,51 @@ func TestProductsListPostgres_GetAll(t *testing.T) {
 	}
 }
 
+func TestProductsListPostgres_Search(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	r := NewProductsListPostgres(db)
+
+	columns := []string{"id", "title", "image", "price", "currency", "sale", "sale_old_price", "category_id", "category", "type", "subtype", "description", "created_at", "min_price", "max_price", "in_stock", "rank", "ts_headline", "ts_headline"}
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT count(*)"+productsFrom+" WHERE c.slug = $2 AND (p.search_vector @@ "+searchQuery+" OR $1 <% p.title)")).
+		WithArgs("тренч", "zhenshchinam").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	rows := sqlmock.NewRows(columns).
+		AddRow("b07221f8-4133-4688-b2d6-d677f41f5b74", "Объемный водоотталкивающий тренч", "", 499000, "RUB", 50, 999000, "6b1e7f7e-2f4b-4c1e-9d65-0d3f3c9b8a11", "zhenshchinam", "odezhda", "starye-kollekcii", "", time.Date(2022, 01, 12, 13, 10, 18, 882593, time.Local), 499000, 499000, true, 0.6, "Объемный водоотталкивающий <b>тренч</b>", "")
+
+	mock.ExpectQuery("SELECT (.+) FROM products p JOIN (.+) WHERE c.slug = \\$2 AND (.+) ORDER BY p.search_vector @@ (.+) DESC, rank DESC, word_similarity\\(\\$1, p.title\\) DESC, p.id LIMIT \\$3 OFFSET \\$4").
+		WithArgs("тренч", "zhenshchinam", 20, 0).
+		WillReturnRows(rows)
+
+	got, err := r.Search(domain.ProductsSearchInput{
+		Query: "тренч",
+		ProductsFilter: domain.ProductsFilter{
+			Category: "zhenshchinam",
+			Limit:    20,
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, domain.ProductsSearchPage{
+		Results: []domain.ProductSearchResult{
+			{
+				ProductsList: domain.ProductsList{
+					Id: "b07221f8-4133-4688-b2d6-d677f41f5b74", Title: "Объемный водоотталкивающий тренч", Price: domain.NewMoney(499000, "RUB"), Sale: 50, SaleOldPrice: domain.NewMoney(999000, "RUB"), CategoryId: "6b1e7f7e-2f4b-4c1e-9d65-0d3f3c9b8a11", Category: "zhenshchinam", Type: "odezhda", Subtype: "starye-kollekcii", CreatedAt: time.Date(2022, 01, 12, 13, 10, 18, 882593, time.Local), MinPrice: domain.NewMoney(499000, "RUB"), MaxPrice: domain.NewMoney(499000, "RUB"), InStock: true,
+				},
+				Rank:      0.6,
+				Highlight: "Объемный водоотталкивающий <b>тренч</b>",
+			},
+		},
+		Total: 1,
+	}, got)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestProductsListPostgres_GetById(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
@@ -191,30 +293,30 @@ func TestProductsListPostgres_GetById(t *testing.T) {
 		{
 			name: "OK",
 			mock: func() {
-				rows := sqlmock.NewRows([]string{"id", "title", "image", "price", "sale", "sale_old_price", "category", "type", "subtype", "description", "created_at"}).
-					AddRow("453b4f0f-1f56-4c57-b43d-7b79792450a7", "Твидовый кардиган из хлопка", "w1.webp", 749000, 0, 0, "Женщинам", "Одежда", "Старые-коллекции", "", time.Date(2022, 01, 12, 13, 8, 21, 32963, time.Local))
+				rows := sqlmock.NewRows([]string{"id", "title", "image", "price", "currency", "sale", "sale_old_price", "category_id", "category", "type", "subtype", "description", "created_at", "min_price", "max_price", "in_stock"}).
+					AddRow("453b4f0f-1f56-4c57-b43d-7b79792450a7", "Твидовый кардиган из хлопка", "w1.webp", 749000, "RUB", 0, 0, "6b1e7f7e-2f4b-4c1e-9d65-0d3f3c9b8a11", "zhenshchinam", "odezhda", "starye-kollekcii", "", time.Date(2022, 01, 12, 13, 8, 21, 32963, time.Local), 749000, 749000, true)
 
-				mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM products WHERE id = $1")).WithArgs("453b4f0f-1f56-4c57-b43d-7b79792450a7").WillReturnRows(rows)
+				mock.ExpectQuery(regexp.QuoteMeta(selectProductQuery + " WHERE p.id = $1")).WithArgs("453b4f0f-1f56-4c57-b43d-7b79792450a7").WillReturnRows(rows)
 			},
 			args: args{
 				productId: "453b4f0f-1f56-4c57-b43d-7b79792450a7",
 			},
 			want: domain.ProductsList{
-				Id: "453b4f0f-1f56-4c57-b43d-7b79792450a7", Title: "Твидовый кардиган из хлопка", Image: "w1.webp", Price: 749000, Sale: 0, SaleOldPrice: 0, Category: "Женщинам", Type: "Одежда", Subtype: "Старые-коллекции", Description: "", CreatedAt: time.Date(2022, 01, 12, 13, 8, 21, 32963, time.Local),
+				Id: "453b4f0f-1f56-4c57-b43d-7b79792450a7", Title: "Твидовый кардиган из хлопка", Image: "w1.webp", Price: domain.NewMoney(749000, "RUB"), Sale: 0, SaleOldPrice: domain.NewMoney(0, "RUB"), CategoryId: "6b1e7f7e-2f4b-4c1e-9d65-0d3f3c9b8a11", Category: "zhenshchinam", Type: "odezhda", Subtype: "starye-kollekcii", Description: "", CreatedAt: time.Date(2022, 01, 12, 13, 8, 21, 32963, time.Local), MinPrice: domain.NewMoney(749000, "RUB"), MaxPrice: domain.NewMoney(749000, "RUB"), InStock: true,
 			},
 		},
 
 		{
 			name: "Not Found",
 			mock: func() {
-				rows := sqlmock.NewRows([]string{"id", "title", "image", "price", "sale", "sale_old_price", "category", "type", "subtype", "description", "created_at"})
+				rows := sqlmock.NewRows([]string{"id", "title", "image", "price", "currency", "sale", "sale_old_price", "category_id", "category", "type", "subtype", "description", "created_at", "min_price", "max_price", "in_stock"})
 
-				mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM products WHERE id = $1")).WithArgs("453b4f0f-1f56-4c57-b43d-7b79792450a7").WillReturnRows(rows)
+				mock.ExpectQuery(regexp.QuoteMeta(selectProductQuery + " WHERE p.id = $1")).WithArgs("453b4f0f-1f56-4c57-b43d-7b79792450a7").WillReturnRows(rows)
 			},
 			args: args{
 				productId: "453b4f0f-1f56-4c57-b43d-7b79792450a7",
 			},
-			want: domain.ProductsList{},
+			wantErr: true,
 		},
 	}
 
@@ -317,19 +419,15 @@ func TestProductsListPostgres_Update(t *testing.T) {
 			name: "OK_AllFields",
 			mock: func() {
 				mock.ExpectExec("UPDATE products SET (.+) WHERE (.+)").
-					WithArgs("new title", 1000, 1000, 100, "new category", "new type", "new subtype", "new description", "453b4f0f-1f56-4c57-b43d-7b79792450a7").WillReturnResult(sqlmock.NewResult(0, 1))
+					WithArgs("new title", 1000, "RUB", "6b1e7f7e-2f4b-4c1e-9d65-0d3f3c9b8a11", "new description", "453b4f0f-1f56-4c57-b43d-7b79792450a7").WillReturnResult(sqlmock.NewResult(0, 1))
 			},
 			args: args{
 				productId: "453b4f0f-1f56-4c57-b43d-7b79792450a7",
 				item: domain.UpdateProductInput{
-					Title:        stringPointer("new title"),
-					Price:        uintPointer(1000),
-					Sale:         uintPointer(1000),
-					SaleOldPrice: uintPointer(100),
-					Category:     stringPointer("new category"),
-					Type:         stringPointer("new type"),
-					Subtype:      stringPointer("new subtype"),
-					Description:  stringPointer("new description"),
+					Title:       stringPointer("new title"),
+					Price:       &domain.PriceInput{Amount: 1000, Currency: "RUB"},
+					CategoryId:  stringPointer("6b1e7f7e-2f4b-4c1e-9d65-0d3f3c9b8a11"),
+					Description: stringPointer("new description"),
 				},
 			},
 		},
@@ -367,3 +465,7 @@ func stringPointer(s string) *string {
 func uintPointer(b uint) *uint {
 	return &b
 }
+
+func boolPointer(b bool) *bool {
+	return &b
+}
@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/AndrewMislyuk/go-shop-backend/internal/domain"
+	"github.com/lib/pq"
+)
+
+const selectPromoCodeQuery = `SELECT id, code, kind, value, min_order_amount, usage_limit, per_user_limit, used_count, starts_at, ends_at, active, created_at, updated_at
+	FROM promo_codes`
+
+type PromoCodesPostgres struct {
+	db *sql.DB
+}
+
+func NewPromoCodesPostgres(db *sql.DB) *PromoCodesPostgres {
+	return &PromoCodesPostgres{
+		db: db,
+	}
+}
+
+func (r *PromoCodesPostgres) Create(code domain.PromoCode) error {
+	_, err := r.db.Exec(`INSERT INTO promo_codes(id, code, kind, value, min_order_amount, usage_limit, per_user_limit, starts_at, ends_at, active, created_at)
+		values($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`,
+		code.Id, code.Code, code.Kind, code.Value, code.MinOrderAmount, code.UsageLimit, code.PerUserLimit, code.StartsAt, code.EndsAt, code.Active, code.CreatedAt)
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+		return domain.ErrPromoCodeTaken
+	}
+
+	return err
+}
+
+func (r *PromoCodesPostgres) GetById(codeId string) (domain.PromoCode, error) {
+	return scanPromoCode(r.db.QueryRow(selectPromoCodeQuery+" WHERE id = $1", codeId))
+}
+
+// GetByCode looks up a normalized code.
+func (r *PromoCodesPostgres) GetByCode(code string) (domain.PromoCode, error) {
+	return scanPromoCode(r.db.QueryRow(selectPromoCodeQuery+" WHERE code = $1", code))
+}
+
+// GetAll returns the promo codes, newest first.
+func (r *PromoCodesPostgres) GetAll(filter domain.PromoCodesFilter) (domain.PromoCodesList, error) {
+	list := domain.PromoCodesList{
+		PromoCodes: make([]domain.PromoCode, 0),
+	}
+
+	if err := r.db.QueryRow("SELECT count(*) FROM promo_codes").Scan(&list.Total); err != nil {
+		return list, err
+	}
+
+	rows, err := r.db.Query(fmt.Sprintf("%s ORDER BY created_at DESC, id DESC LIMIT $1 OFFSET $2", selectPromoCodeQuery), filter.Limit, filter.Offset)
+	if err != nil {
+		return list, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		code, err := scanPromoCode(rows)
+		if err != nil {
+			return list, err
+		}
+
+		list.PromoCodes = append(list.PromoCodes, code)
+	}
+
+	return list, rows.Err()
+}
+
+// Update saves the changeable fields of the promo code, the number of its
+// uses is kept by redemptions only.
+func (r *PromoCodesPostgres) Update(code domain.PromoCode) error {
+	return r.execAffectingPromoCode(`UPDATE promo_codes SET kind = $1, value = $2, min_order_amount = $3, usage_limit = $4, per_user_limit = $5,
+		starts_at = $6, ends_at = $7, active = $8, updated_at = $9 WHERE id = $10`,
+		code.Kind, code.Value, code.MinOrderAmount, code.UsageLimit, code.PerUserLimit, code.StartsAt, code.EndsAt, code.Active, code.UpdatedAt, code.Id)
+}
+
+func (r *PromoCodesPostgres) Delete(codeId string) error {
+	return r.execAffectingPromoCode("DELETE FROM promo_codes WHERE id = $1", codeId)
+}
+
+func (r *PromoCodesPostgres) execAffectingPromoCode(query string, args ...interface{}) error {
+	res, err := r.db.Exec(query, args...)
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return domain.ErrPromoCodeNotFound
+	}
+
+	return nil
+}
+
+func scanPromoCode(row rowScanner) (domain.PromoCode, error) {
+	var code domain.PromoCode
+
+	err := row.Scan(&code.Id, &code.Code, &code.Kind, &code.Value, &code.MinOrderAmount, &code.UsageLimit, &code.PerUserLimit, &code.UsedCount,
+		&code.StartsAt, &code.EndsAt, &code.Active, &code.CreatedAt, &code.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return code, domain.ErrPromoCodeNotFound
+	}
+
+	return code, err
+}
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/AndrewMislyuk/go-shop-backend/internal/domain"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPromoCodesPostgres_GetByCode(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	r := NewPromoCodesPostgres(db)
+
+	columns := []string{"id", "code", "kind", "value", "min_order_amount", "usage_limit", "per_user_limit", "used_count", "starts_at", "ends_at", "active", "created_at", "updated_at"}
+	startsAt := time.Date(2022, 03, 01, 0, 0, 0, 0, time.Local)
+	perUserLimit := 1
+
+	testTable := []struct {
+		name    string
+		mock    func()
+		want    domain.PromoCode
+		wantErr error
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				mock.ExpectQuery(regexp.QuoteMeta(selectPromoCodeQuery + " WHERE code = $1")).
+					WithArgs("SPRING10").
+					WillReturnRows(sqlmock.NewRows(columns).
+						AddRow("1e2d3c4b-5a69-4788-96a5-b4c3d2e1f0a9", "SPRING10", "percent", 10, 300000, nil, 1, 12, startsAt, nil, true, startsAt, nil))
+			},
+			want: domain.PromoCode{
+				Id:             "1e2d3c4b-5a69-4788-96a5-b4c3d2e1f0a9",
+				Code:           "SPRING10",
+				Kind:           domain.DiscountPercent,
+				Value:          10,
+				MinOrderAmount: 300000,
+				PerUserLimit:   &perUserLimit,
+				UsedCount:      12,
+				StartsAt:       startsAt,
+				Active:         true,
+				CreatedAt:      startsAt,
+			},
+		},
+
+		{
+			name: "Not Found",
+			mock: func() {
+				mock.ExpectQuery(regexp.QuoteMeta(selectPromoCodeQuery + " WHERE code = $1")).
+					WithArgs("SPRING10").
+					WillReturnRows(sqlmock.NewRows(columns))
+			},
+			wantErr: domain.ErrPromoCodeNotFound,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mock()
+
+			got, err := r.GetByCode("SPRING10")
+			if testCase.wantErr != nil {
+				assert.ErrorIs(t, err, testCase.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, testCase.want, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
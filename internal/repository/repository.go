@@ -8,34 +8,227 @@ import (
 	"database/sql"
 )
 
+//go:generate mockgen -source=repository.go -destination=mock/mock.go
+
+// Postgres error codes the repositories translate into domain errors.
+const (
+	foreignKeyViolation = "23503"
+	uniqueViolation     = "23505"
+)
+
 type Authorization interface {
-	CreateUser(user domain.UserSignUp, dataId string, timestamp time.Time) (string, error)
-	GetUser(email, password string) (domain.User, error)
+	CreateUser(user domain.UserSignUp, dataId, role string, timestamp time.Time) (string, error)
+	GetUserByEmail(email string) (domain.User, error)
+	GetUserById(userId string) (domain.User, error)
+	UpdatePasswordHash(userId, passwordHash string) error
+	SetEmailVerified(userId string, timestamp time.Time) error
+}
+
+type UserTokens interface {
+	Create(token domain.UserToken) error
+	Consume(tokenHash, purpose string, timestamp time.Time) (domain.UserToken, error)
+}
+
+type Users interface {
+	GetAll(filter domain.UsersFilter) (domain.UsersList, error)
+	Update(userId string, input domain.UpdateUserInput, timestamp time.Time) error
+	SetBlocked(userId string, blockedAt *time.Time) error
+	Anonymize(userId string, timestamp time.Time) error
+}
+
+type Sessions interface {
+	CreateRefreshToken(token domain.RefreshToken) error
+	GetRefreshToken(tokenHash string) (domain.RefreshToken, error)
+	RotateRefreshToken(usedId string, next domain.RefreshToken) error
+	IsSessionActive(sessionId string, timestamp time.Time) (bool, error)
+	RevokeSession(sessionId string, timestamp time.Time) error
+	RevokeUserSessions(userId string, timestamp time.Time) error
+	RevokeOtherSessions(userId, keepSessionId string, timestamp time.Time) error
+}
+
+// LoginAttempts counts failed sign-ins. Failures older than window don't
+// count, the next failure starts over from one.
+type LoginAttempts interface {
+	Get(key string) (domain.LoginAttempt, error)
+	RegisterFailure(key string, timestamp time.Time, window time.Duration) (domain.LoginAttempt, error)
+	Lock(key string, until time.Time) error
+	Reset(key string) error
+	DeleteExpired(now time.Time, window time.Duration) (int, error)
+}
+
+type Roles interface {
+	GetAll() ([]domain.Role, error)
+	Grant(userId, role string, timestamp time.Time) error
+	Revoke(userId, role string) error
 }
 
 type ProductsList interface {
 	Create(list domain.CreateProductInput, productId string, timestamp time.Time) (string, error)
-	GetAll() ([]domain.ProductsList, error)
+	GetAll(filter domain.ProductsFilter, after *domain.ProductsCursor) (domain.ProductsPage, error)
+	Search(input domain.ProductsSearchInput) (domain.ProductsSearchPage, error)
 	GetById(listId string) (domain.ProductsList, error)
 	Update(itemId string, input domain.UpdateProductInput) error
 	Delete(itemId string) error
 }
 
+type ProductVariants interface {
+	Create(variant domain.ProductVariant) error
+	GetByProduct(productId string) ([]domain.ProductVariant, error)
+	Update(productId, variantId string, input domain.UpdateVariantInput, timestamp time.Time) error
+	Delete(productId, variantId string) error
+}
+
+// Inventory changes stock levels under a row lock, so concurrent movements
+// and reservations of the same product or variant are applied one by one.
+type Inventory interface {
+	GetLevels(filter domain.StockLevelsFilter) (domain.StockLevelsList, error)
+	GetMovements(filter domain.StockMovementsFilter) (domain.StockMovementsList, error)
+	RecordMovement(movement domain.StockMovement, version *int) (domain.StockLevel, error)
+	Reserve(reservation domain.Reservation) error
+	ConfirmReservation(reservationId string, movement domain.StockMovement) error
+	ConfirmReservations(movements []domain.StockMovement) error
+	ReleaseReservation(reservationId string, timestamp time.Time) error
+	ExpireReservations(timestamp time.Time) (int, error)
+}
+
+type Carts interface {
+	Create(cart domain.Cart) error
+	GetByUser(userId string) (domain.Cart, error)
+	GetByToken(tokenHash string, timestamp time.Time) (domain.Cart, error)
+	GetItems(cartId string) ([]domain.CartItem, error)
+	AddItem(item domain.CartItem, maxQuantity int) error
+	UpdateItemQuantity(cartId, itemId string, quantity int, timestamp time.Time) error
+	RemoveItem(cartId, itemId string) error
+	AcceptPrices(cartId string, timestamp time.Time) error
+	Touch(cartId string, timestamp, expiresAt time.Time) error
+	AssignToUser(cartId, userId string, timestamp time.Time) error
+	Merge(fromCartId, toCartId string, maxQuantity int, timestamp time.Time) error
+	Clear(cartId string) error
+	DeleteExpired(timestamp time.Time) (int, error)
+}
+
+// Orders changes the status of an order only if it is still in the status the
+// change was made from, the history gets an entry in the same transaction.
+// Cancelling releases the promo code redemption of the order in it as well.
+type Orders interface {
+	Create(order domain.Order, created domain.OrderStatusChange, redemption *domain.PromoCodeRedemption) (int64, error)
+	GetById(orderId string) (domain.Order, error)
+	GetAll(filter domain.OrdersFilter) (domain.OrdersList, error)
+	UpdateStatus(change domain.OrderStatusChange) error
+	GetExpired(timestamp time.Time) ([]string, error)
+}
+
+// Payments keeps the payments of orders and the webhooks already processed,
+// status changes are conditional so a webhook and the request that started
+// the payment can't apply the same change twice.
+type Payments interface {
+	Create(payment domain.Payment) error
+	GetByIntent(provider, intentId string) (domain.Payment, error)
+	GetByOrder(orderId, status string) (domain.Payment, error)
+	UpdateStatus(paymentId, from, to, failureCode string, timestamp time.Time) (bool, error)
+	HasEvent(provider, eventId string) (bool, error)
+	RecordEvent(provider, eventId, eventType string, paymentId *string, timestamp time.Time) error
+}
+
+// Discounts keeps the sales. The best active ones are copied to the products,
+// whose sale_price the catalogue is sorted and filtered by.
+type Discounts interface {
+	Create(discount domain.Discount) error
+	GetById(discountId string) (domain.Discount, error)
+	GetAll(filter domain.DiscountsFilter) (domain.DiscountsList, error)
+	Update(discount domain.Discount) error
+	Delete(discountId string) error
+	RefreshSales() (int, error)
+}
+
+// PromoCodes keeps the promo codes, they are redeemed by Orders.Create.
+type PromoCodes interface {
+	Create(code domain.PromoCode) error
+	GetById(codeId string) (domain.PromoCode, error)
+	GetByCode(code string) (domain.PromoCode, error)
+	GetAll(filter domain.PromoCodesFilter) (domain.PromoCodesList, error)
+	Update(code domain.PromoCode) error
+	Delete(codeId string) error
+}
+
+// ExchangeRates keeps the rates of the currencies against the base currency.
+type ExchangeRates interface {
+	GetAll() ([]domain.ExchangeRate, error)
+	Get(currency string) (domain.ExchangeRate, error)
+	Set(rates []domain.ExchangeRate) error
+}
+
+// ProductPrices keeps the price lists, the regular prices of products in
+// currencies other than the base one.
+type ProductPrices interface {
+	Set(price domain.ProductPrice) error
+	Delete(productId, currency string) error
+	GetByProduct(productId string) ([]domain.ProductPrice, error)
+	GetByProducts(productIds []string, currency string) (map[string]domain.Money, error)
+}
+
+type Categories interface {
+	Create(category domain.Category) error
+	GetById(categoryId string) (domain.Category, error)
+	GetAllWithCounts() ([]domain.CategoryNode, error)
+	Update(categoryId string, input domain.UpdateCategoryInput, timestamp time.Time) error
+	Delete(categoryId string) error
+}
+
+// Files keeps the image galleries of the products and the uploads of the
+// images.
 type Files interface {
-	Create(file domain.File) error
-	GetProductImage(productId string) (string, error)
+	Create(file domain.File) (domain.File, error)
+	GetById(fileId string) (domain.File, error)
+	GetByProduct(productId string) ([]domain.File, error)
+	GetByProducts(productIds []string) (map[string][]domain.File, error)
+	Update(productId, fileId string, input domain.UpdateImageInput) error
+	Reorder(productId string, fileIds []string) error
+	SetPrimary(productId, fileId string) error
+	Delete(productId, fileId string) error
+	UpdateStatus(file domain.File, from domain.FileStatus) error
+	Complete(file domain.File) error
+	FailStale(before, clientBefore time.Time, reason string) ([]string, error)
 }
 
 type Repository struct {
 	Authorization
+	Users
+	Sessions
+	UserTokens
+	Roles
 	ProductsList
+	ProductVariants
+	Inventory
+	Carts
+	Orders
+	Payments
+	Discounts
+	PromoCodes
+	ExchangeRates
+	ProductPrices
+	Categories
 	Files
 }
 
 func NewRepository(db *sql.DB) *Repository {
 	return &Repository{
-		Authorization: NewAuthPostgres(db),
-		ProductsList:  NewProductsListPostgres(db),
-		Files:         NewFilesPostgres(db),
+		Authorization:   NewAuthPostgres(db),
+		Users:           NewUsersPostgres(db),
+		Sessions:        NewSessionsPostgres(db),
+		UserTokens:      NewUserTokensPostgres(db),
+		Roles:           NewRolesPostgres(db),
+		ProductsList:    NewProductsListPostgres(db),
+		ProductVariants: NewProductVariantsPostgres(db),
+		Inventory:       NewInventoryPostgres(db),
+		Carts:           NewCartsPostgres(db),
+		Orders:          NewOrdersPostgres(db),
+		Payments:        NewPaymentsPostgres(db),
+		Discounts:       NewDiscountsPostgres(db),
+		PromoCodes:      NewPromoCodesPostgres(db),
+		ExchangeRates:   NewExchangeRatesPostgres(db),
+		ProductPrices:   NewProductPricesPostgres(db),
+		Categories:      NewCategoriesPostgres(db),
+		Files:           NewFilesPostgres(db),
 	}
 }
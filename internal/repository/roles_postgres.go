@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/AndrewMislyuk/go-shop-backend/internal/domain"
+	"github.com/lib/pq"
+)
+
+type RolesPostgres struct {
+	db *sql.DB
+}
+
+func NewRolesPostgres(db *sql.DB) *RolesPostgres {
+	return &RolesPostgres{
+		db: db,
+	}
+}
+
+func (r *RolesPostgres) GetAll() ([]domain.Role, error) {
+	rows, err := r.db.Query(`SELECT r.name, r.description, COALESCE(array_agg(rp.permission ORDER BY rp.permission) FILTER (WHERE rp.permission IS NOT NULL), '{}')
+		FROM roles r LEFT JOIN role_permissions rp ON rp.role = r.name GROUP BY r.name ORDER BY r.name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	roles := make([]domain.Role, 0)
+	for rows.Next() {
+		var role domain.Role
+		if err := rows.Scan(&role.Name, &role.Description, pq.Array(&role.Permissions)); err != nil {
+			return nil, err
+		}
+
+		roles = append(roles, role)
+	}
+
+	return roles, rows.Err()
+}
+
+func (r *RolesPostgres) Grant(userId, role string, timestamp time.Time) error {
+	_, err := r.db.Exec("INSERT INTO user_roles(user_id, role, granted_at) values($1, $2, $3) ON CONFLICT DO NOTHING", userId, role, timestamp)
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == foreignKeyViolation {
+		if pqErr.Constraint == "user_roles_user_id_fkey" {
+			return domain.ErrUserNotFound
+		}
+
+		return domain.ErrRoleNotFound
+	}
+
+	return err
+}
+
+func (r *RolesPostgres) Revoke(userId, role string) error {
+	_, err := r.db.Exec("DELETE FROM user_roles WHERE user_id = $1 AND role = $2", userId, role)
+
+	return err
+}
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/AndrewMislyuk/go-shop-backend/internal/domain"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRolesPostgres_GetAll(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	r := NewRolesPostgres(db)
+
+	rows := sqlmock.NewRows([]string{"name", "description", "permissions"}).
+		AddRow("ADMIN", "Full access to the shop administration", "{files:upload,products:write,users:manage}").
+		AddRow("CUSTOMER", "Default role assigned at sign-up", "{}")
+
+	mock.ExpectQuery("SELECT (.+) FROM roles r LEFT JOIN role_permissions rp").WillReturnRows(rows)
+
+	got, err := r.GetAll()
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.Role{
+		{Name: "ADMIN", Description: "Full access to the shop administration", Permissions: []string{"files:upload", "products:write", "users:manage"}},
+		{Name: "CUSTOMER", Description: "Default role assigned at sign-up", Permissions: []string{}},
+	}, got)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRolesPostgres_Grant(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	r := NewRolesPostgres(db)
+
+	grantedAt := time.Date(2022, 7, 12, 13, 8, 21, 0, time.UTC)
+
+	testTable := []struct {
+		name    string
+		mock    func()
+		wantErr error
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO user_roles(user_id, role, granted_at) values($1, $2, $3) ON CONFLICT DO NOTHING")).
+					WithArgs("34c8d3e6-b8d7-43dc-847e-5764c4114856", "ADMIN", grantedAt).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+
+		{
+			name: "Unknown Role",
+			mock: func() {
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO user_roles(user_id, role, granted_at) values($1, $2, $3) ON CONFLICT DO NOTHING")).
+					WithArgs("34c8d3e6-b8d7-43dc-847e-5764c4114856", "ADMIN", grantedAt).
+					WillReturnError(&pq.Error{Code: "23503", Constraint: "user_roles_role_fkey"})
+			},
+			wantErr: domain.ErrRoleNotFound,
+		},
+
+		{
+			name: "Unknown User",
+			mock: func() {
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO user_roles(user_id, role, granted_at) values($1, $2, $3) ON CONFLICT DO NOTHING")).
+					WithArgs("34c8d3e6-b8d7-43dc-847e-5764c4114856", "ADMIN", grantedAt).
+					WillReturnError(&pq.Error{Code: "23503", Constraint: "user_roles_user_id_fkey"})
+			},
+			wantErr: domain.ErrUserNotFound,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mock()
+
+			err := r.Grant("34c8d3e6-b8d7-43dc-847e-5764c4114856", "ADMIN", grantedAt)
+			if testCase.wantErr != nil {
+				assert.ErrorIs(t, err, testCase.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/AndrewMislyuk/go-shop-backend/internal/domain"
+	"github.com/sirupsen/logrus"
+)
+
+type SessionsPostgres struct {
+	db *sql.DB
+}
+
+func NewSessionsPostgres(db *sql.DB) *SessionsPostgres {
+	return &SessionsPostgres{
+		db: db,
+	}
+}
+
+func (r *SessionsPostgres) CreateRefreshToken(token domain.RefreshToken) error {
+	_, err := r.db.Exec("INSERT INTO refresh_tokens(id, user_id, session_id, token_hash, expires_at, created_at) values($1, $2, $3, $4, $5, $6)",
+		token.Id, token.UserId, token.SessionId, token.TokenHash, token.ExpiresAt, token.CreatedAt)
+
+	return err
+}
+
+func (r *SessionsPostgres) GetRefreshToken(tokenHash string) (domain.RefreshToken, error) {
+	var token domain.RefreshToken
+
+	row := r.db.QueryRow("SELECT id, user_id, session_id, token_hash, expires_at, used_at, revoked_at, created_at FROM refresh_tokens WHERE token_hash = $1", tokenHash)
+	if err := row.Scan(&token.Id, &token.UserId, &token.SessionId, &token.TokenHash, &token.ExpiresAt, &token.UsedAt, &token.RevokedAt, &token.CreatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return token, domain.ErrRefreshTokenNotFound
+		}
+
+		return token, err
+	}
+
+	return token, nil
+}
+
+// RotateRefreshToken marks the used token and stores its successor in one
+// transaction. If the token was used concurrently, domain.ErrRefreshTokenReused
+// is returned and nothing is stored.
+func (r *SessionsPostgres) RotateRefreshToken(usedId string, next domain.RefreshToken) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	res, err := tx.Exec("UPDATE refresh_tokens SET used_at = $1 WHERE id = $2 AND used_at IS NULL AND revoked_at IS NULL", next.CreatedAt, usedId)
+	if err != nil {
+		if rb := tx.Rollback(); rb != nil {
+			logrus.Fatalf("query failed: %v, unable to abort: %v", err, rb)
+		}
+
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err == nil && affected == 0 {
+		err = domain.ErrRefreshTokenReused
+	}
+
+	if err != nil {
+		if rb := tx.Rollback(); rb != nil {
+			logrus.Fatalf("query failed: %v, unable to abort: %v", err, rb)
+		}
+
+		return err
+	}
+
+	if _, err := tx.Exec("INSERT INTO refresh_tokens(id, user_id, session_id, token_hash, expires_at, created_at) values($1, $2, $3, $4, $5, $6)",
+		next.Id, next.UserId, next.SessionId, next.TokenHash, next.ExpiresAt, next.CreatedAt); err != nil {
+		if rb := tx.Rollback(); rb != nil {
+			logrus.Fatalf("query failed: %v, unable to abort: %v", err, rb)
+		}
+
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// IsSessionActive reports whether the session still has a refresh token that
+// is neither revoked nor expired.
+func (r *SessionsPostgres) IsSessionActive(sessionId string, timestamp time.Time) (bool, error) {
+	var active bool
+
+	row := r.db.QueryRow("SELECT EXISTS(SELECT 1 FROM refresh_tokens WHERE session_id = $1 AND revoked_at IS NULL AND expires_at > $2)", sessionId, timestamp)
+	if err := row.Scan(&active); err != nil {
+		return false, err
+	}
+
+	return active, nil
+}
+
+func (r *SessionsPostgres) RevokeSession(sessionId string, timestamp time.Time) error {
+	_, err := r.db.Exec("UPDATE refresh_tokens SET revoked_at = $1 WHERE session_id = $2 AND revoked_at IS NULL", timestamp, sessionId)
+
+	return err
+}
+
+func (r *SessionsPostgres) RevokeUserSessions(userId string, timestamp time.Time) error {
+	_, err := r.db.Exec("UPDATE refresh_tokens SET revoked_at = $1 WHERE user_id = $2 AND revoked_at IS NULL", timestamp, userId)
+
+	return err
+}
+
+func (r *SessionsPostgres) RevokeOtherSessions(userId, keepSessionId string, timestamp time.Time) error {
+	_, err := r.db.Exec("UPDATE refresh_tokens SET revoked_at = $1 WHERE user_id = $2 AND session_id <> $3 AND revoked_at IS NULL", timestamp, userId, keepSessionId)
+
+	return err
+}
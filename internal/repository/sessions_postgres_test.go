@@ -0,0 +1,169 @@
+package repository
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/AndrewMislyuk/go-shop-backend/internal/domain"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSessionsPostgres_GetRefreshToken(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	r := NewSessionsPostgres(db)
+
+	createdAt := time.Date(2022, 07, 12, 13, 8, 21, 0, time.UTC)
+	usedAt := createdAt.Add(time.Hour)
+
+	testTable := []struct {
+		name      string
+		mock      func()
+		tokenHash string
+		want      domain.RefreshToken
+		wantErr   error
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id", "user_id", "session_id", "token_hash", "expires_at", "used_at", "revoked_at", "created_at"}).
+					AddRow("5f1e3c6d-a5c2-4a4e-9b5e-2f9a2b6b1c01", "34c8d3e6-b8d7-43dc-847e-5764c4114856", "8d1f2e55-6a0e-4a53-8c3c-0b8f3a0f7a11", "hash", createdAt.Add(time.Hour*24), usedAt, nil, createdAt)
+
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT id, user_id, session_id, token_hash, expires_at, used_at, revoked_at, created_at FROM refresh_tokens WHERE token_hash = $1")).
+					WithArgs("hash").
+					WillReturnRows(rows)
+			},
+			tokenHash: "hash",
+			want: domain.RefreshToken{
+				Id:        "5f1e3c6d-a5c2-4a4e-9b5e-2f9a2b6b1c01",
+				UserId:    "34c8d3e6-b8d7-43dc-847e-5764c4114856",
+				SessionId: "8d1f2e55-6a0e-4a53-8c3c-0b8f3a0f7a11",
+				TokenHash: "hash",
+				ExpiresAt: createdAt.Add(time.Hour * 24),
+				UsedAt:    &usedAt,
+				CreatedAt: createdAt,
+			},
+		},
+
+		{
+			name: "Not Found",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id", "user_id", "session_id", "token_hash", "expires_at", "used_at", "revoked_at", "created_at"})
+
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT id, user_id, session_id, token_hash, expires_at, used_at, revoked_at, created_at FROM refresh_tokens WHERE token_hash = $1")).
+					WithArgs("hash").
+					WillReturnRows(rows)
+			},
+			tokenHash: "hash",
+			wantErr:   domain.ErrRefreshTokenNotFound,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mock()
+
+			got, err := r.GetRefreshToken(testCase.tokenHash)
+			if testCase.wantErr != nil {
+				assert.ErrorIs(t, err, testCase.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, testCase.want, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSessionsPostgres_RotateRefreshToken(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	r := NewSessionsPostgres(db)
+
+	next := domain.RefreshToken{
+		Id:        "0c7d9f8e-0d2b-4c1a-b0f3-9a7c2e5d4b21",
+		UserId:    "34c8d3e6-b8d7-43dc-847e-5764c4114856",
+		SessionId: "8d1f2e55-6a0e-4a53-8c3c-0b8f3a0f7a11",
+		TokenHash: "next_hash",
+		ExpiresAt: time.Date(2022, 8, 11, 13, 8, 21, 0, time.UTC),
+		CreatedAt: time.Date(2022, 7, 12, 13, 8, 21, 0, time.UTC),
+	}
+
+	testTable := []struct {
+		name    string
+		mock    func()
+		wantErr error
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE refresh_tokens SET used_at = $1 WHERE id = $2 AND used_at IS NULL AND revoked_at IS NULL")).
+					WithArgs(next.CreatedAt, "5f1e3c6d-a5c2-4a4e-9b5e-2f9a2b6b1c01").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("INSERT INTO refresh_tokens").
+					WithArgs(next.Id, next.UserId, next.SessionId, next.TokenHash, next.ExpiresAt, next.CreatedAt).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+
+		{
+			name: "Already Used",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE refresh_tokens SET used_at = $1 WHERE id = $2 AND used_at IS NULL AND revoked_at IS NULL")).
+					WithArgs(next.CreatedAt, "5f1e3c6d-a5c2-4a4e-9b5e-2f9a2b6b1c01").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+			},
+			wantErr: domain.ErrRefreshTokenReused,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mock()
+
+			err := r.RotateRefreshToken("5f1e3c6d-a5c2-4a4e-9b5e-2f9a2b6b1c01", next)
+			if testCase.wantErr != nil {
+				assert.ErrorIs(t, err, testCase.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSessionsPostgres_IsSessionActive(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	r := NewSessionsPostgres(db)
+
+	now := time.Date(2022, 7, 12, 13, 8, 21, 0, time.UTC)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT EXISTS(SELECT 1 FROM refresh_tokens WHERE session_id = $1 AND revoked_at IS NULL AND expires_at > $2)")).
+		WithArgs("8d1f2e55-6a0e-4a53-8c3c-0b8f3a0f7a11", now).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+
+	active, err := r.IsSessionActive("8d1f2e55-6a0e-4a53-8c3c-0b8f3a0f7a11", now)
+	assert.NoError(t, err)
+	assert.False(t, active)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
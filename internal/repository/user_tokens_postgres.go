@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/AndrewMislyuk/go-shop-backend/internal/domain"
+	"github.com/sirupsen/logrus"
+)
+
+type UserTokensPostgres struct {
+	db *sql.DB
+}
+
+func NewUserTokensPostgres(db *sql.DB) *UserTokensPostgres {
+	return &UserTokensPostgres{
+		db: db,
+	}
+}
+
+// Create stores the token and invalidates tokens with the same purpose issued
+// to the user before, so only the latest email sent stays usable.
+func (r *UserTokensPostgres) Create(token domain.UserToken) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec("UPDATE user_tokens SET used_at = $1 WHERE user_id = $2 AND purpose = $3 AND used_at IS NULL",
+		token.CreatedAt, token.UserId, token.Purpose); err != nil {
+		if rb := tx.Rollback(); rb != nil {
+			logrus.Fatalf("query failed: %v, unable to abort: %v", err, rb)
+		}
+
+		return err
+	}
+
+	if _, err := tx.Exec("INSERT INTO user_tokens(id, user_id, purpose, token_hash, expires_at, created_at) values($1, $2, $3, $4, $5, $6)",
+		token.Id, token.UserId, token.Purpose, token.TokenHash, token.ExpiresAt, token.CreatedAt); err != nil {
+		if rb := tx.Rollback(); rb != nil {
+			logrus.Fatalf("query failed: %v, unable to abort: %v", err, rb)
+		}
+
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Consume marks the token as used and returns it. The update is conditional,
+// so a token can't be consumed twice even by concurrent requests.
+func (r *UserTokensPostgres) Consume(tokenHash, purpose string, timestamp time.Time) (domain.UserToken, error) {
+	var token domain.UserToken
+
+	row := r.db.QueryRow(`UPDATE user_tokens SET used_at = $1 WHERE token_hash = $2 AND purpose = $3 AND used_at IS NULL AND expires_at > $1
+		RETURNING id, user_id, purpose, token_hash, expires_at, used_at, created_at`, timestamp, tokenHash, purpose)
+	if err := row.Scan(&token.Id, &token.UserId, &token.Purpose, &token.TokenHash, &token.ExpiresAt, &token.UsedAt, &token.CreatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return token, domain.ErrInvalidUserToken
+		}
+
+		return token, err
+	}
+
+	return token, nil
+}
@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/AndrewMislyuk/go-shop-backend/internal/domain"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserTokensPostgres_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	r := NewUserTokensPostgres(db)
+
+	createdAt := time.Date(2022, 07, 12, 13, 8, 21, 0, time.UTC)
+	token := domain.UserToken{
+		Id:        "5f1e3c6d-a5c2-4a4e-9b5e-2f9a2b6b1c01",
+		UserId:    "34c8d3e6-b8d7-43dc-847e-5764c4114856",
+		Purpose:   domain.TokenPurposePasswordReset,
+		TokenHash: "hash",
+		ExpiresAt: createdAt.Add(time.Hour),
+		CreatedAt: createdAt,
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE user_tokens SET used_at = $1 WHERE user_id = $2 AND purpose = $3 AND used_at IS NULL")).
+		WithArgs(createdAt, token.UserId, token.Purpose).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO user_tokens(id, user_id, purpose, token_hash, expires_at, created_at) values($1, $2, $3, $4, $5, $6)")).
+		WithArgs(token.Id, token.UserId, token.Purpose, token.TokenHash, token.ExpiresAt, token.CreatedAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	assert.NoError(t, r.Create(token))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserTokensPostgres_Consume(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	r := NewUserTokensPostgres(db)
+
+	createdAt := time.Date(2022, 07, 12, 13, 8, 21, 0, time.UTC)
+	usedAt := createdAt.Add(time.Minute)
+
+	testTable := []struct {
+		name    string
+		mock    func()
+		want    domain.UserToken
+		wantErr error
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id", "user_id", "purpose", "token_hash", "expires_at", "used_at", "created_at"}).
+					AddRow("5f1e3c6d-a5c2-4a4e-9b5e-2f9a2b6b1c01", "34c8d3e6-b8d7-43dc-847e-5764c4114856", domain.TokenPurposeEmailVerification, "hash", createdAt.Add(time.Hour), usedAt, createdAt)
+
+				mock.ExpectQuery(regexp.QuoteMeta("UPDATE user_tokens SET used_at = $1 WHERE token_hash = $2 AND purpose = $3 AND used_at IS NULL AND expires_at > $1")).
+					WithArgs(usedAt, "hash", domain.TokenPurposeEmailVerification).
+					WillReturnRows(rows)
+			},
+			want: domain.UserToken{
+				Id:        "5f1e3c6d-a5c2-4a4e-9b5e-2f9a2b6b1c01",
+				UserId:    "34c8d3e6-b8d7-43dc-847e-5764c4114856",
+				Purpose:   domain.TokenPurposeEmailVerification,
+				TokenHash: "hash",
+				ExpiresAt: createdAt.Add(time.Hour),
+				UsedAt:    &usedAt,
+				CreatedAt: createdAt,
+			},
+		},
+
+		{
+			name: "Used Or Expired",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id", "user_id", "purpose", "token_hash", "expires_at", "used_at", "created_at"})
+
+				mock.ExpectQuery(regexp.QuoteMeta("UPDATE user_tokens SET used_at = $1 WHERE token_hash = $2 AND purpose = $3 AND used_at IS NULL AND expires_at > $1")).
+					WithArgs(usedAt, "hash", domain.TokenPurposeEmailVerification).
+					WillReturnRows(rows)
+			},
+			wantErr: domain.ErrInvalidUserToken,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mock()
+
+			got, err := r.Consume("hash", domain.TokenPurposeEmailVerification, usedAt)
+			if testCase.wantErr != nil {
+				assert.ErrorIs(t, err, testCase.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, testCase.want, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,175 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/AndrewMislyuk/go-shop-backend/internal/domain"
+	"github.com/sirupsen/logrus"
+)
+
+type UsersPostgres struct {
+	db *sql.DB
+}
+
+func NewUsersPostgres(db *sql.DB) *UsersPostgres {
+	return &UsersPostgres{
+		db: db,
+	}
+}
+
+func (r *UsersPostgres) GetAll(filter domain.UsersFilter) (domain.UsersList, error) {
+	conditions := []string{"u.deleted_at IS NULL"}
+	args := make([]interface{}, 0)
+	argId := 1
+
+	if filter.Search != "" {
+		conditions = append(conditions, fmt.Sprintf("(u.name ILIKE $%d OR u.surname ILIKE $%d OR u.email ILIKE $%d)", argId, argId, argId))
+		args = append(args, "%"+filter.Search+"%")
+		argId++
+	}
+
+	if filter.Blocked != nil {
+		if *filter.Blocked {
+			conditions = append(conditions, "u.blocked_at IS NOT NULL")
+		} else {
+			conditions = append(conditions, "u.blocked_at IS NULL")
+		}
+	}
+
+	where := " WHERE " + strings.Join(conditions, " AND ")
+
+	list := domain.UsersList{
+		Users: make([]domain.User, 0),
+	}
+
+	if err := r.db.QueryRow("SELECT count(*) FROM users u"+where, args...).Scan(&list.Total); err != nil {
+		return list, err
+	}
+
+	query := fmt.Sprintf("%s%s GROUP BY u.id ORDER BY u.created_at DESC LIMIT $%d OFFSET $%d", selectUserQuery, where, argId, argId+1)
+	args = append(args, filter.Limit, filter.Offset)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return list, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return list, err
+		}
+
+		list.Users = append(list.Users, user)
+	}
+
+	return list, rows.Err()
+}
+
+func (r *UsersPostgres) Update(userId string, input domain.UpdateUserInput, timestamp time.Time) error {
+	setValues := make([]string, 0)
+	args := make([]interface{}, 0)
+	argId := 1
+
+	if input.Name != nil {
+		setValues = append(setValues, fmt.Sprintf("name=$%d", argId))
+		args = append(args, *input.Name)
+		argId++
+	}
+
+	if input.Surname != nil {
+		setValues = append(setValues, fmt.Sprintf("surname=$%d", argId))
+		args = append(args, *input.Surname)
+		argId++
+	}
+
+	if input.Email != nil {
+		setValues = append(setValues, fmt.Sprintf("email=$%d", argId))
+		args = append(args, *input.Email)
+		argId++
+	}
+
+	if input.Phone != nil {
+		setValues = append(setValues, fmt.Sprintf("phone=$%d", argId))
+		args = append(args, *input.Phone)
+		argId++
+	}
+
+	setValues = append(setValues, fmt.Sprintf("updated_at=$%d", argId))
+	args = append(args, timestamp)
+	argId++
+
+	setQuery := strings.Join(setValues, ", ")
+
+	query := fmt.Sprintf("UPDATE users SET %s WHERE id = $%d AND deleted_at IS NULL", setQuery, argId)
+
+	args = append(args, userId)
+
+	return mapEmailTaken(r.execAffectingUser(query, args...))
+}
+
+func (r *UsersPostgres) SetBlocked(userId string, blockedAt *time.Time) error {
+	return r.execAffectingUser("UPDATE users SET blocked_at = $1 WHERE id = $2 AND deleted_at IS NULL", blockedAt, userId)
+}
+
+// Anonymize erases personal data of the user and everything that lets them
+// sign in again. The row itself is kept so that references to it stay valid.
+func (r *UsersPostgres) Anonymize(userId string, timestamp time.Time) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	queries := []struct {
+		query string
+		args  []interface{}
+	}{
+		{
+			query: `UPDATE users SET name = 'Deleted', surname = 'User', email = 'deleted-' || id || '@deleted.invalid', phone = '',
+				password_hash = '', updated_at = $1, deleted_at = $1 WHERE id = $2 AND deleted_at IS NULL`,
+			args: []interface{}{timestamp, userId},
+		},
+		{
+			query: "DELETE FROM user_roles WHERE user_id = $1",
+			args:  []interface{}{userId},
+		},
+		{
+			query: "UPDATE refresh_tokens SET revoked_at = $1 WHERE user_id = $2 AND revoked_at IS NULL",
+			args:  []interface{}{timestamp, userId},
+		},
+	}
+
+	for _, q := range queries {
+		if _, err := tx.Exec(q.query, q.args...); err != nil {
+			if rb := tx.Rollback(); rb != nil {
+				logrus.Fatalf("query failed: %v, unable to abort: %v", err, rb)
+			}
+
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *UsersPostgres) execAffectingUser(query string, args ...interface{}) error {
+	res, err := r.db.Exec(query, args...)
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return domain.ErrUserNotFound
+	}
+
+	return nil
+}
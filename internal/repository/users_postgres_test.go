@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/AndrewMislyuk/go-shop-backend/internal/domain"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUsersPostgres_Update(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	r := NewUsersPostgres(db)
+
+	timestamp := time.Date(2022, 07, 12, 13, 8, 21, 0, time.UTC)
+	name := "New_Name"
+	phone := "+4412345678"
+
+	testTable := []struct {
+		name    string
+		mock    func()
+		userId  string
+		input   domain.UpdateUserInput
+		wantErr error
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE users SET name=$1, phone=$2, updated_at=$3 WHERE id = $4 AND deleted_at IS NULL")).
+					WithArgs(name, phone, timestamp, "34c8d3e6-b8d7-43dc-847e-5764c4114856").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			userId: "34c8d3e6-b8d7-43dc-847e-5764c4114856",
+			input:  domain.UpdateUserInput{Name: &name, Phone: &phone},
+		},
+		{
+			name: "Not Found",
+			mock: func() {
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE users SET name=$1, updated_at=$2 WHERE id = $3 AND deleted_at IS NULL")).
+					WithArgs(name, timestamp, "34c8d3e6-b8d7-43dc-847e-5764c4114856").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			userId:  "34c8d3e6-b8d7-43dc-847e-5764c4114856",
+			input:   domain.UpdateUserInput{Name: &name},
+			wantErr: domain.ErrUserNotFound,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mock()
+
+			err := r.Update(testCase.userId, testCase.input, timestamp)
+			if testCase.wantErr != nil {
+				assert.ErrorIs(t, err, testCase.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestUsersPostgres_GetAll(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	r := NewUsersPostgres(db)
+
+	createdAt := time.Date(2022, 07, 12, 13, 8, 21, 0, time.UTC)
+	blocked := true
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM users u WHERE u.deleted_at IS NULL AND (u.name ILIKE $1 OR u.surname ILIKE $1 OR u.email ILIKE $1) AND u.blocked_at IS NOT NULL")).
+		WithArgs("%test%").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	rows := sqlmock.NewRows([]string{"id", "name", "surname", "email", "phone", "password_hash", "created_at", "email_verified_at", "blocked_at", "deleted_at", "roles", "permissions"}).
+		AddRow("34c8d3e6-b8d7-43dc-847e-5764c4114856", "Test_Name", "Test_Surname", "test@gmail.com", "+4412345678", "hash", createdAt, createdAt, createdAt, nil, "{CUSTOMER}", "{}")
+
+	mock.ExpectQuery(regexp.QuoteMeta("WHERE u.deleted_at IS NULL AND (u.name ILIKE $1 OR u.surname ILIKE $1 OR u.email ILIKE $1) AND u.blocked_at IS NOT NULL GROUP BY u.id ORDER BY u.created_at DESC LIMIT $2 OFFSET $3")).
+		WithArgs("%test%", 20, 0).
+		WillReturnRows(rows)
+
+	got, err := r.GetAll(domain.UsersFilter{Search: "test", Blocked: &blocked, Limit: 20})
+	assert.NoError(t, err)
+	assert.Equal(t, domain.UsersList{
+		Users: []domain.User{
+			{
+				Id:              "34c8d3e6-b8d7-43dc-847e-5764c4114856",
+				Name:            "Test_Name",
+				Surname:         "Test_Surname",
+				Email:           "test@gmail.com",
+				Phone:           "+4412345678",
+				Password:        "hash",
+				CreatedAt:       createdAt,
+				EmailVerifiedAt: &createdAt,
+				BlockedAt:       &createdAt,
+				Roles:           []string{"CUSTOMER"},
+				Permissions:     []string{},
+			},
+		},
+		Total: 1,
+	}, got)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUsersPostgres_Anonymize(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	r := NewUsersPostgres(db)
+
+	timestamp := time.Date(2022, 07, 12, 13, 8, 21, 0, time.UTC)
+	userId := "34c8d3e6-b8d7-43dc-847e-5764c4114856"
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE users SET name = 'Deleted'")).
+		WithArgs(timestamp, userId).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM user_roles WHERE user_id = $1")).
+		WithArgs(userId).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE refresh_tokens SET revoked_at = $1 WHERE user_id = $2 AND revoked_at IS NULL")).
+		WithArgs(timestamp, userId).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	assert.NoError(t, r.Anonymize(userId, timestamp))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
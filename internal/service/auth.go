@@ -1,86 +1,479 @@
 package service
 
 import (
+	"crypto/rand"
 	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"time"
 
 	"github.com/AndrewMislyuk/go-shop-backend/internal/domain"
 	"github.com/AndrewMislyuk/go-shop-backend/internal/repository"
+	"github.com/AndrewMislyuk/go-shop-backend/pkg/auth"
+	"github.com/AndrewMislyuk/go-shop-backend/pkg/hash"
+	"github.com/AndrewMislyuk/go-shop-backend/pkg/mailer"
 	"github.com/golang-jwt/jwt"
 	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
 )
 
-const (
-	salt       = "superhashkey"
-	signingKey = "L6e2h3e6gfE4ae93AZMfPLRg782Y"
-	tokenTTL   = 12 * time.Hour
-)
+type AuthConfig struct {
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+	// LegacyPasswordSalt verifies hashes stored before argon2id was
+	// introduced. When empty, such accounts have to reset their password.
+	LegacyPasswordSalt   string
+	EmailVerificationTTL time.Duration
+	PasswordResetTTL     time.Duration
+	// RequireVerifiedEmail rejects sign-in until the email is verified.
+	RequireVerifiedEmail bool
+	// AppURL is the frontend address links in emails point to.
+	AppURL string
+}
 
+// tokenClaims only identify the user and the session. Everything else is
+// resolved from the store on every request, so that role changes and account
+// deletion take effect without waiting for the token to expire. Roles are
+// informational for other services, permissions are never taken from them.
 type tokenClaims struct {
 	jwt.StandardClaims
-	User domain.User `json:"data"`
+	Roles     []string `json:"roles"`
+	SessionId string   `json:"sid"`
 }
 
 type Auth struct {
-	repo repository.Authorization
+	repo       repository.Authorization
+	sessions   repository.Sessions
+	userTokens repository.UserTokens
+	hasher     hash.PasswordHasher
+	tokens     auth.TokenManager
+	mailer     mailer.Mailer
+	guard      *LoginGuard
+	cache      *userCache
+	config     AuthConfig
+	dummyHash  string
 }
 
-func NewAuthService(repo repository.Authorization) *Auth {
+func NewAuthService(repo repository.Authorization, sessions repository.Sessions, userTokens repository.UserTokens, hasher hash.PasswordHasher,
+	tokens auth.TokenManager, mailer mailer.Mailer, guard *LoginGuard, cache *userCache, config AuthConfig) *Auth {
+	// dummyHash is verified against when the email is unknown, so that
+	// sign-in takes the same time whether the account exists or not.
+	dummyHash, err := hasher.Hash(uuid.New().String())
+	if err != nil {
+		logrus.Errorf("failed to generate dummy password hash: %s", err.Error())
+	}
+
 	return &Auth{
-		repo: repo,
+		repo:       repo,
+		sessions:   sessions,
+		userTokens: userTokens,
+		hasher:     hasher,
+		tokens:     tokens,
+		mailer:     mailer,
+		guard:      guard,
+		cache:      cache,
+		config:     config,
+		dummyHash:  dummyHash,
 	}
 }
 
 func (a *Auth) CreateUser(user domain.UserSignUp) (string, error) {
-	user.Password = generatePasswordHash(user.Password)
+	if err := user.Normalize(); err != nil {
+		return "", err
+	}
+
+	passwordHash, err := a.hasher.Hash(user.Password)
+	if err != nil {
+		return "", err
+	}
+
+	user.Password = passwordHash
 	dataId := uuid.New().String()
 	timestamp := time.Now()
 
-	return a.repo.CreateUser(user, dataId, timestamp)
+	id, err := a.repo.CreateUser(user, dataId, domain.RoleCustomer, timestamp)
+	if err != nil {
+		return "", err
+	}
+
+	// The account is created either way, a lost email can be recovered
+	// through the password reset flow which verifies the email as well.
+	if err := a.sendVerificationEmail(id, user.Email); err != nil {
+		logrus.Errorf("sendVerificationEmail(): %s", err.Error())
+	}
+
+	return id, nil
 }
 
-func (a *Auth) GenerateToken(email, password string) (string, error) {
-	user, err := a.repo.GetUser(email, generatePasswordHash(password))
+// GenerateToken signs the user in. Failed attempts are counted per email and
+// per clientIP, guessing is answered with *domain.TooManyAttemptsError.
+func (a *Auth) GenerateToken(email, password, clientIP string) (domain.Tokens, error) {
+	email = domain.NormalizeEmail(email)
+
+	if err := a.guard.Check(email, clientIP); err != nil {
+		return domain.Tokens{}, err
+	}
+
+	user, err := a.repo.GetUserByEmail(email)
 	if err != nil {
-		return "", err
+		if errors.Is(err, domain.ErrUserNotFound) {
+			_, _ = a.hasher.Verify(password, a.dummyHash)
+			a.guard.Failure(email, clientIP)
+
+			return domain.Tokens{}, domain.ErrInvalidCredentials
+		}
+
+		return domain.Tokens{}, err
+	}
+
+	ok, err := a.verifyPassword(user, password)
+	if err != nil {
+		return domain.Tokens{}, err
+	}
+
+	if !ok {
+		a.guard.Failure(email, clientIP)
+
+		return domain.Tokens{}, domain.ErrInvalidCredentials
+	}
+
+	a.guard.Success(email)
+
+	if err := checkAccount(user); err != nil {
+		return domain.Tokens{}, err
+	}
+
+	if a.config.RequireVerifiedEmail && user.EmailVerifiedAt == nil {
+		return domain.Tokens{}, domain.ErrEmailNotVerified
+	}
+
+	return a.createSession(user, uuid.New().String())
+}
+
+// RefreshTokens exchanges a refresh token for a new token pair. Every refresh
+// token can be used only once: presenting an already used token means it has
+// leaked, so the whole session it belongs to is revoked.
+func (a *Auth) RefreshTokens(refreshToken string) (domain.Tokens, error) {
+	stored, err := a.sessions.GetRefreshToken(hashToken(refreshToken))
+	if err != nil {
+		if errors.Is(err, domain.ErrRefreshTokenNotFound) {
+			return domain.Tokens{}, domain.ErrInvalidRefreshToken
+		}
+
+		return domain.Tokens{}, err
+	}
+
+	if stored.RevokedAt != nil || time.Now().After(stored.ExpiresAt) {
+		return domain.Tokens{}, domain.ErrInvalidRefreshToken
+	}
+
+	if stored.UsedAt != nil {
+		return domain.Tokens{}, a.revokeReusedSession(stored)
+	}
+
+	user, err := a.repo.GetUserById(stored.UserId)
+	if err == nil {
+		err = checkAccount(user)
+	}
+
+	if errors.Is(err, domain.ErrUserNotFound) {
+		return domain.Tokens{}, domain.ErrInvalidRefreshToken
+	}
+
+	if err != nil {
+		return domain.Tokens{}, err
+	}
+
+	next, rawToken, err := a.newRefreshToken(user.Id, stored.SessionId)
+	if err != nil {
+		return domain.Tokens{}, err
+	}
+
+	if err := a.sessions.RotateRefreshToken(stored.Id, next); err != nil {
+		if errors.Is(err, domain.ErrRefreshTokenReused) {
+			return domain.Tokens{}, a.revokeReusedSession(stored)
+		}
+
+		return domain.Tokens{}, err
+	}
+
+	accessToken, err := a.generateAccessToken(user, stored.SessionId)
+	if err != nil {
+		return domain.Tokens{}, err
+	}
+
+	return domain.Tokens{
+		UserId:       user.Id,
+		AccessToken:  accessToken,
+		RefreshToken: rawToken,
+	}, nil
+}
+
+func (a *Auth) Logout(refreshToken string) error {
+	stored, err := a.sessions.GetRefreshToken(hashToken(refreshToken))
+	if err != nil {
+		if errors.Is(err, domain.ErrRefreshTokenNotFound) {
+			return domain.ErrInvalidRefreshToken
+		}
+
+		return err
+	}
+
+	return a.sessions.RevokeSession(stored.SessionId, time.Now())
+}
+
+func (a *Auth) LogoutAll(userId string) error {
+	return a.sessions.RevokeUserSessions(userId, time.Now())
+}
+
+func (a *Auth) GetMe(accessToken string) (domain.User, error) {
+	identity, err := a.Identify(accessToken)
+
+	return identity.User, err
+}
+
+// Identify resolves the access token to the user and the session it was
+// issued for. Revoked sessions, blocked and deleted accounts are rejected.
+func (a *Auth) Identify(accessToken string) (domain.Identity, error) {
+	claims := &tokenClaims{}
+	if err := a.tokens.Parse(accessToken, claims); err != nil {
+		return domain.Identity{}, fmt.Errorf("%w: %s", domain.ErrInvalidAccessToken, err.Error())
+	}
+
+	active, err := a.sessions.IsSessionActive(claims.SessionId, time.Now())
+	if err != nil {
+		return domain.Identity{}, err
+	}
+
+	if !active {
+		return domain.Identity{}, fmt.Errorf("%w: session has been revoked", domain.ErrInvalidAccessToken)
+	}
+
+	user, err := a.getUser(claims.Subject)
+	if err == nil {
+		err = checkAccount(user)
+	}
+
+	if errors.Is(err, domain.ErrUserNotFound) {
+		return domain.Identity{}, fmt.Errorf("%w: user not found", domain.ErrInvalidAccessToken)
+	}
+
+	if err != nil {
+		return domain.Identity{}, err
+	}
+
+	return domain.Identity{
+		User:      user,
+		SessionId: claims.SessionId,
+	}, nil
+}
+
+// ChangePassword replaces the password after checking the current one and
+// signs the user out everywhere except the session the change was made from.
+func (a *Auth) ChangePassword(userId, sessionId string, input domain.ChangePasswordInput) error {
+	user, err := a.repo.GetUserById(userId)
+	if err != nil {
+		return err
+	}
+
+	ok, err := a.verifyPassword(user, input.CurrentPassword)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return domain.ErrWrongPassword
+	}
+
+	passwordHash, err := a.hasher.Hash(input.NewPassword)
+	if err != nil {
+		return err
+	}
+
+	if err := a.repo.UpdatePasswordHash(userId, passwordHash); err != nil {
+		return err
+	}
+
+	a.cache.Delete(userId)
+
+	return a.sessions.RevokeOtherSessions(userId, sessionId, time.Now())
+}
+
+func (a *Auth) JWKS() auth.JSONWebKeySet {
+	return a.tokens.JWKS()
+}
+
+// DeleteExpiredLoginAttempts deletes the sign-in failures that no longer
+// count.
+func (a *Auth) DeleteExpiredLoginAttempts() (int, error) {
+	return a.guard.DeleteExpired()
+}
+
+func checkAccount(user domain.User) error {
+	if user.DeletedAt != nil {
+		return domain.ErrUserNotFound
+	}
+
+	if user.BlockedAt != nil {
+		return domain.ErrUserBlocked
+	}
+
+	return nil
+}
+
+func (a *Auth) getUser(userId string) (domain.User, error) {
+	if user, ok := a.cache.Get(userId); ok {
+		return user, nil
+	}
+
+	user, err := a.repo.GetUserById(userId)
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	a.cache.Set(user)
+
+	return user, nil
+}
+
+func (a *Auth) createSession(user domain.User, sessionId string) (domain.Tokens, error) {
+	token, rawToken, err := a.newRefreshToken(user.Id, sessionId)
+	if err != nil {
+		return domain.Tokens{}, err
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, &tokenClaims{
-		jwt.StandardClaims{
-			ExpiresAt: time.Now().Add(tokenTTL).Unix(),
+	if err := a.sessions.CreateRefreshToken(token); err != nil {
+		return domain.Tokens{}, err
+	}
+
+	accessToken, err := a.generateAccessToken(user, sessionId)
+	if err != nil {
+		return domain.Tokens{}, err
+	}
+
+	return domain.Tokens{
+		UserId:       user.Id,
+		AccessToken:  accessToken,
+		RefreshToken: rawToken,
+	}, nil
+}
+
+func (a *Auth) generateAccessToken(user domain.User, sessionId string) (string, error) {
+	return a.tokens.Sign(&tokenClaims{
+		StandardClaims: jwt.StandardClaims{
+			Subject:   user.Id,
+			ExpiresAt: time.Now().Add(a.config.AccessTokenTTL).Unix(),
 			IssuedAt:  time.Now().Unix(),
 		},
-		user,
+		Roles:     user.Roles,
+		SessionId: sessionId,
 	})
+}
+
+func (a *Auth) revokeReusedSession(token domain.RefreshToken) error {
+	logrus.WithFields(logrus.Fields{
+		"user_id":    token.UserId,
+		"session_id": token.SessionId,
+	}).Warn("refresh token reuse detected, revoking session")
 
-	return token.SignedString([]byte(signingKey))
+	if err := a.sessions.RevokeSession(token.SessionId, time.Now()); err != nil {
+		return err
+	}
+
+	return domain.ErrRefreshTokenReused
 }
 
-func (a *Auth) GetMe(accessToken string) (domain.User, error) {
-	token, err := jwt.ParseWithClaims(accessToken, &tokenClaims{}, func(t *jwt.Token) (interface{}, error) {
-		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
-			return domain.User{}, errors.New("invalid signing method")
+// newRefreshToken returns the record to persist and the opaque token handed
+// to the client. Only the sha256 of the token is stored.
+func (a *Auth) newRefreshToken(userId, sessionId string) (domain.RefreshToken, string, error) {
+	rawToken, err := randomToken()
+	if err != nil {
+		return domain.RefreshToken{}, "", err
+	}
+
+	timestamp := time.Now()
+
+	return domain.RefreshToken{
+		Id:        uuid.New().String(),
+		UserId:    userId,
+		SessionId: sessionId,
+		TokenHash: hashToken(rawToken),
+		ExpiresAt: timestamp.Add(a.config.RefreshTokenTTL),
+		CreatedAt: timestamp,
+	}, rawToken, nil
+}
+
+// randomToken returns 256 random bits encoded for use in URLs.
+func randomToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+
+	return hex.EncodeToString(sum[:])
+}
+
+// verifyPassword checks the password against the stored hash and upgrades
+// legacy or outdated hashes once the password is known to be correct.
+func (a *Auth) verifyPassword(user domain.User, password string) (bool, error) {
+	if !hash.IsArgon2idHash(user.Password) {
+		if !a.verifyLegacyPasswordHash(password, user.Password) {
+			return false, nil
 		}
 
-		return []byte(signingKey), nil
-	})
-	if err != nil {
-		return domain.User{}, err
+		a.rehashPassword(user.Id, password)
+
+		return true, nil
 	}
 
-	claims, ok := token.Claims.(*tokenClaims)
-	if !ok {
-		return domain.User{}, errors.New("token claims are not of type *tokenClaims")
+	ok, err := a.hasher.Verify(password, user.Password)
+	if err != nil || !ok {
+		return false, err
+	}
+
+	if a.hasher.NeedsRehash(user.Password) {
+		a.rehashPassword(user.Id, password)
 	}
 
-	return claims.User, nil
+	return true, nil
 }
 
-func generatePasswordHash(password string) string {
+func (a *Auth) rehashPassword(userId, password string) {
+	passwordHash, err := a.hasher.Hash(password)
+	if err != nil {
+		logrus.Errorf("rehashPassword(): %s", err.Error())
+
+		return
+	}
+
+	if err := a.repo.UpdatePasswordHash(userId, passwordHash); err != nil {
+		logrus.Errorf("rehashPassword(): %s", err.Error())
+	}
+}
+
+// legacyPasswordHash reproduces the SHA-1 scheme used before argon2id.
+// It is only used to verify and upgrade hashes that were stored with it.
+func legacyPasswordHash(password, salt string) string {
 	hash := sha1.New()
 	hash.Write([]byte(password))
 
 	return fmt.Sprintf("%x", hash.Sum([]byte(salt)))
 }
+
+func (a *Auth) verifyLegacyPasswordHash(password, passwordHash string) bool {
+	if a.config.LegacyPasswordSalt == "" {
+		return false
+	}
+
+	return subtle.ConstantTimeCompare([]byte(legacyPasswordHash(password, a.config.LegacyPasswordSalt)), []byte(passwordHash)) == 1
+}
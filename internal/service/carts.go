@@ -0,0 +1,261 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"github.com/AndrewMislyuk/go-shop-backend/internal/domain"
+	"github.com/AndrewMislyuk/go-shop-backend/internal/repository"
+	"github.com/google/uuid"
+)
+
+const defaultGuestCartTTL = 30 * 24 * time.Hour
+
+type CartsService struct {
+	repo     repository.Carts
+	products repository.ProductsList
+	variants repository.ProductVariants
+	currency string
+	guestTTL time.Duration
+}
+
+// NewCartsService keeps the carts in currency, the base currency the products
+// are priced in.
+func NewCartsService(repo repository.Carts, products repository.ProductsList, variants repository.ProductVariants, currency string, guestTTL time.Duration) *CartsService {
+	if guestTTL == 0 {
+		guestTTL = defaultGuestCartTTL
+	}
+
+	return &CartsService{
+		repo:     repo,
+		products: products,
+		variants: variants,
+		currency: currency,
+		guestTTL: guestTTL,
+	}
+}
+
+// Get returns the cart of the owner with the current prices. An owner without
+// a cart, or with an expired guest cart, gets an empty one.
+func (s *CartsService) Get(owner domain.CartOwner) (domain.Cart, error) {
+	cart, err := s.find(owner)
+	if err != nil {
+		if errors.Is(err, domain.ErrCartNotFound) {
+			return domain.Cart{Items: make([]domain.CartItem, 0), Total: domain.NewMoney(0, s.currency)}, nil
+		}
+
+		return cart, err
+	}
+
+	return s.load(cart)
+}
+
+// AddItem adds the product to the cart, creating the cart on first use. The
+// token of a new guest cart is returned in the cart once.
+func (s *CartsService) AddItem(owner domain.CartOwner, input domain.AddCartItemInput) (domain.Cart, error) {
+	price, err := s.unitPrice(input.ProductId, input.VariantId)
+	if err != nil {
+		return domain.Cart{}, err
+	}
+
+	cart, err := s.find(owner)
+	if errors.Is(err, domain.ErrCartNotFound) {
+		cart, err = s.create(owner)
+	}
+	if err != nil {
+		return domain.Cart{}, err
+	}
+
+	timestamp := time.Now()
+	if err := s.repo.AddItem(domain.CartItem{
+		Id:        uuid.New().String(),
+		CartId:    cart.Id,
+		ProductId: input.ProductId,
+		VariantId: input.VariantId,
+		Quantity:  input.Quantity,
+		SeenPrice: price,
+		CreatedAt: timestamp,
+	}, domain.MaxCartItemQuantity); err != nil {
+		return domain.Cart{}, err
+	}
+
+	return s.touchAndLoad(cart, timestamp)
+}
+
+func (s *CartsService) UpdateItem(owner domain.CartOwner, itemId string, input domain.UpdateCartItemInput) (domain.Cart, error) {
+	cart, err := s.findForItem(owner)
+	if err != nil {
+		return cart, err
+	}
+
+	timestamp := time.Now()
+	if err := s.repo.UpdateItemQuantity(cart.Id, itemId, input.Quantity, timestamp); err != nil {
+		return domain.Cart{}, err
+	}
+
+	return s.touchAndLoad(cart, timestamp)
+}
+
+func (s *CartsService) RemoveItem(owner domain.CartOwner, itemId string) (domain.Cart, error) {
+	cart, err := s.findForItem(owner)
+	if err != nil {
+		return cart, err
+	}
+
+	if err := s.repo.RemoveItem(cart.Id, itemId); err != nil {
+		return domain.Cart{}, err
+	}
+
+	return s.touchAndLoad(cart, time.Now())
+}
+
+// Merge moves the guest cart into the cart of the user who has just signed
+// in. Unknown or expired tokens are ignored.
+func (s *CartsService) Merge(userId, token string) error {
+	guest, err := s.repo.GetByToken(hashToken(token), time.Now())
+	if err != nil {
+		if errors.Is(err, domain.ErrCartNotFound) {
+			return nil
+		}
+
+		return err
+	}
+
+	timestamp := time.Now()
+
+	cart, err := s.repo.GetByUser(userId)
+	if errors.Is(err, domain.ErrCartNotFound) {
+		return s.repo.AssignToUser(guest.Id, userId, timestamp)
+	}
+	if err != nil {
+		return err
+	}
+
+	return s.repo.Merge(guest.Id, cart.Id, domain.MaxCartItemQuantity, timestamp)
+}
+
+func (s *CartsService) DeleteExpired() (int, error) {
+	return s.repo.DeleteExpired(time.Now())
+}
+
+func (s *CartsService) find(owner domain.CartOwner) (domain.Cart, error) {
+	switch {
+	case owner.UserId != "":
+		return s.repo.GetByUser(owner.UserId)
+	case owner.Token != "":
+		return s.repo.GetByToken(hashToken(owner.Token), time.Now())
+	default:
+		return domain.Cart{}, domain.ErrCartNotFound
+	}
+}
+
+// findForItem reports a missing cart as a missing item, there is nothing to
+// change in a cart that doesn't exist.
+func (s *CartsService) findForItem(owner domain.CartOwner) (domain.Cart, error) {
+	cart, err := s.find(owner)
+	if errors.Is(err, domain.ErrCartNotFound) {
+		return cart, domain.ErrCartItemNotFound
+	}
+
+	return cart, err
+}
+
+func (s *CartsService) create(owner domain.CartOwner) (domain.Cart, error) {
+	timestamp := time.Now()
+	cart := domain.Cart{
+		Id:        uuid.New().String(),
+		CreatedAt: timestamp,
+		UpdatedAt: timestamp,
+	}
+
+	if owner.UserId != "" {
+		cart.UserId = &owner.UserId
+	} else {
+		token, err := randomToken()
+		if err != nil {
+			return cart, err
+		}
+
+		expiresAt := timestamp.Add(s.guestTTL)
+		cart.Token = token
+		cart.TokenHash = hashToken(token)
+		cart.ExpiresAt = &expiresAt
+	}
+
+	return cart, s.repo.Create(cart)
+}
+
+func (s *CartsService) touchAndLoad(cart domain.Cart, timestamp time.Time) (domain.Cart, error) {
+	expiresAt := timestamp.Add(s.guestTTL)
+	if err := s.repo.Touch(cart.Id, timestamp, expiresAt); err != nil {
+		return domain.Cart{}, err
+	}
+
+	cart.UpdatedAt = timestamp
+	if cart.UserId == nil {
+		cart.ExpiresAt = &expiresAt
+	}
+
+	return s.load(cart)
+}
+
+// load fills the items and totals of the cart. Prices are re-read on every
+// load, changed ones are flagged once and then remembered as seen.
+func (s *CartsService) load(cart domain.Cart) (domain.Cart, error) {
+	items, err := s.repo.GetItems(cart.Id)
+	if err != nil {
+		return domain.Cart{}, err
+	}
+
+	cart.Items = items
+	cart.Total = domain.NewMoney(0, s.currency)
+	cart.ItemsCount = 0
+
+	for i := range cart.Items {
+		item := &cart.Items[i]
+		item.PriceChanged = item.UnitPrice != item.SeenPrice
+		item.LineTotal = item.UnitPrice.Mul(item.Quantity)
+
+		cart.PriceChanged = cart.PriceChanged || item.PriceChanged
+		cart.Total = cart.Total.Add(item.LineTotal)
+		cart.ItemsCount += item.Quantity
+	}
+
+	if cart.PriceChanged {
+		if err := s.repo.AcceptPrices(cart.Id, time.Now()); err != nil {
+			return domain.Cart{}, err
+		}
+	}
+
+	return cart, nil
+}
+
+// unitPrice checks that the product, or its variant, can be put into a cart
+// and returns its current price.
+func (s *CartsService) unitPrice(productId string, variantId *string) (domain.Money, error) {
+	product, err := s.products.GetById(productId)
+	if err != nil {
+		return domain.Money{}, err
+	}
+
+	variants, err := s.variants.GetByProduct(productId)
+	if err != nil {
+		return domain.Money{}, err
+	}
+
+	if variantId == nil {
+		if len(variants) != 0 {
+			return domain.Money{}, domain.ErrVariantRequired
+		}
+
+		return product.Price, nil
+	}
+
+	for _, variant := range variants {
+		if variant.Id == *variantId {
+			return variant.SalePrice, nil
+		}
+	}
+
+	return domain.Money{}, domain.ErrVariantNotFound
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"time"
+
+	"github.com/AndrewMislyuk/go-shop-backend/internal/domain"
+	"github.com/AndrewMislyuk/go-shop-backend/internal/repository"
+	"github.com/google/uuid"
+)
+
+type CategoriesService struct {
+	repo repository.Categories
+}
+
+func NewCategoriesService(repo repository.Categories) *CategoriesService {
+	return &CategoriesService{
+		repo: repo,
+	}
+}
+
+func (s *CategoriesService) Create(input domain.CreateCategoryInput) (string, error) {
+	if err := input.Validate(); err != nil {
+		return "", err
+	}
+
+	category := domain.Category{
+		Id:        uuid.New().String(),
+		ParentId:  input.ParentId,
+		Level:     domain.CategoryLevelCategory,
+		Slug:      input.Slug,
+		Names:     input.Names,
+		Position:  input.Position,
+		CreatedAt: time.Now(),
+	}
+
+	if input.ParentId != nil {
+		parent, err := s.repo.GetById(*input.ParentId)
+		if err != nil {
+			return "", err
+		}
+
+		if parent.Level == domain.CategoryLevelSubtype {
+			return "", domain.ErrCategoryTooDeep
+		}
+
+		category.Level = parent.Level + 1
+	}
+
+	if err := s.repo.Create(category); err != nil {
+		return "", err
+	}
+
+	return category.Id, nil
+}
+
+func (s *CategoriesService) GetById(categoryId string) (domain.Category, error) {
+	return s.repo.GetById(categoryId)
+}
+
+// Tree returns the top-level categories with their types and subtypes.
+func (s *CategoriesService) Tree() ([]domain.CategoryNode, error) {
+	nodes, err := s.repo.GetAllWithCounts()
+	if err != nil {
+		return nil, err
+	}
+
+	return buildCategoryTree(nodes), nil
+}
+
+func (s *CategoriesService) Update(categoryId string, input domain.UpdateCategoryInput) error {
+	if err := input.Validate(); err != nil {
+		return err
+	}
+
+	return s.repo.Update(categoryId, input, time.Now())
+}
+
+func (s *CategoriesService) Delete(categoryId string) error {
+	return s.repo.Delete(categoryId)
+}
+
+// buildCategoryTree nests the flat list keeping its order and sums product
+// counts up the tree.
+func buildCategoryTree(nodes []domain.CategoryNode) []domain.CategoryNode {
+	children := make(map[string][]domain.CategoryNode)
+	roots := make([]domain.CategoryNode, 0)
+
+	for _, node := range nodes {
+		if node.ParentId == nil {
+			roots = append(roots, node)
+		} else {
+			children[*node.ParentId] = append(children[*node.ParentId], node)
+		}
+	}
+
+	var attach func(node domain.CategoryNode) domain.CategoryNode
+	attach = func(node domain.CategoryNode) domain.CategoryNode {
+		node.Children = make([]domain.CategoryNode, 0, len(children[node.Id]))
+
+		for _, child := range children[node.Id] {
+			child = attach(child)
+			node.ProductsCount += child.ProductsCount
+			node.Children = append(node.Children, child)
+		}
+
+		return node
+	}
+
+	for i := range roots {
+		roots[i] = attach(roots[i])
+	}
+
+	return roots
+}
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"time"
+
+	"github.com/AndrewMislyuk/go-shop-backend/internal/domain"
+	"github.com/AndrewMislyuk/go-shop-backend/internal/repository"
+)
+
+type CurrenciesService struct {
+	repo repository.ExchangeRates
+	base string
+}
+
+// NewCurrenciesService keeps the exchange rates against base, the currency
+// the products are priced in.
+func NewCurrenciesService(repo repository.ExchangeRates, base string) *CurrenciesService {
+	return &CurrenciesService{
+		repo: repo,
+		base: base,
+	}
+}
+
+func (s *CurrenciesService) GetRates() (domain.ExchangeRates, error) {
+	rates, err := s.repo.GetAll()
+	if err != nil {
+		return domain.ExchangeRates{}, err
+	}
+
+	return domain.ExchangeRates{
+		Base:  s.base,
+		Rates: rates,
+	}, nil
+}
+
+// SetRates validates all the rates before any of them is stored.
+func (s *CurrenciesService) SetRates(input domain.SetExchangeRatesInput) error {
+	timestamp := time.Now()
+	rates := make([]domain.ExchangeRate, 0, len(input.Rates))
+
+	for currency, value := range input.Rates {
+		rate := domain.ExchangeRate{
+			Currency:  domain.NormalizeCurrency(currency),
+			Rate:      value,
+			UpdatedAt: timestamp,
+		}
+
+		if rate.Currency == s.base {
+			return domain.ErrBaseCurrency
+		}
+
+		if err := rate.Validate(); err != nil {
+			return err
+		}
+
+		rates = append(rates, rate)
+	}
+
+	return s.repo.Set(rates)
+}
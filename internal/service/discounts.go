@@ -0,0 +1,90 @@
+package service
+
+import (
+	"time"
+
+	"github.com/AndrewMislyuk/go-shop-backend/internal/domain"
+	"github.com/AndrewMislyuk/go-shop-backend/internal/repository"
+	"github.com/google/uuid"
+)
+
+const defaultDiscountsLimit = 20
+
+type DiscountsService struct {
+	repo repository.Discounts
+}
+
+func NewDiscountsService(repo repository.Discounts) *DiscountsService {
+	return &DiscountsService{
+		repo: repo,
+	}
+}
+
+// Create starts the sale at once unless it is scheduled for later.
+func (s *DiscountsService) Create(input domain.CreateDiscountInput) (string, error) {
+	timestamp := time.Now()
+	discount := domain.Discount{
+		Id:         uuid.New().String(),
+		Name:       input.Name,
+		Kind:       input.Kind,
+		Value:      input.Value,
+		ProductId:  input.ProductId,
+		CategoryId: input.CategoryId,
+		StartsAt:   timestamp,
+		EndsAt:     input.EndsAt,
+		Active:     true,
+		CreatedAt:  timestamp,
+	}
+
+	if input.StartsAt != nil {
+		discount.StartsAt = *input.StartsAt
+	}
+
+	if err := discount.Validate(); err != nil {
+		return "", err
+	}
+
+	if err := s.repo.Create(discount); err != nil {
+		return "", err
+	}
+
+	return discount.Id, nil
+}
+
+func (s *DiscountsService) GetById(discountId string) (domain.Discount, error) {
+	return s.repo.GetById(discountId)
+}
+
+func (s *DiscountsService) GetAll(filter domain.DiscountsFilter) (domain.DiscountsList, error) {
+	if filter.Limit == 0 {
+		filter.Limit = defaultDiscountsLimit
+	}
+
+	return s.repo.GetAll(filter)
+}
+
+func (s *DiscountsService) Update(discountId string, input domain.UpdateDiscountInput) error {
+	discount, err := s.repo.GetById(discountId)
+	if err != nil {
+		return err
+	}
+
+	if err := input.Apply(&discount); err != nil {
+		return err
+	}
+
+	timestamp := time.Now()
+	discount.UpdatedAt = &timestamp
+
+	return s.repo.Update(discount)
+}
+
+func (s *DiscountsService) Delete(discountId string) error {
+	return s.repo.Delete(discountId)
+}
+
+// RefreshSales applies the sales that have started or ended to the prices of
+// the products.
+func (s *DiscountsService) RefreshSales() (int, error) {
+	return s.repo.RefreshSales()
+}
@@ -1,14 +1,22 @@
 package service
 
 import (
+	"bufio"
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"io"
+	"net/http"
 	"os"
-	"strings"
+	"sync"
 	"time"
 
 	"github.com/AndrewMislyuk/go-shop-backend/internal/domain"
 	"github.com/AndrewMislyuk/go-shop-backend/internal/repository"
+	"github.com/AndrewMislyuk/go-shop-backend/pkg/imaging"
 	"github.com/AndrewMislyuk/go-shop-backend/pkg/storage"
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
@@ -18,98 +26,643 @@ var folders = map[domain.FileType]string{
 	domain.Image: "images",
 }
 
+// errUploadInterrupted is recorded for the uploads whose worker is gone,
+// e.g. after a restart.
+const errUploadInterrupted = "upload interrupted"
+
+// maxFileErrorLength is the size of the error column of the files.
+const maxFileErrorLength = 255
+
+// sniffLength is how much http.DetectContentType looks at.
+const sniffLength = 512
+
+const (
+	defaultUploadURLTTL   = 15 * time.Minute
+	defaultDownloadURLTTL = time.Hour
+)
+
+// UploadQueueConfig configures the workers pushing the uploaded images to
+// the storage.
+type UploadQueueConfig struct {
+	// Workers push the images in parallel, QueueSize uploads may wait for
+	// them, the next ones are refused.
+	Workers   int
+	QueueSize int
+	// MaxAttempts is how many times the storage is tried before the upload
+	// fails. The pause between the attempts starts at Backoff and doubles
+	// up to MaxBackoff.
+	MaxAttempts int
+	Backoff     time.Duration
+	MaxBackoff  time.Duration
+	// StaleAfter fails the uploads not moved for that long, their worker is
+	// gone.
+	StaleAfter time.Duration
+}
+
+// DirectUploadConfig configures the uploads the clients send to the storage
+// themselves.
+type DirectUploadConfig struct {
+	// MaxSize bounds the declared size of the files, zero leaves it
+	// unbounded.
+	MaxSize int64
+	// UploadURLTTL and DownloadURLTTL are how long the presigned URLs are
+	// valid. The uploads not completed StaleAfter past UploadURLTTL fail.
+	UploadURLTTL   time.Duration
+	DownloadURLTTL time.Duration
+}
+
 type FileService struct {
-	repo    repository.Files
-	storage storage.Provider
+	repo      repository.Files
+	products  repository.ProductsList
+	storage   storage.Provider
+	processor *imaging.Processor
+	config    UploadQueueConfig
+	direct    DirectUploadConfig
+	jobs      chan domain.File
 }
 
-func NewFileService(repo repository.Files, storage storage.Provider) *FileService {
+func NewFileService(repo repository.Files, products repository.ProductsList, storage storage.Provider, processor *imaging.Processor, config UploadQueueConfig, direct DirectUploadConfig) *FileService {
+	if config.Workers <= 0 {
+		config.Workers = 1
+	}
+
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = 1
+	}
+
+	if direct.UploadURLTTL <= 0 {
+		direct.UploadURLTTL = defaultUploadURLTTL
+	}
+
+	if direct.DownloadURLTTL <= 0 {
+		direct.DownloadURLTTL = defaultDownloadURLTTL
+	}
+
 	return &FileService{
-		repo:    repo,
-		storage: storage,
+		repo:      repo,
+		products:  products,
+		storage:   storage,
+		processor: processor,
+		config:    config,
+		direct:    direct,
+		jobs:      make(chan domain.File, config.QueueSize),
+	}
+}
+
+// Upload records the image and queues it for the workers started by
+// RunUploads, which add it to the gallery of its product once it is stored.
+// The returned file is pending, its status is polled with GetFile.
+func (f *FileService) Upload(file domain.File) (domain.File, error) {
+	if file.ProductId == "" {
+		removeFile(file.Name)
+
+		return file, domain.ErrProductIdRequired
+	}
+
+	if err := file.Validate(); err != nil {
+		removeFile(file.Name)
+
+		return file, err
+	}
+
+	if _, err := imaging.FormatOf(file.ContentType); err != nil {
+		removeFile(file.Name)
+
+		return file, domain.ErrInvalidImage
+	}
+
+	now := time.Now()
+	file.ID = uuid.New().String()
+	file.Status = domain.UploadedByClient
+	file.UploadStartedAt = now
+	file.CreatedAt = now
+
+	created, err := f.repo.Create(file)
+	if err != nil {
+		removeFile(file.Name)
+
+		return file, err
+	}
+
+	created.Name = file.Name
+	if err := f.enqueue(created); err != nil {
+		return file, err
+	}
+
+	created.Name = ""
+
+	return created, nil
+}
+
+// Stage writes the image read from r to a unique temporary file, sniffing
+// its type from the first bytes and hashing it on the way. The image is
+// stored after the response, so the staged file is removed by Upload once it
+// is done with it, or by DiscardStaged when it won't be uploaded.
+func (f *FileService) Stage(r io.Reader) (domain.File, error) {
+	buffered := bufio.NewReaderSize(r, sniffLength)
+
+	head, err := buffered.Peek(sniffLength)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return domain.File{}, readError(err)
+	}
+
+	contentType := http.DetectContentType(head)
+	if _, err := imaging.FormatOf(contentType); err != nil {
+		return domain.File{}, domain.ErrUnsupportedFileType
+	}
+
+	tmp, err := os.CreateTemp("", "upload-*")
+	if err != nil {
+		return domain.File{}, fmt.Errorf("failed to create temp file: %w", err)
+	}
+
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(tmp, hash), buffered)
+	if closeErr := tmp.Close(); err == nil && closeErr != nil {
+		err = fmt.Errorf("failed to write temp file: %w", closeErr)
+	} else if err != nil {
+		err = readError(err)
+	}
+
+	if err != nil {
+		removeFile(tmp.Name())
+
+		return domain.File{}, err
 	}
+
+	return domain.File{
+		Type:        domain.Image,
+		ContentType: contentType,
+		Name:        tmp.Name(),
+		Size:        size,
+		Checksum:    hex.EncodeToString(hash.Sum(nil)),
+	}, nil
 }
 
-func (f *FileService) Save(file domain.File) error {
-	return f.repo.Create(file)
+// readError reports a failed read of an uploaded file, one cut off by
+// http.MaxBytesReader is too large.
+func readError(err error) error {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return domain.ErrFileTooLarge
+	}
+
+	return domain.NewValidationError(err)
 }
 
-func (f *FileService) GetProductImage(productId string) (string, error) {
-	return f.repo.GetProductImage(productId)
+// DiscardStaged removes the file staged by Stage when the request fails
+// before it is handed to Upload.
+func (f *FileService) DiscardStaged(file domain.File) {
+	removeFile(file.Name)
 }
 
-func (f *FileService) Upload(file domain.File) (string, error) {
-	defer removeFile(file.Name)
+// CreateUploadURL records the image a client uploads to the storage itself
+// and presigns its upload. The client completes it with CompleteUpload.
+func (f *FileService) CreateUploadURL(input domain.CreateUploadURLInput) (domain.UploadURL, error) {
+	if _, err := imaging.FormatOf(input.ContentType); err != nil {
+		return domain.UploadURL{}, domain.ErrUnsupportedFileType
+	}
 
-	file.UploadStartedAt = time.Now()
-	file.ID = uuid.New().String()
+	if f.direct.MaxSize > 0 && input.Size > f.direct.MaxSize {
+		return domain.UploadURL{}, domain.ErrFileTooLarge
+	}
 
-	c := make(chan string)
-	go f.waitForUpload(file, c)
-	url := <-c
+	now := time.Now()
+	file := domain.File{
+		ID:              uuid.New().String(),
+		ProductId:       input.ProductId,
+		Type:            domain.Image,
+		ContentType:     input.ContentType,
+		Size:            input.Size,
+		Status:          domain.ClientUploadInProgress,
+		AltText:         input.AltText,
+		UploadStartedAt: now,
+		CreatedAt:       now,
+	}
+	file.ObjectName = f.generateUploadName(file)
 
-	file.URL = url
+	if err := file.Validate(); err != nil {
+		return domain.UploadURL{}, err
+	}
 
-	productImage, err := f.GetProductImage(file.ProductId)
+	created, err := f.repo.Create(file)
 	if err != nil {
-		return "", err
+		return domain.UploadURL{}, err
 	}
 
-	if productImage != "" {
-		filename := f.parseImageURL(productImage)
+	url, err := f.storage.PresignedPutURL(context.Background(), storage.PresignInput{
+		Name:        created.ObjectName,
+		ContentType: created.ContentType,
+		Expires:     f.direct.UploadURLTTL,
+	})
+	if err != nil {
+		f.fail(created, domain.ClientUploadInProgress, err)
 
-		err := f.storage.Delete(context.Background(), filename)
-		if err != nil {
-			return "", err
+		return domain.UploadURL{}, err
+	}
+
+	return domain.UploadURL{
+		File:      created,
+		URL:       url,
+		Method:    http.MethodPut,
+		Headers:   map[string]string{"Content-Type": created.ContentType},
+		ExpiresAt: now.Add(f.direct.UploadURLTTL),
+	}, nil
+}
+
+// CompleteUpload checks the file the client has uploaded against the one it
+// has declared and queues it for the workers, like Upload. A mismatching
+// file is deleted and its upload fails.
+func (f *FileService) CompleteUpload(fileId string) (domain.File, error) {
+	file, err := f.repo.GetById(fileId)
+	if err != nil {
+		return file, err
+	}
+
+	if file.Status != domain.ClientUploadInProgress {
+		return file, domain.ErrFileStatusChanged
+	}
+
+	info, err := f.storage.Stat(context.Background(), file.ObjectName)
+	if errors.Is(err, storage.ErrObjectNotFound) {
+		return file, domain.ErrFileNotUploaded
+	}
+	if err != nil {
+		return file, err
+	}
+
+	if info.Size != file.Size || info.ContentType != file.ContentType {
+		f.discard(file)
+		f.fail(file, domain.ClientUploadInProgress, fmt.Errorf("uploaded %d bytes of %s", info.Size, info.ContentType))
+
+		return file, domain.ErrFileMismatch
+	}
+
+	if err := f.setStatus(&file, domain.ClientUploadInProgress, domain.UploadedByClient); err != nil {
+		return file, err
+	}
+
+	if err := f.enqueue(file); err != nil {
+		return file, err
+	}
+
+	return file, nil
+}
+
+// GetDownloadURL presigns the download of the original rendition of a
+// stored file.
+func (f *FileService) GetDownloadURL(fileId string) (domain.DownloadURL, error) {
+	file, err := f.repo.GetById(fileId)
+	if err != nil {
+		return domain.DownloadURL{}, err
+	}
+
+	if file.Status != domain.UploadedToStorage {
+		return domain.DownloadURL{}, domain.ErrFileNotStored
+	}
+
+	expiresAt := time.Now().Add(f.direct.DownloadURLTTL)
+
+	url, err := f.storage.PresignedGetURL(context.Background(), file.ObjectName, f.direct.DownloadURLTTL)
+	if err != nil {
+		return domain.DownloadURL{}, err
+	}
+
+	return domain.DownloadURL{
+		URL:       url,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// GetFile returns the file with the status of its upload.
+func (f *FileService) GetFile(fileId string) (domain.File, error) {
+	return f.repo.GetById(fileId)
+}
+
+// RunUploads pushes the queued images to the storage until stop is closed.
+func (f *FileService) RunUploads(stop <-chan struct{}) {
+	var wg sync.WaitGroup
+
+	for i := 0; i < f.config.Workers; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for {
+				select {
+				case file := <-f.jobs:
+					f.store(file, stop)
+				case <-stop:
+					return
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	// The images still queued won't be stored by this run.
+	for {
+		select {
+		case file := <-f.jobs:
+			f.discard(file)
+			f.fail(file, domain.UploadedByClient, errors.New(errUploadInterrupted))
+		default:
+			return
 		}
 	}
+}
 
-	if err := f.Save(file); err != nil {
-		return "", err
+// FailStaleUploads fails the uploads left behind by the workers of a
+// previous run and the ones the clients never completed, deleting what they
+// have left in the storage.
+func (f *FileService) FailStaleUploads() (int, error) {
+	if f.config.StaleAfter <= 0 {
+		return 0, nil
 	}
 
-	return url, nil
+	before := time.Now().Add(-f.config.StaleAfter)
+
+	names, err := f.repo.FailStale(before, before.Add(-f.direct.UploadURLTTL), errUploadInterrupted)
+	if err != nil {
+		return 0, err
+	}
+
+	f.deleteObjects(names)
+
+	return len(names), nil
 }
 
-func (f *FileService) waitForUpload(file domain.File, c chan string) {
-	url, err := f.upload(file)
+func (f *FileService) GetImages(productId string) ([]domain.File, error) {
+	if _, err := f.products.GetById(productId); err != nil {
+		return nil, err
+	}
+
+	return f.repo.GetByProduct(productId)
+}
+
+func (f *FileService) UpdateImage(productId, imageId string, input domain.UpdateImageInput) error {
+	return f.repo.Update(productId, imageId, input)
+}
+
+func (f *FileService) ReorderImages(productId string, input domain.ReorderImagesInput) error {
+	if err := input.Validate(); err != nil {
+		return err
+	}
+
+	return f.repo.Reorder(productId, input.Ids)
+}
+
+func (f *FileService) SetPrimaryImage(productId, imageId string) error {
+	return f.repo.SetPrimary(productId, imageId)
+}
+
+// DeleteImage removes the image from the gallery first, so a failure of the
+// storage leaves an orphaned object rather than a broken image. Images still
+// uploading are removed too, their worker deletes what it has stored.
+func (f *FileService) DeleteImage(productId, imageId string) error {
+	image, err := f.repo.GetById(imageId)
 	if err != nil {
-		logrus.Fatal(err)
+		return err
+	}
+
+	if image.ProductId != productId {
+		return domain.ErrImageNotFound
+	}
+
+	if err := f.repo.Delete(productId, imageId); err != nil {
+		return err
 	}
 
-	c <- url
+	f.deleteObjects(image.ObjectNames())
+
+	return nil
 }
 
-func (f *FileService) upload(file domain.File) (string, error) {
-	fileData, err := os.Open(file.Name)
+func (f *FileService) process(file domain.File) ([]imaging.Output, error) {
+	format, err := imaging.FormatOf(file.ContentType)
 	if err != nil {
-		return "", err
+		return nil, domain.ErrInvalidImage
 	}
 
-	info, _ := fileData.Stat()
-	logrus.Infof("file info: %+v", info)
+	fileData, err := f.open(file)
+	if err != nil {
+		return nil, err
+	}
 
 	defer fileData.Close()
 
-	return f.storage.Upload(context.Background(), storage.UploadInput{
-		File:        fileData,
-		Size:        file.Size,
-		ContentType: file.ContentType,
-		Name:        f.generateFilename(file),
-	})
+	outputs, err := f.processor.Process(fileData, format)
+	if errors.Is(err, imaging.ErrInvalidImage) {
+		return nil, domain.ErrInvalidImage
+	}
+
+	return outputs, err
 }
 
-func (s *FileService) generateFilename(file domain.File) string {
-	filename := fmt.Sprintf("%s.%s", uuid.New().String(), file.Name)
-	folder := folders[file.Type]
+// open reads the file uploaded through the API from its temporary file, the
+// one uploaded by the client from the storage.
+func (f *FileService) open(file domain.File) (io.ReadCloser, error) {
+	if file.Name != "" {
+		return os.Open(file.Name)
+	}
+
+	return f.storage.Open(context.Background(), file.ObjectName)
+}
 
-	return fmt.Sprintf("%s/%s", folder, filename)
+// enqueue hands the file to the workers, its upload fails when the queue is
+// full.
+func (f *FileService) enqueue(file domain.File) error {
+	select {
+	case f.jobs <- file:
+		return nil
+	default:
+		f.discard(file)
+		f.fail(file, domain.UploadedByClient, domain.ErrUploadQueueFull)
+
+		return domain.ErrUploadQueueFull
+	}
 }
 
-func (s *FileService) parseImageURL(url string) string {
-	str := strings.Split(url, "/")
+// store processes the image and pushes its renditions to the storage,
+// retrying with backoff, then adds it to the gallery. Invalid images aren't
+// retried.
+func (f *FileService) store(file domain.File, stop <-chan struct{}) {
+	defer f.discard(file)
+
+	if err := f.setStatus(&file, domain.UploadedByClient, domain.StorageUploadInProgress); err != nil {
+		logrus.Errorf("store(): file %s: %s", file.ID, err.Error())
+
+		return
+	}
+
+	var outputs []imaging.Output
+
+	backoff := f.config.Backoff
+	for {
+		file.Attempts++
+
+		var err error
+		if outputs == nil {
+			outputs, err = f.process(file)
+		}
+
+		if err == nil {
+			file.Renditions, err = f.upload(file, outputs)
+		}
+
+		if err == nil {
+			break
+		}
+
+		if errors.Is(err, domain.ErrInvalidImage) || file.Attempts >= f.config.MaxAttempts {
+			f.fail(file, domain.StorageUploadInProgress, err)
+
+			return
+		}
+
+		logrus.Errorf("store(): file %s, attempt %d: %s", file.ID, file.Attempts, err.Error())
+
+		file.Error = truncate(err.Error(), maxFileErrorLength)
+		if err := f.setStatus(&file, domain.StorageUploadInProgress, domain.StorageUploadInProgress); err != nil {
+			logrus.Errorf("store(): file %s: %s", file.ID, err.Error())
+
+			return
+		}
+
+		select {
+		case <-time.After(backoff):
+		case <-stop:
+			f.fail(file, domain.StorageUploadInProgress, errors.New(errUploadInterrupted))
+
+			return
+		}
+
+		backoff *= 2
+		if f.config.MaxBackoff > 0 && backoff > f.config.MaxBackoff {
+			backoff = f.config.MaxBackoff
+		}
+	}
+
+	original := file.Renditions[0]
+	file.ObjectName = original.ObjectName
+	file.URL = original.URL
+	now := time.Now()
+	file.UpdatedAt = &now
+
+	if err := f.repo.Complete(file); err != nil {
+		// The image is deleted or the product is gone, nothing refers to
+		// the stored objects anymore.
+		logrus.Errorf("store(): file %s: %s", file.ID, err.Error())
+		f.deleteObjects(file.ObjectNames())
+	}
+}
+
+// setStatus moves the file from status from to status to, it fails with
+// domain.ErrFileStatusChanged when the file has been moved meanwhile, e.g.
+// deleted. Staying in the same status records the attempts and the error.
+func (f *FileService) setStatus(file *domain.File, from, to domain.FileStatus) error {
+	if from != to && !domain.CanTransitionFile(from, to) {
+		return fmt.Errorf("file %s can't move from %s to %s", file.ID, from, to)
+	}
+
+	now := time.Now()
+	file.Status = to
+	file.UpdatedAt = &now
+
+	return f.repo.UpdateStatus(*file, from)
+}
+
+// fail records the error of the upload, the client's one while the file is
+// sent by the client.
+func (f *FileService) fail(file domain.File, from domain.FileStatus, err error) {
+	to := domain.StorageUploadError
+	if from == domain.ClientUploadInProgress {
+		to = domain.ClientUploadError
+	}
+
+	file.Error = truncate(err.Error(), maxFileErrorLength)
+	if err := f.setStatus(&file, from, to); err != nil {
+		logrus.Errorf("fail(): file %s: %s", file.ID, err.Error())
+	}
+}
+
+// discard removes the upload of the file once it isn't needed anymore: the
+// temporary file or the object uploaded by the client.
+func (f *FileService) discard(file domain.File) {
+	if file.Name != "" {
+		removeFile(file.Name)
+	} else {
+		f.deleteObjects([]string{file.ObjectName})
+	}
+}
+
+// upload stores the renditions, the original one first. The renditions
+// already stored are deleted when one of them fails.
+func (f *FileService) upload(file domain.File, outputs []imaging.Output) ([]domain.FileRendition, error) {
+	renditions := make([]domain.FileRendition, 0, len(outputs))
+	for _, output := range outputs {
+		name := f.generateFilename(file, output)
+
+		url, err := f.storage.Upload(context.Background(), storage.UploadInput{
+			File:        bytes.NewReader(output.Data),
+			Size:        int64(len(output.Data)),
+			ContentType: output.ContentType(),
+			Name:        name,
+		})
+		if err != nil {
+			f.deleteObjects(domain.File{Renditions: renditions}.ObjectNames())
+
+			return nil, err
+		}
+
+		renditions = append(renditions, domain.FileRendition{
+			Name:        output.Rendition,
+			Format:      string(output.Format),
+			ContentType: output.ContentType(),
+			Width:       output.Width,
+			Height:      output.Height,
+			Size:        int64(len(output.Data)),
+			URL:         url,
+			ObjectName:  name,
+		})
+	}
+
+	return renditions, nil
+}
+
+func (f *FileService) deleteObjects(names []string) {
+	for _, name := range names {
+		if name == "" {
+			continue
+		}
+
+		if err := f.storage.Delete(context.Background(), name); err != nil {
+			logrus.Errorf("deleteObjects(): %s", err.Error())
+		}
+	}
+}
+
+// generateFilename puts the renditions of a file next to each other, e.g.
+// images/<product id>/<file id>/thumbnail.webp.
+func (s *FileService) generateFilename(file domain.File, output imaging.Output) string {
+	return fmt.Sprintf("%s/%s/%s/%s.%s", folders[file.Type], file.ProductId, file.ID, output.Rendition, output.Format.Extension())
+}
+
+// generateUploadName keeps the files uploaded by the clients apart from the
+// stored renditions, e.g. uploads/<product id>/<file id>.
+func (s *FileService) generateUploadName(file domain.File) string {
+	return fmt.Sprintf("uploads/%s/%s", file.ProductId, file.ID)
+}
+
+func truncate(s string, length int) string {
+	runes := []rune(s)
+	if len(runes) <= length {
+		return s
+	}
 
-	return fmt.Sprintf("images/%s", str[len(str)-1])
+	return string(runes[:length])
 }
 
 func removeFile(filename string) {
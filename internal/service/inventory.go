@@ -0,0 +1,144 @@
+package service
+
+import (
+	"strings"
+	"time"
+
+	"github.com/AndrewMislyuk/go-shop-backend/internal/domain"
+	"github.com/AndrewMislyuk/go-shop-backend/internal/repository"
+	"github.com/google/uuid"
+)
+
+const (
+	defaultInventoryLimit = 20
+	defaultReservationTTL = 15 * time.Minute
+)
+
+type InventoryService struct {
+	repo           repository.Inventory
+	variants       repository.ProductVariants
+	reservationTTL time.Duration
+}
+
+func NewInventoryService(repo repository.Inventory, variants repository.ProductVariants, reservationTTL time.Duration) *InventoryService {
+	if reservationTTL == 0 {
+		reservationTTL = defaultReservationTTL
+	}
+
+	return &InventoryService{
+		repo:           repo,
+		variants:       variants,
+		reservationTTL: reservationTTL,
+	}
+}
+
+func (s *InventoryService) GetLevels(filter domain.StockLevelsFilter) (domain.StockLevelsList, error) {
+	if filter.Limit == 0 {
+		filter.Limit = defaultInventoryLimit
+	}
+
+	return s.repo.GetLevels(filter)
+}
+
+func (s *InventoryService) GetMovements(filter domain.StockMovementsFilter) (domain.StockMovementsList, error) {
+	if filter.Limit == 0 {
+		filter.Limit = defaultInventoryLimit
+	}
+
+	return s.repo.GetMovements(filter)
+}
+
+// RecordMovement records a movement made by hand by the user actorId.
+func (s *InventoryService) RecordMovement(actorId string, input domain.StockMovementInput) (domain.StockLevel, error) {
+	if err := input.Validate(); err != nil {
+		return domain.StockLevel{}, err
+	}
+
+	if err := s.checkVariant(input.ProductId, input.VariantId); err != nil {
+		return domain.StockLevel{}, err
+	}
+
+	return s.repo.RecordMovement(domain.StockMovement{
+		Id:        uuid.New().String(),
+		ProductId: input.ProductId,
+		VariantId: input.VariantId,
+		Kind:      input.Kind,
+		Quantity:  input.Delta(),
+		Reason:    strings.TrimSpace(input.Reason),
+		ActorId:   actorPointer(actorId),
+		CreatedAt: time.Now(),
+	}, input.Version)
+}
+
+func (s *InventoryService) Reserve(input domain.CreateReservationInput) (domain.Reservation, error) {
+	if err := s.checkVariant(input.ProductId, input.VariantId); err != nil {
+		return domain.Reservation{}, err
+	}
+
+	ttl := s.reservationTTL
+	if input.TTL != 0 {
+		ttl = time.Duration(input.TTL) * time.Second
+	}
+
+	timestamp := time.Now()
+	reservation := domain.Reservation{
+		Id:        uuid.New().String(),
+		ProductId: input.ProductId,
+		VariantId: input.VariantId,
+		Quantity:  input.Quantity,
+		Status:    domain.ReservationActive,
+		ExpiresAt: timestamp.Add(ttl),
+		CreatedAt: timestamp,
+	}
+
+	if err := s.repo.Reserve(reservation); err != nil {
+		return domain.Reservation{}, err
+	}
+
+	return reservation, nil
+}
+
+// ConfirmReservation sells the reserved stock. actorId is empty when the
+// reservation is confirmed by the system, e.g. on payment.
+func (s *InventoryService) ConfirmReservation(actorId, reservationId string) error {
+	return s.repo.ConfirmReservation(reservationId, domain.StockMovement{
+		Id:        uuid.New().String(),
+		ActorId:   actorPointer(actorId),
+		CreatedAt: time.Now(),
+	})
+}
+
+func (s *InventoryService) ReleaseReservation(reservationId string) error {
+	return s.repo.ReleaseReservation(reservationId, time.Now())
+}
+
+func (s *InventoryService) ExpireReservations() (int, error) {
+	return s.repo.ExpireReservations(time.Now())
+}
+
+// checkVariant makes sure the stock of products with variants is kept per
+// variant.
+func (s *InventoryService) checkVariant(productId string, variantId *string) error {
+	if variantId != nil {
+		return nil
+	}
+
+	variants, err := s.variants.GetByProduct(productId)
+	if err != nil {
+		return err
+	}
+
+	if len(variants) != 0 {
+		return domain.ErrVariantRequired
+	}
+
+	return nil
+}
+
+func actorPointer(actorId string) *string {
+	if actorId == "" {
+		return nil
+	}
+
+	return &actorId
+}
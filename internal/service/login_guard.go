@@ -0,0 +1,150 @@
+package service
+
+import (
+	"math"
+	"strings"
+	"time"
+
+	"github.com/AndrewMislyuk/go-shop-backend/internal/domain"
+	"github.com/AndrewMislyuk/go-shop-backend/internal/repository"
+	"github.com/sirupsen/logrus"
+)
+
+type LoginGuardConfig struct {
+	// Window is how long a failure is remembered after the last one.
+	Window time.Duration
+	// BaseDelay is the pause required after the first failed attempt for an
+	// account, it doubles with every next failure up to MaxDelay.
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	// MaxAccountFailures and MaxIPFailures lock the account or the client IP
+	// out for LockoutDuration. Zero disables the corresponding lockout.
+	MaxAccountFailures int
+	MaxIPFailures      int
+	LockoutDuration    time.Duration
+}
+
+// LoginGuard slows down password guessing. Progressive delays apply only per
+// account, so that users behind a shared IP don't slow each other down, while
+// both accounts and IPs are locked out after too many failures.
+type LoginGuard struct {
+	store  repository.LoginAttempts
+	config LoginGuardConfig
+}
+
+func NewLoginGuard(store repository.LoginAttempts, config LoginGuardConfig) *LoginGuard {
+	return &LoginGuard{
+		store:  store,
+		config: config,
+	}
+}
+
+// Check returns a *domain.TooManyAttemptsError when the sign-in must not be
+// attempted yet.
+func (g *LoginGuard) Check(email, ip string) error {
+	now := time.Now()
+
+	account, err := g.store.Get(accountKey(email))
+	if err != nil {
+		return err
+	}
+
+	if wait := g.wait(account, now, true); wait > 0 {
+		return &domain.TooManyAttemptsError{RetryAfter: wait}
+	}
+
+	if ip == "" {
+		return nil
+	}
+
+	client, err := g.store.Get(ipKey(ip))
+	if err != nil {
+		return err
+	}
+
+	if wait := g.wait(client, now, false); wait > 0 {
+		return &domain.TooManyAttemptsError{RetryAfter: wait}
+	}
+
+	return nil
+}
+
+func (g *LoginGuard) Failure(email, ip string) {
+	now := time.Now()
+
+	g.registerFailure(accountKey(email), g.config.MaxAccountFailures, now, logrus.Fields{"email": email, "ip": ip})
+
+	if ip != "" {
+		g.registerFailure(ipKey(ip), g.config.MaxIPFailures, now, logrus.Fields{"ip": ip})
+	}
+}
+
+// Success forgets failures of the account. Failures of the IP are kept,
+// otherwise signing in to an own account would reset the IP counter.
+func (g *LoginGuard) Success(email string) {
+	if err := g.store.Reset(accountKey(email)); err != nil {
+		logrus.Errorf("LoginGuard.Success(): %s", err.Error())
+	}
+}
+
+// DeleteExpired forgets the failures out of the window, they don't count
+// anymore, unless a lockout still runs.
+func (g *LoginGuard) DeleteExpired() (int, error) {
+	return g.store.DeleteExpired(time.Now(), g.config.Window)
+}
+
+func (g *LoginGuard) registerFailure(key string, maxFailures int, now time.Time, fields logrus.Fields) {
+	attempt, err := g.store.RegisterFailure(key, now, g.config.Window)
+	if err != nil {
+		logrus.Errorf("LoginGuard.Failure(): %s", err.Error())
+
+		return
+	}
+
+	if maxFailures == 0 || attempt.Failures < maxFailures {
+		return
+	}
+
+	until := now.Add(g.config.LockoutDuration)
+	if err := g.store.Lock(key, until); err != nil {
+		logrus.Errorf("LoginGuard.Failure(): %s", err.Error())
+
+		return
+	}
+
+	fields["event"] = "sign_in_lockout"
+	fields["key"] = key
+	fields["failures"] = attempt.Failures
+	fields["locked_until"] = until
+
+	logrus.WithFields(fields).Warn("sign-in locked out after too many failed attempts")
+}
+
+func (g *LoginGuard) wait(attempt domain.LoginAttempt, now time.Time, progressive bool) time.Duration {
+	if attempt.LockedUntil != nil && attempt.LockedUntil.After(now) {
+		return attempt.LockedUntil.Sub(now)
+	}
+
+	if !progressive || attempt.Failures == 0 || attempt.LastFailureAt.Before(now.Add(-g.config.Window)) {
+		return 0
+	}
+
+	return attempt.LastFailureAt.Add(g.delay(attempt.Failures)).Sub(now)
+}
+
+func (g *LoginGuard) delay(failures int) time.Duration {
+	delay := float64(g.config.BaseDelay) * math.Pow(2, float64(failures-1))
+	if delay > float64(g.config.MaxDelay) {
+		return g.config.MaxDelay
+	}
+
+	return time.Duration(delay)
+}
+
+func accountKey(email string) string {
+	return "account:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+func ipKey(ip string) string {
+	return "ip:" + ip
+}
@@ -5,9 +5,11 @@
 package mock_service
 
 import (
+	io "io"
 	reflect "reflect"
 
 	domain "github.com/AndrewMislyuk/go-shop-backend/internal/domain"
+	auth "github.com/AndrewMislyuk/go-shop-backend/pkg/auth"
 	gomock "github.com/golang/mock/gomock"
 )
 
@@ -34,6 +36,20 @@ func (m *MockUser) EXPECT() *MockUserMockRecorder {
 	return m.recorder
 }
 
+// ChangePassword mocks base method.
+func (m *MockUser) ChangePassword(userId, sessionId string, input domain.ChangePasswordInput) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangePassword", userId, sessionId, input)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ChangePassword indicates an expected call of ChangePassword.
+func (mr *MockUserMockRecorder) ChangePassword(userId, sessionId, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangePassword", reflect.TypeOf((*MockUser)(nil).ChangePassword), userId, sessionId, input)
+}
+
 // CreateUser mocks base method.
 func (m *MockUser) CreateUser(user domain.UserSignUp) (string, error) {
 	m.ctrl.T.Helper()
@@ -49,19 +65,48 @@ func (mr *MockUserMockRecorder) CreateUser(user interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockUser)(nil).CreateUser), user)
 }
 
+// DeleteExpiredLoginAttempts mocks base method.
+func (m *MockUser) DeleteExpiredLoginAttempts() (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredLoginAttempts")
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpiredLoginAttempts indicates an expected call of DeleteExpiredLoginAttempts.
+func (mr *MockUserMockRecorder) DeleteExpiredLoginAttempts() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredLoginAttempts", reflect.TypeOf((*MockUser)(nil).DeleteExpiredLoginAttempts))
+}
+
+// ForgotPassword mocks base method.
+func (m *MockUser) ForgotPassword(email string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ForgotPassword", email)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ForgotPassword indicates an expected call of ForgotPassword.
+func (mr *MockUserMockRecorder) ForgotPassword(email interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForgotPassword", reflect.TypeOf((*MockUser)(nil).ForgotPassword), email)
+}
+
 // GenerateToken mocks base method.
-func (m *MockUser) GenerateToken(email, password string) (string, error) {
+func (m *MockUser) GenerateToken(email, password, clientIP string) (domain.Tokens, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GenerateToken", email, password)
-	ret0, _ := ret[0].(string)
+	ret := m.ctrl.Call(m, "GenerateToken", email, password, clientIP)
+	ret0, _ := ret[0].(domain.Tokens)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GenerateToken indicates an expected call of GenerateToken.
-func (mr *MockUserMockRecorder) GenerateToken(email, password interface{}) *gomock.Call {
+func (mr *MockUserMockRecorder) GenerateToken(email, password, clientIP interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateToken", reflect.TypeOf((*MockUser)(nil).GenerateToken), email, password)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateToken", reflect.TypeOf((*MockUser)(nil).GenerateToken), email, password, clientIP)
 }
 
 // GetMe mocks base method.
@@ -79,6 +124,266 @@ func (mr *MockUserMockRecorder) GetMe(token interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMe", reflect.TypeOf((*MockUser)(nil).GetMe), token)
 }
 
+// Identify mocks base method.
+func (m *MockUser) Identify(token string) (domain.Identity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Identify", token)
+	ret0, _ := ret[0].(domain.Identity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Identify indicates an expected call of Identify.
+func (mr *MockUserMockRecorder) Identify(token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Identify", reflect.TypeOf((*MockUser)(nil).Identify), token)
+}
+
+// JWKS mocks base method.
+func (m *MockUser) JWKS() auth.JSONWebKeySet {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "JWKS")
+	ret0, _ := ret[0].(auth.JSONWebKeySet)
+	return ret0
+}
+
+// JWKS indicates an expected call of JWKS.
+func (mr *MockUserMockRecorder) JWKS() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "JWKS", reflect.TypeOf((*MockUser)(nil).JWKS))
+}
+
+// Logout mocks base method.
+func (m *MockUser) Logout(refreshToken string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Logout", refreshToken)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Logout indicates an expected call of Logout.
+func (mr *MockUserMockRecorder) Logout(refreshToken interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Logout", reflect.TypeOf((*MockUser)(nil).Logout), refreshToken)
+}
+
+// LogoutAll mocks base method.
+func (m *MockUser) LogoutAll(userId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LogoutAll", userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LogoutAll indicates an expected call of LogoutAll.
+func (mr *MockUserMockRecorder) LogoutAll(userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LogoutAll", reflect.TypeOf((*MockUser)(nil).LogoutAll), userId)
+}
+
+// RefreshTokens mocks base method.
+func (m *MockUser) RefreshTokens(refreshToken string) (domain.Tokens, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefreshTokens", refreshToken)
+	ret0, _ := ret[0].(domain.Tokens)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RefreshTokens indicates an expected call of RefreshTokens.
+func (mr *MockUserMockRecorder) RefreshTokens(refreshToken interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshTokens", reflect.TypeOf((*MockUser)(nil).RefreshTokens), refreshToken)
+}
+
+// ResetPassword mocks base method.
+func (m *MockUser) ResetPassword(token, newPassword string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetPassword", token, newPassword)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetPassword indicates an expected call of ResetPassword.
+func (mr *MockUserMockRecorder) ResetPassword(token, newPassword interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetPassword", reflect.TypeOf((*MockUser)(nil).ResetPassword), token, newPassword)
+}
+
+// VerifyEmail mocks base method.
+func (m *MockUser) VerifyEmail(token string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyEmail", token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// VerifyEmail indicates an expected call of VerifyEmail.
+func (mr *MockUserMockRecorder) VerifyEmail(token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyEmail", reflect.TypeOf((*MockUser)(nil).VerifyEmail), token)
+}
+
+// MockUsers is a mock of Users interface.
+type MockUsers struct {
+	ctrl     *gomock.Controller
+	recorder *MockUsersMockRecorder
+}
+
+// MockUsersMockRecorder is the mock recorder for MockUsers.
+type MockUsersMockRecorder struct {
+	mock *MockUsers
+}
+
+// NewMockUsers creates a new mock instance.
+func NewMockUsers(ctrl *gomock.Controller) *MockUsers {
+	mock := &MockUsers{ctrl: ctrl}
+	mock.recorder = &MockUsersMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUsers) EXPECT() *MockUsersMockRecorder {
+	return m.recorder
+}
+
+// Block mocks base method.
+func (m *MockUsers) Block(adminId, userId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Block", adminId, userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Block indicates an expected call of Block.
+func (mr *MockUsersMockRecorder) Block(adminId, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Block", reflect.TypeOf((*MockUsers)(nil).Block), adminId, userId)
+}
+
+// DeleteMe mocks base method.
+func (m *MockUsers) DeleteMe(userId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteMe", userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteMe indicates an expected call of DeleteMe.
+func (mr *MockUsersMockRecorder) DeleteMe(userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMe", reflect.TypeOf((*MockUsers)(nil).DeleteMe), userId)
+}
+
+// GetAll mocks base method.
+func (m *MockUsers) GetAll(filter domain.UsersFilter) (domain.UsersList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", filter)
+	ret0, _ := ret[0].(domain.UsersList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockUsersMockRecorder) GetAll(filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockUsers)(nil).GetAll), filter)
+}
+
+// Unblock mocks base method.
+func (m *MockUsers) Unblock(userId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unblock", userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Unblock indicates an expected call of Unblock.
+func (mr *MockUsersMockRecorder) Unblock(userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unblock", reflect.TypeOf((*MockUsers)(nil).Unblock), userId)
+}
+
+// UpdateMe mocks base method.
+func (m *MockUsers) UpdateMe(userId string, input domain.UpdateUserInput) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateMe", userId, input)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateMe indicates an expected call of UpdateMe.
+func (mr *MockUsersMockRecorder) UpdateMe(userId, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMe", reflect.TypeOf((*MockUsers)(nil).UpdateMe), userId, input)
+}
+
+// MockRoles is a mock of Roles interface.
+type MockRoles struct {
+	ctrl     *gomock.Controller
+	recorder *MockRolesMockRecorder
+}
+
+// MockRolesMockRecorder is the mock recorder for MockRoles.
+type MockRolesMockRecorder struct {
+	mock *MockRoles
+}
+
+// NewMockRoles creates a new mock instance.
+func NewMockRoles(ctrl *gomock.Controller) *MockRoles {
+	mock := &MockRoles{ctrl: ctrl}
+	mock.recorder = &MockRolesMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRoles) EXPECT() *MockRolesMockRecorder {
+	return m.recorder
+}
+
+// GetAll mocks base method.
+func (m *MockRoles) GetAll() ([]domain.Role, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll")
+	ret0, _ := ret[0].([]domain.Role)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockRolesMockRecorder) GetAll() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockRoles)(nil).GetAll))
+}
+
+// Grant mocks base method.
+func (m *MockRoles) Grant(userId, role string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Grant", userId, role)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Grant indicates an expected call of Grant.
+func (mr *MockRolesMockRecorder) Grant(userId, role interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Grant", reflect.TypeOf((*MockRoles)(nil).Grant), userId, role)
+}
+
+// Revoke mocks base method.
+func (m *MockRoles) Revoke(userId, role string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revoke", userId, role)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Revoke indicates an expected call of Revoke.
+func (mr *MockRolesMockRecorder) Revoke(userId, role interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*MockRoles)(nil).Revoke), userId, role)
+}
+
 // MockProductsList is a mock of ProductsList interface.
 type MockProductsList struct {
 	ctrl     *gomock.Controller
@@ -132,33 +437,48 @@ func (mr *MockProductsListMockRecorder) Delete(itemId interface{}) *gomock.Call
 }
 
 // GetAll mocks base method.
-func (m *MockProductsList) GetAll() ([]domain.ProductsList, error) {
+func (m *MockProductsList) GetAll(filter domain.ProductsFilter) (domain.ProductsPage, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAll")
-	ret0, _ := ret[0].([]domain.ProductsList)
+	ret := m.ctrl.Call(m, "GetAll", filter)
+	ret0, _ := ret[0].(domain.ProductsPage)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetAll indicates an expected call of GetAll.
-func (mr *MockProductsListMockRecorder) GetAll() *gomock.Call {
+func (mr *MockProductsListMockRecorder) GetAll(filter interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockProductsList)(nil).GetAll))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockProductsList)(nil).GetAll), filter)
 }
 
 // GetById mocks base method.
-func (m *MockProductsList) GetById(listId string) (domain.ProductsList, error) {
+func (m *MockProductsList) GetById(listId, currency string) (domain.ProductsList, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetById", listId)
+	ret := m.ctrl.Call(m, "GetById", listId, currency)
 	ret0, _ := ret[0].(domain.ProductsList)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetById indicates an expected call of GetById.
-func (mr *MockProductsListMockRecorder) GetById(listId interface{}) *gomock.Call {
+func (mr *MockProductsListMockRecorder) GetById(listId, currency interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetById", reflect.TypeOf((*MockProductsList)(nil).GetById), listId, currency)
+}
+
+// Search mocks base method.
+func (m *MockProductsList) Search(input domain.ProductsSearchInput) (domain.ProductsSearchPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", input)
+	ret0, _ := ret[0].(domain.ProductsSearchPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Search indicates an expected call of Search.
+func (mr *MockProductsListMockRecorder) Search(input interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetById", reflect.TypeOf((*MockProductsList)(nil).GetById), listId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockProductsList)(nil).Search), input)
 }
 
 // Update mocks base method.
@@ -175,67 +495,1200 @@ func (mr *MockProductsListMockRecorder) Update(itemId, input interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockProductsList)(nil).Update), itemId, input)
 }
 
-// MockFiles is a mock of Files interface.
-type MockFiles struct {
+// MockProductVariants is a mock of ProductVariants interface.
+type MockProductVariants struct {
 	ctrl     *gomock.Controller
-	recorder *MockFilesMockRecorder
+	recorder *MockProductVariantsMockRecorder
 }
 
-// MockFilesMockRecorder is the mock recorder for MockFiles.
-type MockFilesMockRecorder struct {
-	mock *MockFiles
+// MockProductVariantsMockRecorder is the mock recorder for MockProductVariants.
+type MockProductVariantsMockRecorder struct {
+	mock *MockProductVariants
 }
 
-// NewMockFiles creates a new mock instance.
-func NewMockFiles(ctrl *gomock.Controller) *MockFiles {
-	mock := &MockFiles{ctrl: ctrl}
-	mock.recorder = &MockFilesMockRecorder{mock}
+// NewMockProductVariants creates a new mock instance.
+func NewMockProductVariants(ctrl *gomock.Controller) *MockProductVariants {
+	mock := &MockProductVariants{ctrl: ctrl}
+	mock.recorder = &MockProductVariantsMockRecorder{mock}
 	return mock
 }
 
 // EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockFiles) EXPECT() *MockFilesMockRecorder {
+func (m *MockProductVariants) EXPECT() *MockProductVariantsMockRecorder {
 	return m.recorder
 }
 
+// Create mocks base method.
+func (m *MockProductVariants) Create(productId string, input domain.CreateVariantInput) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", productId, input)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockProductVariantsMockRecorder) Create(productId, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockProductVariants)(nil).Create), productId, input)
+}
+
 // Delete mocks base method.
-func (m *MockFiles) Delete() error {
+func (m *MockProductVariants) Delete(productId, variantId string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete")
+	ret := m.ctrl.Call(m, "Delete", productId, variantId)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockFilesMockRecorder) Delete() *gomock.Call {
+func (mr *MockProductVariantsMockRecorder) Delete(productId, variantId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockFiles)(nil).Delete))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockProductVariants)(nil).Delete), productId, variantId)
 }
 
-// Get mocks base method.
-func (m *MockFiles) Get() error {
+// GetByProduct mocks base method.
+func (m *MockProductVariants) GetByProduct(productId string) ([]domain.ProductVariant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByProduct", productId)
+	ret0, _ := ret[0].([]domain.ProductVariant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByProduct indicates an expected call of GetByProduct.
+func (mr *MockProductVariantsMockRecorder) GetByProduct(productId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByProduct", reflect.TypeOf((*MockProductVariants)(nil).GetByProduct), productId)
+}
+
+// Update mocks base method.
+func (m *MockProductVariants) Update(productId, variantId string, input domain.UpdateVariantInput) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Get")
+	ret := m.ctrl.Call(m, "Update", productId, variantId, input)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// Get indicates an expected call of Get.
-func (mr *MockFilesMockRecorder) Get() *gomock.Call {
+// Update indicates an expected call of Update.
+func (mr *MockProductVariantsMockRecorder) Update(productId, variantId, input interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockFiles)(nil).Get))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockProductVariants)(nil).Update), productId, variantId, input)
 }
 
-// Upload mocks base method.
-func (m *MockFiles) Upload() error {
+// MockInventory is a mock of Inventory interface.
+type MockInventory struct {
+	ctrl     *gomock.Controller
+	recorder *MockInventoryMockRecorder
+}
+
+// MockInventoryMockRecorder is the mock recorder for MockInventory.
+type MockInventoryMockRecorder struct {
+	mock *MockInventory
+}
+
+// NewMockInventory creates a new mock instance.
+func NewMockInventory(ctrl *gomock.Controller) *MockInventory {
+	mock := &MockInventory{ctrl: ctrl}
+	mock.recorder = &MockInventoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockInventory) EXPECT() *MockInventoryMockRecorder {
+	return m.recorder
+}
+
+// ConfirmReservation mocks base method.
+func (m *MockInventory) ConfirmReservation(actorId, reservationId string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Upload")
+	ret := m.ctrl.Call(m, "ConfirmReservation", actorId, reservationId)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
+// ConfirmReservation indicates an expected call of ConfirmReservation.
+func (mr *MockInventoryMockRecorder) ConfirmReservation(actorId, reservationId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfirmReservation", reflect.TypeOf((*MockInventory)(nil).ConfirmReservation), actorId, reservationId)
+}
+
+// ExpireReservations mocks base method.
+func (m *MockInventory) ExpireReservations() (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpireReservations")
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpireReservations indicates an expected call of ExpireReservations.
+func (mr *MockInventoryMockRecorder) ExpireReservations() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpireReservations", reflect.TypeOf((*MockInventory)(nil).ExpireReservations))
+}
+
+// GetLevels mocks base method.
+func (m *MockInventory) GetLevels(filter domain.StockLevelsFilter) (domain.StockLevelsList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLevels", filter)
+	ret0, _ := ret[0].(domain.StockLevelsList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLevels indicates an expected call of GetLevels.
+func (mr *MockInventoryMockRecorder) GetLevels(filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLevels", reflect.TypeOf((*MockInventory)(nil).GetLevels), filter)
+}
+
+// GetMovements mocks base method.
+func (m *MockInventory) GetMovements(filter domain.StockMovementsFilter) (domain.StockMovementsList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMovements", filter)
+	ret0, _ := ret[0].(domain.StockMovementsList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMovements indicates an expected call of GetMovements.
+func (mr *MockInventoryMockRecorder) GetMovements(filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMovements", reflect.TypeOf((*MockInventory)(nil).GetMovements), filter)
+}
+
+// RecordMovement mocks base method.
+func (m *MockInventory) RecordMovement(actorId string, input domain.StockMovementInput) (domain.StockLevel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordMovement", actorId, input)
+	ret0, _ := ret[0].(domain.StockLevel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecordMovement indicates an expected call of RecordMovement.
+func (mr *MockInventoryMockRecorder) RecordMovement(actorId, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordMovement", reflect.TypeOf((*MockInventory)(nil).RecordMovement), actorId, input)
+}
+
+// ReleaseReservation mocks base method.
+func (m *MockInventory) ReleaseReservation(reservationId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseReservation", reservationId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReleaseReservation indicates an expected call of ReleaseReservation.
+func (mr *MockInventoryMockRecorder) ReleaseReservation(reservationId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseReservation", reflect.TypeOf((*MockInventory)(nil).ReleaseReservation), reservationId)
+}
+
+// Reserve mocks base method.
+func (m *MockInventory) Reserve(input domain.CreateReservationInput) (domain.Reservation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reserve", input)
+	ret0, _ := ret[0].(domain.Reservation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Reserve indicates an expected call of Reserve.
+func (mr *MockInventoryMockRecorder) Reserve(input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reserve", reflect.TypeOf((*MockInventory)(nil).Reserve), input)
+}
+
+// MockCarts is a mock of Carts interface.
+type MockCarts struct {
+	ctrl     *gomock.Controller
+	recorder *MockCartsMockRecorder
+}
+
+// MockCartsMockRecorder is the mock recorder for MockCarts.
+type MockCartsMockRecorder struct {
+	mock *MockCarts
+}
+
+// NewMockCarts creates a new mock instance.
+func NewMockCarts(ctrl *gomock.Controller) *MockCarts {
+	mock := &MockCarts{ctrl: ctrl}
+	mock.recorder = &MockCartsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCarts) EXPECT() *MockCartsMockRecorder {
+	return m.recorder
+}
+
+// AddItem mocks base method.
+func (m *MockCarts) AddItem(owner domain.CartOwner, input domain.AddCartItemInput) (domain.Cart, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddItem", owner, input)
+	ret0, _ := ret[0].(domain.Cart)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddItem indicates an expected call of AddItem.
+func (mr *MockCartsMockRecorder) AddItem(owner, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddItem", reflect.TypeOf((*MockCarts)(nil).AddItem), owner, input)
+}
+
+// DeleteExpired mocks base method.
+func (m *MockCarts) DeleteExpired() (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpired")
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpired indicates an expected call of DeleteExpired.
+func (mr *MockCartsMockRecorder) DeleteExpired() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpired", reflect.TypeOf((*MockCarts)(nil).DeleteExpired))
+}
+
+// Get mocks base method.
+func (m *MockCarts) Get(owner domain.CartOwner) (domain.Cart, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", owner)
+	ret0, _ := ret[0].(domain.Cart)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockCartsMockRecorder) Get(owner interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockCarts)(nil).Get), owner)
+}
+
+// Merge mocks base method.
+func (m *MockCarts) Merge(userId, token string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Merge", userId, token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Merge indicates an expected call of Merge.
+func (mr *MockCartsMockRecorder) Merge(userId, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Merge", reflect.TypeOf((*MockCarts)(nil).Merge), userId, token)
+}
+
+// RemoveItem mocks base method.
+func (m *MockCarts) RemoveItem(owner domain.CartOwner, itemId string) (domain.Cart, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveItem", owner, itemId)
+	ret0, _ := ret[0].(domain.Cart)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveItem indicates an expected call of RemoveItem.
+func (mr *MockCartsMockRecorder) RemoveItem(owner, itemId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveItem", reflect.TypeOf((*MockCarts)(nil).RemoveItem), owner, itemId)
+}
+
+// UpdateItem mocks base method.
+func (m *MockCarts) UpdateItem(owner domain.CartOwner, itemId string, input domain.UpdateCartItemInput) (domain.Cart, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateItem", owner, itemId, input)
+	ret0, _ := ret[0].(domain.Cart)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateItem indicates an expected call of UpdateItem.
+func (mr *MockCartsMockRecorder) UpdateItem(owner, itemId, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateItem", reflect.TypeOf((*MockCarts)(nil).UpdateItem), owner, itemId, input)
+}
+
+// MockOrders is a mock of Orders interface.
+type MockOrders struct {
+	ctrl     *gomock.Controller
+	recorder *MockOrdersMockRecorder
+}
+
+// MockOrdersMockRecorder is the mock recorder for MockOrders.
+type MockOrdersMockRecorder struct {
+	mock *MockOrders
+}
+
+// NewMockOrders creates a new mock instance.
+func NewMockOrders(ctrl *gomock.Controller) *MockOrders {
+	mock := &MockOrders{ctrl: ctrl}
+	mock.recorder = &MockOrdersMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOrders) EXPECT() *MockOrdersMockRecorder {
+	return m.recorder
+}
+
+// CancelExpired mocks base method.
+func (m *MockOrders) CancelExpired() (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelExpired")
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelExpired indicates an expected call of CancelExpired.
+func (mr *MockOrdersMockRecorder) CancelExpired() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelExpired", reflect.TypeOf((*MockOrders)(nil).CancelExpired))
+}
+
+// CancelMine mocks base method.
+func (m *MockOrders) CancelMine(userId, orderId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelMine", userId, orderId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CancelMine indicates an expected call of CancelMine.
+func (mr *MockOrdersMockRecorder) CancelMine(userId, orderId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelMine", reflect.TypeOf((*MockOrders)(nil).CancelMine), userId, orderId)
+}
+
+// ChangeStatus mocks base method.
+func (m *MockOrders) ChangeStatus(actorId, orderId string, input domain.ChangeOrderStatusInput) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangeStatus", actorId, orderId, input)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ChangeStatus indicates an expected call of ChangeStatus.
+func (mr *MockOrdersMockRecorder) ChangeStatus(actorId, orderId, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangeStatus", reflect.TypeOf((*MockOrders)(nil).ChangeStatus), actorId, orderId, input)
+}
+
+// Checkout mocks base method.
+func (m *MockOrders) Checkout(userId string, input domain.CheckoutInput) (domain.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Checkout", userId, input)
+	ret0, _ := ret[0].(domain.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Checkout indicates an expected call of Checkout.
+func (mr *MockOrdersMockRecorder) Checkout(userId, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Checkout", reflect.TypeOf((*MockOrders)(nil).Checkout), userId, input)
+}
+
+// GetAll mocks base method.
+func (m *MockOrders) GetAll(filter domain.OrdersFilter) (domain.OrdersList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", filter)
+	ret0, _ := ret[0].(domain.OrdersList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockOrdersMockRecorder) GetAll(filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockOrders)(nil).GetAll), filter)
+}
+
+// GetById mocks base method.
+func (m *MockOrders) GetById(orderId string) (domain.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetById", orderId)
+	ret0, _ := ret[0].(domain.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetById indicates an expected call of GetById.
+func (mr *MockOrdersMockRecorder) GetById(orderId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetById", reflect.TypeOf((*MockOrders)(nil).GetById), orderId)
+}
+
+// GetMine mocks base method.
+func (m *MockOrders) GetMine(userId string, filter domain.OrdersFilter) (domain.OrdersList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMine", userId, filter)
+	ret0, _ := ret[0].(domain.OrdersList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMine indicates an expected call of GetMine.
+func (mr *MockOrdersMockRecorder) GetMine(userId, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMine", reflect.TypeOf((*MockOrders)(nil).GetMine), userId, filter)
+}
+
+// GetMineById mocks base method.
+func (m *MockOrders) GetMineById(userId, orderId string) (domain.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMineById", userId, orderId)
+	ret0, _ := ret[0].(domain.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMineById indicates an expected call of GetMineById.
+func (mr *MockOrdersMockRecorder) GetMineById(userId, orderId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMineById", reflect.TypeOf((*MockOrders)(nil).GetMineById), userId, orderId)
+}
+
+// MarkPaid mocks base method.
+func (m *MockOrders) MarkPaid(orderId, comment string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkPaid", orderId, comment)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkPaid indicates an expected call of MarkPaid.
+func (mr *MockOrdersMockRecorder) MarkPaid(orderId, comment interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkPaid", reflect.TypeOf((*MockOrders)(nil).MarkPaid), orderId, comment)
+}
+
+// MarkRefunded mocks base method.
+func (m *MockOrders) MarkRefunded(actorId, orderId, comment string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkRefunded", actorId, orderId, comment)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkRefunded indicates an expected call of MarkRefunded.
+func (mr *MockOrdersMockRecorder) MarkRefunded(actorId, orderId, comment interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkRefunded", reflect.TypeOf((*MockOrders)(nil).MarkRefunded), actorId, orderId, comment)
+}
+
+// MockPayments is a mock of Payments interface.
+type MockPayments struct {
+	ctrl     *gomock.Controller
+	recorder *MockPaymentsMockRecorder
+}
+
+// MockPaymentsMockRecorder is the mock recorder for MockPayments.
+type MockPaymentsMockRecorder struct {
+	mock *MockPayments
+}
+
+// NewMockPayments creates a new mock instance.
+func NewMockPayments(ctrl *gomock.Controller) *MockPayments {
+	mock := &MockPayments{ctrl: ctrl}
+	mock.recorder = &MockPaymentsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPayments) EXPECT() *MockPaymentsMockRecorder {
+	return m.recorder
+}
+
+// HandleWebhook mocks base method.
+func (m *MockPayments) HandleWebhook(payload []byte, signature string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HandleWebhook", payload, signature)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// HandleWebhook indicates an expected call of HandleWebhook.
+func (mr *MockPaymentsMockRecorder) HandleWebhook(payload, signature interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleWebhook", reflect.TypeOf((*MockPayments)(nil).HandleWebhook), payload, signature)
+}
+
+// Pay mocks base method.
+func (m *MockPayments) Pay(userId, orderId string, input domain.PayOrderInput) (domain.Payment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Pay", userId, orderId, input)
+	ret0, _ := ret[0].(domain.Payment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Pay indicates an expected call of Pay.
+func (mr *MockPaymentsMockRecorder) Pay(userId, orderId, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Pay", reflect.TypeOf((*MockPayments)(nil).Pay), userId, orderId, input)
+}
+
+// Refund mocks base method.
+func (m *MockPayments) Refund(actorId, orderId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Refund", actorId, orderId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Refund indicates an expected call of Refund.
+func (mr *MockPaymentsMockRecorder) Refund(actorId, orderId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Refund", reflect.TypeOf((*MockPayments)(nil).Refund), actorId, orderId)
+}
+
+// MockDiscounts is a mock of Discounts interface.
+type MockDiscounts struct {
+	ctrl     *gomock.Controller
+	recorder *MockDiscountsMockRecorder
+}
+
+// MockDiscountsMockRecorder is the mock recorder for MockDiscounts.
+type MockDiscountsMockRecorder struct {
+	mock *MockDiscounts
+}
+
+// NewMockDiscounts creates a new mock instance.
+func NewMockDiscounts(ctrl *gomock.Controller) *MockDiscounts {
+	mock := &MockDiscounts{ctrl: ctrl}
+	mock.recorder = &MockDiscountsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDiscounts) EXPECT() *MockDiscountsMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockDiscounts) Create(input domain.CreateDiscountInput) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", input)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockDiscountsMockRecorder) Create(input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockDiscounts)(nil).Create), input)
+}
+
+// Delete mocks base method.
+func (m *MockDiscounts) Delete(discountId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", discountId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockDiscountsMockRecorder) Delete(discountId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockDiscounts)(nil).Delete), discountId)
+}
+
+// GetAll mocks base method.
+func (m *MockDiscounts) GetAll(filter domain.DiscountsFilter) (domain.DiscountsList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", filter)
+	ret0, _ := ret[0].(domain.DiscountsList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockDiscountsMockRecorder) GetAll(filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockDiscounts)(nil).GetAll), filter)
+}
+
+// GetById mocks base method.
+func (m *MockDiscounts) GetById(discountId string) (domain.Discount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetById", discountId)
+	ret0, _ := ret[0].(domain.Discount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetById indicates an expected call of GetById.
+func (mr *MockDiscountsMockRecorder) GetById(discountId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetById", reflect.TypeOf((*MockDiscounts)(nil).GetById), discountId)
+}
+
+// RefreshSales mocks base method.
+func (m *MockDiscounts) RefreshSales() (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefreshSales")
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RefreshSales indicates an expected call of RefreshSales.
+func (mr *MockDiscountsMockRecorder) RefreshSales() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshSales", reflect.TypeOf((*MockDiscounts)(nil).RefreshSales))
+}
+
+// Update mocks base method.
+func (m *MockDiscounts) Update(discountId string, input domain.UpdateDiscountInput) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", discountId, input)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockDiscountsMockRecorder) Update(discountId, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockDiscounts)(nil).Update), discountId, input)
+}
+
+// MockPromoCodes is a mock of PromoCodes interface.
+type MockPromoCodes struct {
+	ctrl     *gomock.Controller
+	recorder *MockPromoCodesMockRecorder
+}
+
+// MockPromoCodesMockRecorder is the mock recorder for MockPromoCodes.
+type MockPromoCodesMockRecorder struct {
+	mock *MockPromoCodes
+}
+
+// NewMockPromoCodes creates a new mock instance.
+func NewMockPromoCodes(ctrl *gomock.Controller) *MockPromoCodes {
+	mock := &MockPromoCodes{ctrl: ctrl}
+	mock.recorder = &MockPromoCodesMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPromoCodes) EXPECT() *MockPromoCodesMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockPromoCodes) Create(input domain.CreatePromoCodeInput) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", input)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockPromoCodesMockRecorder) Create(input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockPromoCodes)(nil).Create), input)
+}
+
+// Delete mocks base method.
+func (m *MockPromoCodes) Delete(codeId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", codeId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockPromoCodesMockRecorder) Delete(codeId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockPromoCodes)(nil).Delete), codeId)
+}
+
+// GetAll mocks base method.
+func (m *MockPromoCodes) GetAll(filter domain.PromoCodesFilter) (domain.PromoCodesList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", filter)
+	ret0, _ := ret[0].(domain.PromoCodesList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockPromoCodesMockRecorder) GetAll(filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockPromoCodes)(nil).GetAll), filter)
+}
+
+// GetById mocks base method.
+func (m *MockPromoCodes) GetById(codeId string) (domain.PromoCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetById", codeId)
+	ret0, _ := ret[0].(domain.PromoCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetById indicates an expected call of GetById.
+func (mr *MockPromoCodesMockRecorder) GetById(codeId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetById", reflect.TypeOf((*MockPromoCodes)(nil).GetById), codeId)
+}
+
+// Update mocks base method.
+func (m *MockPromoCodes) Update(codeId string, input domain.UpdatePromoCodeInput) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", codeId, input)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockPromoCodesMockRecorder) Update(codeId, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockPromoCodes)(nil).Update), codeId, input)
+}
+
+// MockCurrencies is a mock of Currencies interface.
+type MockCurrencies struct {
+	ctrl     *gomock.Controller
+	recorder *MockCurrenciesMockRecorder
+}
+
+// MockCurrenciesMockRecorder is the mock recorder for MockCurrencies.
+type MockCurrenciesMockRecorder struct {
+	mock *MockCurrencies
+}
+
+// NewMockCurrencies creates a new mock instance.
+func NewMockCurrencies(ctrl *gomock.Controller) *MockCurrencies {
+	mock := &MockCurrencies{ctrl: ctrl}
+	mock.recorder = &MockCurrenciesMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCurrencies) EXPECT() *MockCurrenciesMockRecorder {
+	return m.recorder
+}
+
+// GetRates mocks base method.
+func (m *MockCurrencies) GetRates() (domain.ExchangeRates, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRates")
+	ret0, _ := ret[0].(domain.ExchangeRates)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRates indicates an expected call of GetRates.
+func (mr *MockCurrenciesMockRecorder) GetRates() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRates", reflect.TypeOf((*MockCurrencies)(nil).GetRates))
+}
+
+// SetRates mocks base method.
+func (m *MockCurrencies) SetRates(input domain.SetExchangeRatesInput) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetRates", input)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetRates indicates an expected call of SetRates.
+func (mr *MockCurrenciesMockRecorder) SetRates(input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetRates", reflect.TypeOf((*MockCurrencies)(nil).SetRates), input)
+}
+
+// MockProductPrices is a mock of ProductPrices interface.
+type MockProductPrices struct {
+	ctrl     *gomock.Controller
+	recorder *MockProductPricesMockRecorder
+}
+
+// MockProductPricesMockRecorder is the mock recorder for MockProductPrices.
+type MockProductPricesMockRecorder struct {
+	mock *MockProductPrices
+}
+
+// NewMockProductPrices creates a new mock instance.
+func NewMockProductPrices(ctrl *gomock.Controller) *MockProductPrices {
+	mock := &MockProductPrices{ctrl: ctrl}
+	mock.recorder = &MockProductPricesMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProductPrices) EXPECT() *MockProductPricesMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockProductPrices) Delete(productId, currency string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", productId, currency)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockProductPricesMockRecorder) Delete(productId, currency interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockProductPrices)(nil).Delete), productId, currency)
+}
+
+// GetByProduct mocks base method.
+func (m *MockProductPrices) GetByProduct(productId string) ([]domain.ProductPrice, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByProduct", productId)
+	ret0, _ := ret[0].([]domain.ProductPrice)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByProduct indicates an expected call of GetByProduct.
+func (mr *MockProductPricesMockRecorder) GetByProduct(productId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByProduct", reflect.TypeOf((*MockProductPrices)(nil).GetByProduct), productId)
+}
+
+// Set mocks base method.
+func (m *MockProductPrices) Set(productId, currency string, input domain.SetProductPriceInput) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Set", productId, currency, input)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Set indicates an expected call of Set.
+func (mr *MockProductPricesMockRecorder) Set(productId, currency, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockProductPrices)(nil).Set), productId, currency, input)
+}
+
+// MockCategories is a mock of Categories interface.
+type MockCategories struct {
+	ctrl     *gomock.Controller
+	recorder *MockCategoriesMockRecorder
+}
+
+// MockCategoriesMockRecorder is the mock recorder for MockCategories.
+type MockCategoriesMockRecorder struct {
+	mock *MockCategories
+}
+
+// NewMockCategories creates a new mock instance.
+func NewMockCategories(ctrl *gomock.Controller) *MockCategories {
+	mock := &MockCategories{ctrl: ctrl}
+	mock.recorder = &MockCategoriesMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCategories) EXPECT() *MockCategoriesMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockCategories) Create(input domain.CreateCategoryInput) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", input)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockCategoriesMockRecorder) Create(input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockCategories)(nil).Create), input)
+}
+
+// Delete mocks base method.
+func (m *MockCategories) Delete(categoryId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", categoryId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockCategoriesMockRecorder) Delete(categoryId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockCategories)(nil).Delete), categoryId)
+}
+
+// GetById mocks base method.
+func (m *MockCategories) GetById(categoryId string) (domain.Category, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetById", categoryId)
+	ret0, _ := ret[0].(domain.Category)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetById indicates an expected call of GetById.
+func (mr *MockCategoriesMockRecorder) GetById(categoryId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetById", reflect.TypeOf((*MockCategories)(nil).GetById), categoryId)
+}
+
+// Tree mocks base method.
+func (m *MockCategories) Tree() ([]domain.CategoryNode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Tree")
+	ret0, _ := ret[0].([]domain.CategoryNode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Tree indicates an expected call of Tree.
+func (mr *MockCategoriesMockRecorder) Tree() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Tree", reflect.TypeOf((*MockCategories)(nil).Tree))
+}
+
+// Update mocks base method.
+func (m *MockCategories) Update(categoryId string, input domain.UpdateCategoryInput) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", categoryId, input)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockCategoriesMockRecorder) Update(categoryId, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockCategories)(nil).Update), categoryId, input)
+}
+
+// MockFiles is a mock of Files interface.
+type MockFiles struct {
+	ctrl     *gomock.Controller
+	recorder *MockFilesMockRecorder
+}
+
+// MockFilesMockRecorder is the mock recorder for MockFiles.
+type MockFilesMockRecorder struct {
+	mock *MockFiles
+}
+
+// NewMockFiles creates a new mock instance.
+func NewMockFiles(ctrl *gomock.Controller) *MockFiles {
+	mock := &MockFiles{ctrl: ctrl}
+	mock.recorder = &MockFilesMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFiles) EXPECT() *MockFilesMockRecorder {
+	return m.recorder
+}
+
+// CompleteUpload mocks base method.
+func (m *MockFiles) CompleteUpload(fileId string) (domain.File, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteUpload", fileId)
+	ret0, _ := ret[0].(domain.File)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CompleteUpload indicates an expected call of CompleteUpload.
+func (mr *MockFilesMockRecorder) CompleteUpload(fileId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteUpload", reflect.TypeOf((*MockFiles)(nil).CompleteUpload), fileId)
+}
+
+// CreateUploadURL mocks base method.
+func (m *MockFiles) CreateUploadURL(input domain.CreateUploadURLInput) (domain.UploadURL, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUploadURL", input)
+	ret0, _ := ret[0].(domain.UploadURL)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUploadURL indicates an expected call of CreateUploadURL.
+func (mr *MockFilesMockRecorder) CreateUploadURL(input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUploadURL", reflect.TypeOf((*MockFiles)(nil).CreateUploadURL), input)
+}
+
+// DeleteImage mocks base method.
+func (m *MockFiles) DeleteImage(productId, imageId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteImage", productId, imageId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteImage indicates an expected call of DeleteImage.
+func (mr *MockFilesMockRecorder) DeleteImage(productId, imageId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteImage", reflect.TypeOf((*MockFiles)(nil).DeleteImage), productId, imageId)
+}
+
+// DiscardStaged mocks base method.
+func (m *MockFiles) DiscardStaged(file domain.File) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "DiscardStaged", file)
+}
+
+// DiscardStaged indicates an expected call of DiscardStaged.
+func (mr *MockFilesMockRecorder) DiscardStaged(file interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DiscardStaged", reflect.TypeOf((*MockFiles)(nil).DiscardStaged), file)
+}
+
+// FailStaleUploads mocks base method.
+func (m *MockFiles) FailStaleUploads() (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FailStaleUploads")
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FailStaleUploads indicates an expected call of FailStaleUploads.
+func (mr *MockFilesMockRecorder) FailStaleUploads() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FailStaleUploads", reflect.TypeOf((*MockFiles)(nil).FailStaleUploads))
+}
+
+// GetDownloadURL mocks base method.
+func (m *MockFiles) GetDownloadURL(fileId string) (domain.DownloadURL, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDownloadURL", fileId)
+	ret0, _ := ret[0].(domain.DownloadURL)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDownloadURL indicates an expected call of GetDownloadURL.
+func (mr *MockFilesMockRecorder) GetDownloadURL(fileId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDownloadURL", reflect.TypeOf((*MockFiles)(nil).GetDownloadURL), fileId)
+}
+
+// GetFile mocks base method.
+func (m *MockFiles) GetFile(fileId string) (domain.File, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFile", fileId)
+	ret0, _ := ret[0].(domain.File)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFile indicates an expected call of GetFile.
+func (mr *MockFilesMockRecorder) GetFile(fileId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFile", reflect.TypeOf((*MockFiles)(nil).GetFile), fileId)
+}
+
+// GetImages mocks base method.
+func (m *MockFiles) GetImages(productId string) ([]domain.File, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetImages", productId)
+	ret0, _ := ret[0].([]domain.File)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetImages indicates an expected call of GetImages.
+func (mr *MockFilesMockRecorder) GetImages(productId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetImages", reflect.TypeOf((*MockFiles)(nil).GetImages), productId)
+}
+
+// ReorderImages mocks base method.
+func (m *MockFiles) ReorderImages(productId string, input domain.ReorderImagesInput) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReorderImages", productId, input)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReorderImages indicates an expected call of ReorderImages.
+func (mr *MockFilesMockRecorder) ReorderImages(productId, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReorderImages", reflect.TypeOf((*MockFiles)(nil).ReorderImages), productId, input)
+}
+
+// RunUploads mocks base method.
+func (m *MockFiles) RunUploads(stop <-chan struct{}) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RunUploads", stop)
+}
+
+// RunUploads indicates an expected call of RunUploads.
+func (mr *MockFilesMockRecorder) RunUploads(stop interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunUploads", reflect.TypeOf((*MockFiles)(nil).RunUploads), stop)
+}
+
+// SetPrimaryImage mocks base method.
+func (m *MockFiles) SetPrimaryImage(productId, imageId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetPrimaryImage", productId, imageId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetPrimaryImage indicates an expected call of SetPrimaryImage.
+func (mr *MockFilesMockRecorder) SetPrimaryImage(productId, imageId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPrimaryImage", reflect.TypeOf((*MockFiles)(nil).SetPrimaryImage), productId, imageId)
+}
+
+// Stage mocks base method.
+func (m *MockFiles) Stage(r io.Reader) (domain.File, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stage", r)
+	ret0, _ := ret[0].(domain.File)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Stage indicates an expected call of Stage.
+func (mr *MockFilesMockRecorder) Stage(r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stage", reflect.TypeOf((*MockFiles)(nil).Stage), r)
+}
+
+// UpdateImage mocks base method.
+func (m *MockFiles) UpdateImage(productId, imageId string, input domain.UpdateImageInput) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateImage", productId, imageId, input)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateImage indicates an expected call of UpdateImage.
+func (mr *MockFilesMockRecorder) UpdateImage(productId, imageId, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateImage", reflect.TypeOf((*MockFiles)(nil).UpdateImage), productId, imageId, input)
+}
+
+// Upload mocks base method.
+func (m *MockFiles) Upload(file domain.File) (domain.File, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Upload", file)
+	ret0, _ := ret[0].(domain.File)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
 // Upload indicates an expected call of Upload.
-func (mr *MockFilesMockRecorder) Upload() *gomock.Call {
+func (mr *MockFilesMockRecorder) Upload(file interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upload", reflect.TypeOf((*MockFiles)(nil).Upload))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upload", reflect.TypeOf((*MockFiles)(nil).Upload), file)
 }
@@ -0,0 +1,432 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/AndrewMislyuk/go-shop-backend/internal/domain"
+	"github.com/AndrewMislyuk/go-shop-backend/internal/repository"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultOrdersLimit = 20
+	defaultPaymentTTL  = time.Hour
+)
+
+type OrdersService struct {
+	repo       repository.Orders
+	carts      repository.Carts
+	products   repository.ProductsList
+	inventory  repository.Inventory
+	promoCodes repository.PromoCodes
+	paymentTTL time.Duration
+}
+
+func NewOrdersService(repo repository.Orders, carts repository.Carts, products repository.ProductsList, inventory repository.Inventory,
+	promoCodes repository.PromoCodes, paymentTTL time.Duration) *OrdersService {
+	if paymentTTL == 0 {
+		paymentTTL = defaultPaymentTTL
+	}
+
+	return &OrdersService{
+		repo:       repo,
+		carts:      carts,
+		products:   products,
+		inventory:  inventory,
+		promoCodes: promoCodes,
+		paymentTTL: paymentTTL,
+	}
+}
+
+// Checkout turns the cart of the user into a pending order. The stock of
+// every line is reserved until the order is paid or cancelled. A cart whose
+// prices have changed since the customer last saw it is not checked out, the
+// customer has to review it first. The promo code of the input, if any, is
+// taken off the subtotal of the order.
+func (s *OrdersService) Checkout(userId string, input domain.CheckoutInput) (domain.Order, error) {
+	cart, err := s.carts.GetByUser(userId)
+	if err != nil {
+		if errors.Is(err, domain.ErrCartNotFound) {
+			return domain.Order{}, domain.ErrCartEmpty
+		}
+
+		return domain.Order{}, err
+	}
+
+	lines, err := s.carts.GetItems(cart.Id)
+	if err != nil {
+		return domain.Order{}, err
+	}
+
+	if len(lines) == 0 {
+		return domain.Order{}, domain.ErrCartEmpty
+	}
+
+	for _, line := range lines {
+		if line.UnitPrice != line.SeenPrice {
+			if err := s.carts.AcceptPrices(cart.Id, time.Now()); err != nil {
+				return domain.Order{}, err
+			}
+
+			return domain.Order{}, domain.ErrCartPricesChanged
+		}
+	}
+
+	// the cart is priced in the base currency, so is the order
+	currency := lines[0].UnitPrice.Currency
+
+	subtotal := domain.NewMoney(0, currency)
+	for _, line := range lines {
+		subtotal = subtotal.Add(line.UnitPrice.Mul(line.Quantity))
+	}
+
+	timestamp := time.Now()
+
+	var code *domain.PromoCode
+	if input.PromoCode != "" {
+		promoCode, err := s.promoCodes.GetByCode(domain.NormalizePromoCode(input.PromoCode))
+		if err != nil {
+			return domain.Order{}, err
+		}
+
+		if err := promoCode.CheckApplicable(subtotal.Amount, timestamp); err != nil {
+			return domain.Order{}, err
+		}
+
+		code = &promoCode
+	}
+
+	expiresAt := timestamp.Add(s.paymentTTL)
+	order := domain.Order{
+		Id:        uuid.New().String(),
+		UserId:    userId,
+		Status:    domain.OrderPending,
+		Items:     make([]domain.OrderItem, 0, len(lines)),
+		Subtotal:  domain.NewMoney(0, currency),
+		Discount:  domain.NewMoney(0, currency),
+		ExpiresAt: &expiresAt,
+		CreatedAt: timestamp,
+	}
+
+	for _, line := range lines {
+		item, err := s.snapshot(order.Id, line)
+		if err != nil {
+			s.releaseItems(order.Items)
+			return domain.Order{}, err
+		}
+
+		reservationId := uuid.New().String()
+		if err := s.inventory.Reserve(domain.Reservation{
+			Id:        reservationId,
+			ProductId: line.ProductId,
+			VariantId: line.VariantId,
+			Quantity:  line.Quantity,
+			Status:    domain.ReservationActive,
+			ExpiresAt: expiresAt,
+			CreatedAt: timestamp,
+		}); err != nil {
+			s.releaseItems(order.Items)
+			return domain.Order{}, err
+		}
+
+		item.ReservationId = &reservationId
+		order.Items = append(order.Items, item)
+		order.ItemsCount += item.Quantity
+		order.Subtotal = order.Subtotal.Add(item.LineTotal)
+	}
+
+	var redemption *domain.PromoCodeRedemption
+	if code != nil {
+		order.PromoCode = code.Code
+		order.Discount = domain.NewMoney(code.Amount(order.Subtotal.Amount), currency)
+		redemption = &domain.PromoCodeRedemption{
+			Id:          uuid.New().String(),
+			PromoCodeId: code.Id,
+			UserId:      userId,
+			OrderId:     order.Id,
+			Amount:      order.Discount.Amount,
+			CreatedAt:   timestamp,
+		}
+	}
+
+	order.Total = order.Subtotal.Sub(order.Discount)
+
+	order.Number, err = s.repo.Create(order, domain.OrderStatusChange{
+		Id:        uuid.New().String(),
+		OrderId:   order.Id,
+		ToStatus:  domain.OrderPending,
+		ActorId:   &userId,
+		CreatedAt: timestamp,
+	}, redemption)
+	if err != nil {
+		s.releaseItems(order.Items)
+		return domain.Order{}, err
+	}
+
+	if err := s.carts.Clear(cart.Id); err != nil {
+		logrus.Errorf("Checkout(): %s", err.Error())
+	}
+
+	return order, nil
+}
+
+func (s *OrdersService) GetMine(userId string, filter domain.OrdersFilter) (domain.OrdersList, error) {
+	filter.UserId = userId
+
+	return s.GetAll(filter)
+}
+
+// GetMineById hides the orders of other users as missing.
+func (s *OrdersService) GetMineById(userId, orderId string) (domain.Order, error) {
+	order, err := s.repo.GetById(orderId)
+	if err != nil {
+		return order, err
+	}
+
+	if order.UserId != userId {
+		return domain.Order{}, domain.ErrOrderNotFound
+	}
+
+	return order, nil
+}
+
+// CancelMine cancels an order of the user that hasn't been paid yet.
+func (s *OrdersService) CancelMine(userId, orderId string) error {
+	order, err := s.GetMineById(userId, orderId)
+	if err != nil {
+		return err
+	}
+
+	if order.Status != domain.OrderPending {
+		return domain.ErrInvalidOrderTransition
+	}
+
+	return s.changeStatus(order, userId, domain.OrderCancelled, "")
+}
+
+func (s *OrdersService) GetAll(filter domain.OrdersFilter) (domain.OrdersList, error) {
+	if filter.Status != "" && !domain.IsOrderStatus(filter.Status) {
+		return domain.OrdersList{}, domain.ErrUnknownOrderStatus
+	}
+
+	if filter.Limit == 0 {
+		filter.Limit = defaultOrdersLimit
+	}
+
+	return s.repo.GetAll(filter)
+}
+
+func (s *OrdersService) GetById(orderId string) (domain.Order, error) {
+	return s.repo.GetById(orderId)
+}
+
+// ChangeStatus moves the order to the next status on behalf of the admin
+// actorId. Paid and refunded follow the money, only the payments set them
+// through MarkPaid and MarkRefunded.
+func (s *OrdersService) ChangeStatus(actorId, orderId string, input domain.ChangeOrderStatusInput) error {
+	if !domain.IsOrderStatus(input.Status) {
+		return domain.ErrUnknownOrderStatus
+	}
+
+	if input.Status == domain.OrderPaid || input.Status == domain.OrderRefunded {
+		return domain.ErrOrderStatusByPayment
+	}
+
+	order, err := s.repo.GetById(orderId)
+	if err != nil {
+		return err
+	}
+
+	return s.changeStatus(order, actorId, input.Status, strings.TrimSpace(input.Comment))
+}
+
+// MarkPaid pays the order once its payment is captured and sells its
+// reserved stock. An order whose reservations are gone, e.g. expired before
+// the sweep has cancelled it, can't be paid: its stock may be sold already.
+func (s *OrdersService) MarkPaid(orderId, comment string) error {
+	order, err := s.repo.GetById(orderId)
+	if err != nil {
+		return err
+	}
+
+	if !domain.CanTransitionOrder(order.Status, domain.OrderPaid) {
+		return domain.ErrInvalidOrderTransition
+	}
+
+	if err := s.confirmItems(order.Items); err != nil {
+		if errors.Is(err, domain.ErrReservationNotActive) || errors.Is(err, domain.ErrReservationNotFound) {
+			return domain.ErrOrderNotPayable
+		}
+
+		return err
+	}
+
+	if err := s.recordStatus(order, "", domain.OrderPaid, comment); err != nil {
+		// the order stays unpaid, e.g. it has been cancelled meanwhile, so
+		// its stock goes back
+		s.returnItems(order, "", "order not paid")
+
+		return err
+	}
+
+	return nil
+}
+
+// MarkRefunded refunds the order once its payment is refunded, actorId is
+// the admin who asked for the refund or empty for the provider.
+func (s *OrdersService) MarkRefunded(actorId, orderId, comment string) error {
+	order, err := s.repo.GetById(orderId)
+	if err != nil {
+		return err
+	}
+
+	return s.changeStatus(order, actorId, domain.OrderRefunded, comment)
+}
+
+// CancelExpired cancels pending orders that haven't been paid in time.
+func (s *OrdersService) CancelExpired() (int, error) {
+	ids, err := s.repo.GetExpired(time.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	cancelled := 0
+	for _, id := range ids {
+		order, err := s.repo.GetById(id)
+		if err != nil {
+			return cancelled, err
+		}
+
+		err = s.changeStatus(order, "", domain.OrderCancelled, "not paid in time")
+		if errors.Is(err, domain.ErrOrderStatusChanged) || errors.Is(err, domain.ErrInvalidOrderTransition) {
+			continue
+		}
+		if err != nil {
+			return cancelled, err
+		}
+
+		cancelled++
+	}
+
+	return cancelled, nil
+}
+
+// changeStatus records the change and then moves the stock of the order:
+// cancelling releases the reserved stock and refunding a paid order puts the
+// sold stock back. The stock is moved on a best-effort basis, the order
+// status is the source of truth. Paying is done by MarkPaid.
+func (s *OrdersService) changeStatus(order domain.Order, actorId, status, comment string) error {
+	if err := s.recordStatus(order, actorId, status, comment); err != nil {
+		return err
+	}
+
+	switch {
+	case status == domain.OrderCancelled:
+		s.releaseItems(order.Items)
+	case status == domain.OrderRefunded && order.Status == domain.OrderPaid:
+		s.returnItems(order, actorId, "refund of order")
+	}
+
+	return nil
+}
+
+func (s *OrdersService) recordStatus(order domain.Order, actorId, status, comment string) error {
+	if !domain.CanTransitionOrder(order.Status, status) {
+		return domain.ErrInvalidOrderTransition
+	}
+
+	from := order.Status
+
+	return s.repo.UpdateStatus(domain.OrderStatusChange{
+		Id:         uuid.New().String(),
+		OrderId:    order.Id,
+		FromStatus: &from,
+		ToStatus:   status,
+		ActorId:    actorPointer(actorId),
+		Comment:    comment,
+		CreatedAt:  time.Now(),
+	})
+}
+
+func (s *OrdersService) snapshot(orderId string, line domain.CartItem) (domain.OrderItem, error) {
+	product, err := s.products.GetById(line.ProductId)
+	if err != nil {
+		return domain.OrderItem{}, err
+	}
+
+	productId := line.ProductId
+
+	return domain.OrderItem{
+		Id:           uuid.New().String(),
+		OrderId:      orderId,
+		ProductId:    &productId,
+		VariantId:    line.VariantId,
+		Title:        product.Title,
+		Sku:          line.Sku,
+		Options:      line.Options,
+		Price:        line.UnitPrice,
+		Sale:         product.Sale,
+		SaleOldPrice: product.SaleOldPrice,
+		Quantity:     line.Quantity,
+		LineTotal:    line.UnitPrice.Mul(line.Quantity),
+	}, nil
+}
+
+// confirmItems sells the reserved stock of all the items or of none.
+func (s *OrdersService) confirmItems(items []domain.OrderItem) error {
+	movements := make([]domain.StockMovement, 0, len(items))
+	for _, item := range items {
+		if item.ReservationId == nil {
+			continue
+		}
+
+		movements = append(movements, domain.StockMovement{
+			Id:            uuid.New().String(),
+			ReservationId: item.ReservationId,
+			CreatedAt:     time.Now(),
+		})
+	}
+
+	if len(movements) == 0 {
+		return nil
+	}
+
+	return s.inventory.ConfirmReservations(movements)
+}
+
+func (s *OrdersService) releaseItems(items []domain.OrderItem) {
+	for _, item := range items {
+		if item.ReservationId == nil {
+			continue
+		}
+
+		err := s.inventory.ReleaseReservation(*item.ReservationId, time.Now())
+		if err != nil && !errors.Is(err, domain.ErrReservationNotActive) {
+			logrus.Errorf("releaseItems(): %s", err.Error())
+		}
+	}
+}
+
+func (s *OrdersService) returnItems(order domain.Order, actorId, reason string) {
+	for _, item := range order.Items {
+		if item.ProductId == nil {
+			continue
+		}
+
+		if _, err := s.inventory.RecordMovement(domain.StockMovement{
+			Id:        uuid.New().String(),
+			ProductId: *item.ProductId,
+			VariantId: item.VariantId,
+			Kind:      domain.MovementReturn,
+			Quantity:  item.Quantity,
+			Reason:    reason,
+			ActorId:   actorPointer(actorId),
+			CreatedAt: time.Now(),
+		}, nil); err != nil {
+			logrus.Errorf("returnItems(): %s", err.Error())
+		}
+	}
+}
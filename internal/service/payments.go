@@ -0,0 +1,251 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/AndrewMislyuk/go-shop-backend/internal/domain"
+	"github.com/AndrewMislyuk/go-shop-backend/internal/repository"
+	"github.com/AndrewMislyuk/go-shop-backend/pkg/payment"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+type PaymentsService struct {
+	repo     repository.Payments
+	orders   Orders
+	provider payment.Provider
+}
+
+func NewPaymentsService(repo repository.Payments, orders Orders, provider payment.Provider) *PaymentsService {
+	return &PaymentsService{
+		repo:     repo,
+		orders:   orders,
+		provider: provider,
+	}
+}
+
+// Pay charges the total of a pending order of the user in the currency of the
+// order. The payment is
+// authorized and captured at once, the order becomes paid as soon as the
+// capture succeeds; the webhook of the provider confirms the same later.
+func (s *PaymentsService) Pay(userId, orderId string, input domain.PayOrderInput) (domain.Payment, error) {
+	order, err := s.orders.GetMineById(userId, orderId)
+	if err != nil {
+		return domain.Payment{}, err
+	}
+
+	// the reservations of an expired order are gone even if the sweep hasn't
+	// cancelled it yet
+	if order.Status != domain.OrderPending || order.ExpiresAt != nil && !order.ExpiresAt.After(time.Now()) {
+		return domain.Payment{}, domain.ErrOrderNotPayable
+	}
+
+	p := domain.Payment{
+		Id:        uuid.New().String(),
+		OrderId:   order.Id,
+		Provider:  s.provider.Name(),
+		Amount:    order.Total.Amount,
+		Currency:  order.Total.Currency,
+		CreatedAt: time.Now(),
+	}
+
+	intent, err := s.provider.CreateIntent(context.Background(), payment.CreateIntentInput{
+		Amount:         p.Amount,
+		Currency:       p.Currency,
+		PaymentMethod:  input.PaymentMethod,
+		Description:    fmt.Sprintf("order %d", order.Number),
+		IdempotencyKey: p.Id,
+	})
+	if err != nil {
+		return domain.Payment{}, err
+	}
+
+	p.IntentId = intent.Id
+	p.Status = domain.PaymentAuthorized
+	if intent.Status == payment.IntentFailed {
+		p.Status, p.FailureCode = domain.PaymentFailed, intent.FailureCode
+	}
+
+	if err := s.repo.Create(p); err != nil {
+		return domain.Payment{}, err
+	}
+
+	if p.Status == domain.PaymentFailed {
+		return p, domain.NewPaymentDeclinedError(p.FailureCode)
+	}
+
+	if _, err := s.provider.Capture(context.Background(), p.IntentId); err != nil {
+		if _, failErr := s.repo.UpdateStatus(p.Id, domain.PaymentAuthorized, domain.PaymentFailed, "capture_failed", time.Now()); failErr != nil {
+			logrus.Errorf("Pay(): %s", failErr.Error())
+		}
+
+		return domain.Payment{}, err
+	}
+
+	if err := s.captured(p); err != nil {
+		return domain.Payment{}, err
+	}
+
+	p.Status = domain.PaymentCaptured
+
+	return p, nil
+}
+
+// Refund returns the captured payment of the order on behalf of the admin
+// actorId and refunds the order.
+func (s *PaymentsService) Refund(actorId, orderId string) error {
+	order, err := s.orders.GetById(orderId)
+	if err != nil {
+		return err
+	}
+
+	if !domain.CanTransitionOrder(order.Status, domain.OrderRefunded) {
+		return domain.ErrInvalidOrderTransition
+	}
+
+	p, err := s.repo.GetByOrder(orderId, domain.PaymentCaptured)
+	if err != nil {
+		if errors.Is(err, domain.ErrPaymentNotFound) {
+			return domain.ErrOrderNotRefundable
+		}
+
+		return err
+	}
+
+	if _, err := s.provider.Refund(context.Background(), p.IntentId, p.Amount); err != nil {
+		if errors.Is(err, payment.ErrInvalidState) {
+			return domain.ErrOrderNotRefundable
+		}
+
+		return err
+	}
+
+	return s.refunded(p, actorId)
+}
+
+// HandleWebhook applies a notification of the provider. Webhooks may come
+// more than once and in any order, processed ones are skipped and every
+// change is applied only if it hasn't been applied yet.
+func (s *PaymentsService) HandleWebhook(payload []byte, signature string) error {
+	event, err := s.provider.VerifyWebhook(payload, signature)
+	if err != nil {
+		if errors.Is(err, payment.ErrInvalidSignature) {
+			return domain.ErrInvalidWebhook
+		}
+
+		return err
+	}
+
+	provider := s.provider.Name()
+
+	seen, err := s.repo.HasEvent(provider, event.Id)
+	if err != nil || seen {
+		return err
+	}
+
+	p, err := s.repo.GetByIntent(provider, event.IntentId)
+	if err != nil {
+		if !errors.Is(err, domain.ErrPaymentNotFound) {
+			return err
+		}
+
+		// Declines are reported before the payment is stored, Pay records
+		// them itself.
+		logrus.Infof("payment webhook %s of unknown intent %s", event.Type, event.IntentId)
+
+		return s.repo.RecordEvent(provider, event.Id, event.Type, nil, time.Now())
+	}
+
+	switch event.Type {
+	case payment.EventPaymentSucceeded:
+		// an order that can't be paid is cancelled and refunded by captured
+		if err = s.captured(p); errors.Is(err, domain.ErrOrderNotPayable) {
+			err = nil
+		}
+	case payment.EventPaymentFailed:
+		_, err = s.repo.UpdateStatus(p.Id, domain.PaymentAuthorized, domain.PaymentFailed, event.FailureCode, time.Now())
+	case payment.EventRefundSucceeded:
+		err = s.refunded(p, "")
+	}
+	if err != nil {
+		return err
+	}
+
+	return s.repo.RecordEvent(provider, event.Id, event.Type, &p.Id, time.Now())
+}
+
+// captured marks the payment captured and the order paid. An order cancelled
+// while the customer was paying can't be paid anymore, its money goes back.
+// So does the money of an order whose stock reservations are gone, the order
+// is cancelled and ErrOrderNotPayable returned.
+func (s *PaymentsService) captured(p domain.Payment) error {
+	changed, err := s.repo.UpdateStatus(p.Id, domain.PaymentAuthorized, domain.PaymentCaptured, "", time.Now())
+	if err != nil {
+		return err
+	}
+
+	order, err := s.orders.GetById(p.OrderId)
+	if err != nil {
+		return err
+	}
+
+	switch order.Status {
+	case domain.OrderPending:
+		err := s.orders.MarkPaid(order.Id, "payment "+p.IntentId)
+		if !errors.Is(err, domain.ErrOrderNotPayable) {
+			return err
+		}
+
+		err = s.orders.ChangeStatus("", order.Id, domain.ChangeOrderStatusInput{
+			Status:  domain.OrderCancelled,
+			Comment: "not paid in time",
+		})
+		if err != nil && !errors.Is(err, domain.ErrOrderStatusChanged) && !errors.Is(err, domain.ErrInvalidOrderTransition) {
+			return err
+		}
+
+		if err := s.refundCancelled(p, changed); err != nil {
+			return err
+		}
+
+		return domain.ErrOrderNotPayable
+	case domain.OrderCancelled:
+		return s.refundCancelled(p, changed)
+	}
+
+	return nil
+}
+
+// refundCancelled gives the money of a cancelled order back, only once: the
+// payment is refunded by whoever has captured it.
+func (s *PaymentsService) refundCancelled(p domain.Payment, captured bool) error {
+	if !captured {
+		return nil
+	}
+
+	_, err := s.provider.Refund(context.Background(), p.IntentId, p.Amount)
+
+	return err
+}
+
+// refunded marks the payment refunded and refunds the order unless it is
+// already refunded or has been cancelled.
+func (s *PaymentsService) refunded(p domain.Payment, actorId string) error {
+	if _, err := s.repo.UpdateStatus(p.Id, domain.PaymentCaptured, domain.PaymentRefunded, "", time.Now()); err != nil {
+		return err
+	}
+
+	order, err := s.orders.GetById(p.OrderId)
+	if err != nil {
+		return err
+	}
+
+	if !domain.CanTransitionOrder(order.Status, domain.OrderRefunded) {
+		return nil
+	}
+
+	return s.orders.MarkRefunded(actorId, order.Id, "refund of payment "+p.IntentId)
+}
@@ -0,0 +1,376 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AndrewMislyuk/go-shop-backend/internal/domain"
+	mock_repository "github.com/AndrewMislyuk/go-shop-backend/internal/repository/mock"
+	"github.com/AndrewMislyuk/go-shop-backend/pkg/payment"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testUserId    = "7c9e6679-7425-40de-944b-e07fc1f90ae7"
+	testCartId    = "1b4e28ba-2fa1-41d2-883f-0016d3cca427"
+	testProductId = "453b4f0f-1f56-4c57-b43d-7b79792450a7"
+	testAdminId   = "9f3c2a1b-8d7e-4f6a-b5c4-3d2e1f0a9b8c"
+)
+
+type webhook struct {
+	payload   []byte
+	signature string
+}
+
+// checkout runs the orders and payments services with the fake provider over
+// mocked repositories, which keep the single order of the user and its
+// payments in memory. The webhooks of the provider are queued until
+// delivered, unless deliverNow hands them over right away.
+type checkout struct {
+	t        *testing.T
+	orders   *OrdersService
+	payments *PaymentsService
+
+	order    domain.Order
+	stored   map[string]domain.Payment
+	events   map[string]bool
+	webhooks []webhook
+
+	deliverNow bool
+	confirmErr error
+	confirmed  int
+	released   int
+	returned   int
+}
+
+func newCheckout(t *testing.T, c *gomock.Controller) *checkout {
+	f := &checkout{
+		t:      t,
+		stored: make(map[string]domain.Payment),
+		events: make(map[string]bool),
+	}
+
+	provider := payment.NewFakeProvider("secret", func(payload []byte, signature string) {
+		if f.deliverNow {
+			assert.NoError(t, f.payments.HandleWebhook(payload, signature))
+
+			return
+		}
+
+		f.webhooks = append(f.webhooks, webhook{payload: payload, signature: signature})
+	})
+
+	price := domain.NewMoney(749000, "RUB")
+
+	carts := mock_repository.NewMockCarts(c)
+	carts.EXPECT().GetByUser(testUserId).Return(domain.Cart{Id: testCartId}, nil).AnyTimes()
+	carts.EXPECT().GetItems(testCartId).Return([]domain.CartItem{
+		{ProductId: testProductId, Quantity: 2, UnitPrice: price, SeenPrice: price},
+	}, nil).AnyTimes()
+	carts.EXPECT().Clear(testCartId).Return(nil).AnyTimes()
+
+	products := mock_repository.NewMockProductsList(c)
+	products.EXPECT().GetById(testProductId).Return(domain.ProductsList{Id: testProductId, Title: "Твидовый кардиган из хлопка", Price: price}, nil).AnyTimes()
+
+	inventory := mock_repository.NewMockInventory(c)
+	inventory.EXPECT().Reserve(gomock.Any()).Return(nil).AnyTimes()
+	inventory.EXPECT().ConfirmReservations(gomock.Any()).DoAndReturn(func(movements []domain.StockMovement) error {
+		if f.confirmErr != nil {
+			return f.confirmErr
+		}
+
+		f.confirmed += len(movements)
+
+		return nil
+	}).AnyTimes()
+	inventory.EXPECT().ReleaseReservation(gomock.Any(), gomock.Any()).DoAndReturn(func(reservationId string, timestamp time.Time) error {
+		f.released++
+
+		return nil
+	}).AnyTimes()
+	inventory.EXPECT().RecordMovement(gomock.Any(), gomock.Any()).DoAndReturn(func(movement domain.StockMovement, version *int) (domain.StockLevel, error) {
+		f.returned += movement.Quantity
+
+		return domain.StockLevel{}, nil
+	}).AnyTimes()
+
+	orders := mock_repository.NewMockOrders(c)
+	orders.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(order domain.Order, created domain.OrderStatusChange, redemption *domain.PromoCodeRedemption) (int64, error) {
+		f.order = order
+
+		return 1, nil
+	}).AnyTimes()
+	orders.EXPECT().GetById(gomock.Any()).DoAndReturn(func(orderId string) (domain.Order, error) {
+		if orderId != f.order.Id {
+			return domain.Order{}, domain.ErrOrderNotFound
+		}
+
+		return f.order, nil
+	}).AnyTimes()
+	orders.EXPECT().UpdateStatus(gomock.Any()).DoAndReturn(func(change domain.OrderStatusChange) error {
+		if *change.FromStatus != f.order.Status {
+			return domain.ErrOrderStatusChanged
+		}
+
+		f.order.Status = change.ToStatus
+
+		return nil
+	}).AnyTimes()
+
+	payments := mock_repository.NewMockPayments(c)
+	payments.EXPECT().Create(gomock.Any()).DoAndReturn(func(p domain.Payment) error {
+		// payments_order_id_active_key
+		for _, stored := range f.stored {
+			if stored.OrderId == p.OrderId && stored.Status != domain.PaymentFailed {
+				return domain.ErrOrderNotPayable
+			}
+		}
+
+		f.stored[p.Id] = p
+
+		return nil
+	}).AnyTimes()
+	payments.EXPECT().GetByIntent(gomock.Any(), gomock.Any()).DoAndReturn(func(provider, intentId string) (domain.Payment, error) {
+		for _, stored := range f.stored {
+			if stored.IntentId == intentId {
+				return stored, nil
+			}
+		}
+
+		return domain.Payment{}, domain.ErrPaymentNotFound
+	}).AnyTimes()
+	payments.EXPECT().GetByOrder(gomock.Any(), gomock.Any()).DoAndReturn(func(orderId, status string) (domain.Payment, error) {
+		for _, stored := range f.stored {
+			if stored.OrderId == orderId && stored.Status == status {
+				return stored, nil
+			}
+		}
+
+		return domain.Payment{}, domain.ErrPaymentNotFound
+	}).AnyTimes()
+	payments.EXPECT().UpdateStatus(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(paymentId, from, to, failureCode string, timestamp time.Time) (bool, error) {
+		stored := f.stored[paymentId]
+		if stored.Status != from {
+			return false, nil
+		}
+
+		stored.Status, stored.FailureCode = to, failureCode
+		f.stored[paymentId] = stored
+
+		return true, nil
+	}).AnyTimes()
+	payments.EXPECT().HasEvent("fake", gomock.Any()).DoAndReturn(func(provider, eventId string) (bool, error) {
+		return f.events[eventId], nil
+	}).AnyTimes()
+	payments.EXPECT().RecordEvent("fake", gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(provider, eventId, eventType string, paymentId *string, timestamp time.Time) error {
+		f.events[eventId] = true
+
+		return nil
+	}).AnyTimes()
+
+	f.orders = NewOrdersService(orders, carts, products, inventory, nil, time.Hour)
+	f.payments = NewPaymentsService(payments, f.orders, provider)
+
+	return f
+}
+
+// deliver hands the queued webhooks to the service twice, the way a provider
+// retrying them would.
+func (f *checkout) deliver() {
+	webhooks := f.webhooks
+	f.webhooks = nil
+
+	for _, w := range webhooks {
+		assert.NoError(f.t, f.payments.HandleWebhook(w.payload, w.signature))
+		assert.NoError(f.t, f.payments.HandleWebhook(w.payload, w.signature))
+	}
+}
+
+func (f *checkout) payment() domain.Payment {
+	assert.Len(f.t, f.stored, 1)
+
+	for _, p := range f.stored {
+		return p
+	}
+
+	return domain.Payment{}
+}
+
+func TestPaymentsService_CheckoutPayRefund(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	f := newCheckout(t, c)
+
+	order, err := f.orders.Checkout(testUserId, domain.CheckoutInput{})
+	assert.NoError(t, err)
+	assert.Equal(t, domain.OrderPending, order.Status)
+	assert.Equal(t, domain.NewMoney(1498000, "RUB"), order.Total)
+
+	p, err := f.payments.Pay(testUserId, order.Id, domain.PayOrderInput{PaymentMethod: payment.CardSucceeds})
+	assert.NoError(t, err)
+	assert.Equal(t, domain.PaymentCaptured, p.Status)
+	assert.Equal(t, domain.OrderPaid, f.order.Status)
+	assert.Equal(t, 1, f.confirmed)
+
+	// payment.succeeded confirms what Pay has done already
+	f.deliver()
+	assert.Equal(t, domain.OrderPaid, f.order.Status)
+	assert.Equal(t, domain.PaymentCaptured, f.payment().Status)
+	assert.Equal(t, 1, f.confirmed)
+
+	_, err = f.payments.Pay(testUserId, order.Id, domain.PayOrderInput{PaymentMethod: payment.CardSucceeds})
+	assert.ErrorIs(t, err, domain.ErrOrderNotPayable)
+
+	assert.NoError(t, f.payments.Refund(testAdminId, order.Id))
+	assert.Equal(t, domain.OrderRefunded, f.order.Status)
+	assert.Equal(t, domain.PaymentRefunded, f.payment().Status)
+	assert.Equal(t, 2, f.returned)
+
+	// refund.succeeded finds everything refunded
+	f.deliver()
+	assert.Equal(t, domain.OrderRefunded, f.order.Status)
+	assert.Equal(t, 2, f.returned)
+
+	assert.ErrorIs(t, f.payments.Refund(testAdminId, order.Id), domain.ErrInvalidOrderTransition)
+}
+
+func TestPaymentsService_WebhookBeforePayEnds(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	f := newCheckout(t, c)
+	f.deliverNow = true
+
+	order, err := f.orders.Checkout(testUserId, domain.CheckoutInput{})
+	assert.NoError(t, err)
+
+	p, err := f.payments.Pay(testUserId, order.Id, domain.PayOrderInput{PaymentMethod: payment.CardSucceeds})
+	assert.NoError(t, err)
+	assert.Equal(t, domain.PaymentCaptured, p.Status)
+	assert.Equal(t, domain.OrderPaid, f.order.Status)
+	assert.Equal(t, 1, f.confirmed)
+	assert.Len(t, f.events, 1)
+}
+
+func TestPaymentsService_Declined(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	f := newCheckout(t, c)
+
+	order, err := f.orders.Checkout(testUserId, domain.CheckoutInput{})
+	assert.NoError(t, err)
+
+	_, err = f.payments.Pay(testUserId, order.Id, domain.PayOrderInput{PaymentMethod: payment.CardDeclined})
+	assert.EqualError(t, err, "payment was declined: card_declined")
+	assert.Equal(t, domain.PaymentFailed, f.payment().Status)
+	assert.Equal(t, domain.OrderPending, f.order.Status)
+
+	f.deliver()
+	assert.Equal(t, domain.OrderPending, f.order.Status)
+
+	// a failed payment doesn't hold the order
+	_, err = f.payments.Pay(testUserId, order.Id, domain.PayOrderInput{PaymentMethod: payment.CardSucceeds})
+	assert.NoError(t, err)
+	assert.Equal(t, domain.OrderPaid, f.order.Status)
+}
+
+func TestPaymentsService_PayTwice(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	f := newCheckout(t, c)
+
+	order, err := f.orders.Checkout(testUserId, domain.CheckoutInput{})
+	assert.NoError(t, err)
+
+	// another request is paying the order, its payment is authorized
+	f.stored["0f8e7d6c-5b4a-4392-8170-6f5e4d3c2b1a"] = domain.Payment{
+		Id:       "0f8e7d6c-5b4a-4392-8170-6f5e4d3c2b1a",
+		OrderId:  order.Id,
+		IntentId: "pi_other",
+		Status:   domain.PaymentAuthorized,
+	}
+
+	_, err = f.payments.Pay(testUserId, order.Id, domain.PayOrderInput{PaymentMethod: payment.CardSucceeds})
+	assert.ErrorIs(t, err, domain.ErrOrderNotPayable)
+	assert.Equal(t, domain.OrderPending, f.order.Status)
+	assert.Equal(t, 0, f.confirmed)
+	assert.Len(t, f.webhooks, 0)
+}
+
+func TestPaymentsService_PayExpired(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	f := newCheckout(t, c)
+
+	order, err := f.orders.Checkout(testUserId, domain.CheckoutInput{})
+	assert.NoError(t, err)
+
+	// not swept yet
+	expiresAt := time.Now().Add(-time.Minute)
+	f.order.ExpiresAt = &expiresAt
+
+	_, err = f.payments.Pay(testUserId, order.Id, domain.PayOrderInput{PaymentMethod: payment.CardSucceeds})
+	assert.ErrorIs(t, err, domain.ErrOrderNotPayable)
+	assert.Len(t, f.stored, 0)
+	assert.Equal(t, domain.OrderPending, f.order.Status)
+}
+
+func TestPaymentsService_PayWithoutReservations(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	f := newCheckout(t, c)
+
+	order, err := f.orders.Checkout(testUserId, domain.CheckoutInput{})
+	assert.NoError(t, err)
+
+	f.confirmErr = domain.ErrReservationNotActive
+
+	_, err = f.payments.Pay(testUserId, order.Id, domain.PayOrderInput{PaymentMethod: payment.CardSucceeds})
+	assert.ErrorIs(t, err, domain.ErrOrderNotPayable)
+	assert.Equal(t, domain.OrderCancelled, f.order.Status)
+	assert.Equal(t, 1, f.released)
+
+	// payment.succeeded and refund.succeeded of the money given back
+	assert.Len(t, f.webhooks, 2)
+	f.deliver()
+	assert.Equal(t, domain.OrderCancelled, f.order.Status)
+	assert.Equal(t, domain.PaymentRefunded, f.payment().Status)
+	assert.Equal(t, 0, f.returned)
+}
+
+func TestPaymentsService_HandleWebhookInvalidSignature(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	f := newCheckout(t, c)
+
+	payload := []byte(`{"id":"evt_1","type":"refund.succeeded","intent_id":"pi_1"}`)
+
+	err := f.payments.HandleWebhook(payload, payment.Sign([]byte("other"), payload, time.Now()))
+	assert.ErrorIs(t, err, domain.ErrInvalidWebhook)
+	assert.Len(t, f.events, 0)
+}
+
+func TestOrdersService_ChangeStatusSetByPayment(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	f := newCheckout(t, c)
+
+	order, err := f.orders.Checkout(testUserId, domain.CheckoutInput{})
+	assert.NoError(t, err)
+
+	for _, status := range []string{domain.OrderPaid, domain.OrderRefunded} {
+		err := f.orders.ChangeStatus(testAdminId, order.Id, domain.ChangeOrderStatusInput{Status: status})
+		assert.ErrorIs(t, err, domain.ErrOrderStatusByPayment)
+	}
+
+	assert.Equal(t, domain.OrderPending, f.order.Status)
+	assert.Equal(t, 0, f.confirmed)
+}
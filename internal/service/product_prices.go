@@ -0,0 +1,68 @@
+package service
+
+import (
+	"time"
+
+	"github.com/AndrewMislyuk/go-shop-backend/internal/domain"
+	"github.com/AndrewMislyuk/go-shop-backend/internal/repository"
+)
+
+type ProductPricesService struct {
+	repo     repository.ProductPrices
+	products repository.ProductsList
+	base     string
+}
+
+// NewProductPricesService keeps the price lists of currencies other than base.
+func NewProductPricesService(repo repository.ProductPrices, products repository.ProductsList, base string) *ProductPricesService {
+	return &ProductPricesService{
+		repo:     repo,
+		products: products,
+		base:     base,
+	}
+}
+
+func (s *ProductPricesService) GetByProduct(productId string) ([]domain.ProductPrice, error) {
+	if _, err := s.products.GetById(productId); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetByProduct(productId)
+}
+
+// Set lists the regular price of the product in currency, sales apply to it
+// the same way they do to the base price.
+func (s *ProductPricesService) Set(productId, currency string, input domain.SetProductPriceInput) error {
+	currency, err := s.currency(currency)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.Set(domain.ProductPrice{
+		ProductId: productId,
+		Price:     domain.NewMoney(input.Amount, currency),
+		UpdatedAt: time.Now(),
+	})
+}
+
+func (s *ProductPricesService) Delete(productId, currency string) error {
+	currency, err := s.currency(currency)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.Delete(productId, currency)
+}
+
+func (s *ProductPricesService) currency(code string) (string, error) {
+	code = domain.NormalizeCurrency(code)
+	if !domain.IsCurrency(code) {
+		return "", domain.ErrUnknownCurrency
+	}
+
+	if code == s.base {
+		return "", domain.ErrBaseCurrency
+	}
+
+	return code, nil
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"time"
+
+	"github.com/AndrewMislyuk/go-shop-backend/internal/domain"
+	"github.com/AndrewMislyuk/go-shop-backend/internal/repository"
+	"github.com/google/uuid"
+)
+
+type ProductVariantsService struct {
+	repo     repository.ProductVariants
+	products repository.ProductsList
+}
+
+func NewProductVariantsService(repo repository.ProductVariants, products repository.ProductsList) *ProductVariantsService {
+	return &ProductVariantsService{
+		repo:     repo,
+		products: products,
+	}
+}
+
+func (s *ProductVariantsService) Create(productId string, input domain.CreateVariantInput) (string, error) {
+	if err := input.Validate(); err != nil {
+		return "", err
+	}
+
+	variant := domain.ProductVariant{
+		Id:        uuid.New().String(),
+		ProductId: productId,
+		Sku:       domain.NormalizeSku(input.Sku),
+		Options:   input.Options,
+		CreatedAt: time.Now(),
+	}
+
+	if input.Price != nil {
+		price, err := s.price(productId, *input.Price)
+		if err != nil {
+			return "", err
+		}
+
+		variant.Price = &price
+	}
+
+	if variant.Options == nil {
+		variant.Options = make(map[string]string)
+	}
+
+	if err := s.repo.Create(variant); err != nil {
+		return "", err
+	}
+
+	return variant.Id, nil
+}
+
+func (s *ProductVariantsService) GetByProduct(productId string) ([]domain.ProductVariant, error) {
+	if _, err := s.products.GetById(productId); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetByProduct(productId)
+}
+
+func (s *ProductVariantsService) Update(productId, variantId string, input domain.UpdateVariantInput) error {
+	if err := input.Validate(); err != nil {
+		return err
+	}
+
+	if input.Sku != nil {
+		sku := domain.NormalizeSku(*input.Sku)
+		input.Sku = &sku
+	}
+
+	if input.Price != nil && !input.UseProductPrice {
+		if _, err := s.price(productId, *input.Price); err != nil {
+			return err
+		}
+	}
+
+	return s.repo.Update(productId, variantId, input, time.Now())
+}
+
+func (s *ProductVariantsService) Delete(productId, variantId string) error {
+	return s.repo.Delete(productId, variantId)
+}
+
+// price checks that the price of a variant is in the currency of its product.
+func (s *ProductVariantsService) price(productId string, input domain.PriceInput) (domain.Money, error) {
+	product, err := s.products.GetById(productId)
+	if err != nil {
+		return domain.Money{}, err
+	}
+
+	return input.Money(product.Price.Currency)
+}
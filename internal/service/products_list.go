@@ -2,67 +2,309 @@ package service
 
 import (
 	"context"
-	"fmt"
-	"strings"
 	"time"
 
 	"github.com/AndrewMislyuk/go-shop-backend/internal/domain"
 	"github.com/AndrewMislyuk/go-shop-backend/internal/repository"
 	"github.com/AndrewMislyuk/go-shop-backend/pkg/storage"
 	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
 )
 
+const defaultProductsLimit = 20
+
 type ProductsListService struct {
-	repo    repository.ProductsList
-	storage storage.Provider
+	repo       repository.ProductsList
+	categories repository.Categories
+	variants   repository.ProductVariants
+	images     repository.Files
+	rates      repository.ExchangeRates
+	prices     repository.ProductPrices
+	storage    storage.Provider
+	currency   string
 }
 
-func NewProductsListService(repo repository.ProductsList, storage storage.Provider) *ProductsListService {
+// NewProductsListService prices the products in currency, the base currency.
+func NewProductsListService(repo repository.ProductsList, categories repository.Categories, variants repository.ProductVariants,
+	images repository.Files, rates repository.ExchangeRates, prices repository.ProductPrices, storage storage.Provider, currency string) *ProductsListService {
 	return &ProductsListService{
-		repo:    repo,
-		storage: storage,
+		repo:       repo,
+		categories: categories,
+		variants:   variants,
+		images:     images,
+		rates:      rates,
+		prices:     prices,
+		storage:    storage,
+		currency:   currency,
 	}
 }
 
 func (s *ProductsListService) Create(list domain.CreateProductInput) (string, error) {
+	price, err := list.Price.Money(s.currency)
+	if err != nil {
+		return "", err
+	}
+
+	list.Price.Currency = price.Currency
+
+	if err := s.checkSubtype(list.CategoryId); err != nil {
+		return "", err
+	}
+
 	productId := uuid.New().String()
 	timestamp := time.Now()
 
 	return s.repo.Create(list, productId, timestamp)
 }
 
-func (s *ProductsListService) GetAll() ([]domain.ProductsList, error) {
-	return s.repo.GetAll()
+// GetAll returns a page of products with the prices in filter.Currency, if
+// given.
+func (s *ProductsListService) GetAll(filter domain.ProductsFilter) (domain.ProductsPage, error) {
+	filter.Currency = domain.NormalizeCurrency(filter.Currency)
+	if err := filter.Validate(); err != nil {
+		return domain.ProductsPage{}, err
+	}
+
+	if filter.Limit == 0 {
+		filter.Limit = defaultProductsLimit
+	}
+
+	if filter.Sort == "" {
+		filter.Sort = domain.DefaultProductsSort
+	}
+
+	var after *domain.ProductsCursor
+	if filter.Cursor != "" {
+		cursor, err := domain.DecodeProductsCursor(filter.Cursor, filter.Sort)
+		if err != nil {
+			return domain.ProductsPage{}, err
+		}
+
+		after = &cursor
+	}
+
+	page, err := s.repo.GetAll(filter, after)
+	if err != nil {
+		return page, err
+	}
+
+	products := make([]*domain.ProductsList, len(page.Products))
+	for i := range page.Products {
+		products[i] = &page.Products[i]
+	}
+
+	if err := s.attachImages(products); err != nil {
+		return page, err
+	}
+
+	return page, s.localize(products, filter.Currency)
+}
+
+func (s *ProductsListService) Search(input domain.ProductsSearchInput) (domain.ProductsSearchPage, error) {
+	input.Currency = domain.NormalizeCurrency(input.Currency)
+	if err := input.Validate(); err != nil {
+		return domain.ProductsSearchPage{}, err
+	}
+
+	if input.Limit == 0 {
+		input.Limit = defaultProductsLimit
+	}
+
+	page, err := s.repo.Search(input)
+	if err != nil {
+		return page, err
+	}
+
+	products := make([]*domain.ProductsList, len(page.Results))
+	for i := range page.Results {
+		products[i] = &page.Results[i].ProductsList
+	}
+
+	if err := s.attachImages(products); err != nil {
+		return page, err
+	}
+
+	return page, s.localize(products, input.Currency)
 }
 
-func (s *ProductsListService) GetById(listId string) (domain.ProductsList, error) {
-	return s.repo.GetById(listId)
+// GetById returns the product with its variants and images, the prices are in currency
+// unless it is empty.
+func (s *ProductsListService) GetById(listId, currency string) (domain.ProductsList, error) {
+	currency = domain.NormalizeCurrency(currency)
+	if currency != "" && !domain.IsCurrency(currency) {
+		return domain.ProductsList{}, domain.ErrUnknownCurrency
+	}
+
+	product, err := s.repo.GetById(listId)
+	if err != nil {
+		return product, err
+	}
+
+	if product.Variants, err = s.variants.GetByProduct(listId); err != nil {
+		return product, err
+	}
+
+	if product.Images, err = s.images.GetByProduct(listId); err != nil {
+		return product, err
+	}
+
+	return product, s.localize([]*domain.ProductsList{&product}, currency)
 }
 
 func (s *ProductsListService) Update(itemId string, input domain.UpdateProductInput) error {
+	if input.Price != nil {
+		price, err := input.Price.Money(s.currency)
+		if err != nil {
+			return err
+		}
+
+		input.Price.Currency = price.Currency
+	}
+
+	if input.CategoryId != nil {
+		if err := s.checkSubtype(*input.CategoryId); err != nil {
+			return err
+		}
+	}
+
 	return s.repo.Update(itemId, input)
 }
 
+// Delete removes the product along with the files of its gallery.
 func (s *ProductsListService) Delete(itemId string) error {
-	product, err := s.GetById(itemId)
+	images, err := s.images.GetByProduct(itemId)
 	if err != nil {
 		return err
 	}
 
-	if product.Image != "" {
-		filename := s.parseImageURL(product.Image)
+	if err := s.repo.Delete(itemId); err != nil {
+		return err
+	}
 
-		err := s.storage.Delete(context.Background(), filename)
-		if err != nil {
-			return err
+	for _, image := range images {
+		for _, name := range image.ObjectNames() {
+			if err := s.storage.Delete(context.Background(), name); err != nil {
+				logrus.Errorf("Delete(): %s", err.Error())
+			}
 		}
 	}
 
-	return s.repo.Delete(itemId)
+	return nil
 }
 
-func (s *ProductsListService) parseImageURL(url string) string {
-	str := strings.Split(url, "/")
+// attachImages fills in the galleries of the products.
+func (s *ProductsListService) attachImages(products []*domain.ProductsList) error {
+	if len(products) == 0 {
+		return nil
+	}
+
+	ids := make([]string, len(products))
+	for i, product := range products {
+		ids[i] = product.Id
+	}
+
+	images, err := s.images.GetByProducts(ids)
+	if err != nil {
+		return err
+	}
+
+	for _, product := range products {
+		product.Images = images[product.Id]
+	}
+
+	return nil
+}
+
+// localize converts the prices of the products, variants included, into
+// currency. A price listed for the product in currency is taken as is and the
+// other prices of the product keep their proportions to it, the rest are
+// converted at the exchange rate of currency.
+func (s *ProductsListService) localize(products []*domain.ProductsList, currency string) error {
+	if currency == "" || len(products) == 0 {
+		return nil
+	}
+
+	ids := make([]string, 0, len(products))
+	for _, product := range products {
+		if product.Price.Currency != currency {
+			ids = append(ids, product.Id)
+		}
+	}
+
+	if len(ids) == 0 {
+		return nil
+	}
+
+	listed, err := s.prices.GetByProducts(ids, currency)
+	if err != nil {
+		return err
+	}
+
+	var rated *domain.MoneyConverter
+	for _, product := range products {
+		if product.Price.Currency == currency {
+			continue
+		}
+
+		var converter domain.MoneyConverter
+		if price, ok := listed[product.Id]; ok {
+			regular := product.Price
+			if !product.SaleOldPrice.IsZero() {
+				regular = product.SaleOldPrice
+			}
+
+			converter = domain.NewPriceListConverter(regular, price)
+		} else {
+			if rated == nil {
+				rate, err := s.rates.Get(currency)
+				if err != nil {
+					return err
+				}
+
+				converter, err := domain.NewRateConverter(s.currency, rate)
+				if err != nil {
+					return err
+				}
+
+				rated = &converter
+			}
+
+			converter = *rated
+		}
+
+		convertProduct(product, converter)
+	}
+
+	return nil
+}
+
+func convertProduct(product *domain.ProductsList, converter domain.MoneyConverter) {
+	product.Price = converter.Convert(product.Price)
+	product.SaleOldPrice = converter.Convert(product.SaleOldPrice)
+	product.MinPrice = converter.Convert(product.MinPrice)
+	product.MaxPrice = converter.Convert(product.MaxPrice)
+
+	for i := range product.Variants {
+		variant := &product.Variants[i]
+		if variant.Price != nil {
+			price := converter.Convert(*variant.Price)
+			variant.Price = &price
+		}
+
+		variant.SalePrice = converter.Convert(variant.SalePrice)
+	}
+}
+
+// checkSubtype makes sure products are only assigned to leaves of the taxonomy.
+func (s *ProductsListService) checkSubtype(categoryId string) error {
+	category, err := s.categories.GetById(categoryId)
+	if err != nil {
+		return err
+	}
+
+	if category.Level != domain.CategoryLevelSubtype {
+		return domain.ErrCategoryNotLeaf
+	}
 
-	return fmt.Sprintf("images/%s", str[len(str)-1])
+	return nil
 }
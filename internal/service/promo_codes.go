@@ -0,0 +1,84 @@
+package service
+
+import (
+	"time"
+
+	"github.com/AndrewMislyuk/go-shop-backend/internal/domain"
+	"github.com/AndrewMislyuk/go-shop-backend/internal/repository"
+	"github.com/google/uuid"
+)
+
+const defaultPromoCodesLimit = 20
+
+type PromoCodesService struct {
+	repo repository.PromoCodes
+}
+
+func NewPromoCodesService(repo repository.PromoCodes) *PromoCodesService {
+	return &PromoCodesService{
+		repo: repo,
+	}
+}
+
+func (s *PromoCodesService) Create(input domain.CreatePromoCodeInput) (string, error) {
+	timestamp := time.Now()
+	code := domain.PromoCode{
+		Id:             uuid.New().String(),
+		Code:           domain.NormalizePromoCode(input.Code),
+		Kind:           input.Kind,
+		Value:          input.Value,
+		MinOrderAmount: input.MinOrderAmount,
+		UsageLimit:     input.UsageLimit,
+		PerUserLimit:   input.PerUserLimit,
+		StartsAt:       timestamp,
+		EndsAt:         input.EndsAt,
+		Active:         true,
+		CreatedAt:      timestamp,
+	}
+
+	if input.StartsAt != nil {
+		code.StartsAt = *input.StartsAt
+	}
+
+	if err := code.Validate(); err != nil {
+		return "", err
+	}
+
+	if err := s.repo.Create(code); err != nil {
+		return "", err
+	}
+
+	return code.Id, nil
+}
+
+func (s *PromoCodesService) GetById(codeId string) (domain.PromoCode, error) {
+	return s.repo.GetById(codeId)
+}
+
+func (s *PromoCodesService) GetAll(filter domain.PromoCodesFilter) (domain.PromoCodesList, error) {
+	if filter.Limit == 0 {
+		filter.Limit = defaultPromoCodesLimit
+	}
+
+	return s.repo.GetAll(filter)
+}
+
+func (s *PromoCodesService) Update(codeId string, input domain.UpdatePromoCodeInput) error {
+	code, err := s.repo.GetById(codeId)
+	if err != nil {
+		return err
+	}
+
+	if err := input.Apply(&code); err != nil {
+		return err
+	}
+
+	timestamp := time.Now()
+	code.UpdatedAt = &timestamp
+
+	return s.repo.Update(code)
+}
+
+func (s *PromoCodesService) Delete(codeId string) error {
+	return s.repo.Delete(codeId)
+}
@@ -0,0 +1,44 @@
+package service
+
+import (
+	"time"
+
+	"github.com/AndrewMislyuk/go-shop-backend/internal/domain"
+	"github.com/AndrewMislyuk/go-shop-backend/internal/repository"
+)
+
+type RolesService struct {
+	repo  repository.Roles
+	cache *userCache
+}
+
+func NewRolesService(repo repository.Roles, cache *userCache) *RolesService {
+	return &RolesService{
+		repo:  repo,
+		cache: cache,
+	}
+}
+
+func (s *RolesService) GetAll() ([]domain.Role, error) {
+	return s.repo.GetAll()
+}
+
+func (s *RolesService) Grant(userId, role string) error {
+	if err := s.repo.Grant(userId, role, time.Now()); err != nil {
+		return err
+	}
+
+	s.cache.Delete(userId)
+
+	return nil
+}
+
+func (s *RolesService) Revoke(userId, role string) error {
+	if err := s.repo.Revoke(userId, role); err != nil {
+		return err
+	}
+
+	s.cache.Delete(userId)
+
+	return nil
+}
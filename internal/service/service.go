@@ -1,8 +1,16 @@
 package service
 
 import (
+	"io"
+	"time"
+
 	"github.com/AndrewMislyuk/go-shop-backend/internal/domain"
 	"github.com/AndrewMislyuk/go-shop-backend/internal/repository"
+	"github.com/AndrewMislyuk/go-shop-backend/pkg/auth"
+	"github.com/AndrewMislyuk/go-shop-backend/pkg/hash"
+	"github.com/AndrewMislyuk/go-shop-backend/pkg/imaging"
+	"github.com/AndrewMislyuk/go-shop-backend/pkg/mailer"
+	"github.com/AndrewMislyuk/go-shop-backend/pkg/payment"
 	"github.com/AndrewMislyuk/go-shop-backend/pkg/storage"
 )
 
@@ -10,32 +18,214 @@ import (
 
 type User interface {
 	CreateUser(user domain.UserSignUp) (string, error)
-	GenerateToken(email, password string) (string, error)
+	GenerateToken(email, password, clientIP string) (domain.Tokens, error)
+	RefreshTokens(refreshToken string) (domain.Tokens, error)
+	Logout(refreshToken string) error
+	LogoutAll(userId string) error
 	GetMe(token string) (domain.User, error)
+	Identify(token string) (domain.Identity, error)
+	ChangePassword(userId, sessionId string, input domain.ChangePasswordInput) error
+	VerifyEmail(token string) error
+	ForgotPassword(email string) error
+	ResetPassword(token, newPassword string) error
+	JWKS() auth.JSONWebKeySet
+	DeleteExpiredLoginAttempts() (int, error)
+}
+
+type Users interface {
+	GetAll(filter domain.UsersFilter) (domain.UsersList, error)
+	UpdateMe(userId string, input domain.UpdateUserInput) error
+	DeleteMe(userId string) error
+	Block(adminId, userId string) error
+	Unblock(userId string) error
+}
+
+type Roles interface {
+	GetAll() ([]domain.Role, error)
+	Grant(userId, role string) error
+	Revoke(userId, role string) error
 }
 
 type ProductsList interface {
 	Create(list domain.CreateProductInput) (string, error)
-	GetAll() ([]domain.ProductsList, error)
-	GetById(listId string) (domain.ProductsList, error)
+	GetAll(filter domain.ProductsFilter) (domain.ProductsPage, error)
+	Search(input domain.ProductsSearchInput) (domain.ProductsSearchPage, error)
+	GetById(listId, currency string) (domain.ProductsList, error)
 	Update(itemId string, input domain.UpdateProductInput) error
 	Delete(itemId string) error
 }
 
+type ProductVariants interface {
+	Create(productId string, input domain.CreateVariantInput) (string, error)
+	GetByProduct(productId string) ([]domain.ProductVariant, error)
+	Update(productId, variantId string, input domain.UpdateVariantInput) error
+	Delete(productId, variantId string) error
+}
+
+type Inventory interface {
+	GetLevels(filter domain.StockLevelsFilter) (domain.StockLevelsList, error)
+	GetMovements(filter domain.StockMovementsFilter) (domain.StockMovementsList, error)
+	RecordMovement(actorId string, input domain.StockMovementInput) (domain.StockLevel, error)
+	Reserve(input domain.CreateReservationInput) (domain.Reservation, error)
+	ConfirmReservation(actorId, reservationId string) error
+	ReleaseReservation(reservationId string) error
+	ExpireReservations() (int, error)
+}
+
+type Carts interface {
+	Get(owner domain.CartOwner) (domain.Cart, error)
+	AddItem(owner domain.CartOwner, input domain.AddCartItemInput) (domain.Cart, error)
+	UpdateItem(owner domain.CartOwner, itemId string, input domain.UpdateCartItemInput) (domain.Cart, error)
+	RemoveItem(owner domain.CartOwner, itemId string) (domain.Cart, error)
+	Merge(userId, token string) error
+	DeleteExpired() (int, error)
+}
+
+type Orders interface {
+	Checkout(userId string, input domain.CheckoutInput) (domain.Order, error)
+	GetMine(userId string, filter domain.OrdersFilter) (domain.OrdersList, error)
+	GetMineById(userId, orderId string) (domain.Order, error)
+	CancelMine(userId, orderId string) error
+	GetAll(filter domain.OrdersFilter) (domain.OrdersList, error)
+	GetById(orderId string) (domain.Order, error)
+	ChangeStatus(actorId, orderId string, input domain.ChangeOrderStatusInput) error
+	MarkPaid(orderId, comment string) error
+	MarkRefunded(actorId, orderId, comment string) error
+	CancelExpired() (int, error)
+}
+
+type Payments interface {
+	Pay(userId, orderId string, input domain.PayOrderInput) (domain.Payment, error)
+	Refund(actorId, orderId string) error
+	HandleWebhook(payload []byte, signature string) error
+}
+
+type Discounts interface {
+	Create(input domain.CreateDiscountInput) (string, error)
+	GetById(discountId string) (domain.Discount, error)
+	GetAll(filter domain.DiscountsFilter) (domain.DiscountsList, error)
+	Update(discountId string, input domain.UpdateDiscountInput) error
+	Delete(discountId string) error
+	RefreshSales() (int, error)
+}
+
+type PromoCodes interface {
+	Create(input domain.CreatePromoCodeInput) (string, error)
+	GetById(codeId string) (domain.PromoCode, error)
+	GetAll(filter domain.PromoCodesFilter) (domain.PromoCodesList, error)
+	Update(codeId string, input domain.UpdatePromoCodeInput) error
+	Delete(codeId string) error
+}
+
+type Currencies interface {
+	GetRates() (domain.ExchangeRates, error)
+	SetRates(input domain.SetExchangeRatesInput) error
+}
+
+type ProductPrices interface {
+	GetByProduct(productId string) ([]domain.ProductPrice, error)
+	Set(productId, currency string, input domain.SetProductPriceInput) error
+	Delete(productId, currency string) error
+}
+
+type Categories interface {
+	Create(input domain.CreateCategoryInput) (string, error)
+	GetById(categoryId string) (domain.Category, error)
+	Tree() ([]domain.CategoryNode, error)
+	Update(categoryId string, input domain.UpdateCategoryInput) error
+	Delete(categoryId string) error
+}
+
 type Files interface {
-	Upload(file domain.File) (string, error)
+	Stage(r io.Reader) (domain.File, error)
+	DiscardStaged(file domain.File)
+	Upload(file domain.File) (domain.File, error)
+	GetFile(fileId string) (domain.File, error)
+	CreateUploadURL(input domain.CreateUploadURLInput) (domain.UploadURL, error)
+	CompleteUpload(fileId string) (domain.File, error)
+	GetDownloadURL(fileId string) (domain.DownloadURL, error)
+	RunUploads(stop <-chan struct{})
+	FailStaleUploads() (int, error)
+	GetImages(productId string) ([]domain.File, error)
+	UpdateImage(productId, imageId string, input domain.UpdateImageInput) error
+	ReorderImages(productId string, input domain.ReorderImagesInput) error
+	SetPrimaryImage(productId, imageId string) error
+	DeleteImage(productId, imageId string) error
 }
 
 type Service struct {
 	User
+	Users
+	Roles
 	ProductsList
+	ProductVariants
+	Inventory
+	Carts
+	Orders
+	Payments
+	Discounts
+	PromoCodes
+	Currencies
+	ProductPrices
+	Categories
 	Files
 }
 
-func NewService(repos *repository.Repository, storage storage.Provider) *Service {
+type Deps struct {
+	Repos                *repository.Repository
+	Storage              storage.Provider
+	ImageProcessor       *imaging.Processor
+	Hasher               hash.PasswordHasher
+	TokenManager         auth.TokenManager
+	Mailer               mailer.Mailer
+	PaymentProvider      payment.Provider
+	LoginAttempts        repository.LoginAttempts
+	LoginGuard           LoginGuardConfig
+	UploadQueue          UploadQueueConfig
+	DirectUploads        DirectUploadConfig
+	AccessTokenTTL       time.Duration
+	RefreshTokenTTL      time.Duration
+	EmailVerificationTTL time.Duration
+	PasswordResetTTL     time.Duration
+	RequireVerifiedEmail bool
+	LegacyPasswordSalt   string
+	AppURL               string
+	ReservationTTL       time.Duration
+	GuestCartTTL         time.Duration
+	PaymentTTL           time.Duration
+	BaseCurrency         string
+}
+
+func NewService(deps Deps) *Service {
+	cache := newUserCache(userCacheTTL)
+	guard := NewLoginGuard(deps.LoginAttempts, deps.LoginGuard)
+	authConfig := AuthConfig{
+		AccessTokenTTL:       deps.AccessTokenTTL,
+		RefreshTokenTTL:      deps.RefreshTokenTTL,
+		LegacyPasswordSalt:   deps.LegacyPasswordSalt,
+		EmailVerificationTTL: deps.EmailVerificationTTL,
+		PasswordResetTTL:     deps.PasswordResetTTL,
+		RequireVerifiedEmail: deps.RequireVerifiedEmail,
+		AppURL:               deps.AppURL,
+	}
+
+	orders := NewOrdersService(deps.Repos.Orders, deps.Repos.Carts, deps.Repos.ProductsList, deps.Repos.Inventory, deps.Repos.PromoCodes, deps.PaymentTTL)
+
 	return &Service{
-		User:         NewAuthService(repos.Authorization),
-		ProductsList: NewProductsListService(repos.ProductsList, storage),
-		Files:        NewFileService(repos.Files, storage),
+		User:            NewAuthService(deps.Repos.Authorization, deps.Repos.Sessions, deps.Repos.UserTokens, deps.Hasher, deps.TokenManager, deps.Mailer, guard, cache, authConfig),
+		Users:           NewUsersService(deps.Repos.Users, deps.Repos.Sessions, cache),
+		Roles:           NewRolesService(deps.Repos.Roles, cache),
+		ProductsList:    NewProductsListService(deps.Repos.ProductsList, deps.Repos.Categories, deps.Repos.ProductVariants, deps.Repos.Files, deps.Repos.ExchangeRates, deps.Repos.ProductPrices, deps.Storage, deps.BaseCurrency),
+		ProductVariants: NewProductVariantsService(deps.Repos.ProductVariants, deps.Repos.ProductsList),
+		Inventory:       NewInventoryService(deps.Repos.Inventory, deps.Repos.ProductVariants, deps.ReservationTTL),
+		Carts:           NewCartsService(deps.Repos.Carts, deps.Repos.ProductsList, deps.Repos.ProductVariants, deps.BaseCurrency, deps.GuestCartTTL),
+		Orders:          orders,
+		Payments:        NewPaymentsService(deps.Repos.Payments, orders, deps.PaymentProvider),
+		Discounts:       NewDiscountsService(deps.Repos.Discounts),
+		PromoCodes:      NewPromoCodesService(deps.Repos.PromoCodes),
+		Currencies:      NewCurrenciesService(deps.Repos.ExchangeRates, deps.BaseCurrency),
+		ProductPrices:   NewProductPricesService(deps.Repos.ProductPrices, deps.Repos.ProductsList, deps.BaseCurrency),
+		Categories:      NewCategoriesService(deps.Repos.Categories),
+		Files:           NewFileService(deps.Repos.Files, deps.Repos.ProductsList, deps.Storage, deps.ImageProcessor, deps.UploadQueue, deps.DirectUploads),
 	}
 }
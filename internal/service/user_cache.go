@@ -0,0 +1,67 @@
+package service
+
+import (
+	"sync"
+	"time"
+
+	"github.com/AndrewMislyuk/go-shop-backend/internal/domain"
+)
+
+const userCacheTTL = 30 * time.Second
+
+type userCacheItem struct {
+	user      domain.User
+	expiresAt time.Time
+}
+
+// userCache keeps recently resolved users so that authenticating a request
+// does not hit the database every time. Services that change users must call
+// Delete, the TTL only bounds staleness for changes made by other instances.
+type userCache struct {
+	mu    sync.RWMutex
+	ttl   time.Duration
+	items map[string]userCacheItem
+}
+
+func newUserCache(ttl time.Duration) *userCache {
+	return &userCache{
+		ttl:   ttl,
+		items: make(map[string]userCacheItem),
+	}
+}
+
+func (c *userCache) Get(userId string) (domain.User, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	item, ok := c.items[userId]
+	if !ok || time.Now().After(item.expiresAt) {
+		return domain.User{}, false
+	}
+
+	return item.user, true
+}
+
+func (c *userCache) Set(user domain.User) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	for id, item := range c.items {
+		if now.After(item.expiresAt) {
+			delete(c.items, id)
+		}
+	}
+
+	c.items[user.Id] = userCacheItem{
+		user:      user,
+		expiresAt: now.Add(c.ttl),
+	}
+}
+
+func (c *userCache) Delete(userId string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.items, userId)
+}
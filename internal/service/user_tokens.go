@@ -0,0 +1,133 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/AndrewMislyuk/go-shop-backend/internal/domain"
+	"github.com/AndrewMislyuk/go-shop-backend/pkg/mailer"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	verificationEmailSubject = "Подтверждение email"
+	verificationEmailBody    = "Здравствуйте!\n\nЧтобы подтвердить email, перейдите по ссылке:\n%s\n\nСсылка действительна до %s.\n"
+
+	passwordResetEmailSubject = "Восстановление пароля"
+	passwordResetEmailBody    = "Здравствуйте!\n\nЧтобы задать новый пароль, перейдите по ссылке:\n%s\n\nСсылка действительна до %s. Если вы не запрашивали восстановление пароля, просто проигнорируйте это письмо.\n"
+)
+
+func (a *Auth) VerifyEmail(token string) error {
+	timestamp := time.Now()
+
+	stored, err := a.userTokens.Consume(hashToken(token), domain.TokenPurposeEmailVerification, timestamp)
+	if err != nil {
+		return err
+	}
+
+	if err := a.repo.SetEmailVerified(stored.UserId, timestamp); err != nil {
+		return err
+	}
+
+	a.cache.Delete(stored.UserId)
+
+	return nil
+}
+
+// ForgotPassword sends a password reset link. Unknown and deleted accounts
+// are silently ignored, so the response doesn't reveal which emails exist.
+func (a *Auth) ForgotPassword(email string) error {
+	user, err := a.repo.GetUserByEmail(domain.NormalizeEmail(email))
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return nil
+		}
+
+		return err
+	}
+
+	if user.DeletedAt != nil {
+		return nil
+	}
+
+	link, expiresAt, err := a.issueUserToken(user.Id, domain.TokenPurposePasswordReset, a.config.PasswordResetTTL, "reset-password")
+	if err != nil {
+		return err
+	}
+
+	return a.mailer.Send(mailer.Message{
+		To:      user.Email,
+		Subject: passwordResetEmailSubject,
+		Body:    fmt.Sprintf(passwordResetEmailBody, link, expiresAt.Format("02.01.2006 15:04 MST")),
+	})
+}
+
+// ResetPassword sets a new password and signs the user out everywhere.
+// Following the link proves the email belongs to the user, so an unverified
+// email becomes verified.
+func (a *Auth) ResetPassword(token, newPassword string) error {
+	timestamp := time.Now()
+
+	stored, err := a.userTokens.Consume(hashToken(token), domain.TokenPurposePasswordReset, timestamp)
+	if err != nil {
+		return err
+	}
+
+	passwordHash, err := a.hasher.Hash(newPassword)
+	if err != nil {
+		return err
+	}
+
+	if err := a.repo.UpdatePasswordHash(stored.UserId, passwordHash); err != nil {
+		return err
+	}
+
+	if err := a.repo.SetEmailVerified(stored.UserId, timestamp); err != nil {
+		logrus.Errorf("ResetPassword(): %s", err.Error())
+	}
+
+	a.cache.Delete(stored.UserId)
+
+	return a.sessions.RevokeUserSessions(stored.UserId, timestamp)
+}
+
+func (a *Auth) sendVerificationEmail(userId, email string) error {
+	link, expiresAt, err := a.issueUserToken(userId, domain.TokenPurposeEmailVerification, a.config.EmailVerificationTTL, "verify-email")
+	if err != nil {
+		return err
+	}
+
+	return a.mailer.Send(mailer.Message{
+		To:      email,
+		Subject: verificationEmailSubject,
+		Body:    fmt.Sprintf(verificationEmailBody, link, expiresAt.Format("02.01.2006 15:04 MST")),
+	})
+}
+
+// issueUserToken stores a new single-use token and returns the frontend link
+// that carries it.
+func (a *Auth) issueUserToken(userId, purpose string, ttl time.Duration, path string) (string, time.Time, error) {
+	rawToken, err := randomToken()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	timestamp := time.Now()
+	expiresAt := timestamp.Add(ttl)
+
+	if err := a.userTokens.Create(domain.UserToken{
+		Id:        uuid.New().String(),
+		UserId:    userId,
+		Purpose:   purpose,
+		TokenHash: hashToken(rawToken),
+		ExpiresAt: expiresAt,
+		CreatedAt: timestamp,
+	}); err != nil {
+		return "", time.Time{}, err
+	}
+
+	return fmt.Sprintf("%s/%s?token=%s", a.config.AppURL, path, url.QueryEscape(rawToken)), expiresAt, nil
+}
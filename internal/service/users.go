@@ -0,0 +1,87 @@
+package service
+
+import (
+	"time"
+
+	"github.com/AndrewMislyuk/go-shop-backend/internal/domain"
+	"github.com/AndrewMislyuk/go-shop-backend/internal/repository"
+)
+
+const defaultUsersLimit = 20
+
+type UsersService struct {
+	repo     repository.Users
+	sessions repository.Sessions
+	cache    *userCache
+}
+
+func NewUsersService(repo repository.Users, sessions repository.Sessions, cache *userCache) *UsersService {
+	return &UsersService{
+		repo:     repo,
+		sessions: sessions,
+		cache:    cache,
+	}
+}
+
+func (s *UsersService) GetAll(filter domain.UsersFilter) (domain.UsersList, error) {
+	if filter.Limit == 0 {
+		filter.Limit = defaultUsersLimit
+	}
+
+	return s.repo.GetAll(filter)
+}
+
+func (s *UsersService) UpdateMe(userId string, input domain.UpdateUserInput) error {
+	if err := input.Validate(); err != nil {
+		return err
+	}
+
+	if err := input.Normalize(); err != nil {
+		return err
+	}
+
+	if err := s.repo.Update(userId, input, time.Now()); err != nil {
+		return err
+	}
+
+	s.cache.Delete(userId)
+
+	return nil
+}
+
+// DeleteMe anonymizes the account instead of deleting the row, so orders and
+// other records referencing the user stay consistent.
+func (s *UsersService) DeleteMe(userId string) error {
+	if err := s.repo.Anonymize(userId, time.Now()); err != nil {
+		return err
+	}
+
+	s.cache.Delete(userId)
+
+	return nil
+}
+
+func (s *UsersService) Block(adminId, userId string) error {
+	if adminId == userId {
+		return domain.ErrBlockYourself
+	}
+
+	timestamp := time.Now()
+	if err := s.repo.SetBlocked(userId, &timestamp); err != nil {
+		return err
+	}
+
+	s.cache.Delete(userId)
+
+	return s.sessions.RevokeUserSessions(userId, timestamp)
+}
+
+func (s *UsersService) Unblock(userId string) error {
+	if err := s.repo.SetBlocked(userId, nil); err != nil {
+		return err
+	}
+
+	s.cache.Delete(userId)
+
+	return nil
+}
@@ -0,0 +1,229 @@
+package auth
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/golang-jwt/jwt"
+)
+
+const (
+	HS256 = "HS256"
+	RS256 = "RS256"
+	EdDSA = "EdDSA"
+
+	minHMACSecretLength = 32
+)
+
+var (
+	ErrUnknownKey       = errors.New("unknown signing key")
+	ErrUnsupportedAlg   = errors.New("unsupported signing algorithm")
+	ErrVerificationOnly = errors.New("key can only be used for verification")
+)
+
+// TokenManager signs tokens with the active key and verifies tokens signed
+// with any of the configured keys, so keys can be rotated without
+// invalidating tokens that are still in flight.
+type TokenManager interface {
+	Sign(claims jwt.Claims) (string, error)
+	Parse(token string, claims jwt.Claims) error
+	JWKS() JSONWebKeySet
+}
+
+type Key struct {
+	Id        string
+	Algorithm string
+	signKey   interface{}
+	verifyKey interface{}
+}
+
+func NewHMACKey(id string, secret []byte) (Key, error) {
+	if len(secret) < minHMACSecretLength {
+		return Key{}, fmt.Errorf("key %s: hmac secret must be at least %d bytes", id, minHMACSecretLength)
+	}
+
+	return Key{
+		Id:        id,
+		Algorithm: HS256,
+		signKey:   secret,
+		verifyKey: secret,
+	}, nil
+}
+
+func ParsePrivateKey(id, alg string, data []byte) (Key, error) {
+	key := Key{Id: id, Algorithm: alg}
+
+	switch alg {
+	case RS256:
+		private, err := jwt.ParseRSAPrivateKeyFromPEM(data)
+		if err != nil {
+			return key, fmt.Errorf("key %s: %w", id, err)
+		}
+
+		key.signKey, key.verifyKey = private, &private.PublicKey
+	case EdDSA:
+		private, err := jwt.ParseEdPrivateKeyFromPEM(data)
+		if err != nil {
+			return key, fmt.Errorf("key %s: %w", id, err)
+		}
+
+		edPrivate, ok := private.(ed25519.PrivateKey)
+		if !ok {
+			return key, fmt.Errorf("key %s: not an ed25519 private key", id)
+		}
+
+		key.signKey, key.verifyKey = edPrivate, edPrivate.Public()
+	default:
+		return key, fmt.Errorf("key %s: %w: %s", id, ErrUnsupportedAlg, alg)
+	}
+
+	return key, nil
+}
+
+// ParsePublicKey loads a key that is only used to verify tokens, e.g. a key
+// that has been rotated out but whose tokens have not expired yet.
+func ParsePublicKey(id, alg string, data []byte) (Key, error) {
+	key := Key{Id: id, Algorithm: alg}
+
+	switch alg {
+	case RS256:
+		public, err := jwt.ParseRSAPublicKeyFromPEM(data)
+		if err != nil {
+			return key, fmt.Errorf("key %s: %w", id, err)
+		}
+
+		key.verifyKey = public
+	case EdDSA:
+		public, err := jwt.ParseEdPublicKeyFromPEM(data)
+		if err != nil {
+			return key, fmt.Errorf("key %s: %w", id, err)
+		}
+
+		key.verifyKey = public
+	default:
+		return key, fmt.Errorf("key %s: %w: %s", id, ErrUnsupportedAlg, alg)
+	}
+
+	return key, nil
+}
+
+type JWTManager struct {
+	signing Key
+	keys    map[string]Key
+}
+
+func NewJWTManager(signingKeyId string, keys ...Key) (*JWTManager, error) {
+	m := &JWTManager{
+		keys: make(map[string]Key, len(keys)),
+	}
+
+	for _, key := range keys {
+		if _, ok := m.keys[key.Id]; ok {
+			return nil, fmt.Errorf("duplicate key id: %s", key.Id)
+		}
+
+		m.keys[key.Id] = key
+	}
+
+	signing, ok := m.keys[signingKeyId]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownKey, signingKeyId)
+	}
+
+	if signing.signKey == nil {
+		return nil, fmt.Errorf("%w: %s", ErrVerificationOnly, signingKeyId)
+	}
+
+	m.signing = signing
+
+	return m, nil
+}
+
+func (m *JWTManager) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(jwt.GetSigningMethod(m.signing.Algorithm), claims)
+	token.Header["kid"] = m.signing.Id
+
+	return token.SignedString(m.signing.signKey)
+}
+
+func (m *JWTManager) Parse(token string, claims jwt.Claims) error {
+	_, err := jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+
+		key, ok := m.keys[kid]
+		if !ok {
+			return nil, ErrUnknownKey
+		}
+
+		if t.Method.Alg() != key.Algorithm {
+			return nil, fmt.Errorf("unexpected signing method: %s", t.Method.Alg())
+		}
+
+		return key.verifyKey, nil
+	})
+
+	// jwt.ValidationError does not support errors.Is, expose the cause instead.
+	var validationErr *jwt.ValidationError
+	if errors.As(err, &validationErr) && validationErr.Inner != nil {
+		return validationErr.Inner
+	}
+
+	return err
+}
+
+type JSONWebKey struct {
+	KeyType   string `json:"kty"`
+	KeyId     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	Curve     string `json:"crv,omitempty"`
+	X         string `json:"x,omitempty"`
+	N         string `json:"n,omitempty"`
+	E         string `json:"e,omitempty"`
+}
+
+type JSONWebKeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
+// JWKS returns the public part of every asymmetric key. HMAC secrets are
+// never published.
+func (m *JWTManager) JWKS() JSONWebKeySet {
+	set := JSONWebKeySet{
+		Keys: make([]JSONWebKey, 0, len(m.keys)),
+	}
+
+	for _, key := range m.keys {
+		switch public := key.verifyKey.(type) {
+		case *rsa.PublicKey:
+			set.Keys = append(set.Keys, JSONWebKey{
+				KeyType:   "RSA",
+				KeyId:     key.Id,
+				Use:       "sig",
+				Algorithm: key.Algorithm,
+				N:         base64.RawURLEncoding.EncodeToString(public.N.Bytes()),
+				E:         base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes()),
+			})
+		case ed25519.PublicKey:
+			set.Keys = append(set.Keys, JSONWebKey{
+				KeyType:   "OKP",
+				KeyId:     key.Id,
+				Use:       "sig",
+				Algorithm: key.Algorithm,
+				Curve:     "Ed25519",
+				X:         base64.RawURLEncoding.EncodeToString(public),
+			})
+		}
+	}
+
+	sort.Slice(set.Keys, func(i, j int) bool {
+		return set.Keys[i].KeyId < set.Keys[j].KeyId
+	})
+
+	return set
+}
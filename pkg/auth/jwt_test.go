@@ -0,0 +1,74 @@
+package auth
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJWTManager_Rotation(t *testing.T) {
+	oldKey, err := NewHMACKey("2022-06-hs256", []byte("0123456789abcdef0123456789abcdef"))
+	assert.NoError(t, err)
+
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	assert.NoError(t, err)
+
+	newKey, err := ParsePrivateKey("2022-07-eddsa", EdDSA, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+	assert.NoError(t, err)
+
+	claims := func() *jwt.StandardClaims {
+		return &jwt.StandardClaims{
+			Subject:   "34c8d3e6-b8d7-43dc-847e-5764c4114856",
+			ExpiresAt: time.Now().Add(time.Minute).Unix(),
+		}
+	}
+
+	before, err := NewJWTManager(oldKey.Id, oldKey)
+	assert.NoError(t, err)
+
+	oldToken, err := before.Sign(claims())
+	assert.NoError(t, err)
+
+	after, err := NewJWTManager(newKey.Id, oldKey, newKey)
+	assert.NoError(t, err)
+
+	newToken, err := after.Sign(claims())
+	assert.NoError(t, err)
+
+	for _, token := range []string{oldToken, newToken} {
+		parsed := &jwt.StandardClaims{}
+		assert.NoError(t, after.Parse(token, parsed))
+		assert.Equal(t, "34c8d3e6-b8d7-43dc-847e-5764c4114856", parsed.Subject)
+	}
+
+	assert.ErrorIs(t, before.Parse(newToken, &jwt.StandardClaims{}), ErrUnknownKey)
+
+	jwks := after.JWKS()
+	assert.Len(t, jwks.Keys, 1)
+	assert.Equal(t, "2022-07-eddsa", jwks.Keys[0].KeyId)
+	assert.Equal(t, "OKP", jwks.Keys[0].KeyType)
+	assert.Equal(t, "Ed25519", jwks.Keys[0].Curve)
+}
+
+func TestNewJWTManager(t *testing.T) {
+	_, err := NewHMACKey("short", []byte("secret"))
+	assert.Error(t, err)
+
+	key, err := NewHMACKey("2022-06-hs256", []byte("0123456789abcdef0123456789abcdef"))
+	assert.NoError(t, err)
+
+	_, err = NewJWTManager("missing", key)
+	assert.ErrorIs(t, err, ErrUnknownKey)
+
+	_, err = NewJWTManager(key.Id, key, key)
+	assert.Error(t, err)
+}
@@ -0,0 +1,35 @@
+package hash
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestArgon2idHasher(t *testing.T) {
+	hasher := NewArgon2idHasher(Argon2Params{Memory: 1024, Iterations: 1, Threads: 1, SaltLength: 16, KeyLength: 32})
+
+	encoded, err := hasher.Hash("1234QWER@")
+	assert.NoError(t, err)
+	assert.True(t, IsArgon2idHash(encoded))
+	assert.False(t, hasher.NeedsRehash(encoded))
+
+	ok, err := hasher.Verify("1234QWER@", encoded)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = hasher.Verify("wrong-password", encoded)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	other, err := hasher.Hash("1234QWER@")
+	assert.NoError(t, err)
+	assert.NotEqual(t, encoded, other)
+
+	stronger := NewArgon2idHasher(Argon2Params{Memory: 2048, Iterations: 1, Threads: 1, SaltLength: 16, KeyLength: 32})
+	assert.True(t, stronger.NeedsRehash(encoded))
+	assert.True(t, stronger.NeedsRehash("73757065726861736806b3b1d9b33ddde6bed8bb1b6bd5b2d56e53339c"))
+
+	_, err = hasher.Verify("1234QWER@", "$argon2id$broken")
+	assert.ErrorIs(t, err, ErrInvalidHash)
+}
@@ -0,0 +1,228 @@
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+
+	"github.com/chai2010/webp"
+	"golang.org/x/image/draw"
+)
+
+// Original names the output of the full size image.
+const Original = "original"
+
+const defaultQuality = 85
+
+// MaxPixels bounds the size of the images, decoding one takes 4 bytes a
+// pixel and orienting it twice as much again.
+const MaxPixels = 40 * 1000 * 1000
+
+var (
+	ErrUnsupportedFormat = errors.New("unsupported image format")
+	ErrInvalidImage      = errors.New("invalid image")
+)
+
+type Format string
+
+const (
+	JPEG Format = "jpeg"
+	PNG  Format = "png"
+	WebP Format = "webp"
+)
+
+var contentTypes = map[Format]string{
+	JPEG: "image/jpeg",
+	PNG:  "image/png",
+	WebP: "image/webp",
+}
+
+var extensions = map[Format]string{
+	JPEG: "jpg",
+	PNG:  "png",
+	WebP: "webp",
+}
+
+// FormatOf returns the format of the content type.
+func FormatOf(contentType string) (Format, error) {
+	switch contentType {
+	case "image/jpeg":
+		return JPEG, nil
+	case "image/png":
+		return PNG, nil
+	case "image/webp":
+		return WebP, nil
+	default:
+		return "", ErrUnsupportedFormat
+	}
+}
+
+func (f Format) ContentType() string {
+	return contentTypes[f]
+}
+
+func (f Format) Extension() string {
+	return extensions[f]
+}
+
+// Rendition is a size of the image. The image is scaled down to fit in
+// Width by Height, keeping its proportions, a zero bound leaves that side
+// unbounded. Images are never scaled up.
+type Rendition struct {
+	Name   string
+	Width  int
+	Height int
+}
+
+// Output is one encoded rendition of an image.
+type Output struct {
+	Rendition string
+	Format    Format
+	Width     int
+	Height    int
+	Data      []byte
+}
+
+func (o Output) ContentType() string {
+	return o.Format.ContentType()
+}
+
+// Processor turns an uploaded image into its renditions. Every rendition is
+// encoded in WebP and in the format of the upload, the Original one only in
+// the latter. The images are decoded and encoded again, so none of the
+// metadata of the upload survives, and JPEG images are rotated by their EXIF
+// orientation first. Images of more than MaxPixels are invalid.
+type Processor struct {
+	renditions []Rendition
+	quality    int
+}
+
+// NewProcessor encodes the lossy formats with quality, from 1 to 100.
+func NewProcessor(renditions []Rendition, quality int) *Processor {
+	if quality <= 0 || quality > 100 {
+		quality = defaultQuality
+	}
+
+	return &Processor{
+		renditions: renditions,
+		quality:    quality,
+	}
+}
+
+func (p *Processor) Process(r io.Reader, format Format) ([]Output, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidImage, err)
+	}
+
+	if int64(config.Width)*int64(config.Height) > MaxPixels {
+		return nil, fmt.Errorf("%w: %dx%d is more than %d pixels", ErrInvalidImage, config.Width, config.Height, MaxPixels)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidImage, err)
+	}
+
+	if format == JPEG {
+		img = orient(img, jpegOrientation(data))
+	}
+
+	original, err := p.encode(img, Original, format)
+	if err != nil {
+		return nil, err
+	}
+
+	outputs := []Output{original}
+	for _, rendition := range p.renditions {
+		scaled := scale(img, rendition.Width, rendition.Height)
+
+		formats := []Format{WebP}
+		if format != WebP {
+			formats = append(formats, format)
+		}
+
+		for _, f := range formats {
+			output, err := p.encode(scaled, rendition.Name, f)
+			if err != nil {
+				return nil, err
+			}
+
+			outputs = append(outputs, output)
+		}
+	}
+
+	return outputs, nil
+}
+
+func (p *Processor) encode(img image.Image, rendition string, format Format) (Output, error) {
+	var (
+		buf bytes.Buffer
+		err error
+	)
+
+	switch format {
+	case JPEG:
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: p.quality})
+	case PNG:
+		err = png.Encode(&buf, img)
+	case WebP:
+		err = webp.Encode(&buf, img, &webp.Options{Quality: float32(p.quality)})
+	default:
+		err = ErrUnsupportedFormat
+	}
+
+	if err != nil {
+		return Output{}, fmt.Errorf("encode %s %s: %w", rendition, format, err)
+	}
+
+	bounds := img.Bounds()
+
+	return Output{
+		Rendition: rendition,
+		Format:    format,
+		Width:     bounds.Dx(),
+		Height:    bounds.Dy(),
+		Data:      buf.Bytes(),
+	}, nil
+}
+
+// scale fits the image in width by height.
+func scale(img image.Image, width, height int) image.Image {
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+
+	ratio := 1.0
+	if width > 0 && w > width {
+		ratio = float64(width) / float64(w)
+	}
+	if height > 0 && float64(h)*ratio > float64(height) {
+		ratio = float64(height) / float64(h)
+	}
+
+	if ratio == 1.0 {
+		return img
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, max(1, int(float64(w)*ratio+0.5)), max(1, int(float64(h)*ratio+0.5))))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, bounds, draw.Src, nil)
+
+	return dst
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+
+	return b
+}
@@ -0,0 +1,142 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testRenditions = []Rendition{
+	{Name: "thumbnail", Width: 40, Height: 40},
+	{Name: "large", Width: 1000, Height: 1000},
+}
+
+func TestProcessor_Process(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 200, 100))))
+
+	outputs, err := NewProcessor(testRenditions, 80).Process(&buf, PNG)
+	require.NoError(t, err)
+
+	got := make([]string, len(outputs))
+	for i, output := range outputs {
+		got[i] = output.Rendition + "." + output.Format.Extension()
+	}
+	assert.Equal(t, []string{"original.png", "thumbnail.webp", "thumbnail.png", "large.webp", "large.png"}, got)
+
+	assert.Equal(t, 200, outputs[0].Width)
+	assert.Equal(t, 40, outputs[1].Width)
+	assert.Equal(t, 20, outputs[1].Height)
+	assert.Equal(t, 200, outputs[3].Width, "images are not scaled up")
+	assert.Equal(t, "image/webp", outputs[1].ContentType())
+
+	for _, output := range outputs {
+		_, format, err := image.Decode(bytes.NewReader(output.Data))
+		require.NoError(t, err)
+		assert.Equal(t, string(output.Format), format)
+	}
+}
+
+func TestProcessor_Process_TooManyPixels(t *testing.T) {
+	// a PNG header declaring 50000x50000 pixels, the decoder would allocate
+	// 10 GB for it
+	var buf bytes.Buffer
+	buf.WriteString("\x89PNG\r\n\x1a\n")
+
+	ihdr := make([]byte, 17)
+	copy(ihdr, "IHDR")
+	binary.BigEndian.PutUint32(ihdr[4:], 50000)
+	binary.BigEndian.PutUint32(ihdr[8:], 50000)
+	ihdr[12] = 8 // bit depth
+	ihdr[13] = 6 // RGBA
+
+	binary.Write(&buf, binary.BigEndian, uint32(len(ihdr)-4))
+	buf.Write(ihdr)
+	binary.Write(&buf, binary.BigEndian, crc32.ChecksumIEEE(ihdr))
+
+	_, err := NewProcessor(testRenditions, 80).Process(&buf, PNG)
+	assert.ErrorIs(t, err, ErrInvalidImage)
+	assert.Contains(t, err.Error(), "50000x50000")
+}
+
+func TestProcessor_Process_WebP(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 200, 100))))
+
+	processor := NewProcessor(testRenditions, 80)
+
+	outputs, err := processor.Process(&buf, PNG)
+	require.NoError(t, err)
+
+	outputs, err = processor.Process(bytes.NewReader(outputs[1].Data), WebP)
+	require.NoError(t, err)
+	assert.Len(t, outputs, 3)
+}
+
+func TestProcessor_Process_Orientation(t *testing.T) {
+	// the top left pixel is red, the image is stored rotated and its EXIF
+	// orientation tells to turn it 90° clockwise
+	img := image.NewRGBA(image.Rect(0, 0, 64, 32))
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			img.Set(x, y, color.RGBA{R: 255, A: 255})
+		}
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, jpeg.Encode(&buf, img, &jpeg.Options{Quality: 100}))
+	data := withOrientation(buf.Bytes(), 6)
+
+	outputs, err := NewProcessor(nil, 100).Process(bytes.NewReader(data), JPEG)
+	require.NoError(t, err)
+	require.Len(t, outputs, 1)
+
+	assert.False(t, bytes.Contains(outputs[0].Data, []byte("Exif\x00\x00")), "EXIF is stripped")
+
+	upright, err := jpeg.Decode(bytes.NewReader(outputs[0].Data))
+	require.NoError(t, err)
+	assert.Equal(t, image.Rect(0, 0, 32, 64), upright.Bounds())
+
+	r, g, b, _ := upright.At(28, 2).RGBA()
+	assert.True(t, r > 0xc000 && g < 0x4000 && b < 0x4000, "the red corner moves to the top right")
+}
+
+func TestJpegOrientation(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, jpeg.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 8, 8)), nil))
+
+	assert.Equal(t, 1, jpegOrientation(buf.Bytes()))
+	assert.Equal(t, 8, jpegOrientation(withOrientation(buf.Bytes(), 8)))
+	assert.Equal(t, 1, jpegOrientation([]byte("not an image")))
+}
+
+// withOrientation inserts an EXIF segment with the orientation after the SOI
+// marker of the JPEG image.
+func withOrientation(data []byte, orientation uint16) []byte {
+	tiff := []byte{'M', 'M', 0, 42, 0, 0, 0, 8, 0, 1}
+	entry := make([]byte, 12)
+	binary.BigEndian.PutUint16(entry[0:], orientationTag)
+	binary.BigEndian.PutUint16(entry[2:], 3) // SHORT
+	binary.BigEndian.PutUint32(entry[4:], 1)
+	binary.BigEndian.PutUint16(entry[8:], orientation)
+	tiff = append(tiff, entry...)
+	tiff = append(tiff, 0, 0, 0, 0)
+
+	segment := append([]byte("Exif\x00\x00"), tiff...)
+	header := []byte{0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(header[2:], uint16(len(segment)+2))
+
+	out := append([]byte{}, data[:2]...)
+	out = append(out, header...)
+	out = append(out, segment...)
+
+	return append(out, data[2:]...)
+}
@@ -0,0 +1,42 @@
+package mailer
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+// FileMailer writes every message to its own .eml file in dir instead of
+// sending it. It is meant for local development and tests.
+type FileMailer struct {
+	dir  string
+	from string
+}
+
+func NewFileMailer(dir, from string) (*FileMailer, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	return &FileMailer{
+		dir:  dir,
+		from: from,
+	}, nil
+}
+
+func (m *FileMailer) Send(msg Message) error {
+	now := time.Now()
+
+	f, err := os.CreateTemp(m.dir, fmt.Sprintf("%s-*.eml", now.Format("20060102T150405")))
+	if err != nil {
+		return err
+	}
+
+	if _, err := f.Write(encode(m.from, msg, now)); err != nil {
+		f.Close()
+
+		return err
+	}
+
+	return f.Close()
+}
@@ -0,0 +1,19 @@
+package mailer
+
+import "github.com/sirupsen/logrus"
+
+// LogMailer only logs messages, bodies included.
+type LogMailer struct{}
+
+func NewLogMailer() *LogMailer {
+	return &LogMailer{}
+}
+
+func (m *LogMailer) Send(msg Message) error {
+	logrus.WithFields(logrus.Fields{
+		"to":      msg.To,
+		"subject": msg.Subject,
+	}).Info(msg.Body)
+
+	return nil
+}
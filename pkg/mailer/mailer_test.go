@@ -0,0 +1,35 @@
+package mailer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileMailer_Send(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "mail")
+
+	m, err := NewFileMailer(dir, "shop@example.com")
+	require.NoError(t, err)
+
+	require.NoError(t, m.Send(Message{
+		To:      "test@gmail.com",
+		Subject: "Подтверждение email",
+		Body:    "token: abc",
+	}))
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.eml"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+
+	data, err := os.ReadFile(files[0])
+	require.NoError(t, err)
+
+	email := string(data)
+	assert.True(t, strings.HasPrefix(email, "From: shop@example.com\r\nTo: test@gmail.com\r\nSubject: =?utf-8?q?"))
+	assert.Contains(t, email, "Content-Type: text/plain; charset=utf-8\r\n\r\ntoken: abc")
+}
@@ -0,0 +1,219 @@
+package payment
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// Test cards of the fake provider. Any other payment method is declined as
+// an invalid card.
+const (
+	CardSucceeds          = "4242424242424242"
+	CardDeclined          = "4000000000000002"
+	CardInsufficientFunds = "4000000000009995"
+)
+
+const webhookTolerance = 5 * time.Minute
+
+var fakeDeclines = map[string]string{
+	CardDeclined:          "card_declined",
+	CardInsufficientFunds: "insufficient_funds",
+}
+
+// Deliver sends a signed webhook to the shop.
+type Deliver func(payload []byte, signature string)
+
+// FakeProvider is an in-memory provider that never leaves the process. The
+// outcome of a payment depends only on the test card it is made with, so the
+// whole checkout can be run locally and in tests. Webhooks are signed with
+// secret like the ones of a real provider and handed to deliver.
+type FakeProvider struct {
+	secret  []byte
+	deliver Deliver
+
+	mu         sync.Mutex
+	intents    map[string]*Intent
+	idempotent map[string]string
+	refunded   map[string]uint
+}
+
+func NewFakeProvider(secret string, deliver Deliver) *FakeProvider {
+	return &FakeProvider{
+		secret:     []byte(secret),
+		deliver:    deliver,
+		intents:    make(map[string]*Intent),
+		idempotent: make(map[string]string),
+		refunded:   make(map[string]uint),
+	}
+}
+
+func (p *FakeProvider) Name() string {
+	return "fake"
+}
+
+func (p *FakeProvider) CreateIntent(ctx context.Context, input CreateIntentInput) (Intent, error) {
+	var events []Event
+	defer p.notify(&events)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if id, ok := p.idempotent[input.IdempotencyKey]; ok && input.IdempotencyKey != "" {
+		return *p.intents[id], nil
+	}
+
+	intent := &Intent{
+		Id:       "pi_" + uuid.New().String(),
+		Amount:   input.Amount,
+		Currency: input.Currency,
+		Status:   IntentRequiresCapture,
+	}
+
+	switch code, declined := fakeDeclines[input.PaymentMethod]; {
+	case declined:
+		intent.Status, intent.FailureCode = IntentFailed, code
+	case input.PaymentMethod != CardSucceeds:
+		intent.Status, intent.FailureCode = IntentFailed, "invalid_card"
+	}
+
+	p.intents[intent.Id] = intent
+	if input.IdempotencyKey != "" {
+		p.idempotent[input.IdempotencyKey] = intent.Id
+	}
+
+	if intent.Status == IntentFailed {
+		events = append(events, Event{Type: EventPaymentFailed, IntentId: intent.Id, Amount: intent.Amount, FailureCode: intent.FailureCode})
+	}
+
+	return *intent, nil
+}
+
+func (p *FakeProvider) Capture(ctx context.Context, intentId string) (Intent, error) {
+	var events []Event
+	defer p.notify(&events)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	intent, ok := p.intents[intentId]
+	if !ok {
+		return Intent{}, ErrIntentNotFound
+	}
+
+	switch intent.Status {
+	case IntentSucceeded:
+		return *intent, nil
+	case IntentRequiresCapture:
+		intent.Status = IntentSucceeded
+		events = append(events, Event{Type: EventPaymentSucceeded, IntentId: intent.Id, Amount: intent.Amount})
+
+		return *intent, nil
+	default:
+		return Intent{}, ErrInvalidState
+	}
+}
+
+// Refund returns up to the captured amount of the intent, in one or several
+// refunds.
+func (p *FakeProvider) Refund(ctx context.Context, intentId string, amount uint) (Refund, error) {
+	var events []Event
+	defer p.notify(&events)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	intent, ok := p.intents[intentId]
+	if !ok {
+		return Refund{}, ErrIntentNotFound
+	}
+
+	if intent.Status != IntentSucceeded || p.refunded[intentId]+amount > intent.Amount {
+		return Refund{}, ErrInvalidState
+	}
+
+	p.refunded[intentId] += amount
+	refund := Refund{
+		Id:       "re_" + uuid.New().String(),
+		IntentId: intentId,
+		Amount:   amount,
+	}
+	events = append(events, Event{Type: EventRefundSucceeded, IntentId: intentId, Amount: amount})
+
+	return refund, nil
+}
+
+func (p *FakeProvider) VerifyWebhook(payload []byte, signature string) (Event, error) {
+	var event Event
+
+	if err := Verify(p.secret, payload, signature, time.Now(), webhookTolerance); err != nil {
+		return event, err
+	}
+
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return event, fmt.Errorf("%w: %s", ErrInvalidSignature, err.Error())
+	}
+
+	return event, nil
+}
+
+// notify signs the events and delivers them. It is deferred before the lock
+// is taken, so deliver may call the provider again.
+func (p *FakeProvider) notify(events *[]Event) {
+	if p.deliver == nil {
+		return
+	}
+
+	for _, event := range *events {
+		event.Id = "evt_" + uuid.New().String()
+		event.CreatedAt = time.Now().UTC()
+
+		payload, err := json.Marshal(event)
+		if err != nil {
+			logrus.Errorf("notify(): %s", err.Error())
+
+			continue
+		}
+
+		p.deliver(payload, Sign(p.secret, payload, event.CreatedAt))
+	}
+}
+
+// PostWebhook delivers webhooks to url in the background, the way a real
+// provider calls the shop.
+func PostWebhook(url string) Deliver {
+	client := &http.Client{Timeout: 10 * time.Second}
+
+	return func(payload []byte, signature string) {
+		go func() {
+			req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(payload))
+			if err != nil {
+				logrus.Errorf("PostWebhook(): %s", err.Error())
+
+				return
+			}
+
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set(SignatureHeader, signature)
+
+			resp, err := client.Do(req)
+			if err != nil {
+				logrus.Errorf("PostWebhook(): %s", err.Error())
+
+				return
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode >= http.StatusMultipleChoices {
+				logrus.Errorf("PostWebhook(): %s responded %d", url, resp.StatusCode)
+			}
+		}()
+	}
+}
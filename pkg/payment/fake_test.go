@@ -0,0 +1,129 @@
+package payment
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type webhook struct {
+	payload   []byte
+	signature string
+}
+
+func TestFakeProvider_Payment(t *testing.T) {
+	var webhooks []webhook
+	p := NewFakeProvider("secret", func(payload []byte, signature string) {
+		webhooks = append(webhooks, webhook{payload: payload, signature: signature})
+	})
+
+	intent, err := p.CreateIntent(context.Background(), CreateIntentInput{Amount: 9980, Currency: "RUB", PaymentMethod: CardSucceeds, IdempotencyKey: "key"})
+	require.NoError(t, err)
+	assert.Equal(t, IntentRequiresCapture, intent.Status)
+
+	again, err := p.CreateIntent(context.Background(), CreateIntentInput{Amount: 9980, Currency: "RUB", PaymentMethod: CardSucceeds, IdempotencyKey: "key"})
+	require.NoError(t, err)
+	assert.Equal(t, intent.Id, again.Id)
+
+	_, err = p.Refund(context.Background(), intent.Id, 9980)
+	assert.ErrorIs(t, err, ErrInvalidState)
+
+	captured, err := p.Capture(context.Background(), intent.Id)
+	require.NoError(t, err)
+	assert.Equal(t, IntentSucceeded, captured.Status)
+
+	_, err = p.Refund(context.Background(), intent.Id, 5000)
+	require.NoError(t, err)
+
+	_, err = p.Refund(context.Background(), intent.Id, 5000)
+	assert.ErrorIs(t, err, ErrInvalidState)
+
+	require.Len(t, webhooks, 2)
+
+	event, err := p.VerifyWebhook(webhooks[0].payload, webhooks[0].signature)
+	require.NoError(t, err)
+	assert.Equal(t, EventPaymentSucceeded, event.Type)
+	assert.Equal(t, intent.Id, event.IntentId)
+	assert.Equal(t, uint(9980), event.Amount)
+
+	event, err = p.VerifyWebhook(webhooks[1].payload, webhooks[1].signature)
+	require.NoError(t, err)
+	assert.Equal(t, EventRefundSucceeded, event.Type)
+	assert.Equal(t, uint(5000), event.Amount)
+}
+
+func TestFakeProvider_Declines(t *testing.T) {
+	testTable := []struct {
+		name            string
+		paymentMethod   string
+		wantFailureCode string
+	}{
+		{name: "Declined", paymentMethod: CardDeclined, wantFailureCode: "card_declined"},
+		{name: "Insufficient Funds", paymentMethod: CardInsufficientFunds, wantFailureCode: "insufficient_funds"},
+		{name: "Unknown Card", paymentMethod: "4111111111111111", wantFailureCode: "invalid_card"},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			var events []Event
+			p := NewFakeProvider("secret", nil)
+			p.deliver = func(payload []byte, signature string) {
+				event, err := p.VerifyWebhook(payload, signature)
+				require.NoError(t, err)
+				events = append(events, event)
+			}
+
+			intent, err := p.CreateIntent(context.Background(), CreateIntentInput{Amount: 100, Currency: "RUB", PaymentMethod: testCase.paymentMethod})
+			require.NoError(t, err)
+			assert.Equal(t, IntentFailed, intent.Status)
+			assert.Equal(t, testCase.wantFailureCode, intent.FailureCode)
+
+			_, err = p.Capture(context.Background(), intent.Id)
+			assert.ErrorIs(t, err, ErrInvalidState)
+
+			require.Len(t, events, 1)
+			assert.Equal(t, EventPaymentFailed, events[0].Type)
+			assert.Equal(t, testCase.wantFailureCode, events[0].FailureCode)
+		})
+	}
+}
+
+func TestVerify(t *testing.T) {
+	secret := []byte("secret")
+	payload := []byte(`{"id":"evt_1"}`)
+	now := time.Unix(1642000000, 0)
+
+	testTable := []struct {
+		name      string
+		secret    []byte
+		payload   []byte
+		signature string
+		wantErr   bool
+	}{
+		{name: "OK", payload: payload, signature: Sign(secret, payload, now)},
+		{name: "Tampered Payload", payload: []byte(`{"id":"evt_2"}`), signature: Sign(secret, payload, now), wantErr: true},
+		{name: "Other Secret", payload: payload, signature: Sign([]byte("other"), payload, now), wantErr: true},
+		{name: "Stale", payload: payload, signature: Sign(secret, payload, now.Add(-time.Hour)), wantErr: true},
+		{name: "Malformed", payload: payload, signature: "v1=abc", wantErr: true},
+		{name: "Empty Secret", secret: []byte{}, payload: payload, signature: Sign(nil, payload, now), wantErr: true},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			key := secret
+			if testCase.secret != nil {
+				key = testCase.secret
+			}
+
+			err := Verify(key, testCase.payload, testCase.signature, now, 5*time.Minute)
+			if testCase.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidSignature)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package payment
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Intent statuses. An intent is authorized when created and waits for the
+// capture, a declined intent is failed and can't be used anymore.
+const (
+	IntentRequiresCapture = "requires_capture"
+	IntentSucceeded       = "succeeded"
+	IntentFailed          = "failed"
+)
+
+// Webhook event types.
+const (
+	EventPaymentSucceeded = "payment.succeeded"
+	EventPaymentFailed    = "payment.failed"
+	EventRefundSucceeded  = "refund.succeeded"
+)
+
+var (
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+	ErrIntentNotFound   = errors.New("payment intent not found")
+	ErrInvalidState     = errors.New("payment intent is in a wrong state for this operation")
+)
+
+// CreateIntentInput asks for Amount in the smallest units of Currency.
+// PaymentMethod is whatever the provider takes from the client, e.g. a card
+// token. Requests with the same IdempotencyKey create one intent.
+type CreateIntentInput struct {
+	Amount         uint
+	Currency       string
+	PaymentMethod  string
+	Description    string
+	IdempotencyKey string
+}
+
+// Intent is an authorization of a payment. FailureCode tells why a failed
+// intent was declined, e.g. "card_declined" or "insufficient_funds".
+type Intent struct {
+	Id          string
+	Amount      uint
+	Currency    string
+	Status      string
+	FailureCode string
+}
+
+type Refund struct {
+	Id       string
+	IntentId string
+	Amount   uint
+}
+
+// Event is a verified webhook notification.
+type Event struct {
+	Id          string    `json:"id"`
+	Type        string    `json:"type"`
+	IntentId    string    `json:"intent_id"`
+	Amount      uint      `json:"amount"`
+	FailureCode string    `json:"failure_code,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type Provider interface {
+	Name() string
+	CreateIntent(ctx context.Context, input CreateIntentInput) (Intent, error)
+	Capture(ctx context.Context, intentId string) (Intent, error)
+	Refund(ctx context.Context, intentId string, amount uint) (Refund, error)
+	VerifyWebhook(payload []byte, signature string) (Event, error)
+}
@@ -0,0 +1,71 @@
+package payment
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SignatureHeader carries the signature of a webhook.
+const SignatureHeader = "X-Payment-Signature"
+
+// Sign returns the signature of a webhook payload sent at timestamp, in the
+// form "t=<unix seconds>,v1=<hex HMAC-SHA256 of "t.payload">".
+func Sign(secret, payload []byte, timestamp time.Time) string {
+	t := strconv.FormatInt(timestamp.Unix(), 10)
+
+	return fmt.Sprintf("t=%s,v1=%s", t, hex.EncodeToString(mac(secret, t, payload)))
+}
+
+// Verify checks a signature made by Sign. Signatures older than tolerance
+// are rejected, so a captured webhook can't be replayed later. Nothing is
+// valid with an empty secret, anyone could sign with it.
+func Verify(secret, payload []byte, signature string, now time.Time, tolerance time.Duration) error {
+	if len(secret) == 0 {
+		return ErrInvalidSignature
+	}
+
+	var t, v1 string
+	for _, part := range strings.Split(signature, ",") {
+		kv := strings.SplitN(strings.TrimSpace(part), "=", 2)
+		if len(kv) != 2 {
+			return ErrInvalidSignature
+		}
+
+		switch kv[0] {
+		case "t":
+			t = kv[1]
+		case "v1":
+			v1 = kv[1]
+		}
+	}
+
+	unix, err := strconv.ParseInt(t, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+
+	if age := now.Sub(time.Unix(unix, 0)); age > tolerance || age < -tolerance {
+		return ErrInvalidSignature
+	}
+
+	expected, err := hex.DecodeString(v1)
+	if err != nil || !hmac.Equal(expected, mac(secret, t, payload)) {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
+
+func mac(secret []byte, t string, payload []byte) []byte {
+	h := hmac.New(sha256.New, secret)
+	h.Write([]byte(t))
+	h.Write([]byte("."))
+	h.Write(payload)
+
+	return h.Sum(nil)
+}
@@ -3,6 +3,9 @@ package storage
 import (
 	"context"
 	"fmt"
+	"io"
+	"net/http"
+	"time"
 
 	"github.com/minio/minio-go/v7"
 )
@@ -43,6 +46,65 @@ func (fs *FileStorage) Delete(ctx context.Context, filename string) error {
 	return fs.client.RemoveObject(ctx, fs.bucket, filename, opts)
 }
 
+func (fs *FileStorage) PresignedPutURL(ctx context.Context, input PresignInput) (string, error) {
+	headers := http.Header{}
+	headers.Set("Content-Type", input.ContentType)
+
+	u, err := fs.client.PresignHeader(ctx, http.MethodPut, fs.bucket, input.Name, input.Expires, nil, headers)
+	if err != nil {
+		return "", err
+	}
+
+	return u.String(), nil
+}
+
+func (fs *FileStorage) PresignedGetURL(ctx context.Context, filename string, expires time.Duration) (string, error) {
+	u, err := fs.client.PresignedGetObject(ctx, fs.bucket, filename, expires, nil)
+	if err != nil {
+		return "", err
+	}
+
+	return u.String(), nil
+}
+
+func (fs *FileStorage) Stat(ctx context.Context, filename string) (ObjectInfo, error) {
+	info, err := fs.client.StatObject(ctx, fs.bucket, filename, minio.StatObjectOptions{})
+	if err != nil {
+		return ObjectInfo{}, notFound(err)
+	}
+
+	return ObjectInfo{
+		Size:        info.Size,
+		ContentType: info.ContentType,
+	}, nil
+}
+
+// Open checks that the object exists first, GetObject only fails on the
+// first read.
+func (fs *FileStorage) Open(ctx context.Context, filename string) (io.ReadCloser, error) {
+	object, err := fs.client.GetObject(ctx, fs.bucket, filename, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, notFound(err)
+	}
+
+	if _, err := object.Stat(); err != nil {
+		object.Close()
+
+		return nil, notFound(err)
+	}
+
+	return object, nil
+}
+
+// notFound maps the missing objects to ErrObjectNotFound.
+func notFound(err error) error {
+	if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+		return ErrObjectNotFound
+	}
+
+	return err
+}
+
 func (fs *FileStorage) generateFileURL(filename string) string {
 	return fmt.Sprintf("https://%s.%s/%s", fs.bucket, fs.endpoint, filename)
 }
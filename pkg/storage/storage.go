@@ -2,9 +2,13 @@ package storage
 
 import (
 	"context"
+	"errors"
 	"io"
+	"time"
 )
 
+var ErrObjectNotFound = errors.New("object not found")
+
 type UploadInput struct {
 	File        io.Reader
 	Name        string
@@ -12,7 +16,27 @@ type UploadInput struct {
 	ContentType string
 }
 
+// PresignInput describes the object a client uploads itself. The content type
+// is signed, the client must send it in the Content-Type header.
+type PresignInput struct {
+	Name        string
+	ContentType string
+	Expires     time.Duration
+}
+
+type ObjectInfo struct {
+	Size        int64
+	ContentType string
+}
+
 type Provider interface {
 	Upload(ctx context.Context, input UploadInput) (string, error)
 	Delete(ctx context.Context, filename string) error
+	// PresignedPutURL lets a client upload the object without the API.
+	PresignedPutURL(ctx context.Context, input PresignInput) (string, error)
+	// PresignedGetURL lets a client download the object until expires.
+	PresignedGetURL(ctx context.Context, filename string, expires time.Duration) (string, error)
+	// Stat and Open return ErrObjectNotFound when there is no such object.
+	Stat(ctx context.Context, filename string) (ObjectInfo, error)
+	Open(ctx context.Context, filename string) (io.ReadCloser, error)
 }
@@ -0,0 +1,18 @@
+CREATE TABLE "refresh_tokens" (
+  "id" uuid PRIMARY KEY,
+  "user_id" uuid NOT NULL REFERENCES "users" ("id") ON DELETE CASCADE,
+  "session_id" uuid NOT NULL,
+  "token_hash" varchar(64) NOT NULL UNIQUE,
+  "expires_at" timestamp NOT NULL,
+  "used_at" timestamp,
+  "revoked_at" timestamp,
+  "created_at" timestamp NOT NULL
+);
+
+CREATE INDEX "refresh_tokens_session_id_idx" ON "refresh_tokens" ("session_id");
+
+CREATE INDEX "refresh_tokens_user_id_idx" ON "refresh_tokens" ("user_id");
+
+COMMENT ON COLUMN "refresh_tokens"."session_id" IS 'token family, shared by all rotations of one sign-in';
+
+COMMENT ON COLUMN "refresh_tokens"."token_hash" IS 'sha256 of the opaque token, the token itself is never stored';
@@ -0,0 +1,13 @@
+ALTER TABLE users ADD COLUMN role varchar(255) NOT NULL DEFAULT 'CUSTOMER';
+
+UPDATE users SET role = 'ADMIN' WHERE id IN (SELECT user_id FROM user_roles WHERE role = 'ADMIN');
+
+ALTER TABLE users ALTER COLUMN role DROP DEFAULT;
+
+DROP TABLE user_roles;
+
+DROP TABLE role_permissions;
+
+DROP TABLE permissions;
+
+DROP TABLE roles;
@@ -0,0 +1,45 @@
+CREATE TABLE "roles" (
+  "name" varchar(64) PRIMARY KEY,
+  "description" varchar(255) NOT NULL DEFAULT ''
+);
+
+CREATE TABLE "permissions" (
+  "name" varchar(64) PRIMARY KEY,
+  "description" varchar(255) NOT NULL DEFAULT ''
+);
+
+CREATE TABLE "role_permissions" (
+  "role" varchar(64) NOT NULL REFERENCES "roles" ("name") ON DELETE CASCADE,
+  "permission" varchar(64) NOT NULL REFERENCES "permissions" ("name") ON DELETE CASCADE,
+  PRIMARY KEY ("role", "permission")
+);
+
+CREATE TABLE "user_roles" (
+  "user_id" uuid NOT NULL REFERENCES "users" ("id") ON DELETE CASCADE,
+  "role" varchar(64) NOT NULL REFERENCES "roles" ("name") ON DELETE CASCADE,
+  "granted_at" timestamp NOT NULL,
+  PRIMARY KEY ("user_id", "role")
+);
+
+INSERT INTO "roles" ("name", "description") VALUES
+('ADMIN', 'Full access to the shop administration'),
+('CUSTOMER', 'Default role assigned at sign-up');
+
+INSERT INTO "permissions" ("name", "description") VALUES
+('products:write', 'Create, update and delete products'),
+('files:upload', 'Upload product images'),
+('users:manage', 'List users and grant or revoke roles');
+
+INSERT INTO "role_permissions" ("role", "permission") VALUES
+('ADMIN', 'products:write'),
+('ADMIN', 'files:upload'),
+('ADMIN', 'users:manage');
+
+INSERT INTO "user_roles" ("user_id", "role", "granted_at")
+SELECT "id", 'CUSTOMER', now() FROM "users";
+
+-- Sign-up used to accept any role, review these grants after the migration.
+INSERT INTO "user_roles" ("user_id", "role", "granted_at")
+SELECT "id", 'ADMIN', now() FROM "users" WHERE "role" = 'ADMIN';
+
+ALTER TABLE "users" DROP COLUMN "role";
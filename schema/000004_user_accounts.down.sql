@@ -0,0 +1,5 @@
+ALTER TABLE users DROP COLUMN deleted_at;
+
+ALTER TABLE users DROP COLUMN blocked_at;
+
+ALTER TABLE users DROP COLUMN updated_at;
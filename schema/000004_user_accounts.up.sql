@@ -0,0 +1,7 @@
+ALTER TABLE "users" ADD COLUMN "updated_at" timestamp;
+
+ALTER TABLE "users" ADD COLUMN "blocked_at" timestamp;
+
+ALTER TABLE "users" ADD COLUMN "deleted_at" timestamp;
+
+COMMENT ON COLUMN "users"."deleted_at" IS 'personal data of deleted users is anonymized, the row is kept for orders history';
@@ -0,0 +1,21 @@
+ALTER TABLE "users" ADD COLUMN "email_verified_at" timestamp;
+
+UPDATE "users" SET "email_verified_at" = "created_at";
+
+CREATE TABLE "user_tokens" (
+  "id" uuid PRIMARY KEY,
+  "user_id" uuid NOT NULL REFERENCES "users" ("id") ON DELETE CASCADE,
+  "purpose" varchar(32) NOT NULL,
+  "token_hash" varchar(64) NOT NULL UNIQUE,
+  "expires_at" timestamp NOT NULL,
+  "used_at" timestamp,
+  "created_at" timestamp NOT NULL
+);
+
+CREATE INDEX "user_tokens_user_id_purpose_idx" ON "user_tokens" ("user_id", "purpose");
+
+COMMENT ON COLUMN "users"."email_verified_at" IS 'accounts created before email verification was introduced are treated as verified';
+
+COMMENT ON COLUMN "user_tokens"."purpose" IS 'email_verification or password_reset';
+
+COMMENT ON COLUMN "user_tokens"."token_hash" IS 'sha256 of the token sent by email, the token itself is never stored';
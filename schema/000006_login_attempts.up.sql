@@ -0,0 +1,8 @@
+CREATE TABLE "login_attempts" (
+  "key" varchar(320) PRIMARY KEY,
+  "failures" int NOT NULL,
+  "last_failure_at" timestamp NOT NULL,
+  "locked_until" timestamp
+);
+
+COMMENT ON COLUMN "login_attempts"."key" IS 'account:<email> or ip:<address>';
@@ -0,0 +1,11 @@
+UPDATE "users" SET "email" = lower(trim("email"));
+
+-- Only the oldest account keeps a duplicated email. Newer ones get a
+-- placeholder email, they can be recovered manually by support.
+UPDATE "users" u SET "email" = 'duplicate-' || u."id" || '@duplicate.invalid'
+FROM (
+  SELECT "id", row_number() OVER (PARTITION BY "email" ORDER BY "created_at", "id") AS "n" FROM "users"
+) d
+WHERE u."id" = d."id" AND d."n" > 1;
+
+CREATE UNIQUE INDEX "users_email_key" ON "users" (lower("email"));
@@ -0,0 +1,5 @@
+DROP INDEX products_on_sale_idx;
+DROP INDEX products_title_id_idx;
+DROP INDEX products_created_at_id_idx;
+DROP INDEX products_price_id_idx;
+DROP INDEX products_category_type_subtype_idx;
@@ -0,0 +1,9 @@
+CREATE INDEX "products_category_type_subtype_idx" ON "products" ("category", "type", "subtype");
+
+CREATE INDEX "products_price_id_idx" ON "products" ("price", "id");
+
+CREATE INDEX "products_created_at_id_idx" ON "products" ("created_at", "id");
+
+CREATE INDEX "products_title_id_idx" ON "products" ("title", "id");
+
+CREATE INDEX "products_on_sale_idx" ON "products" ("created_at", "id") WHERE "sale" > 0;
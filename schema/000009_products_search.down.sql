@@ -0,0 +1,6 @@
+DROP INDEX products_title_trgm_idx;
+DROP INDEX products_search_vector_idx;
+
+ALTER TABLE products DROP COLUMN search_vector;
+
+DROP EXTENSION pg_trgm;
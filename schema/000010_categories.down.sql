@@ -0,0 +1,17 @@
+DELETE FROM permissions WHERE name = 'categories:write';
+
+ALTER TABLE products ADD COLUMN category varchar(255), ADD COLUMN type varchar(255), ADD COLUMN subtype varchar(255);
+
+UPDATE products p SET category = c.names->>'ru', type = t.names->>'ru', subtype = s.names->>'ru'
+FROM categories s
+JOIN categories t ON t.id = s.parent_id
+JOIN categories c ON c.id = t.parent_id
+WHERE s.id = p.category_id;
+
+ALTER TABLE products ALTER COLUMN category SET NOT NULL, ALTER COLUMN type SET NOT NULL, ALTER COLUMN subtype SET NOT NULL;
+
+ALTER TABLE products DROP COLUMN category_id;
+
+CREATE INDEX products_category_type_subtype_idx ON products (category, type, subtype);
+
+DROP TABLE categories;
@@ -0,0 +1,66 @@
+CREATE TABLE "categories" (
+  "id" uuid PRIMARY KEY,
+  "parent_id" uuid REFERENCES "categories" ("id") ON DELETE RESTRICT,
+  "level" smallint NOT NULL CHECK ("level" BETWEEN 1 AND 3),
+  "slug" varchar(255) NOT NULL,
+  "names" jsonb NOT NULL,
+  "position" integer NOT NULL DEFAULT 0,
+  "created_at" timestamp NOT NULL,
+  "updated_at" timestamp
+);
+
+COMMENT ON COLUMN "categories"."level" IS '1 - category, 2 - type, 3 - subtype';
+
+COMMENT ON COLUMN "categories"."names" IS 'localized names, e.g. {"ru": "Женщинам", "en": "Women"}';
+
+CREATE UNIQUE INDEX "categories_root_slug_key" ON "categories" ("slug") WHERE "parent_id" IS NULL;
+
+CREATE UNIQUE INDEX "categories_parent_id_slug_key" ON "categories" ("parent_id", "slug") WHERE "parent_id" IS NOT NULL;
+
+CREATE FUNCTION pg_temp.slugify(value text) RETURNS text AS $$
+  SELECT trim(BOTH '-' FROM regexp_replace(
+    translate(
+      replace(replace(replace(replace(replace(replace(replace(lower(value),
+        'щ', 'shch'), 'ж', 'zh'), 'ч', 'ch'), 'ш', 'sh'), 'х', 'kh'), 'ю', 'yu'), 'я', 'ya'),
+      'абвгдеёзийклмнопрстуфцыэъь', 'abvgdeeziiklmnoprstufcye'),
+    '[^a-z0-9]+', '-', 'g'))
+$$ LANGUAGE sql IMMUTABLE;
+
+INSERT INTO "categories" ("id", "parent_id", "level", "slug", "names", "position", "created_at")
+SELECT gen_random_uuid(), NULL, 1, pg_temp.slugify("category"), jsonb_build_object('ru', "category"), 0, now()
+FROM "products" GROUP BY "category";
+
+INSERT INTO "categories" ("id", "parent_id", "level", "slug", "names", "position", "created_at")
+SELECT gen_random_uuid(), c."id", 2, pg_temp.slugify(p."type"), jsonb_build_object('ru', p."type"), 0, now()
+FROM "products" p JOIN "categories" c ON c."level" = 1 AND c."names"->>'ru' = p."category"
+GROUP BY c."id", p."type";
+
+INSERT INTO "categories" ("id", "parent_id", "level", "slug", "names", "position", "created_at")
+SELECT gen_random_uuid(), t."id", 3, pg_temp.slugify(p."subtype"), jsonb_build_object('ru', p."subtype"), 0, now()
+FROM "products" p
+JOIN "categories" c ON c."level" = 1 AND c."names"->>'ru' = p."category"
+JOIN "categories" t ON t."parent_id" = c."id" AND t."names"->>'ru' = p."type"
+GROUP BY t."id", p."subtype";
+
+ALTER TABLE "products" ADD COLUMN "category_id" uuid REFERENCES "categories" ("id") ON DELETE RESTRICT;
+
+UPDATE "products" p SET "category_id" = s."id"
+FROM "categories" c
+JOIN "categories" t ON t."parent_id" = c."id"
+JOIN "categories" s ON s."parent_id" = t."id"
+WHERE c."level" = 1
+  AND c."names"->>'ru' = p."category"
+  AND t."names"->>'ru' = p."type"
+  AND s."names"->>'ru' = p."subtype";
+
+ALTER TABLE "products" ALTER COLUMN "category_id" SET NOT NULL;
+
+ALTER TABLE "products" DROP COLUMN "category", DROP COLUMN "type", DROP COLUMN "subtype";
+
+CREATE INDEX "products_category_id_idx" ON "products" ("category_id");
+
+INSERT INTO "permissions" ("name", "description") VALUES
+('categories:write', 'Create, update and delete categories');
+
+INSERT INTO "role_permissions" ("role", "permission") VALUES
+('ADMIN', 'categories:write');
@@ -0,0 +1,16 @@
+CREATE TABLE "product_variants" (
+  "id" uuid PRIMARY KEY,
+  "product_id" uuid NOT NULL REFERENCES "products" ("id") ON DELETE CASCADE,
+  "sku" varchar(64) NOT NULL UNIQUE,
+  "options" jsonb NOT NULL DEFAULT '{}',
+  "price" bigint CHECK ("price" > 0),
+  "stock" integer NOT NULL DEFAULT 0 CHECK ("stock" >= 0),
+  "created_at" timestamp NOT NULL,
+  "updated_at" timestamp
+);
+
+COMMENT ON COLUMN "product_variants"."options" IS 'option values, e.g. {"size": "M", "colour": "black"}';
+
+COMMENT ON COLUMN "product_variants"."price" IS 'overrides the product price when set';
+
+CREATE UNIQUE INDEX "product_variants_product_id_options_key" ON "product_variants" ("product_id", "options");
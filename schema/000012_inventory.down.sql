@@ -0,0 +1,13 @@
+DELETE FROM permissions WHERE name = 'inventory:write';
+
+ALTER TABLE product_variants ADD COLUMN stock integer NOT NULL DEFAULT 0 CHECK (stock >= 0);
+
+UPDATE product_variants v SET stock = l.on_hand - l.reserved
+FROM stock_levels l
+WHERE l.variant_id = v.id;
+
+DROP TABLE stock_movements;
+
+DROP TABLE stock_reservations;
+
+DROP TABLE stock_levels;
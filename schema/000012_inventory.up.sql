@@ -0,0 +1,59 @@
+CREATE TABLE "stock_levels" (
+  "id" uuid PRIMARY KEY,
+  "product_id" uuid NOT NULL REFERENCES "products" ("id") ON DELETE CASCADE,
+  "variant_id" uuid UNIQUE REFERENCES "product_variants" ("id") ON DELETE CASCADE,
+  "on_hand" integer NOT NULL DEFAULT 0 CHECK ("on_hand" >= 0),
+  "reserved" integer NOT NULL DEFAULT 0 CHECK ("reserved" >= 0 AND "reserved" <= "on_hand"),
+  "version" integer NOT NULL DEFAULT 1,
+  "updated_at" timestamp NOT NULL
+);
+
+COMMENT ON COLUMN "stock_levels"."variant_id" IS 'NULL for products without variants';
+
+COMMENT ON COLUMN "stock_levels"."version" IS 'incremented on every change, used for optimistic checks';
+
+CREATE UNIQUE INDEX "stock_levels_product_id_key" ON "stock_levels" ("product_id") WHERE "variant_id" IS NULL;
+
+CREATE TABLE "stock_reservations" (
+  "id" uuid PRIMARY KEY,
+  "stock_level_id" uuid NOT NULL REFERENCES "stock_levels" ("id") ON DELETE CASCADE,
+  "quantity" integer NOT NULL CHECK ("quantity" > 0),
+  "status" varchar(16) NOT NULL CHECK ("status" IN ('active', 'confirmed', 'released', 'expired')),
+  "expires_at" timestamp NOT NULL,
+  "created_at" timestamp NOT NULL,
+  "updated_at" timestamp
+);
+
+CREATE INDEX "stock_reservations_expires_at_idx" ON "stock_reservations" ("expires_at") WHERE "status" = 'active';
+
+CREATE TABLE "stock_movements" (
+  "id" uuid PRIMARY KEY,
+  "stock_level_id" uuid NOT NULL REFERENCES "stock_levels" ("id") ON DELETE CASCADE,
+  "kind" varchar(16) NOT NULL CHECK ("kind" IN ('receipt', 'sale', 'return', 'adjustment')),
+  "quantity" integer NOT NULL CHECK ("quantity" <> 0),
+  "reason" varchar(255) NOT NULL DEFAULT '',
+  "actor_id" uuid REFERENCES "users" ("id") ON DELETE SET NULL,
+  "reservation_id" uuid REFERENCES "stock_reservations" ("id") ON DELETE SET NULL,
+  "created_at" timestamp NOT NULL
+);
+
+COMMENT ON COLUMN "stock_movements"."quantity" IS 'signed change of on_hand';
+
+CREATE INDEX "stock_movements_stock_level_id_created_at_idx" ON "stock_movements" ("stock_level_id", "created_at");
+
+INSERT INTO "stock_levels" ("id", "product_id", "variant_id", "on_hand", "updated_at")
+SELECT gen_random_uuid(), "product_id", "id", "stock", now()
+FROM "product_variants";
+
+INSERT INTO "stock_movements" ("id", "stock_level_id", "kind", "quantity", "reason", "created_at")
+SELECT gen_random_uuid(), "id", 'adjustment', "on_hand", 'initial stock', now()
+FROM "stock_levels"
+WHERE "on_hand" > 0;
+
+ALTER TABLE "product_variants" DROP COLUMN "stock";
+
+INSERT INTO "permissions" ("name", "description") VALUES
+('inventory:write', 'Receive, adjust and view stock');
+
+INSERT INTO "role_permissions" ("role", "permission") VALUES
+('ADMIN', 'inventory:write');
@@ -0,0 +1,30 @@
+CREATE TABLE "carts" (
+  "id" uuid PRIMARY KEY,
+  "user_id" uuid UNIQUE REFERENCES "users" ("id") ON DELETE CASCADE,
+  "token_hash" varchar(64) UNIQUE,
+  "expires_at" timestamp,
+  "created_at" timestamp NOT NULL,
+  "updated_at" timestamp NOT NULL,
+  CHECK (("user_id" IS NULL) <> ("token_hash" IS NULL))
+);
+
+COMMENT ON COLUMN "carts"."token_hash" IS 'sha256 of the guest cart token, NULL for carts of users';
+
+COMMENT ON COLUMN "carts"."expires_at" IS 'guest carts are deleted after this time, NULL for carts of users';
+
+CREATE INDEX "carts_expires_at_idx" ON "carts" ("expires_at") WHERE "user_id" IS NULL;
+
+CREATE TABLE "cart_items" (
+  "id" uuid PRIMARY KEY,
+  "cart_id" uuid NOT NULL REFERENCES "carts" ("id") ON DELETE CASCADE,
+  "product_id" uuid NOT NULL REFERENCES "products" ("id") ON DELETE CASCADE,
+  "variant_id" uuid REFERENCES "product_variants" ("id") ON DELETE CASCADE,
+  "quantity" integer NOT NULL CHECK ("quantity" > 0),
+  "price" bigint NOT NULL,
+  "created_at" timestamp NOT NULL,
+  "updated_at" timestamp
+);
+
+COMMENT ON COLUMN "cart_items"."price" IS 'unit price the customer has last seen';
+
+CREATE UNIQUE INDEX "cart_items_line_key" ON "cart_items" ("cart_id", "product_id", COALESCE("variant_id", "product_id"));
@@ -0,0 +1,7 @@
+DELETE FROM permissions WHERE name = 'orders:manage';
+
+DROP TABLE order_status_history;
+
+DROP TABLE order_items;
+
+DROP TABLE orders;
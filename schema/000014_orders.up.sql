@@ -0,0 +1,59 @@
+CREATE TABLE "orders" (
+  "id" uuid PRIMARY KEY,
+  "number" bigint GENERATED ALWAYS AS IDENTITY UNIQUE,
+  "user_id" uuid NOT NULL REFERENCES "users" ("id") ON DELETE RESTRICT,
+  "status" varchar(16) NOT NULL CHECK ("status" IN ('pending', 'paid', 'shipped', 'delivered', 'cancelled', 'refunded')),
+  "items_count" integer NOT NULL,
+  "total" bigint NOT NULL,
+  "expires_at" timestamp,
+  "created_at" timestamp NOT NULL,
+  "updated_at" timestamp
+);
+
+COMMENT ON COLUMN "orders"."expires_at" IS 'pending orders not paid by this time are cancelled';
+
+CREATE INDEX "orders_user_id_created_at_idx" ON "orders" ("user_id", "created_at");
+
+CREATE INDEX "orders_status_idx" ON "orders" ("status");
+
+CREATE INDEX "orders_expires_at_idx" ON "orders" ("expires_at") WHERE "status" = 'pending';
+
+CREATE TABLE "order_items" (
+  "id" uuid PRIMARY KEY,
+  "order_id" uuid NOT NULL REFERENCES "orders" ("id") ON DELETE CASCADE,
+  "position" integer NOT NULL,
+  "product_id" uuid REFERENCES "products" ("id") ON DELETE SET NULL,
+  "variant_id" uuid REFERENCES "product_variants" ("id") ON DELETE SET NULL,
+  "title" varchar(255) NOT NULL,
+  "sku" varchar(64) NOT NULL DEFAULT '',
+  "options" jsonb NOT NULL DEFAULT '{}',
+  "price" bigint NOT NULL,
+  "sale" bigint NOT NULL DEFAULT 0,
+  "sale_old_price" bigint NOT NULL DEFAULT 0,
+  "quantity" integer NOT NULL CHECK ("quantity" > 0),
+  "reservation_id" uuid REFERENCES "stock_reservations" ("id") ON DELETE SET NULL
+);
+
+COMMENT ON COLUMN "order_items"."price" IS 'unit price at the time of purchase';
+
+CREATE INDEX "order_items_order_id_idx" ON "order_items" ("order_id");
+
+CREATE TABLE "order_status_history" (
+  "id" uuid PRIMARY KEY,
+  "order_id" uuid NOT NULL REFERENCES "orders" ("id") ON DELETE CASCADE,
+  "from_status" varchar(16),
+  "to_status" varchar(16) NOT NULL,
+  "actor_id" uuid REFERENCES "users" ("id") ON DELETE SET NULL,
+  "comment" varchar(255) NOT NULL DEFAULT '',
+  "created_at" timestamp NOT NULL
+);
+
+COMMENT ON COLUMN "order_status_history"."actor_id" IS 'NULL for changes made by the system';
+
+CREATE INDEX "order_status_history_order_id_idx" ON "order_status_history" ("order_id", "created_at");
+
+INSERT INTO "permissions" ("name", "description") VALUES
+('orders:manage', 'View all orders and change their status');
+
+INSERT INTO "role_permissions" ("role", "permission") VALUES
+('ADMIN', 'orders:manage');
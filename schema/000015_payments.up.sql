@@ -0,0 +1,29 @@
+CREATE TABLE "payments" (
+  "id" uuid PRIMARY KEY,
+  "order_id" uuid NOT NULL REFERENCES "orders" ("id") ON DELETE CASCADE,
+  "provider" varchar(32) NOT NULL,
+  "intent_id" varchar(255) NOT NULL,
+  "amount" bigint NOT NULL,
+  "currency" varchar(3) NOT NULL,
+  "status" varchar(16) NOT NULL CHECK ("status" IN ('authorized', 'captured', 'failed', 'refunded')),
+  "failure_code" varchar(64) NOT NULL DEFAULT '',
+  "created_at" timestamp NOT NULL,
+  "updated_at" timestamp,
+  UNIQUE ("provider", "intent_id")
+);
+
+CREATE INDEX "payments_order_id_idx" ON "payments" ("order_id");
+
+-- an order has at most one payment that hasn't failed, so it can't be paid twice
+CREATE UNIQUE INDEX "payments_order_id_active_key" ON "payments" ("order_id") WHERE "status" <> 'failed';
+
+CREATE TABLE "payment_events" (
+  "provider" varchar(32) NOT NULL,
+  "event_id" varchar(255) NOT NULL,
+  "type" varchar(64) NOT NULL,
+  "payment_id" uuid REFERENCES "payments" ("id") ON DELETE CASCADE,
+  "created_at" timestamp NOT NULL,
+  PRIMARY KEY ("provider", "event_id")
+);
+
+COMMENT ON TABLE "payment_events" IS 'processed webhooks, a webhook delivered again is skipped';
@@ -0,0 +1,20 @@
+DELETE FROM permissions WHERE name = 'discounts:write';
+
+ALTER TABLE products ADD COLUMN sale integer NOT NULL DEFAULT 0, ADD COLUMN sale_old_price bigint NOT NULL DEFAULT 0;
+
+UPDATE products p SET sale = d.value, sale_old_price = p.price, price = p.price - p.price * d.value / 100
+FROM discounts d WHERE d.product_id = p.id AND d.kind = 'percent' AND d.active;
+
+CREATE INDEX products_on_sale_idx ON products (created_at, id) WHERE sale > 0;
+
+ALTER TABLE orders DROP COLUMN subtotal, DROP COLUMN discount, DROP COLUMN promo_code;
+
+DROP TABLE promo_code_redemptions;
+
+DROP TABLE promo_codes;
+
+DROP VIEW product_discounts;
+
+DROP FUNCTION discounted_price;
+
+DROP TABLE discounts;
@@ -0,0 +1,97 @@
+CREATE TABLE "discounts" (
+  "id" uuid PRIMARY KEY,
+  "name" varchar(255) NOT NULL,
+  "kind" varchar(16) NOT NULL CHECK ("kind" IN ('percent', 'fixed')),
+  "value" bigint NOT NULL CHECK ("value" > 0),
+  "product_id" uuid REFERENCES "products" ("id") ON DELETE CASCADE,
+  "category_id" uuid REFERENCES "categories" ("id") ON DELETE CASCADE,
+  "starts_at" timestamp NOT NULL,
+  "ends_at" timestamp,
+  "active" boolean NOT NULL DEFAULT true,
+  "created_at" timestamp NOT NULL,
+  "updated_at" timestamp,
+  CHECK (("product_id" IS NULL) <> ("category_id" IS NULL)),
+  CHECK ("kind" <> 'percent' OR "value" <= 100),
+  CHECK ("ends_at" IS NULL OR "ends_at" > "starts_at")
+);
+
+COMMENT ON TABLE "discounts" IS 'sales applied automatically to a product or to every product of a category';
+
+CREATE INDEX "discounts_product_id_idx" ON "discounts" ("product_id") WHERE "product_id" IS NOT NULL;
+
+CREATE INDEX "discounts_category_id_idx" ON "discounts" ("category_id") WHERE "category_id" IS NOT NULL;
+
+-- price after the best of the percent and the fixed discount, never below zero
+CREATE FUNCTION "discounted_price"("price" bigint, "percent" bigint, "fixed" bigint) RETURNS bigint
+LANGUAGE sql IMMUTABLE AS $$
+  SELECT "price" - GREATEST(COALESCE("price" * "percent" / 100, 0), LEAST(COALESCE("fixed", 0), "price"))
+$$;
+
+-- best active sales of every product, the category ones come from its subtype, type and category
+CREATE VIEW "product_discounts" AS
+SELECT p."id" AS "product_id",
+  max(d."value") FILTER (WHERE d."kind" = 'percent') AS "percent",
+  max(d."value") FILTER (WHERE d."kind" = 'fixed') AS "fixed"
+FROM "products" p
+JOIN "categories" s ON s."id" = p."category_id"
+JOIN "categories" t ON t."id" = s."parent_id"
+JOIN "discounts" d ON d."product_id" = p."id" OR d."category_id" IN (s."id", t."id", t."parent_id")
+WHERE d."active" AND d."starts_at" <= now() AND (d."ends_at" IS NULL OR d."ends_at" > now())
+GROUP BY p."id";
+
+CREATE TABLE "promo_codes" (
+  "id" uuid PRIMARY KEY,
+  "code" varchar(64) NOT NULL UNIQUE,
+  "kind" varchar(16) NOT NULL CHECK ("kind" IN ('percent', 'fixed')),
+  "value" bigint NOT NULL CHECK ("value" > 0),
+  "min_order_amount" bigint NOT NULL DEFAULT 0,
+  "usage_limit" integer CHECK ("usage_limit" > 0),
+  "per_user_limit" integer CHECK ("per_user_limit" > 0),
+  "used_count" integer NOT NULL DEFAULT 0,
+  "starts_at" timestamp NOT NULL,
+  "ends_at" timestamp,
+  "active" boolean NOT NULL DEFAULT true,
+  "created_at" timestamp NOT NULL,
+  "updated_at" timestamp,
+  CHECK ("kind" <> 'percent' OR "value" <= 100),
+  CHECK ("ends_at" IS NULL OR "ends_at" > "starts_at")
+);
+
+COMMENT ON COLUMN "promo_codes"."code" IS 'stored upper-case, codes are case-insensitive';
+
+CREATE TABLE "promo_code_redemptions" (
+  "id" uuid PRIMARY KEY,
+  "promo_code_id" uuid NOT NULL REFERENCES "promo_codes" ("id") ON DELETE CASCADE,
+  "user_id" uuid NOT NULL REFERENCES "users" ("id") ON DELETE CASCADE,
+  "order_id" uuid NOT NULL UNIQUE REFERENCES "orders" ("id") ON DELETE CASCADE,
+  "amount" bigint NOT NULL,
+  "created_at" timestamp NOT NULL
+);
+
+CREATE INDEX "promo_code_redemptions_promo_code_id_user_id_idx" ON "promo_code_redemptions" ("promo_code_id", "user_id");
+
+ALTER TABLE "orders"
+  ADD COLUMN "subtotal" bigint,
+  ADD COLUMN "discount" bigint NOT NULL DEFAULT 0,
+  ADD COLUMN "promo_code" varchar(64);
+
+UPDATE "orders" SET "subtotal" = "total";
+
+ALTER TABLE "orders" ALTER COLUMN "subtotal" SET NOT NULL;
+
+COMMENT ON COLUMN "orders"."total" IS 'subtotal minus the discount of the promo code';
+
+-- the hand-maintained sales become product discounts, price is the price before the sale again
+INSERT INTO "discounts" ("id", "name", "kind", "value", "product_id", "starts_at", "created_at")
+SELECT gen_random_uuid(), 'Скидка ' || "sale" || '%', 'percent', "sale", "id", now(), now()
+FROM "products" WHERE "sale" > 0 AND "sale" <= 100;
+
+UPDATE "products" SET "price" = "sale_old_price" WHERE "sale" > 0 AND "sale" <= 100 AND "sale_old_price" > "price";
+
+ALTER TABLE "products" DROP COLUMN "sale", DROP COLUMN "sale_old_price";
+
+INSERT INTO "permissions" ("name", "description") VALUES
+('discounts:write', 'Create, update and delete sales and promo codes');
+
+INSERT INTO "role_permissions" ("role", "permission") VALUES
+('ADMIN', 'discounts:write');
@@ -0,0 +1,9 @@
+DELETE FROM permissions WHERE name = 'currencies:write';
+
+DROP TABLE product_prices;
+
+DROP TABLE exchange_rates;
+
+ALTER TABLE orders DROP COLUMN currency;
+
+ALTER TABLE products DROP COLUMN currency;
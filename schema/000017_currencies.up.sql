@@ -0,0 +1,29 @@
+ALTER TABLE "products" ADD COLUMN "currency" varchar(3) NOT NULL DEFAULT 'RUB';
+
+ALTER TABLE "orders" ADD COLUMN "currency" varchar(3) NOT NULL DEFAULT 'RUB';
+
+COMMENT ON COLUMN "orders"."currency" IS 'currency of every amount of the order and of its items';
+
+CREATE TABLE "exchange_rates" (
+  "currency" varchar(3) PRIMARY KEY,
+  "rate" numeric(20, 10) NOT NULL CHECK ("rate" > 0),
+  "updated_at" timestamp NOT NULL
+);
+
+COMMENT ON COLUMN "exchange_rates"."rate" IS 'units of the currency one unit of the base currency buys';
+
+CREATE TABLE "product_prices" (
+  "product_id" uuid NOT NULL REFERENCES "products" ("id") ON DELETE CASCADE,
+  "currency" varchar(3) NOT NULL,
+  "price" bigint NOT NULL CHECK ("price" > 0),
+  "updated_at" timestamp NOT NULL,
+  PRIMARY KEY ("product_id", "currency")
+);
+
+COMMENT ON TABLE "product_prices" IS 'regular prices listed in currencies other than the base one, used instead of the exchange rates';
+
+INSERT INTO "permissions" ("name", "description") VALUES
+('currencies:write', 'Set exchange rates and product price lists');
+
+INSERT INTO "role_permissions" ("role", "permission") VALUES
+('ADMIN', 'currencies:write');
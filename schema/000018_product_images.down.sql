@@ -0,0 +1,3 @@
+COMMENT ON COLUMN products.image IS NULL;
+
+DROP TABLE product_images;
@@ -0,0 +1,28 @@
+CREATE TABLE "product_images" (
+  "id" uuid PRIMARY KEY,
+  "product_id" uuid NOT NULL REFERENCES "products" ("id") ON DELETE CASCADE,
+  "object_name" varchar(255) NOT NULL,
+  "url" varchar(255) NOT NULL,
+  "content_type" varchar(64) NOT NULL DEFAULT '',
+  "size" bigint NOT NULL DEFAULT 0 CHECK ("size" >= 0),
+  "status" smallint NOT NULL,
+  "position" integer NOT NULL CHECK ("position" >= 0),
+  "is_primary" boolean NOT NULL DEFAULT false,
+  "alt_text" varchar(255) NOT NULL DEFAULT '',
+  "upload_started_at" timestamp NOT NULL,
+  "created_at" timestamp NOT NULL
+);
+
+COMMENT ON COLUMN "product_images"."object_name" IS 'key of the object in the file storage';
+
+COMMENT ON COLUMN "product_images"."status" IS 'domain.FileStatus of the upload';
+
+CREATE INDEX "product_images_product_id_position_idx" ON "product_images" ("product_id", "position");
+
+CREATE UNIQUE INDEX "product_images_product_id_primary_key" ON "product_images" ("product_id") WHERE "is_primary";
+
+COMMENT ON COLUMN "products"."image" IS 'url of the primary image of the gallery';
+
+INSERT INTO "product_images" ("id", "product_id", "object_name", "url", "status", "position", "is_primary", "upload_started_at", "created_at")
+SELECT gen_random_uuid(), "id", 'images/' || regexp_replace("image", '^.*/', ''), "image", 4, 0, true, now(), now()
+FROM "products" WHERE "image" IS NOT NULL AND "image" <> '';
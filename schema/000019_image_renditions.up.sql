@@ -0,0 +1,3 @@
+ALTER TABLE "product_images" ADD COLUMN "renditions" jsonb NOT NULL DEFAULT '[]';
+
+COMMENT ON COLUMN "product_images"."renditions" IS 'scaled copies of the image, e.g. [{"name": "thumbnail", "format": "webp", "object_name": "images/…/thumbnail.webp", …}]';
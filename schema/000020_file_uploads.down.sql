@@ -0,0 +1,8 @@
+DELETE FROM product_images WHERE status <> 4;
+
+ALTER TABLE product_images
+  ALTER COLUMN object_name DROP DEFAULT,
+  ALTER COLUMN url DROP DEFAULT,
+  DROP COLUMN attempts,
+  DROP COLUMN error,
+  DROP COLUMN updated_at;
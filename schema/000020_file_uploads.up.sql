@@ -0,0 +1,10 @@
+ALTER TABLE "product_images"
+  ALTER COLUMN "object_name" SET DEFAULT '',
+  ALTER COLUMN "url" SET DEFAULT '',
+  ADD COLUMN "attempts" integer NOT NULL DEFAULT 0,
+  ADD COLUMN "error" varchar(255) NOT NULL DEFAULT '',
+  ADD COLUMN "updated_at" timestamp;
+
+COMMENT ON COLUMN "product_images"."attempts" IS 'attempts to push the file to the storage';
+
+CREATE INDEX "product_images_uploading_idx" ON "product_images" ("updated_at") WHERE "status" IN (1, 3);
@@ -0,0 +1,3 @@
+ALTER TABLE "product_images" ADD COLUMN "checksum" varchar(64) NOT NULL DEFAULT '';
+
+COMMENT ON COLUMN "product_images"."checksum" IS 'hex SHA-256 of the uploaded file';
@@ -0,0 +1,3 @@
+DROP INDEX "product_images_uploading_idx";
+
+CREATE INDEX "product_images_uploading_idx" ON "product_images" ("updated_at") WHERE "status" IN (0, 1, 3);
@@ -0,0 +1,11 @@
+DROP TRIGGER categories_refresh_product_sales ON categories;
+
+DROP TRIGGER products_refresh_product_sales ON products;
+
+DROP TRIGGER discounts_refresh_product_sales ON discounts;
+
+DROP FUNCTION refresh_product_sales_trigger;
+
+DROP FUNCTION refresh_product_sales;
+
+ALTER TABLE products DROP COLUMN sale_price, DROP COLUMN sale_percent, DROP COLUMN sale_fixed;
@@ -0,0 +1,50 @@
+-- the best active sales of product_discounts are kept on the product, the
+-- view depends on now() and the price after them can't be indexed
+ALTER TABLE "products"
+  ADD COLUMN "sale_percent" bigint,
+  ADD COLUMN "sale_fixed" bigint;
+
+ALTER TABLE "products" ADD COLUMN "sale_price" bigint GENERATED ALWAYS AS (discounted_price("price", "sale_percent", "sale_fixed")) STORED;
+
+COMMENT ON COLUMN "products"."sale_price" IS 'price after the sales in sale_percent and sale_fixed, products are sorted by it';
+
+CREATE INDEX "products_sale_price_id_idx" ON "products" ("sale_price", "id");
+
+-- copies the best active sales of product_discounts to the products whose
+-- sales have changed and returns their number; run when sales start or end
+CREATE FUNCTION "refresh_product_sales"() RETURNS integer
+LANGUAGE plpgsql AS $$
+DECLARE
+  "refreshed" integer;
+BEGIN
+  UPDATE "products" p SET "sale_percent" = s."percent", "sale_fixed" = s."fixed"
+  FROM (SELECT p."id", sd."percent", sd."fixed" FROM "products" p LEFT JOIN "product_discounts" sd ON sd."product_id" = p."id") s
+  WHERE s."id" = p."id" AND (p."sale_percent", p."sale_fixed") IS DISTINCT FROM (s."percent", s."fixed");
+
+  GET DIAGNOSTICS "refreshed" = ROW_COUNT;
+
+  RETURN "refreshed";
+END
+$$;
+
+CREATE FUNCTION "refresh_product_sales_trigger"() RETURNS trigger
+LANGUAGE plpgsql AS $$
+BEGIN
+  PERFORM "refresh_product_sales"();
+
+  RETURN NULL;
+END
+$$;
+
+-- the sales of a product change with the discounts and with the categories it
+-- belongs to
+CREATE TRIGGER "discounts_refresh_product_sales" AFTER INSERT OR UPDATE OR DELETE ON "discounts"
+FOR EACH STATEMENT EXECUTE FUNCTION "refresh_product_sales_trigger"();
+
+CREATE TRIGGER "products_refresh_product_sales" AFTER INSERT OR UPDATE OF "category_id" ON "products"
+FOR EACH STATEMENT EXECUTE FUNCTION "refresh_product_sales_trigger"();
+
+CREATE TRIGGER "categories_refresh_product_sales" AFTER UPDATE OF "parent_id" ON "categories"
+FOR EACH STATEMENT EXECUTE FUNCTION "refresh_product_sales_trigger"();
+
+SELECT "refresh_product_sales"();